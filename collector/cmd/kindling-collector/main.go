@@ -26,14 +26,21 @@ func main() {
 
 	// Register signal handler
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 	// Block until a signal is received.
-	select {
-	case sig := <-sigCh:
+	for sig := range sigCh {
+		if sig == syscall.SIGHUP {
+			log.Printf("Received signal [%v], and will reload the configuration", sig)
+			if err = app.Reload(); err != nil {
+				log.Printf("Error happened when reloading the configuration: %v", err)
+			}
+			continue
+		}
 		log.Printf("Received signal [%v], and will exit", sig)
 		if err = app.Shutdown(); err != nil {
 			log.Printf("Error happened when shutting down: %v", err)
 			os.Exit(1)
 		}
+		return
 	}
 }
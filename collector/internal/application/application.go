@@ -1,8 +1,11 @@
 package application
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/Kindling-project/kindling/collector/pkg/component"
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer"
@@ -10,15 +13,16 @@ import (
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network"
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/tcpconnectanalyzer"
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/tcpmetricanalyzer"
-	"github.com/Kindling-project/kindling/collector/pkg/component/consumer"
 	"github.com/Kindling-project/kindling/collector/pkg/component/consumer/exporter/logexporter"
 	"github.com/Kindling-project/kindling/collector/pkg/component/consumer/exporter/otelexporter"
 	"github.com/Kindling-project/kindling/collector/pkg/component/consumer/processor/aggregateprocessor"
 	"github.com/Kindling-project/kindling/collector/pkg/component/consumer/processor/k8sprocessor"
 	"github.com/Kindling-project/kindling/collector/pkg/component/receiver"
-	"github.com/Kindling-project/kindling/collector/pkg/component/receiver/cgoreceiver"
+	"github.com/Kindling-project/kindling/collector/pkg/component/receiver/filereceiver"
+	"github.com/Kindling-project/kindling/collector/pkg/component/receiver/generatorreceiver"
+	"github.com/Kindling-project/kindling/collector/pkg/component/receiver/grpcreceiver"
+	"github.com/Kindling-project/kindling/collector/pkg/component/receiver/pcapreceiver"
 	"github.com/spf13/viper"
-	"go.uber.org/multierr"
 )
 
 type Application struct {
@@ -27,6 +31,14 @@ type Application struct {
 	telemetry         *component.TelemetryManager
 	receiver          receiver.Receiver
 	analyzerManager   *analyzer.Manager
+	pipelinesConfig   *PipelinesConfig
+	// components contains all the components created in the pipeline in order
+	components  []componentInstance
+	configPath  string
+	watchConfig bool
+	reloadMutex sync.Mutex
+	// shutdownTimeout limits the time spent on flushing the pending data when shutting down
+	shutdownTimeout time.Duration
 }
 
 func New() (*Application, error) {
@@ -35,10 +47,15 @@ func New() (*Application, error) {
 		componentsFactory: NewComponentsFactory(),
 		telemetry:         component.NewTelemetryManager(),
 	}
-	app.registerFactory()
+	registerFactory(app.componentsFactory)
 	// Initialize flags
 	configPath := flag.String("config", "kindling-collector-config.yml", "Configuration file")
+	watchConfig := flag.Bool("watch-config", false, "Reload the configuration when the configuration file changes")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 10*time.Second, "The maximum time to wait for the pending data to be flushed when shutting down")
 	flag.Parse()
+	app.configPath = *configPath
+	app.watchConfig = *watchConfig
+	app.shutdownTimeout = *shutdownTimeout
 	err := app.readInConfig(*configPath)
 	if err != nil {
 		return nil, fmt.Errorf("fail to read configuration: %w", err)
@@ -52,36 +69,38 @@ func New() (*Application, error) {
 }
 
 func (a *Application) Run() error {
-	err := a.analyzerManager.StartAll(a.telemetry.Telemetry.Logger)
+	err := a.start(context.Background())
 	if err != nil {
-		return fmt.Errorf("failed to start application: %v", err)
+		return fmt.Errorf("failed to start application: %w", err)
 	}
-	// Wait until the receiver shutdowns
-	err = a.receiver.Start()
-	if err != nil {
-		return fmt.Errorf("failed to start application: %v", err)
+	newSelfMetrics(a.telemetry.Telemetry.MeterProvider, a)
+	if a.watchConfig {
+		a.watchConfigFile()
 	}
 	return nil
 }
 
-func (a *Application) Shutdown() error {
-	return multierr.Combine(a.receiver.Shutdown(), a.analyzerManager.ShutdownAll(a.telemetry.Telemetry.Logger))
-}
-
 func initFlags() error {
 	return nil
 }
 
-func (a *Application) registerFactory() {
-	a.componentsFactory.RegisterReceiver(cgoreceiver.Cgo, cgoreceiver.NewCgoReceiver, &cgoreceiver.Config{})
-	a.componentsFactory.RegisterAnalyzer(network.Network.String(), network.NewNetworkAnalyzer, &network.Config{})
-	a.componentsFactory.RegisterProcessor(k8sprocessor.K8sMetadata, k8sprocessor.NewKubernetesProcessor, &k8sprocessor.DefaultConfig)
-	a.componentsFactory.RegisterExporter(otelexporter.Otel, otelexporter.NewExporter, &otelexporter.Config{})
-	a.componentsFactory.RegisterAnalyzer(tcpmetricanalyzer.TcpMetric.String(), tcpmetricanalyzer.NewTcpMetricAnalyzer, &tcpmetricanalyzer.Config{})
-	a.componentsFactory.RegisterExporter(logexporter.Type, logexporter.New, &logexporter.Config{})
-	a.componentsFactory.RegisterAnalyzer(loganalyzer.Type.String(), loganalyzer.New, &loganalyzer.Config{})
-	a.componentsFactory.RegisterProcessor(aggregateprocessor.Type, aggregateprocessor.New, aggregateprocessor.NewDefaultConfig())
-	a.componentsFactory.RegisterAnalyzer(tcpconnectanalyzer.Type.String(), tcpconnectanalyzer.New, tcpconnectanalyzer.NewDefaultConfig())
+// registerFactory registers all the components into the factory. Every call creates new
+// configuration instances, so the factory could be used to construct a new configuration
+// without touching the running one.
+func registerFactory(factory *ComponentsFactory) {
+	registerCgoReceiver(factory)
+	factory.RegisterReceiver(filereceiver.File, filereceiver.NewFileReceiver, filereceiver.NewDefaultConfig())
+	factory.RegisterReceiver(grpcreceiver.Grpc, grpcreceiver.NewGrpcReceiver, grpcreceiver.NewDefaultConfig())
+	factory.RegisterReceiver(generatorreceiver.Generator, generatorreceiver.NewGeneratorReceiver, generatorreceiver.NewDefaultConfig())
+	factory.RegisterReceiver(pcapreceiver.Pcap, pcapreceiver.NewPcapReceiver, pcapreceiver.NewDefaultConfig())
+	factory.RegisterAnalyzer(network.Network.String(), network.NewNetworkAnalyzer, &network.Config{})
+	factory.RegisterProcessor(k8sprocessor.K8sMetadata, k8sprocessor.NewKubernetesProcessor, k8sprocessor.NewDefaultConfig())
+	factory.RegisterExporter(otelexporter.Otel, otelexporter.NewExporter, &otelexporter.Config{})
+	factory.RegisterAnalyzer(tcpmetricanalyzer.TcpMetric.String(), tcpmetricanalyzer.NewTcpMetricAnalyzer, &tcpmetricanalyzer.Config{})
+	factory.RegisterExporter(logexporter.Type, logexporter.New, &logexporter.Config{})
+	factory.RegisterAnalyzer(loganalyzer.Type.String(), loganalyzer.New, &loganalyzer.Config{})
+	factory.RegisterProcessor(aggregateprocessor.Type, aggregateprocessor.New, aggregateprocessor.NewDefaultConfig())
+	factory.RegisterAnalyzer(tcpconnectanalyzer.Type.String(), tcpconnectanalyzer.New, tcpconnectanalyzer.NewDefaultConfig())
 }
 
 func (a *Application) readInConfig(path string) error {
@@ -95,44 +114,9 @@ func (a *Application) readInConfig(path string) error {
 	if err != nil {
 		return fmt.Errorf("error happened while constructing config: %w", err)
 	}
-	return nil
-}
-
-// buildPipeline builds a event processing pipeline based on hard-code.
-func (a *Application) buildPipeline() error {
-	// TODO: Build pipeline via configuration to implement dependency injection
-	// Initialize exporters
-	otelExporterFactory := a.componentsFactory.Exporters[otelexporter.Otel]
-	otelExporter := otelExporterFactory.NewFunc(otelExporterFactory.Config, a.telemetry.Telemetry)
-	// Initialize all processors
-	// 1. DataGroup Aggregator
-	aggregateProcessorFactory := a.componentsFactory.Processors[aggregateprocessor.Type]
-	aggregateProcessor := aggregateProcessorFactory.NewFunc(aggregateProcessorFactory.Config, a.telemetry.Telemetry, otelExporter)
-	// 2. Kubernetes metadata processor
-	k8sProcessorFactory := a.componentsFactory.Processors[k8sprocessor.K8sMetadata]
-	k8sMetadataProcessor := k8sProcessorFactory.NewFunc(k8sProcessorFactory.Config, a.telemetry.Telemetry, aggregateProcessor)
-	// Initialize all analyzers
-	// 1. Common network request analyzer
-	networkAnalyzerFactory := a.componentsFactory.Analyzers[network.Network.String()]
-	// Now NetworkAnalyzer must be initialized before any other analyzers, because it will
-	// use its configuration to initialize the conntracker module which is also used by others.
-	networkAnalyzer := networkAnalyzerFactory.NewFunc(networkAnalyzerFactory.Config, a.telemetry.Telemetry, []consumer.Consumer{k8sMetadataProcessor})
-	// 2. Layer 4 TCP events analyzer
-	aggregateProcessorForTcp := aggregateProcessorFactory.NewFunc(aggregateProcessorFactory.Config, a.telemetry.Telemetry, otelExporter)
-	k8sMetadataProcessor2 := k8sProcessorFactory.NewFunc(k8sProcessorFactory.Config, a.telemetry.Telemetry, aggregateProcessorForTcp)
-	tcpAnalyzerFactory := a.componentsFactory.Analyzers[tcpmetricanalyzer.TcpMetric.String()]
-	tcpAnalyzer := tcpAnalyzerFactory.NewFunc(tcpAnalyzerFactory.Config, a.telemetry.Telemetry, []consumer.Consumer{k8sMetadataProcessor2})
-	tcpConnectAnalyzerFactory := a.componentsFactory.Analyzers[tcpconnectanalyzer.Type.String()]
-	tcpConnectAnalyzer := tcpConnectAnalyzerFactory.NewFunc(tcpConnectAnalyzerFactory.Config, a.telemetry.Telemetry, []consumer.Consumer{k8sMetadataProcessor})
-	// Initialize receiver packaged with multiple analyzers
-	analyzerManager, err := analyzer.NewManager(networkAnalyzer, tcpAnalyzer, tcpConnectAnalyzer)
+	a.pipelinesConfig, err = newPipelinesConfig(a.viper)
 	if err != nil {
-		return fmt.Errorf("error happened while creating analyzer manager: %w", err)
+		return fmt.Errorf("error happened while constructing pipelines config: %w", err)
 	}
-	a.analyzerManager = analyzerManager
-
-	cgoReceiverFactory := a.componentsFactory.Receivers[cgoreceiver.Cgo]
-	cgoReceiver := cgoReceiverFactory.NewFunc(cgoReceiverFactory.Config, a.telemetry.Telemetry, analyzerManager)
-	a.receiver = cgoReceiver
 	return nil
 }
@@ -103,6 +103,43 @@ func (c *ComponentsFactory) RegisterExporter(
 	}
 }
 
+// getConfig returns the configuration of the component registered with the kind and name.
+func (c *ComponentsFactory) getConfig(kind string, name string) interface{} {
+	switch kind {
+	case ReceiversKey:
+		return c.Receivers[name].Config
+	case AnalyzersKey:
+		return c.Analyzers[name].Config
+	case ProcessorsKey:
+		return c.Processors[name].Config
+	case ExportersKey:
+		return c.Exporters[name].Config
+	}
+	return nil
+}
+
+// setConfig replaces the configuration of the component registered with the kind and name.
+func (c *ComponentsFactory) setConfig(kind string, name string, config interface{}) {
+	switch kind {
+	case ReceiversKey:
+		factory := c.Receivers[name]
+		factory.Config = config
+		c.Receivers[name] = factory
+	case AnalyzersKey:
+		factory := c.Analyzers[name]
+		factory.Config = config
+		c.Analyzers[name] = factory
+	case ProcessorsKey:
+		factory := c.Processors[name]
+		factory.Config = config
+		c.Processors[name] = factory
+	case ExportersKey:
+		factory := c.Exporters[name]
+		factory.Config = config
+		c.Exporters[name] = factory
+	}
+}
+
 func (c *ComponentsFactory) ConstructConfig(viper *viper.Viper) error {
 	for _, componentKind := range ComponentsKeyMap {
 		switch componentKind {
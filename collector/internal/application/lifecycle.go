@@ -0,0 +1,77 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component"
+	"github.com/hashicorp/go-multierror"
+)
+
+// ComponentHealth is the health of a component created in the pipeline.
+type ComponentHealth struct {
+	Kind string
+	Name string
+	component.Health
+}
+
+// Health returns the health of all the components in the order of their creation.
+func (a *Application) Health() []ComponentHealth {
+	ret := make([]ComponentHealth, 0, len(a.components))
+	for _, c := range a.components {
+		ret = append(ret, ComponentHealth{Kind: c.kind, Name: c.name, Health: c.instance.Health()})
+	}
+	return ret
+}
+
+// start starts the components against the direction of the data flow, so that no component
+// sends data to the ones not started yet. The components are created from the exporters to the
+// receiver, so they are started in the order of creation. If any component fails to start, the
+// started ones are shut down and the error is returned.
+func (a *Application) start(ctx context.Context) error {
+	logger := a.telemetry.Telemetry.Logger
+	for i, c := range a.components {
+		logger.Sugar().Infof("Starting %s [%s]", c.kind, c.name)
+		if err := c.instance.Start(ctx); err != nil {
+			var retErr error = fmt.Errorf("failed to start [%s]: %w", c.name, err)
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
+			defer cancel()
+			if shutdownErr := a.shutdownComponents(shutdownCtx, a.components[:i]); shutdownErr != nil {
+				retErr = multierror.Append(retErr, shutdownErr)
+			}
+			return retErr
+		}
+	}
+	return nil
+}
+
+// Shutdown stops the components in the direction of the data flow, so that each component
+// could flush its pending data to the downstream ones before they are stopped. The receiver
+// stops and drains its events first, then the analyzers flush the pending message pairs, the
+// processors flush their states, and the exporters export the data they hold at last.
+//
+// The whole procedure is limited by the shutdown timeout. The components that don't finish
+// in time give up flushing, and the rest are still stopped.
+func (a *Application) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
+	defer cancel()
+	return a.shutdown(ctx)
+}
+
+func (a *Application) shutdown(ctx context.Context) error {
+	return a.shutdownComponents(ctx, a.components)
+}
+
+// shutdownComponents stops the components in reverse order.
+func (a *Application) shutdownComponents(ctx context.Context, components []componentInstance) error {
+	logger := a.telemetry.Telemetry.Logger
+	var retErr error
+	for i := len(components) - 1; i >= 0; i-- {
+		c := components[i]
+		logger.Sugar().Infof("Shutdown %s [%s]", c.kind, c.name)
+		if err := c.instance.Shutdown(ctx); err != nil {
+			retErr = multierror.Append(retErr, fmt.Errorf("failed to shutdown [%s]: %w", c.name, err))
+		}
+	}
+	return retErr
+}
@@ -0,0 +1,186 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer"
+	"github.com/Kindling-project/kindling/collector/pkg/component/consumer"
+	"github.com/Kindling-project/kindling/collector/pkg/component/consumer/exporter"
+	"github.com/Kindling-project/kindling/collector/pkg/component/consumer/processor"
+	"github.com/Kindling-project/kindling/collector/pkg/component/receiver"
+)
+
+type lifecycleRecorder struct {
+	mutex   sync.Mutex
+	started []string
+	stopped []string
+	// startErrors contains the errors returned when the components are started
+	startErrors map[string]error
+}
+
+func (r *lifecycleRecorder) start(name string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err, ok := r.startErrors[name]; ok {
+		return err
+	}
+	r.started = append(r.started, name)
+	return nil
+}
+
+func (r *lifecycleRecorder) stop(name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.stopped = append(r.stopped, name)
+}
+
+// blockingComponent is a receiver or an analyzer whose Shutdown returns only when unblocked
+// or ctx is done.
+type blockingComponent struct {
+	mockComponent
+	recorder *lifecycleRecorder
+	block    chan struct{}
+}
+
+func (c *blockingComponent) Start(ctx context.Context) error {
+	return c.recorder.start(c.name)
+}
+
+func (c *blockingComponent) Shutdown(ctx context.Context) error {
+	select {
+	case <-c.block:
+		c.recorder.stop(c.name)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flushingComponent is a processor or an exporter.
+type flushingComponent struct {
+	nopExporter
+	name     string
+	recorder *lifecycleRecorder
+}
+
+func (c *flushingComponent) Start(ctx context.Context) error {
+	return c.recorder.start(c.name)
+}
+
+func (c *flushingComponent) Shutdown(ctx context.Context) error {
+	c.recorder.stop(c.name)
+	return nil
+}
+
+func newLifecycleTestApplication(recorder *lifecycleRecorder, block chan struct{}) *Application {
+	factory := NewComponentsFactory()
+	factory.RegisterReceiver("mockreceiver", func(cfg interface{}, telemetry *component.TelemetryTools, analyzerManager *analyzer.Manager) receiver.Receiver {
+		return &blockingComponent{mockComponent: mockComponent{name: "mockreceiver"}, recorder: recorder, block: block}
+	}, nil)
+	factory.RegisterAnalyzer("mockanalyzer", func(cfg interface{}, telemetry *component.TelemetryTools, consumers []consumer.Consumer) analyzer.Analyzer {
+		return &blockingComponent{mockComponent: mockComponent{name: "mockanalyzer"}, recorder: recorder, block: block}
+	}, nil)
+	factory.RegisterProcessor("mockprocessor", func(cfg interface{}, telemetry *component.TelemetryTools, consumer consumer.Consumer) processor.Processor {
+		return &flushingComponent{name: "mockprocessor", recorder: recorder}
+	}, nil)
+	factory.RegisterExporter("mockexporter", func(cfg interface{}, telemetry *component.TelemetryTools) exporter.Exporter {
+		return &flushingComponent{name: "mockexporter", recorder: recorder}
+	}, nil)
+	return &Application{
+		componentsFactory: factory,
+		telemetry:         component.NewTelemetryManager(),
+		shutdownTimeout:   time.Second,
+		pipelinesConfig: &PipelinesConfig{
+			Receiver: "mockreceiver",
+			Chains: []PipelineConfig{
+				{
+					Name:       "chain",
+					Analyzers:  []string{"mockanalyzer"},
+					Processors: []string{"mockprocessor"},
+					Exporters:  []string{"mockexporter"},
+				},
+			},
+		},
+	}
+}
+
+func TestStart(t *testing.T) {
+	recorder := &lifecycleRecorder{}
+	app := newLifecycleTestApplication(recorder, nil)
+	if err := app.buildPipeline(); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"mockexporter", "mockprocessor", "mockanalyzer", "mockreceiver"}
+	if !reflect.DeepEqual(recorder.started, expected) {
+		t.Errorf("Expected the start order %v, but get %v", expected, recorder.started)
+	}
+}
+
+func TestStartWithError(t *testing.T) {
+	recorder := &lifecycleRecorder{startErrors: map[string]error{"mockanalyzer": errors.New("mock error")}}
+	block := make(chan struct{})
+	close(block)
+	app := newLifecycleTestApplication(recorder, block)
+	if err := app.buildPipeline(); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.start(context.Background()); err == nil {
+		t.Fatalf("Expected an error, but get nil")
+	}
+	expected := []string{"mockprocessor", "mockexporter"}
+	if !reflect.DeepEqual(recorder.stopped, expected) {
+		t.Errorf("The started components are expected to be stopped, expected %v, but get %v", expected, recorder.stopped)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	recorder := &lifecycleRecorder{}
+	block := make(chan struct{})
+	close(block)
+	app := newLifecycleTestApplication(recorder, block)
+	if err := app.buildPipeline(); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"mockreceiver", "mockanalyzer", "mockprocessor", "mockexporter"}
+	if !reflect.DeepEqual(recorder.stopped, expected) {
+		t.Errorf("Expected the shutdown order %v, but get %v", expected, recorder.stopped)
+	}
+}
+
+func TestShutdownWithDeadline(t *testing.T) {
+	recorder := &lifecycleRecorder{}
+	block := make(chan struct{})
+	defer close(block)
+	app := newLifecycleTestApplication(recorder, block)
+	if err := app.buildPipeline(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := app.shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline exceeded error, but get %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Shutdown is expected to return after the deadline, but it takes %v", elapsed)
+	}
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	expected := []string{"mockprocessor", "mockexporter"}
+	if !reflect.DeepEqual(recorder.stopped, expected) {
+		t.Errorf("The rest components are expected to be stopped, expected %v, but get %v", expected, recorder.stopped)
+	}
+}
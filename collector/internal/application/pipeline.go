@@ -0,0 +1,199 @@
+package application
+
+import (
+	"fmt"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/tcpconnectanalyzer"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/tcpmetricanalyzer"
+	"github.com/Kindling-project/kindling/collector/pkg/component/consumer"
+	"github.com/Kindling-project/kindling/collector/pkg/component/consumer/exporter"
+	"github.com/Kindling-project/kindling/collector/pkg/component/consumer/exporter/otelexporter"
+	"github.com/Kindling-project/kindling/collector/pkg/component/consumer/processor/aggregateprocessor"
+	"github.com/Kindling-project/kindling/collector/pkg/component/consumer/processor/k8sprocessor"
+	"github.com/Kindling-project/kindling/collector/pkg/component/receiver/cgoreceiver"
+	"github.com/spf13/viper"
+)
+
+const PipelinesKey = "pipelines"
+
+// PipelinesConfig describes how the components are connected with each other.
+// All the analyzers declared in the chains are fed by the same receiver.
+type PipelinesConfig struct {
+	Receiver string           `mapstructure:"receiver"`
+	Chains   []PipelineConfig `mapstructure:"chains"`
+}
+
+// PipelineConfig declares one chain of the pipeline graph. The analyzers send their
+// dataGroups to the first processor, the processors are connected in the listed order,
+// and the last processor sends dataGroups to all the exporters. If there is no processor,
+// the analyzers send dataGroups to the exporters directly.
+//
+// Processors are created for each chain, while exporters with the same name are shared
+// by all the chains.
+type PipelineConfig struct {
+	Name       string   `mapstructure:"name"`
+	Analyzers  []string `mapstructure:"analyzers"`
+	Processors []string `mapstructure:"processors"`
+	Exporters  []string `mapstructure:"exporters"`
+}
+
+// NewDefaultPipelinesConfig returns the pipeline graph used when there is no "pipelines"
+// section in the configuration file.
+func NewDefaultPipelinesConfig() *PipelinesConfig {
+	return &PipelinesConfig{
+		Receiver: cgoreceiver.Cgo,
+		Chains: []PipelineConfig{
+			{
+				Name:       "network",
+				Analyzers:  []string{network.Network.String(), tcpconnectanalyzer.Type.String()},
+				Processors: []string{k8sprocessor.K8sMetadata, aggregateprocessor.Type},
+				Exporters:  []string{otelexporter.Otel},
+			},
+			{
+				Name:       "tcp",
+				Analyzers:  []string{tcpmetricanalyzer.TcpMetric.String()},
+				Processors: []string{k8sprocessor.K8sMetadata, aggregateprocessor.Type},
+				Exporters:  []string{otelexporter.Otel},
+			},
+		},
+	}
+}
+
+func newPipelinesConfig(viper *viper.Viper) (*PipelinesConfig, error) {
+	if !viper.IsSet(PipelinesKey) {
+		return NewDefaultPipelinesConfig(), nil
+	}
+	config := &PipelinesConfig{}
+	err := viper.UnmarshalKey(PipelinesKey, config)
+	if err != nil {
+		return nil, err
+	}
+	if config.Receiver == "" {
+		config.Receiver = cgoreceiver.Cgo
+	}
+	return config, nil
+}
+
+// validate checks whether all the components used in the pipelines have been registered
+// and whether the graph could be built.
+func (c *PipelinesConfig) validate(factory *ComponentsFactory) error {
+	if _, ok := factory.Receivers[c.Receiver]; !ok {
+		return fmt.Errorf("unknown receiver [%s]", c.Receiver)
+	}
+	if len(c.Chains) == 0 {
+		return fmt.Errorf("no pipeline found, but must provide at least one pipeline")
+	}
+	analyzerOwners := make(map[string]string)
+	for i, chain := range c.Chains {
+		name := chain.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+		if len(chain.Analyzers) == 0 {
+			return fmt.Errorf("no analyzers found in pipeline [%s]", name)
+		}
+		if len(chain.Exporters) == 0 {
+			return fmt.Errorf("no exporters found in pipeline [%s]", name)
+		}
+		for _, analyzerName := range chain.Analyzers {
+			if _, ok := factory.Analyzers[analyzerName]; !ok {
+				return fmt.Errorf("unknown analyzer [%s] in pipeline [%s]", analyzerName, name)
+			}
+			// The receiver would send the same event to an analyzer twice if it is declared
+			// in more than one pipelines.
+			if owner, ok := analyzerOwners[analyzerName]; ok {
+				return fmt.Errorf("analyzer [%s] is declared in both pipeline [%s] and [%s]", analyzerName, owner, name)
+			}
+			analyzerOwners[analyzerName] = name
+		}
+		for _, processorName := range chain.Processors {
+			if _, ok := factory.Processors[processorName]; !ok {
+				return fmt.Errorf("unknown processor [%s] in pipeline [%s]", processorName, name)
+			}
+		}
+		for _, exporterName := range chain.Exporters {
+			if _, ok := factory.Exporters[exporterName]; !ok {
+				return fmt.Errorf("unknown exporter [%s] in pipeline [%s]", exporterName, name)
+			}
+		}
+	}
+	return nil
+}
+
+// componentInstance records a component created in the pipeline.
+type componentInstance struct {
+	// kind is one of ReceiversKey, AnalyzersKey, ProcessorsKey and ExportersKey
+	kind     string
+	name     string
+	instance component.Component
+}
+
+// buildPipeline builds an event processing pipeline based on the pipelines configuration.
+func (a *Application) buildPipeline() error {
+	cfg := a.pipelinesConfig
+	err := cfg.validate(a.componentsFactory)
+	if err != nil {
+		return fmt.Errorf("invalid pipelines configuration: %w", err)
+	}
+	// Initialize exporters and processors from the tail of each chain
+	exporters := make(map[string]exporter.Exporter)
+	chainConsumers := make([][]consumer.Consumer, len(cfg.Chains))
+	for i, chain := range cfg.Chains {
+		exporterConsumers := make([]consumer.Consumer, 0, len(chain.Exporters))
+		for _, exporterName := range chain.Exporters {
+			exp, ok := exporters[exporterName]
+			if !ok {
+				exporterFactory := a.componentsFactory.Exporters[exporterName]
+				exp = exporterFactory.NewFunc(exporterFactory.Config, a.telemetry.Telemetry)
+				exporters[exporterName] = exp
+				a.components = append(a.components, componentInstance{ExportersKey, exporterName, exp})
+			}
+			exporterConsumers = append(exporterConsumers, exp)
+		}
+		if len(chain.Processors) == 0 {
+			chainConsumers[i] = exporterConsumers
+			continue
+		}
+		nextConsumer := consumer.NewFanOutConsumer(exporterConsumers...)
+		for j := len(chain.Processors) - 1; j >= 0; j-- {
+			processorFactory := a.componentsFactory.Processors[chain.Processors[j]]
+			newProcessor := processorFactory.NewFunc(processorFactory.Config, a.telemetry.Telemetry, nextConsumer)
+			a.components = append(a.components, componentInstance{ProcessorsKey, chain.Processors[j], newProcessor})
+			nextConsumer = newProcessor
+		}
+		chainConsumers[i] = []consumer.Consumer{nextConsumer}
+	}
+	// Initialize all analyzers.
+	// Now NetworkAnalyzer must be initialized before any other analyzers, because it will
+	// use its configuration to initialize the conntracker module which is also used by others.
+	analyzers := make([]analyzer.Analyzer, 0)
+	newAnalyzers := func(filter func(name string) bool) {
+		for i, chain := range cfg.Chains {
+			for _, analyzerName := range chain.Analyzers {
+				if !filter(analyzerName) {
+					continue
+				}
+				analyzerFactory := a.componentsFactory.Analyzers[analyzerName]
+				newAnalyzer := analyzerFactory.NewFunc(analyzerFactory.Config, a.telemetry.Telemetry, chainConsumers[i])
+				analyzers = append(analyzers, newAnalyzer)
+				a.components = append(a.components, componentInstance{AnalyzersKey, analyzerName, newAnalyzer})
+			}
+		}
+	}
+	newAnalyzers(func(name string) bool { return name == network.Network.String() })
+	newAnalyzers(func(name string) bool { return name != network.Network.String() })
+	// Initialize receiver packaged with multiple analyzers
+	analyzerManager, err := analyzer.NewManager(analyzers...)
+	if err != nil {
+		return fmt.Errorf("error happened while creating analyzer manager: %w", err)
+	}
+	a.analyzerManager = analyzerManager
+
+	receiverFactory := a.componentsFactory.Receivers[cfg.Receiver]
+	a.receiver = receiverFactory.NewFunc(receiverFactory.Config, a.telemetry.Telemetry, analyzerManager)
+	a.components = append(a.components, componentInstance{ReceiversKey, cfg.Receiver, a.receiver})
+	return nil
+}
@@ -0,0 +1,224 @@
+package application
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer"
+	"github.com/Kindling-project/kindling/collector/pkg/component/consumer"
+	"github.com/Kindling-project/kindling/collector/pkg/component/consumer/exporter"
+	"github.com/Kindling-project/kindling/collector/pkg/component/consumer/processor"
+	"github.com/Kindling-project/kindling/collector/pkg/component/receiver"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/spf13/viper"
+)
+
+func TestNewPipelinesConfig(t *testing.T) {
+	v := viper.New()
+	v.SetConfigFile("testdata/kindling-collector-config.yaml")
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := newPipelinesConfig(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedCfg := NewDefaultPipelinesConfig()
+	expectedCfg.Chains[1].Exporters = []string{"otelexporter", "logexporter"}
+	if !reflect.DeepEqual(cfg, expectedCfg) {
+		t.Errorf("Expected %v, but get %v", expectedCfg, cfg)
+	}
+
+	cfg, err = newPipelinesConfig(viper.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg, NewDefaultPipelinesConfig()) {
+		t.Errorf("Expected the default pipelines config, but get %v", cfg)
+	}
+}
+
+// nopLifecycle implements component.Component for the mock components.
+type nopLifecycle struct{}
+
+func (nopLifecycle) Start(ctx context.Context) error    { return nil }
+func (nopLifecycle) Shutdown(ctx context.Context) error { return nil }
+func (nopLifecycle) Health() component.Health {
+	return component.Health{Status: component.StatusRunning}
+}
+
+type mockComponent struct {
+	nopLifecycle
+	name string
+	next []consumer.Consumer
+}
+
+func (m *mockComponent) Consume(dataGroup *model.DataGroup) error {
+	for _, next := range m.next {
+		_ = next.Consume(dataGroup)
+	}
+	return nil
+}
+
+func (m *mockComponent) Type() analyzer.Type                           { return analyzer.Type(m.name) }
+func (m *mockComponent) ConsumableEvents() []string                    { return []string{analyzer.ConsumeAllEvents} }
+func (m *mockComponent) ConsumeEvent(event *model.KindlingEvent) error { return nil }
+
+type mockRecorder struct {
+	created  map[string]int
+	consumed map[string]int
+}
+
+func (r *mockRecorder) newComponent(name string, next ...consumer.Consumer) *mockComponent {
+	r.created[name]++
+	return &mockComponent{name: name, next: next}
+}
+
+func (r *mockRecorder) newExporter(name string) *recordExporter {
+	r.created[name]++
+	return &recordExporter{name: name, recorder: r}
+}
+
+type recordExporter struct {
+	nopLifecycle
+	name     string
+	recorder *mockRecorder
+}
+
+func (e *recordExporter) Consume(dataGroup *model.DataGroup) error {
+	e.recorder.consumed[e.name]++
+	return nil
+}
+
+func newMockApplication(recorder *mockRecorder) *Application {
+	factory := NewComponentsFactory()
+	factory.RegisterReceiver("mockreceiver", func(cfg interface{}, telemetry *component.TelemetryTools, analyzerManager *analyzer.Manager) receiver.Receiver {
+		return recorder.newComponent("mockreceiver")
+	}, nil)
+	for _, name := range []string{"networkanalyzer", "mockanalyzer1", "mockanalyzer2"} {
+		analyzerName := name
+		factory.RegisterAnalyzer(analyzerName, func(cfg interface{}, telemetry *component.TelemetryTools, consumers []consumer.Consumer) analyzer.Analyzer {
+			return recorder.newComponent(analyzerName, consumers...)
+		}, nil)
+	}
+	factory.RegisterProcessor("mockprocessor", func(cfg interface{}, telemetry *component.TelemetryTools, consumer consumer.Consumer) processor.Processor {
+		return recorder.newComponent("mockprocessor", consumer)
+	}, nil)
+	for _, name := range []string{"mockexporter1", "mockexporter2"} {
+		exporterName := name
+		factory.RegisterExporter(exporterName, func(cfg interface{}, telemetry *component.TelemetryTools) exporter.Exporter {
+			return recorder.newExporter(exporterName)
+		}, nil)
+	}
+	return &Application{
+		componentsFactory: factory,
+		telemetry:         component.NewTelemetryManager(),
+	}
+}
+
+func TestBuildPipeline(t *testing.T) {
+	recorder := &mockRecorder{created: make(map[string]int), consumed: make(map[string]int)}
+	app := newMockApplication(recorder)
+	app.pipelinesConfig = &PipelinesConfig{
+		Receiver: "mockreceiver",
+		Chains: []PipelineConfig{
+			{
+				Name:       "chain1",
+				Analyzers:  []string{"mockanalyzer1"},
+				Processors: []string{"mockprocessor", "mockprocessor"},
+				Exporters:  []string{"mockexporter1", "mockexporter2"},
+			},
+			{
+				Name:      "chain2",
+				Analyzers: []string{"mockanalyzer2", "networkanalyzer"},
+				Exporters: []string{"mockexporter1"},
+			},
+		},
+	}
+	if err := app.buildPipeline(); err != nil {
+		t.Fatal(err)
+	}
+	expectedCreated := map[string]int{
+		"mockreceiver":    1,
+		"networkanalyzer": 1,
+		"mockanalyzer1":   1,
+		"mockanalyzer2":   1,
+		"mockprocessor":   2,
+		"mockexporter1":   1,
+		"mockexporter2":   1,
+	}
+	if !reflect.DeepEqual(recorder.created, expectedCreated) {
+		t.Errorf("Expected %v, but get %v", expectedCreated, recorder.created)
+	}
+
+	consumable := app.analyzerManager.GetConsumableAnalyzers("any")
+	if len(consumable) != 3 || consumable[0].Type() != "networkanalyzer" {
+		t.Fatalf("networkanalyzer is expected to be created first, but get %v", consumable)
+	}
+	for _, a := range consumable {
+		_ = a.(consumer.Consumer).Consume(&model.DataGroup{})
+	}
+	expectedConsumed := map[string]int{
+		"mockexporter1": 3,
+		"mockexporter2": 1,
+	}
+	if !reflect.DeepEqual(recorder.consumed, expectedConsumed) {
+		t.Errorf("Expected %v, but get %v", expectedConsumed, recorder.consumed)
+	}
+}
+
+func TestBuildPipelineWithInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *PipelinesConfig
+	}{
+		{
+			name:   "unknown receiver",
+			config: &PipelinesConfig{Receiver: "unknown"},
+		},
+		{
+			name:   "no chains",
+			config: &PipelinesConfig{Receiver: "mockreceiver"},
+		},
+		{
+			name: "unknown analyzer",
+			config: &PipelinesConfig{Receiver: "mockreceiver", Chains: []PipelineConfig{
+				{Name: "a", Analyzers: []string{"unknown"}, Exporters: []string{"mockexporter1"}},
+			}},
+		},
+		{
+			name: "unknown processor",
+			config: &PipelinesConfig{Receiver: "mockreceiver", Chains: []PipelineConfig{
+				{Name: "a", Analyzers: []string{"mockanalyzer1"}, Processors: []string{"unknown"}, Exporters: []string{"mockexporter1"}},
+			}},
+		},
+		{
+			name: "no exporters",
+			config: &PipelinesConfig{Receiver: "mockreceiver", Chains: []PipelineConfig{
+				{Name: "a", Analyzers: []string{"mockanalyzer1"}},
+			}},
+		},
+		{
+			name: "duplicated analyzer",
+			config: &PipelinesConfig{Receiver: "mockreceiver", Chains: []PipelineConfig{
+				{Name: "a", Analyzers: []string{"mockanalyzer1"}, Exporters: []string{"mockexporter1"}},
+				{Name: "b", Analyzers: []string{"mockanalyzer1"}, Exporters: []string{"mockexporter2"}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &mockRecorder{created: make(map[string]int), consumed: make(map[string]int)}
+			app := newMockApplication(recorder)
+			app.pipelinesConfig = tt.config
+			if err := app.buildPipeline(); err == nil {
+				t.Errorf("Expected an error, but get nil")
+			}
+			if len(recorder.created) != 0 {
+				t.Errorf("No components are expected to be created, but get %v", recorder.created)
+			}
+		})
+	}
+}
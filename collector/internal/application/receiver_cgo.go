@@ -0,0 +1,10 @@
+//go:build cgo
+// +build cgo
+
+package application
+
+import "github.com/Kindling-project/kindling/collector/pkg/component/receiver/cgoreceiver"
+
+func registerCgoReceiver(factory *ComponentsFactory) {
+	factory.RegisterReceiver(cgoreceiver.Cgo, cgoreceiver.NewCgoReceiver, cgoreceiver.NewDefaultConfig())
+}
@@ -0,0 +1,9 @@
+//go:build !cgo
+// +build !cgo
+
+package application
+
+// registerCgoReceiver does nothing when the collector is built without cgo, in which case
+// the events can be received by grpcreceiver from the probe running in another process.
+func registerCgoReceiver(factory *ComponentsFactory) {
+}
@@ -0,0 +1,152 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component"
+	"github.com/fsnotify/fsnotify"
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/viper"
+)
+
+// configChange describes a component whose configuration is different from the running one.
+type configChange struct {
+	kind      string
+	name      string
+	newConfig interface{}
+	instances []interface{}
+}
+
+// Reload reads the configuration file again and applies the changes to the running components
+// without restarting the agent.
+//
+// The pipelines and the observability settings can't be changed at runtime. The configuration
+// of a component can only be changed if the component implements component.Reloadable. All the
+// changed components are checked before any of them is touched, so an unsupported change is
+// rejected as a whole. If a component fails to apply its new configuration, the components that
+// have applied theirs are rolled back to the old ones, so the configuration is never half applied.
+func (a *Application) Reload() error {
+	a.reloadMutex.Lock()
+	defer a.reloadMutex.Unlock()
+	newViper := viper.New()
+	newViper.SetConfigFile(a.configPath)
+	err := newViper.ReadInConfig()
+	if err != nil {
+		return fmt.Errorf("error happened while reading config file: %w", err)
+	}
+	newFactory := NewComponentsFactory()
+	registerFactory(newFactory)
+	return a.applyConfig(newViper, newFactory)
+}
+
+// applyConfig applies the configuration read by the viper to the running components. The factory
+// must contain the same components as the running one, but with new configuration instances.
+func (a *Application) applyConfig(newViper *viper.Viper, newFactory *ComponentsFactory) error {
+	err := newFactory.ConstructConfig(newViper)
+	if err != nil {
+		return fmt.Errorf("error happened while constructing config: %w", err)
+	}
+	pipelinesConfig, err := newPipelinesConfig(newViper)
+	if err != nil {
+		return fmt.Errorf("error happened while constructing pipelines config: %w", err)
+	}
+	if !reflect.DeepEqual(pipelinesConfig, a.pipelinesConfig) {
+		return errors.New("the pipelines can't be changed at runtime, please restart the agent to apply it")
+	}
+	if !reflect.DeepEqual(newViper.Get(component.ObservabilityConfig), a.viper.Get(component.ObservabilityConfig)) {
+		return errors.New("the observability settings can't be changed at runtime, please restart the agent to apply it")
+	}
+
+	changes := a.diffConfigs(newFactory)
+	if len(changes) == 0 {
+		a.telemetry.Telemetry.Logger.Info("No changes found in the configuration file")
+		return nil
+	}
+	for _, change := range changes {
+		for _, instance := range change.instances {
+			if _, ok := instance.(component.Reloadable); !ok {
+				return fmt.Errorf("[%s] doesn't support changing its configuration at runtime, please restart the agent to apply it", change.name)
+			}
+		}
+	}
+
+	applied := make([]appliedConfig, 0)
+	for _, change := range changes {
+		oldConfig := a.componentsFactory.getConfig(change.kind, change.name)
+		for _, instance := range change.instances {
+			reloadable := instance.(component.Reloadable)
+			if err := reloadable.ApplyConfig(change.newConfig); err != nil {
+				return a.rollbackConfig(applied, fmt.Errorf("failed to apply the new configuration of [%s]: %w", change.name, err))
+			}
+			applied = append(applied, appliedConfig{name: change.name, instance: reloadable, oldConfig: oldConfig})
+		}
+	}
+	for _, change := range changes {
+		a.componentsFactory.setConfig(change.kind, change.name, change.newConfig)
+		a.telemetry.Telemetry.Logger.Sugar().Infof("The new configuration of [%s] is applied", change.name)
+	}
+	return nil
+}
+
+// appliedConfig is a component instance that has applied its new configuration.
+type appliedConfig struct {
+	name      string
+	instance  component.Reloadable
+	oldConfig interface{}
+}
+
+// rollbackConfig applies the old configurations to the instances in the reverse order. The
+// errors happened during the rollback are appended to retErr, which is returned.
+func (a *Application) rollbackConfig(applied []appliedConfig, retErr error) error {
+	for i := len(applied) - 1; i >= 0; i-- {
+		if err := applied[i].instance.ApplyConfig(applied[i].oldConfig); err != nil {
+			retErr = multierror.Append(retErr, fmt.Errorf("failed to roll back the configuration of [%s]: %w", applied[i].name, err))
+			continue
+		}
+		a.telemetry.Telemetry.Logger.Sugar().Infof("The configuration of [%s] is rolled back", applied[i].name)
+	}
+	return retErr
+}
+
+// diffConfigs returns the running components whose configurations in the factory are different
+// from the current ones. The changes are in the order of the components' creation.
+func (a *Application) diffConfigs(newFactory *ComponentsFactory) []*configChange {
+	changes := make([]*configChange, 0)
+	changeMap := make(map[string]*configChange)
+	for _, c := range a.components {
+		key := c.kind + "." + c.name
+		if change, ok := changeMap[key]; ok {
+			change.instances = append(change.instances, c.instance)
+			continue
+		}
+		newConfig := newFactory.getConfig(c.kind, c.name)
+		if reflect.DeepEqual(newConfig, a.componentsFactory.getConfig(c.kind, c.name)) {
+			continue
+		}
+		change := &configChange{
+			kind:      c.kind,
+			name:      c.name,
+			newConfig: newConfig,
+			instances: []interface{}{c.instance},
+		}
+		changeMap[key] = change
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// watchConfigFile reloads the configuration when the configuration file changes. The symbolic
+// links used by the Kubernetes ConfigMap volumes are also handled.
+func (a *Application) watchConfigFile() {
+	watcher := viper.New()
+	watcher.SetConfigFile(a.configPath)
+	watcher.OnConfigChange(func(in fsnotify.Event) {
+		a.telemetry.Telemetry.Logger.Sugar().Infof("The configuration file is changed: %s", in.String())
+		if err := a.Reload(); err != nil {
+			a.telemetry.Telemetry.Logger.Sugar().Errorf("Failed to reload the configuration: %v", err)
+		}
+	})
+	watcher.WatchConfig()
+}
@@ -0,0 +1,197 @@
+package application
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer"
+	"github.com/Kindling-project/kindling/collector/pkg/component/consumer"
+	"github.com/Kindling-project/kindling/collector/pkg/component/consumer/exporter"
+	"github.com/Kindling-project/kindling/collector/pkg/component/consumer/processor"
+	"github.com/Kindling-project/kindling/collector/pkg/component/receiver"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/spf13/viper"
+)
+
+type mockConfig struct {
+	Value int `mapstructure:"value"`
+}
+
+type reloadableProcessor struct {
+	mockComponent
+	cfg *mockConfig
+}
+
+func (p *reloadableProcessor) ApplyConfig(config interface{}) error {
+	p.cfg = config.(*mockConfig)
+	return nil
+}
+
+// reloadableAnalyzer rejects the negative values.
+type reloadableAnalyzer struct {
+	mockComponent
+	cfg *mockConfig
+}
+
+func (a *reloadableAnalyzer) ApplyConfig(config interface{}) error {
+	cfg := config.(*mockConfig)
+	if cfg.Value < 0 {
+		return component.NewNotReloadableError(a.name, "value")
+	}
+	a.cfg = cfg
+	return nil
+}
+
+type nopExporter struct {
+	nopLifecycle
+}
+
+func (e *nopExporter) Consume(dataGroup *model.DataGroup) error {
+	return nil
+}
+
+const reloadTestPipelines = `
+pipelines:
+  receiver: mockreceiver
+  chains:
+    - name: chain1
+      analyzers: [ mockanalyzer1 ]
+      processors: [ mockprocessor ]
+      exporters: [ mockexporter ]
+    - name: chain2
+      analyzers: [ mockanalyzer2 ]
+      processors: [ mockprocessor ]
+      exporters: [ mockexporter ]
+`
+
+func newReloadTestFactory() *ComponentsFactory {
+	factory := NewComponentsFactory()
+	factory.RegisterReceiver("mockreceiver", func(cfg interface{}, telemetry *component.TelemetryTools, analyzerManager *analyzer.Manager) receiver.Receiver {
+		return &mockComponent{name: "mockreceiver"}
+	}, &mockConfig{})
+	factory.RegisterAnalyzer("mockanalyzer1", func(cfg interface{}, telemetry *component.TelemetryTools, consumers []consumer.Consumer) analyzer.Analyzer {
+		return &reloadableAnalyzer{mockComponent: mockComponent{name: "mockanalyzer1", next: consumers}, cfg: cfg.(*mockConfig)}
+	}, &mockConfig{})
+	factory.RegisterAnalyzer("mockanalyzer2", func(cfg interface{}, telemetry *component.TelemetryTools, consumers []consumer.Consumer) analyzer.Analyzer {
+		return &mockComponent{name: "mockanalyzer2", next: consumers}
+	}, &mockConfig{})
+	factory.RegisterProcessor("mockprocessor", func(cfg interface{}, telemetry *component.TelemetryTools, consumer consumer.Consumer) processor.Processor {
+		return &reloadableProcessor{cfg: cfg.(*mockConfig)}
+	}, &mockConfig{})
+	factory.RegisterExporter("mockexporter", func(cfg interface{}, telemetry *component.TelemetryTools) exporter.Exporter {
+		return &nopExporter{}
+	}, &mockConfig{})
+	return factory
+}
+
+func newReloadTestApplication(t *testing.T, config string) *Application {
+	v := readTestConfig(t, config)
+	app := &Application{
+		viper:             v,
+		componentsFactory: newReloadTestFactory(),
+		telemetry:         component.NewTelemetryManager(),
+	}
+	if err := app.componentsFactory.ConstructConfig(v); err != nil {
+		t.Fatal(err)
+	}
+	pipelinesConfig, err := newPipelinesConfig(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.pipelinesConfig = pipelinesConfig
+	if err = app.buildPipeline(); err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+func readTestConfig(t *testing.T, config string) *viper.Viper {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(config)); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func getReloadableProcessors(app *Application) []*reloadableProcessor {
+	ret := make([]*reloadableProcessor, 0)
+	for _, c := range app.components {
+		if p, ok := c.instance.(*reloadableProcessor); ok {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
+
+func TestApplyConfig(t *testing.T) {
+	app := newReloadTestApplication(t, "processors:\n  mockprocessor:\n    value: 1\n"+reloadTestPipelines)
+	err := app.applyConfig(readTestConfig(t, "processors:\n  mockprocessor:\n    value: 2\n"+reloadTestPipelines), newReloadTestFactory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	processors := getReloadableProcessors(app)
+	if len(processors) != 2 {
+		t.Fatalf("Expected 2 processors, but get %d", len(processors))
+	}
+	for _, p := range processors {
+		if p.cfg.Value != 2 {
+			t.Errorf("Expected the new value 2, but get %d", p.cfg.Value)
+		}
+	}
+	if app.componentsFactory.Processors["mockprocessor"].Config.(*mockConfig).Value != 2 {
+		t.Errorf("The configuration in the factory is not updated")
+	}
+}
+
+func TestApplyConfigWithUnsupportedChanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{
+			name:   "not reloadable component",
+			config: "processors:\n  mockprocessor:\n    value: 2\nexporters:\n  mockexporter:\n    value: 2\n" + reloadTestPipelines,
+		},
+		{
+			name:   "pipelines",
+			config: "processors:\n  mockprocessor:\n    value: 2\n" + strings.Replace(reloadTestPipelines, "mockanalyzer2", "mockanalyzer1", 1),
+		},
+		{
+			name:   "observability",
+			config: "processors:\n  mockprocessor:\n    value: 2\nobservability:\n  logger:\n    console_level: debug\n" + reloadTestPipelines,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newReloadTestApplication(t, "processors:\n  mockprocessor:\n    value: 1\n"+reloadTestPipelines)
+			err := app.applyConfig(readTestConfig(t, tt.config), newReloadTestFactory())
+			if err == nil {
+				t.Fatalf("Expected an error, but get nil")
+			}
+			for _, p := range getReloadableProcessors(app) {
+				if p.cfg.Value != 1 {
+					t.Errorf("Nothing is expected to be applied, but get value %d", p.cfg.Value)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyConfigRollback(t *testing.T) {
+	app := newReloadTestApplication(t, "processors:\n  mockprocessor:\n    value: 1\n"+reloadTestPipelines)
+	// The processors apply the new value before the analyzer rejects its configuration.
+	newConfig := "processors:\n  mockprocessor:\n    value: 2\nanalyzers:\n  mockanalyzer1:\n    value: -1\n" + reloadTestPipelines
+	if err := app.applyConfig(readTestConfig(t, newConfig), newReloadTestFactory()); err == nil {
+		t.Fatalf("Expected an error, but get nil")
+	}
+	for _, p := range getReloadableProcessors(app) {
+		if p.cfg.Value != 1 {
+			t.Errorf("Expected the processor to be rolled back to 1, but get %d", p.cfg.Value)
+		}
+	}
+	if app.componentsFactory.Processors["mockprocessor"].Config.(*mockConfig).Value != 1 {
+		t.Errorf("The configuration in the factory should not be updated")
+	}
+}
@@ -0,0 +1,35 @@
+package application
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const componentHealthMetric = "kindling_telemetry_component_health"
+
+type healthKey struct {
+	kind   string
+	name   string
+	status string
+}
+
+// newSelfMetrics reports the number of the component instances in each status.
+func newSelfMetrics(meterProvider metric.MeterProvider, app *Application) {
+	meter := metric.Must(meterProvider.Meter("kindling"))
+	meter.NewInt64GaugeObserver(componentHealthMetric,
+		func(ctx context.Context, result metric.Int64ObserverResult) {
+			// The same processor could be created in several chains
+			counts := make(map[healthKey]int64)
+			for _, h := range app.Health() {
+				counts[healthKey{kind: h.Kind, name: h.Name, status: h.Status.String()}]++
+			}
+			for key, count := range counts {
+				result.Observe(count,
+					attribute.String("kind", key.kind),
+					attribute.String("name", key.name),
+					attribute.String("status", key.status))
+			}
+		})
+}
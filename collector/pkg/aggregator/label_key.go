@@ -50,11 +50,18 @@ func (s *LabelSelectors) AppendSelectors(selectors ...LabelSelector) {
 	s.selectors = append(s.selectors, selectors...)
 }
 
-const maxLabelKeySize = 35
+// Len returns the number of the selectors. Only the first ones fitting in LabelKeys are used.
+func (s *LabelSelectors) Len() int {
+	return len(s.selectors)
+}
+
+// maxLabelKeySize leaves room for the labels of the protocols and the ones configured by the users.
+// Each LabelKey takes 64 bytes, so a key of the aggregation takes 4KB.
+const maxLabelKeySize = 64
 
 type LabelKeys struct {
 	// LabelKeys will be used as key of map, so it is must be an array instead of a slice.
-	// Now 34 is enough for all cases. If there is more than 34 labels, must increase this value.
+	// If there are more than maxLabelKeySize labels, must increase this value.
 	keys [maxLabelKeySize]LabelKey
 }
 
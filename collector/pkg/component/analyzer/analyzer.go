@@ -1,6 +1,9 @@
 package analyzer
 
-import "github.com/Kindling-project/kindling/collector/pkg/model"
+import (
+	"github.com/Kindling-project/kindling/collector/pkg/component"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+)
 
 type Type string
 
@@ -9,14 +12,20 @@ func (t Type) String() string {
 }
 
 type Analyzer interface {
-	// Start initializes the analyzer
-	Start() error
+	// Start initializes the analyzer, and Shutdown cleans all the resources used by the analyzer
+	component.Component
 	// ConsumeEvent gets the event from the previous component
 	ConsumeEvent(event *model.KindlingEvent) error
-	// Shutdown cleans all the resources used by the analyzer
-	Shutdown() error
 	// Type returns the type of the analyzer
 	Type() Type
 	// ConsumableEvents returns the events' name that this analyzer can consume
 	ConsumableEvents() []string
 }
+
+// ShardableAnalyzer is implemented by the analyzers whose ConsumeEvent is safe to be called
+// from several goroutines, as long as the events of a socket are consumed by one goroutine in order.
+type ShardableAnalyzer interface {
+	Analyzer
+	// Shardable returns true if the analyzer could consume the events in parallel.
+	Shardable() bool
+}
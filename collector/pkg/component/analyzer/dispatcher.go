@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"sync"
+
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"go.uber.org/zap"
+)
+
+// shardQueueSize is the maximum number of the events waiting for a worker. The dispatching
+// goroutine is blocked when the queue is full, so the backpressure reaches the receiver.
+const shardQueueSize = 10000
+
+// route is the analyzers of an event name, split by whether they are shardable.
+type route struct {
+	serial  []Analyzer
+	sharded []Analyzer
+}
+
+type shardedEvent struct {
+	event     *model.KindlingEvent
+	analyzers []Analyzer
+}
+
+// Dispatcher sends the events to the analyzers. With more than one worker, the events consumed
+// by the shardable analyzers are distributed to the workers by their socket keys, so the events
+// of a socket are still consumed in order while different sockets are analyzed in parallel. The
+// other analyzers consume the events in the goroutine calling Dispatch, in the dispatched order.
+type Dispatcher struct {
+	manager *Manager
+	logger  *zap.Logger
+	shards  []chan shardedEvent
+	wg      sync.WaitGroup
+	// routes caches the routes by the event names. It is only accessed by the dispatching goroutine.
+	routes map[string]*route
+}
+
+// NewDispatcher starts the workers. Dispatch and Close must be called from the same goroutine.
+func NewDispatcher(manager *Manager, workers int, logger *zap.Logger) *Dispatcher {
+	d := &Dispatcher{
+		manager: manager,
+		logger:  logger,
+		routes:  make(map[string]*route),
+	}
+	if workers <= 1 {
+		return d
+	}
+	d.shards = make([]chan shardedEvent, workers)
+	for i := range d.shards {
+		d.shards[i] = make(chan shardedEvent, shardQueueSize)
+		d.wg.Add(1)
+		go d.work(d.shards[i])
+	}
+	return d
+}
+
+// Dispatch sends the event to the analyzers consuming it.
+func (d *Dispatcher) Dispatch(evt *model.KindlingEvent) {
+	if len(d.shards) == 0 {
+		d.consume(evt, d.manager.GetConsumableAnalyzers(evt.Name))
+		return
+	}
+	r := d.getRoute(evt.Name)
+	d.consume(evt, r.serial)
+	if len(r.sharded) > 0 {
+		d.shards[shardIndex(evt.GetSocketKey(), len(d.shards))] <- shardedEvent{event: evt, analyzers: r.sharded}
+	}
+}
+
+// Close waits until the workers consume all the events dispatched.
+func (d *Dispatcher) Close() {
+	for _, shard := range d.shards {
+		close(shard)
+	}
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) getRoute(name string) *route {
+	if r, ok := d.routes[name]; ok {
+		return r
+	}
+	r := &route{}
+	for _, analyzer := range d.manager.GetConsumableAnalyzers(name) {
+		if shardable, ok := analyzer.(ShardableAnalyzer); ok && shardable.Shardable() {
+			r.sharded = append(r.sharded, analyzer)
+		} else {
+			r.serial = append(r.serial, analyzer)
+		}
+	}
+	d.routes[name] = r
+	return r
+}
+
+func (d *Dispatcher) work(shard chan shardedEvent) {
+	defer d.wg.Done()
+	for e := range shard {
+		d.consume(e.event, e.analyzers)
+	}
+}
+
+func (d *Dispatcher) consume(evt *model.KindlingEvent, analyzers []Analyzer) {
+	for _, analyzer := range analyzers {
+		if err := analyzer.ConsumeEvent(evt); err != nil {
+			d.logger.Warn("Error sending event to next consumer: ", zap.Error(err))
+		}
+	}
+}
+
+// shardIndex mixes the bits of the socket key, because the pids and the fds are both small
+// numbers that don't spread well by themselves.
+func shardIndex(socketKey uint64, shards int) int {
+	return int((socketKey * 0x9e3779b97f4a7c15 >> 32) % uint64(shards))
+}
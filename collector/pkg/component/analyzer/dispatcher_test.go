@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordAnalyzer records the timestamps of the events consumed by their socket keys.
+type recordAnalyzer struct {
+	shardable  bool
+	mutex      sync.Mutex
+	timestamps map[uint64][]uint64
+	component.HealthReporter
+}
+
+func newRecordAnalyzer(shardable bool) *recordAnalyzer {
+	return &recordAnalyzer{shardable: shardable, timestamps: make(map[uint64][]uint64)}
+}
+
+func (a *recordAnalyzer) Start(ctx context.Context) error {
+	return nil
+}
+
+func (a *recordAnalyzer) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (a *recordAnalyzer) ConsumeEvent(event *model.KindlingEvent) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	key := event.GetSocketKey()
+	a.timestamps[key] = append(a.timestamps[key], event.Timestamp)
+	return nil
+}
+
+func (a *recordAnalyzer) Type() Type {
+	return "recordanalyzer"
+}
+
+func (a *recordAnalyzer) ConsumableEvents() []string {
+	return []string{"read"}
+}
+
+func (a *recordAnalyzer) Shardable() bool {
+	return a.shardable
+}
+
+func TestDispatcher(t *testing.T) {
+	const (
+		sockets = 100
+		rounds  = 50
+	)
+	for _, workers := range []int{1, 4} {
+		sharded, serial := newRecordAnalyzer(true), newRecordAnalyzer(false)
+		manager, err := NewManager(sharded, serial)
+		assert.NoError(t, err)
+		dispatcher := NewDispatcher(manager, workers, component.NewDefaultTelemetryTools().Logger)
+		for i := 0; i < rounds; i++ {
+			for fd := 0; fd < sockets; fd++ {
+				dispatcher.Dispatch(&model.KindlingEvent{
+					Name:      "read",
+					Timestamp: uint64(i),
+					Ctx: model.Context{
+						ThreadInfo: model.Thread{Pid: uint32(fd % 3)},
+						FdInfo:     model.Fd{Num: int32(fd)},
+					},
+				})
+			}
+		}
+		dispatcher.Close()
+		for _, a := range []*recordAnalyzer{sharded, serial} {
+			assert.Equal(t, sockets, len(a.timestamps))
+			for key, timestamps := range a.timestamps {
+				assert.Equal(t, rounds, len(timestamps), "socket %x", key)
+				for i, ts := range timestamps {
+					if ts != uint64(i) {
+						t.Fatalf("The events of socket %x are consumed out of order with %d workers: %v", key, workers, timestamps)
+					}
+				}
+			}
+		}
+	}
+}
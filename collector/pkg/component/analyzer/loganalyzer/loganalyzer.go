@@ -1,6 +1,8 @@
 package loganalyzer
 
 import (
+	"context"
+
 	"github.com/Kindling-project/kindling/collector/pkg/component"
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer"
 	"github.com/Kindling-project/kindling/collector/pkg/component/consumer"
@@ -15,6 +17,7 @@ type LogAnalyzer struct {
 	cfg           *Config
 	nextConsumers []consumer.Consumer
 	telemetry     *component.TelemetryTools
+	component.HealthReporter
 }
 
 func New(cfg interface{}, telemetry *component.TelemetryTools, consumer []consumer.Consumer) analyzer.Analyzer {
@@ -29,7 +32,8 @@ func New(cfg interface{}, telemetry *component.TelemetryTools, consumer []consum
 	}
 }
 
-func (a *LogAnalyzer) Start() error {
+func (a *LogAnalyzer) Start(ctx context.Context) error {
+	a.SetHealth(component.StatusRunning, nil)
 	return nil
 }
 
@@ -45,7 +49,8 @@ func (a *LogAnalyzer) ConsumeEvent(event *model.KindlingEvent) error {
 	return nil
 }
 
-func (a *LogAnalyzer) Shutdown() error {
+func (a *LogAnalyzer) Shutdown(ctx context.Context) error {
+	a.SetHealth(component.StatusStopped, nil)
 	return nil
 }
 
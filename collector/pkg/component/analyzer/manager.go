@@ -1,6 +1,7 @@
 package analyzer
 
 import (
+	"context"
 	"errors"
 
 	"github.com/hashicorp/go-multierror"
@@ -54,10 +55,10 @@ func NewManager(analyzers ...Analyzer) (*Manager, error) {
 	}, nil
 }
 
-func (m *Manager) StartAll(logger *zap.Logger) error {
+func (m *Manager) StartAll(ctx context.Context, logger *zap.Logger) error {
 	for _, analyzer := range m.allAnalyzers {
 		logger.Sugar().Infof("Starting analyzer [%s]", analyzer.Type())
-		err := analyzer.Start()
+		err := analyzer.Start(ctx)
 		if err != nil {
 			return err
 		}
@@ -65,11 +66,11 @@ func (m *Manager) StartAll(logger *zap.Logger) error {
 	return nil
 }
 
-func (m *Manager) ShutdownAll(logger *zap.Logger) error {
+func (m *Manager) ShutdownAll(ctx context.Context, logger *zap.Logger) error {
 	var retErr error = nil
 	for _, analyzer := range m.allAnalyzers {
 		logger.Sugar().Infof("Shutdown analyzer [%s]", analyzer.Type())
-		err := analyzer.Shutdown()
+		err := analyzer.Shutdown(ctx)
 		if err != nil {
 			retErr = multierror.Append(retErr, err)
 		}
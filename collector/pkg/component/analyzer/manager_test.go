@@ -1,8 +1,10 @@
 package analyzer
 
 import (
+	"context"
 	"testing"
 
+	"github.com/Kindling-project/kindling/collector/pkg/component"
 	"github.com/Kindling-project/kindling/collector/pkg/model"
 	"github.com/stretchr/testify/assert"
 )
@@ -43,7 +45,7 @@ func (t *testAnalyzer) ConsumableEvents() []string {
 }
 
 // Start initializes the analyzer
-func (t *testAnalyzer) Start() error {
+func (t *testAnalyzer) Start(ctx context.Context) error {
 	return nil
 }
 
@@ -53,10 +55,15 @@ func (t *testAnalyzer) ConsumeEvent(event *model.KindlingEvent) error {
 }
 
 // Shutdown cleans all the resources used by the analyzer
-func (t *testAnalyzer) Shutdown() error {
+func (t *testAnalyzer) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// Health returns the current status of the analyzer
+func (t *testAnalyzer) Health() component.Health {
+	return component.Health{Status: component.StatusRunning}
+}
+
 // Type returns the type of the analyzer
 func (t *testAnalyzer) Type() Type {
 	return "testanalyzer"
@@ -71,7 +78,7 @@ func (t *testConsumeAllAnalyzer) ConsumableEvents() []string {
 }
 
 // Start initializes the analyzer
-func (t *testConsumeAllAnalyzer) Start() error {
+func (t *testConsumeAllAnalyzer) Start(ctx context.Context) error {
 	return nil
 }
 
@@ -81,10 +88,15 @@ func (t *testConsumeAllAnalyzer) ConsumeEvent(event *model.KindlingEvent) error
 }
 
 // Shutdown cleans all the resources used by the analyzer
-func (t *testConsumeAllAnalyzer) Shutdown() error {
+func (t *testConsumeAllAnalyzer) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// Health returns the current status of the analyzer
+func (t *testConsumeAllAnalyzer) Health() component.Health {
+	return component.Health{Status: component.StatusRunning}
+}
+
 // Type returns the type of the analyzer
 func (t *testConsumeAllAnalyzer) Type() Type {
 	return "testconsumeallanalyzer"
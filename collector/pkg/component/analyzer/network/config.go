@@ -1,5 +1,15 @@
 package network
 
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/http"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constvalues"
+)
+
 const (
 	defaultRequestTimeout        = 1
 	defaultConnectTimeout        = 1
@@ -41,16 +51,59 @@ func NewDefaultConfig() *Config {
 				Key:           "dubbo",
 				PayloadLength: 200,
 			},
+			{
+				Key:       "thrift",
+				Threshold: 500,
+			},
+			{
+				Key:       "fastcgi",
+				Threshold: 500,
+			},
 			{
 				Key:       "mysql",
 				Ports:     []uint32{3306},
 				Threshold: 100,
 			},
+			{
+				Key:       "postgresql",
+				Ports:     []uint32{5432},
+				Threshold: 100,
+			},
+			{
+				Key:       "mongodb",
+				Ports:     []uint32{27017},
+				Threshold: 100,
+			},
 			{
 				Key:       "kafka",
 				Ports:     []uint32{9092},
 				Threshold: 100,
 			},
+			{
+				Key:       "rocketmq",
+				Ports:     []uint32{9876, 10911},
+				Threshold: 100,
+			},
+			{
+				Key:       "amqp",
+				Ports:     []uint32{5672},
+				Threshold: 100,
+			},
+			{
+				Key:       "mqtt",
+				Ports:     []uint32{1883},
+				Threshold: 100,
+			},
+			{
+				Key:       "memcached",
+				Ports:     []uint32{11211},
+				Threshold: 100,
+			},
+			{
+				Key:       "zookeeper",
+				Ports:     []uint32{2181},
+				Threshold: 100,
+			},
 			{
 				Key:       "dns",
 				Ports:     []uint32{53},
@@ -77,6 +130,82 @@ type ProtocolConfig struct {
 	PayloadLength  int      `mapstructure:"payload_length"`
 	DisableDiscern bool     `mapstructure:"disable_discern,omitempty"`
 	Threshold      int      `mapstructure:"slow_threshold,omitempty"`
+	// Labels are extracted from the messages, which are only supported by HTTP now.
+	Labels []LabelConfig `mapstructure:"labels,omitempty"`
+}
+
+// LabelConfig extracts the value of a header or a field of the JSON body as the label Name.
+// The label is attached to the records, and it is aggregated and exported only if it is
+// listed in the extracted labels of aggregateprocessor and the exporters.
+type LabelConfig struct {
+	Name     string `mapstructure:"name"`
+	Header   string `mapstructure:"header,omitempty"`
+	JsonPath string `mapstructure:"json_path,omitempty"`
+	Response bool   `mapstructure:"response,omitempty"`
+}
+
+var labelNameRegexp = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+
+// metricNames are the metrics of the requests, which are exported along with the labels.
+var metricNames = map[string]bool{
+	constvalues.RequestCount:         true,
+	constvalues.RequestTotalTime:     true,
+	constvalues.ConnectTime:          true,
+	constvalues.RequestSentTime:      true,
+	constvalues.WaitingTtfbTime:      true,
+	constvalues.ContentDownloadTime:  true,
+	constvalues.RequestTimeHistogram: true,
+	constvalues.RequestIo:            true,
+	constvalues.ResponseIo:           true,
+}
+
+func (cfg *Config) validateLabels() error {
+	names := make(map[string]bool)
+	for _, config := range cfg.ProtocolConfigs {
+		if len(config.Labels) > 0 && config.Key != protocol.HTTP {
+			return fmt.Errorf("labels are not supported by protocol %s", config.Key)
+		}
+		for _, label := range config.Labels {
+			if !labelNameRegexp.MatchString(label.Name) {
+				return fmt.Errorf("invalid label name: %q", label.Name)
+			}
+			if constlabels.IsReserved(label.Name) || metricNames[label.Name] {
+				return fmt.Errorf("label name %s is taken by the built-in one", label.Name)
+			}
+			if names[label.Name] {
+				return fmt.Errorf("duplicated label name: %s", label.Name)
+			}
+			names[label.Name] = true
+			if (len(label.Header) > 0) == (len(label.JsonPath) > 0) {
+				return fmt.Errorf("either header or json_path must be set for label %s", label.Name)
+			}
+			if len(label.JsonPath) > 0 {
+				if _, err := http.ParseJsonPath(label.JsonPath); err != nil {
+					return fmt.Errorf("invalid json_path of label %s: %w", label.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// getHttpLabels returns the labels extracted from HTTP.
+func (cfg *Config) getHttpLabels() []http.Label {
+	var labels []http.Label
+	for _, config := range cfg.ProtocolConfigs {
+		if config.Key != protocol.HTTP {
+			continue
+		}
+		for _, label := range config.Labels {
+			labels = append(labels, http.Label{
+				Name:     label.Name,
+				Header:   label.Header,
+				JsonPath: label.JsonPath,
+				Response: label.Response,
+			})
+		}
+	}
+	return labels
 }
 
 func (cfg *Config) GetConnectTimeout() int {
@@ -4,6 +4,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
 	"github.com/Kindling-project/kindling/collector/pkg/metadata/conntracker"
 	"github.com/Kindling-project/kindling/collector/pkg/model"
 )
@@ -11,6 +12,9 @@ import (
 const (
 	LOWER32 = 0x00000000FFFFFFFF
 	LOWER16 = 0x000000000000FFFF
+
+	// maxPendingRequests limits the requests of a multiplexed protocol waiting for responses.
+	maxPendingRequests = 100
 )
 
 type mergableEvent struct {
@@ -20,6 +24,8 @@ type mergableEvent struct {
 	resVal  int64
 	ts      uint64
 	data    []byte
+	// dropped is true if some events are not kept.
+	dropped bool
 }
 
 type events struct {
@@ -65,6 +71,8 @@ func (evts *events) mergeEvent(evt *model.KindlingEvent) {
 	if len(evts.mergable.events) < 10 {
 		// persistent connect
 		evts.mergable.events = append(evts.mergable.events, evt)
+	} else {
+		evts.mergable.dropped = true
 	}
 	evts.mergable.latency = evt.Timestamp - evts.mergable.ts + evts.mergable.latency
 	evts.mergable.resVal += evt.GetResVal()
@@ -89,6 +97,10 @@ func (evts *events) getData() []byte {
 	return evts.mergable.data
 }
 
+func (evts *events) hasDroppedEvents() bool {
+	return evts != nil && evts.mergable != nil && evts.mergable.dropped
+}
+
 func (evts *events) getFirstTimestamp() uint64 {
 	return evts.event.Timestamp
 }
@@ -131,10 +143,36 @@ type messagePairs struct {
 	requests  *events
 	responses *events
 	natTuple  *conntracker.IPTranslation
+	// states are the states of the connection used by the protocol parsers.
+	states *protocol.ConnectionStates
+	// pendingRequests are the requests of a multiplexed protocol moved from the previous pairs of
+	// the connection, which are still waiting for their responses.
+	pendingRequests []*pendingRequest
+	// next is the following pairs of the same connection, which the pending requests are moved to.
+	next *messagePairs
 
 	mutex sync.RWMutex // only for update latency and resval now
 }
 
+type pendingRequest struct {
+	event   *model.KindlingEvent
+	message *protocol.PayloadMessage
+	// last and size are set for the requests of a pipelined protocol, see pipelinedMessage.
+	last *model.KindlingEvent
+	size uint64
+}
+
+// pipelinedMessage is a request or a response of a pipelined protocol like HTTP/1.1. It may share
+// an event with other messages, or be sent by several events like a large body.
+type pipelinedMessage struct {
+	first *model.KindlingEvent
+	// last is the last event of the message, which is nil if the message is sent by one event.
+	last *model.KindlingEvent
+	// size is the bytes of the message in its events.
+	size    uint64
+	message *protocol.PayloadMessage
+}
+
 func (mps *messagePairs) getKey() messagePairKey {
 	if mps.connects != nil {
 		return getMessagePairKey(mps.connects.event)
@@ -176,6 +214,31 @@ func (mps *messagePairs) mergeResponse(evt *model.KindlingEvent) {
 	mps.mutex.Unlock()
 }
 
+// prepareMessage attaches the states of the connection to the message and sets its size.
+func (mps *messagePairs) prepareMessage(message *protocol.PayloadMessage, size int64) {
+	message.Size = int(size)
+	message.SetConnectionStates(mps.states)
+}
+
+// carryRequest moves the request still waiting for its response to the next pairs of the
+// connection. False is returned if there are no next pairs or the request has waited too long.
+func (mps *messagePairs) carryRequest(request *pendingRequest, timeout int) bool {
+	next := mps.next
+	if next == nil || next.requests == nil || len(next.pendingRequests) >= maxPendingRequests {
+		return false
+	}
+	nextTimestamp := next.requests.event.Timestamp
+	if nextTimestamp > request.event.Timestamp && nextTimestamp-request.event.Timestamp > uint64(timeout)*uint64(time.Second) {
+		return false
+	}
+	next.pendingRequests = append(next.pendingRequests, request)
+	return true
+}
+
+func (mps *messagePairs) hasDroppedEvents() bool {
+	return mps.requests.hasDroppedEvents() || mps.responses.hasDroppedEvents()
+}
+
 func (mps *messagePairs) getPort() uint32 {
 	if mps.requests != nil {
 		return mps.requests.event.GetDport()
@@ -264,6 +327,28 @@ func (mps *messagePairs) getResponseSize() uint64 {
 type messagePair struct {
 	request  *model.KindlingEvent
 	response *model.KindlingEvent
+	// requestEnd and responseEnd are the last events of the messages sent by several events,
+	// which are nil if the messages are sent by one event.
+	requestEnd  *model.KindlingEvent
+	responseEnd *model.KindlingEvent
+	// requestSize and responseSize are the sizes of the messages sharing the events with others.
+	// Zero means the sizes of the events are used.
+	requestSize  uint64
+	responseSize uint64
+}
+
+func (mp *messagePair) getRequestEnd() *model.KindlingEvent {
+	if mp.requestEnd != nil {
+		return mp.requestEnd
+	}
+	return mp.request
+}
+
+func (mp *messagePair) getResponseEnd() *model.KindlingEvent {
+	if mp.responseEnd != nil {
+		return mp.responseEnd
+	}
+	return mp.response
 }
 
 func (mp *messagePair) getSentTime() int64 {
@@ -271,7 +356,7 @@ func (mp *messagePair) getSentTime() int64 {
 		return -1
 	}
 
-	return int64(mp.request.GetLatency())
+	return int64(mp.getRequestEnd().Timestamp - mp.request.GetStartTime())
 }
 
 func (mp *messagePair) getWaitingTime() int64 {
@@ -279,7 +364,7 @@ func (mp *messagePair) getWaitingTime() int64 {
 		return -1
 	}
 
-	return int64(mp.response.Timestamp - mp.response.GetLatency() - mp.request.Timestamp)
+	return int64(mp.response.Timestamp - mp.response.GetLatency() - mp.getRequestEnd().Timestamp)
 }
 
 func (mp *messagePair) getDownloadTime() int64 {
@@ -287,13 +372,16 @@ func (mp *messagePair) getDownloadTime() int64 {
 		return -1
 	}
 
-	return int64(mp.response.GetLatency())
+	return int64(mp.getResponseEnd().Timestamp - mp.response.GetStartTime())
 }
 
 func (mp *messagePair) getRquestSize() uint64 {
 	if mp.request == nil {
 		return 0
 	}
+	if mp.requestSize > 0 {
+		return mp.requestSize
+	}
 	return uint64(mp.request.GetResVal())
 }
 
@@ -301,6 +389,9 @@ func (mp *messagePair) getResponseSize() uint64 {
 	if mp.response == nil {
 		return 0
 	}
+	if mp.responseSize > 0 {
+		return mp.responseSize
+	}
 	return uint64(mp.response.GetResVal())
 }
 
@@ -309,7 +400,7 @@ func (mp *messagePair) getDuration() uint64 {
 		return 0
 	}
 
-	return mp.response.Timestamp + mp.request.GetLatency() - mp.request.Timestamp
+	return mp.getResponseEnd().Timestamp + mp.request.GetLatency() - mp.request.Timestamp
 }
 
 // DNS will send different ip and port data with sharing fd and pid socket.
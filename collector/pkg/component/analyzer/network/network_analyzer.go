@@ -2,6 +2,7 @@ package network
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"sync"
 	"sync/atomic"
@@ -28,6 +29,11 @@ const (
 	CACHE_ADD_THRESHOLD   = 50
 	CACHE_RESET_THRESHOLD = 5000
 
+	// socketLockCount is the number of the locks the message pairs are striped over.
+	socketLockCount = 256
+	// connectionStatesTimeout is how long the states of an idle connection are kept.
+	connectionStatesTimeout = 10 * time.Minute
+
 	Network analyzer.Type = "networkanalyzer"
 )
 
@@ -36,17 +42,29 @@ type NetworkAnalyzer struct {
 	nextConsumers []consumer.Consumer
 	conntracker   conntracker2.Conntracker
 
+	// mutex protects the configuration and the states built from it, which could be
+	// replaced when a new configuration is applied at runtime.
+	mutex            sync.RWMutex
 	staticPortMap    map[uint32]string
 	slowThresholdMap map[string]int
 	protocolMap      map[string]*protocol.ProtocolParser
 	parserFactory    *factory.ParserFactory
 	parsers          []*protocol.ProtocolParser
 
-	dataGroupPool      *DataGroupPool
-	requestMonitor     sync.Map
+	dataGroupPool  *DataGroupPool
+	requestMonitor sync.Map
+	// socketLocks serialize the updates of the message pairs of a socket. The events of a socket
+	// are consumed by one goroutine, but the pairs timing out are distributed by another one.
+	socketLocks [socketLockCount]sync.Mutex
+	// connectionStates keeps the states of the connections used by the protocol parsers.
+	connectionStates   sync.Map
 	tcpMessagePairSize int64
 	udpMessagePairSize int64
 	telemetry          *component.TelemetryTools
+
+	stopCh     chan struct{}
+	shutdownWG sync.WaitGroup
+	component.HealthReporter
 }
 
 func NewNetworkAnalyzer(cfg interface{}, telemetry *component.TelemetryTools, consumers []consumer.Consumer) analyzer.Analyzer {
@@ -56,6 +74,7 @@ func NewNetworkAnalyzer(cfg interface{}, telemetry *component.TelemetryTools, co
 		dataGroupPool: NewDataGroupPool(),
 		nextConsumers: consumers,
 		telemetry:     telemetry,
+		stopCh:        make(chan struct{}),
 	}
 	if config.EnableConntrack {
 		connConfig := &conntracker2.Config{
@@ -68,8 +87,6 @@ func NewNetworkAnalyzer(cfg interface{}, telemetry *component.TelemetryTools, co
 		}
 		na.conntracker, _ = conntracker2.NewConntracker(connConfig)
 	}
-
-	na.parserFactory = factory.NewParserFactory(factory.WithUrlClusteringMethod(na.cfg.UrlClusteringMethod))
 	return na
 }
 
@@ -86,14 +103,34 @@ func (na *NetworkAnalyzer) ConsumableEvents() []string {
 	}
 }
 
-func (na *NetworkAnalyzer) Start() error {
+func (na *NetworkAnalyzer) Start(ctx context.Context) error {
+	if err := na.cfg.validateLabels(); err != nil {
+		return fmt.Errorf("invalid [%s] config: %w", Network, err)
+	}
 	// TODO When import multi annalyzers, this part should move to factory. The metric will relate with analyzers.
 	newSelfMetrics(na.telemetry.MeterProvider, na)
 
+	na.shutdownWG.Add(1)
 	go na.consumerFdNoReusingTrace()
 
+	na.mutex.Lock()
+	na.initProtocolParsers(na.cfg)
+	na.mutex.Unlock()
+
+	rand.Seed(time.Now().UnixNano())
+	na.SetHealth(component.StatusRunning, nil)
+	return nil
+}
+
+// initProtocolParsers builds the parsers and the protocol-related states from the configuration.
+// The caller must hold the write lock.
+func (na *NetworkAnalyzer) initProtocolParsers(cfg *Config) {
+	na.cfg = cfg
+	na.parserFactory = factory.NewParserFactory(factory.WithUrlClusteringMethod(cfg.UrlClusteringMethod),
+		factory.WithHttpLabels(cfg.getHttpLabels()))
+
 	na.staticPortMap = map[uint32]string{}
-	for _, config := range na.cfg.ProtocolConfigs {
+	for _, config := range cfg.ProtocolConfigs {
 		for _, port := range config.Ports {
 			na.staticPortMap[port] = config.Key
 		}
@@ -101,7 +138,7 @@ func (na *NetworkAnalyzer) Start() error {
 
 	na.slowThresholdMap = map[string]int{}
 	disableDisernProtocols := map[string]bool{}
-	for _, config := range na.cfg.ProtocolConfigs {
+	for _, config := range cfg.ProtocolConfigs {
 		protocol.SetPayLoadLength(config.Key, config.PayloadLength)
 		na.slowThresholdMap[config.Key] = config.Threshold
 		disableDisernProtocols[config.Key] = config.DisableDiscern
@@ -109,7 +146,7 @@ func (na *NetworkAnalyzer) Start() error {
 
 	na.protocolMap = map[string]*protocol.ProtocolParser{}
 	parsers := make([]*protocol.ProtocolParser, 0)
-	for _, protocol := range na.cfg.ProtocolParser {
+	for _, protocol := range cfg.ProtocolParser {
 		protocolparser := na.parserFactory.GetParser(protocol)
 		if protocolparser != nil {
 			na.protocolMap[protocol] = protocolparser
@@ -122,24 +159,100 @@ func (na *NetworkAnalyzer) Start() error {
 	// Add Generic Last
 	parsers = append(parsers, na.parserFactory.GetGenericParser())
 	na.parsers = parsers
+}
 
-	rand.Seed(time.Now().UnixNano())
+// ApplyConfig applies a new configuration at runtime. The pending message pairs are kept,
+// and they will be parsed with the new parsers. The conntrack options can't be changed
+// because the conntracker is shared with other analyzers.
+func (na *NetworkAnalyzer) ApplyConfig(config interface{}) error {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return fmt.Errorf("cannot convert [%s] config", Network)
+	}
+	if err := cfg.validateLabels(); err != nil {
+		return fmt.Errorf("invalid [%s] config: %w", Network, err)
+	}
+	na.mutex.Lock()
+	defer na.mutex.Unlock()
+	switch {
+	case cfg.EnableConntrack != na.cfg.EnableConntrack:
+		return component.NewNotReloadableError(Network.String(), "enable_conntrack")
+	case cfg.ConntrackMaxStateSize != na.cfg.ConntrackMaxStateSize:
+		return component.NewNotReloadableError(Network.String(), "conntrack_max_state_size")
+	case cfg.ConntrackRateLimit != na.cfg.ConntrackRateLimit:
+		return component.NewNotReloadableError(Network.String(), "conntrack_rate_limit")
+	case cfg.ProcRoot != na.cfg.ProcRoot:
+		return component.NewNotReloadableError(Network.String(), "proc_root")
+	}
+	na.initProtocolParsers(cfg)
+	na.telemetry.Logger.Sugar().Infof("[%s] applied the new configuration: %+v", Network, cfg)
 	return nil
 }
 
-func (na *NetworkAnalyzer) Shutdown() error {
-	// TODO: implement
-	return nil
+// Shutdown stops checking the timeout message pairs and flushes all the pending ones.
+// The pairs with requests are sent to the next consumers, so those still waiting for
+// responses become no-response records. The pairs with only connect events are dropped
+// because their connections haven't failed yet.
+// The receiver must have stopped sending events before Shutdown is called.
+func (na *NetworkAnalyzer) Shutdown(ctx context.Context) error {
+	if na.Health().Status != component.StatusRunning {
+		return nil
+	}
+	close(na.stopCh)
+	na.shutdownWG.Wait()
+	defer na.SetHealth(component.StatusStopped, nil)
+
+	na.mutex.RLock()
+	defer na.mutex.RUnlock()
+	var flushed, dropped int
+	na.requestMonitor.Range(func(k, v interface{}) bool {
+		if ctx.Err() != nil {
+			return false
+		}
+		lock := na.lockSocket(k.(messagePairKey))
+		defer lock.Unlock()
+		mps := v.(*messagePairs)
+		mps.mutex.RLock()
+		connects, requests := mps.connects, mps.requests
+		mps.mutex.RUnlock()
+		if requests != nil {
+			na.distributeTraceMetric(mps, nil)
+			flushed++
+		} else if connects != nil {
+			na.recordMessagePairSize(connects.event, -1)
+			na.requestMonitor.Delete(k)
+			dropped++
+		}
+		return true
+	})
+	na.telemetry.Logger.Sugar().Infof("[%s] flushed %d pending message pairs and dropped %d ones without requests", Network, flushed, dropped)
+	return ctx.Err()
 }
 
 func (na *NetworkAnalyzer) Type() analyzer.Type {
 	return Network
 }
 
+// Shardable returns true because the message pairs of different sockets are independent, and
+// the states shared by the sockets are safe for concurrent use.
+func (na *NetworkAnalyzer) Shardable() bool {
+	return true
+}
+
+// lockSocket locks the message pairs with the key and returns the lock.
+func (na *NetworkAnalyzer) lockSocket(key messagePairKey) *sync.Mutex {
+	hash := (uint64(key.pid)<<32 | uint64(uint32(key.fd))) * 0x9e3779b97f4a7c15
+	lock := &na.socketLocks[hash>>56%socketLockCount]
+	lock.Lock()
+	return lock
+}
+
 func (na *NetworkAnalyzer) ConsumeEvent(evt *model.KindlingEvent) error {
 	if evt.Category != model.Category_CAT_NET {
 		return nil
 	}
+	na.mutex.RLock()
+	defer na.mutex.RUnlock()
 
 	ctx := evt.GetCtx()
 	if ctx == nil || ctx.GetThreadInfo() == nil {
@@ -163,6 +276,7 @@ func (na *NetworkAnalyzer) ConsumeEvent(evt *model.KindlingEvent) error {
 
 	if evt.IsConnect() {
 		// connect event
+		defer na.lockSocket(getMessagePairKey(evt)).Unlock()
 		return na.analyseConnect(evt)
 	}
 
@@ -175,6 +289,7 @@ func (na *NetworkAnalyzer) ConsumeEvent(evt *model.KindlingEvent) error {
 	if err != nil {
 		return err
 	}
+	defer na.lockSocket(getMessagePairKey(evt)).Unlock()
 	if isRequest {
 		return na.analyseRequest(evt)
 	} else {
@@ -183,11 +298,22 @@ func (na *NetworkAnalyzer) ConsumeEvent(evt *model.KindlingEvent) error {
 }
 
 func (na *NetworkAnalyzer) consumerFdNoReusingTrace() {
+	defer na.shutdownWG.Done()
 	timer := time.NewTicker(1 * time.Second)
+	defer timer.Stop()
 	for {
 		select {
+		case <-na.stopCh:
+			return
 		case <-timer.C:
+			na.mutex.RLock()
 			na.requestMonitor.Range(func(k, v interface{}) bool {
+				lock := na.lockSocket(k.(messagePairKey))
+				defer lock.Unlock()
+				// The pairs may have been replaced since they were ranged.
+				if current, ok := na.requestMonitor.Load(k); !ok || current != v {
+					return true
+				}
 				mps := v.(*messagePairs)
 				var timeoutTs = mps.getTimeoutTs()
 				if timeoutTs != 0 && (time.Now().UnixNano()/1000000000-int64(timeoutTs)/1000000000) >= 15 {
@@ -195,8 +321,63 @@ func (na *NetworkAnalyzer) consumerFdNoReusingTrace() {
 				}
 				return true
 			})
+			na.mutex.RUnlock()
+			na.cleanConnectionStates()
+		}
+	}
+}
+
+// connectionStatesEntry keeps the states of a connection across its message pairs.
+type connectionStatesEntry struct {
+	states *protocol.ConnectionStates
+	sport  uint32
+	dport  uint32
+	// lastUsed is the unix time in nanoseconds, which is accessed atomically.
+	lastUsed int64
+}
+
+// loadConnectionStates returns the states of the connection the event belongs to. If the states
+// are not kept yet, a new one is returned, and it is kept by storeConnectionStates once used.
+func (na *NetworkAnalyzer) loadConnectionStates(evt *model.KindlingEvent) *protocol.ConnectionStates {
+	key := getMessagePairKey(evt)
+	if value, ok := na.connectionStates.Load(key); ok {
+		entry := value.(*connectionStatesEntry)
+		if entry.sport == evt.GetSport() && entry.dport == evt.GetDport() {
+			atomic.StoreInt64(&entry.lastUsed, time.Now().UnixNano())
+			return entry.states
 		}
+		// The socket is used by another connection now.
+		na.connectionStates.Delete(key)
 	}
+	return protocol.NewConnectionStates()
+}
+
+func (na *NetworkAnalyzer) storeConnectionStates(evt *model.KindlingEvent, states *protocol.ConnectionStates) {
+	if states.IsEmpty() {
+		return
+	}
+	key := getMessagePairKey(evt)
+	if value, ok := na.connectionStates.Load(key); ok && value.(*connectionStatesEntry).states == states {
+		return
+	}
+	na.connectionStates.Store(key, &connectionStatesEntry{
+		states:   states,
+		sport:    evt.GetSport(),
+		dport:    evt.GetDport(),
+		lastUsed: time.Now().UnixNano(),
+	})
+}
+
+// cleanConnectionStates removes the states of the connections idle for a long time, which are
+// likely to be closed.
+func (na *NetworkAnalyzer) cleanConnectionStates() {
+	expired := time.Now().Add(-connectionStatesTimeout).UnixNano()
+	na.connectionStates.Range(func(k, v interface{}) bool {
+		if atomic.LoadInt64(&v.(*connectionStatesEntry).lastUsed) < expired {
+			na.connectionStates.Delete(k)
+		}
+		return true
+	})
 }
 
 func (na *NetworkAnalyzer) analyseConnect(evt *model.KindlingEvent) error {
@@ -206,6 +387,8 @@ func (na *NetworkAnalyzer) analyseConnect(evt *model.KindlingEvent) error {
 		responses: nil,
 		mutex:     sync.RWMutex{},
 	}
+	// The connection is new, so its states are not needed any more.
+	na.connectionStates.Delete(mps.getKey())
 	if pairInterface, exist := na.requestMonitor.LoadOrStore(mps.getKey(), mps); exist {
 		// There is an old message pair
 		var oldPairs = pairInterface.(*messagePairs)
@@ -295,6 +478,11 @@ func (na *NetworkAnalyzer) distributeTraceMetric(oldPairs *messagePairs, newPair
 
 	if newPairs != nil {
 		na.requestMonitor.Store(newPairs.getKey(), newPairs)
+		if newPairs.requests != nil && oldPairs.requests != nil &&
+			!oldPairs.requests.IsTimeout(newPairs.requests.event, na.cfg.GetRequestTimeout()) {
+			// The requests still waiting for responses could be moved to the following pairs.
+			oldPairs.next = newPairs
+		}
 	} else {
 		na.recordMessagePairSize(queryEvt, -1)
 		na.requestMonitor.Delete(oldPairs.getKey())
@@ -317,7 +505,12 @@ func (na *NetworkAnalyzer) distributeTraceMetric(oldPairs *messagePairs, newPair
 	// Case 1 ConnectFail    Connect
 	// Case 2 Request 498   Connect/Request                         Request
 	// Case 3 Normal             Connect/Request/Response   Request/Response
+	oldPairs.states = na.loadConnectionStates(queryEvt)
 	records := na.parseProtocols(oldPairs)
+	if oldPairs.hasDroppedEvents() {
+		oldPairs.states.AddMissed()
+	}
+	na.storeConnectionStates(queryEvt, oldPairs.states)
 	for _, record := range records {
 		if ce := na.telemetry.Logger.Check(zapcore.DebugLevel, "NetworkAnalyzer To NextProcess: "); ce != nil {
 			ce.Write(
@@ -344,8 +537,8 @@ func (na *NetworkAnalyzer) parseProtocols(mps *messagePairs) []*model.DataGroup
 		}
 
 		if parser, exist := na.protocolMap[staticProtocol]; exist {
-			records := na.parseProtocol(mps, parser)
-			if records != nil {
+			records, ignored := na.parseProtocol(mps, parser)
+			if records != nil || ignored {
 				return records
 			}
 		}
@@ -361,11 +554,14 @@ func (na *NetworkAnalyzer) parseProtocols(mps *messagePairs) []*model.DataGroup
 	}
 
 	// Step2 Cache protocol and port
-	// TODO There is concurrent modify case when looping. Considering threadsafe.
+	// The cached slices are never modified in place, so they are safe to loop over.
 	cacheParsers, ok := na.parserFactory.GetCachedParsersByPort(port)
 	if ok {
 		for _, parser := range cacheParsers {
-			records := na.parseProtocol(mps, parser)
+			records, ignored := na.parseProtocol(mps, parser)
+			if ignored {
+				return nil
+			}
 			if records != nil {
 				if protocol.NOSUPPORT == parser.GetProtocol() {
 					// Reset mapping for  generic and port when exceed threshold so as to parsed by other protcols.
@@ -381,10 +577,13 @@ func (na *NetworkAnalyzer) parseProtocols(mps *messagePairs) []*model.DataGroup
 
 	// Step3 Loop all protocols
 	for _, parser := range na.parsers {
-		records := na.parseProtocol(mps, parser)
+		// The pairs with only the ignored messages, like the pings, are left to the other
+		// protocols, because a few bytes of them could look like many protocols.
+		records, _ := na.parseProtocol(mps, parser)
 		if records != nil {
-			// Add mapping for port and protocol when exceed threshold
-			if parser.AddPortCount(port) == CACHE_ADD_THRESHOLD {
+			// Add mapping for port and protocol when exceed threshold. The requests waiting
+			// for their responses are not counted until they are reported.
+			if len(records) > 0 && parser.AddPortCount(port) == CACHE_ADD_THRESHOLD {
 				na.parserFactory.AddCachedParser(port, parser)
 			}
 			return records
@@ -393,7 +592,12 @@ func (na *NetworkAnalyzer) parseProtocols(mps *messagePairs) []*model.DataGroup
 	return na.getRecords(mps, protocol.NOSUPPORT, nil)
 }
 
-func (na *NetworkAnalyzer) parseProtocol(mps *messagePairs, parser *protocol.ProtocolParser) []*model.DataGroup {
+// parseProtocol returns nil if the messagePairs are not of the protocol. If all the messages are
+// ignored by the parser, nil is returned with ignored true, which doesn't tell the protocol.
+func (na *NetworkAnalyzer) parseProtocol(mps *messagePairs, parser *protocol.ProtocolParser) (records []*model.DataGroup, ignored bool) {
+	if parser.Pipelined() {
+		return na.parsePipelinedRequests(mps, parser), false
+	}
 	if parser.MultiRequests() {
 		// Not mergable requests
 		return na.parseMultipleRequests(mps, parser)
@@ -401,88 +605,273 @@ func (na *NetworkAnalyzer) parseProtocol(mps *messagePairs, parser *protocol.Pro
 
 	// Mergable Data
 	requestMsg := protocol.NewRequestMessage(mps.requests.getData())
+	mps.prepareMessage(requestMsg, int64(mps.getRquestSize()))
 	if !parser.ParseRequest(requestMsg) {
 		// Parse failure
-		return nil
+		return nil, false
 	}
 	if mps.responses == nil {
-		return na.getRecords(mps, parser.GetProtocol(), requestMsg.GetAttributes())
+		return na.getRecords(mps, parser.GetProtocol(), requestMsg.GetAttributes()), false
 	}
 
 	responseMsg := protocol.NewResponseMessage(mps.responses.getData(), requestMsg.GetAttributes())
+	mps.prepareMessage(responseMsg, int64(mps.getResponseSize()))
 	if !parser.ParseResponse(responseMsg) {
 		// Parse failure
-		return nil
+		return nil, false
 	}
 
-	return na.getRecords(mps, parser.GetProtocol(), responseMsg.GetAttributes())
+	return na.getRecords(mps, parser.GetProtocol(), responseMsg.GetAttributes()), false
 }
 
 // parseMultipleRequests parses the messagePairs when we know there could be multiple read requests.
-// This is used only when the protocol is DNS now.
-func (na *NetworkAnalyzer) parseMultipleRequests(mps *messagePairs, parser *protocol.ProtocolParser) []*model.DataGroup {
+// This is used when the protocol is DNS or multiplexed like HTTP/2. If no record is produced and
+// no request is waiting for its response, all the messages are ignored and nil is returned.
+func (na *NetworkAnalyzer) parseMultipleRequests(mps *messagePairs, parser *protocol.ProtocolParser) (records []*model.DataGroup, ignored bool) {
+	multiplexed := parser.Multiplexed()
+	var reqEvents []*model.KindlingEvent
+	var parsedReqMsgs []*protocol.PayloadMessage
+	if multiplexed {
+		for _, pending := range mps.pendingRequests {
+			reqEvents = append(reqEvents, pending.event)
+			parsedReqMsgs = append(parsedReqMsgs, pending.message)
+		}
+	}
 	// Match with key when disordering.
 	size := mps.requests.size()
-	parsedReqMsgs := make([]*protocol.PayloadMessage, size)
 	for i := 0; i < size; i++ {
 		req := mps.requests.getEvent(i)
 		requestMsg := protocol.NewRequestMessage(req.GetData())
+		mps.prepareMessage(requestMsg, req.GetResVal())
 		if !parser.ParseRequest(requestMsg) {
-			// Parse failure
-			return nil
+			// The first request decides whether the pairs are of the protocol. The following
+			// ones of a multiplexed protocol may start in the middle of a frame.
+			if !multiplexed || i == 0 {
+				// Parse failure
+				return nil, false
+			}
+			requestMsg.Ignore()
 		}
-		parsedReqMsgs[i] = requestMsg
+		reqEvents = append(reqEvents, req)
+		parsedReqMsgs = append(parsedReqMsgs, requestMsg)
 	}
 
-	records := make([]*model.DataGroup, 0)
-	if mps.responses == nil {
-		size := mps.requests.size()
-		for i := 0; i < size; i++ {
-			req := mps.requests.getEvent(i)
-			mp := &messagePair{
-				request:  req,
-				response: nil,
-			}
-			records = append(records, na.getRecordWithSinglePair(mps, mp, parser.GetProtocol(), parsedReqMsgs[i].GetAttributes()))
-		}
-		return records
-	} else {
-		matchedRequestIdx := make(map[int]bool)
+	type matchedPair struct {
+		requestIdx int
+		response   *model.KindlingEvent
+		attributes *model.AttributeMap
+	}
+	var matchedPairs []*matchedPair
+	matchedRequestIdx := make(map[int]*matchedPair)
+	if mps.responses != nil {
 		size := mps.responses.size()
 		for i := 0; i < size; i++ {
 			resp := mps.responses.getEvent(i)
 			responseMsg := protocol.NewResponseMessage(resp.GetData(), model.NewAttributeMap())
+			mps.prepareMessage(responseMsg, resp.GetResVal())
 			if !parser.ParseResponse(responseMsg) {
+				if multiplexed {
+					continue
+				}
 				// Parse failure
-				return nil
+				return nil, false
+			}
+			if responseMsg.IsIgnored() {
+				continue
 			}
 			// Match Request with repsone
 			matchIdx := parser.PairMatch(parsedReqMsgs, responseMsg)
 			if matchIdx == -1 {
+				if multiplexed {
+					// The request has been paired before or it is not captured.
+					continue
+				}
+				return nil, false
+			}
+			if matched, ok := matchedRequestIdx[matchIdx]; ok && multiplexed {
+				// The following frames of the response, like the trailers of gRPC.
+				matched.attributes.Merge(responseMsg.GetAttributes())
+				continue
+			}
+			matched := &matchedPair{requestIdx: matchIdx, response: resp, attributes: responseMsg.GetAttributes()}
+			matchedRequestIdx[matchIdx] = matched
+			matchedPairs = append(matchedPairs, matched)
+		}
+	}
+
+	records = make([]*model.DataGroup, 0, len(reqEvents))
+	carried := false
+	for _, matched := range matchedPairs {
+		attributes := parsedReqMsgs[matched.requestIdx].GetAttributes()
+		attributes.Merge(matched.attributes)
+		mp := &messagePair{
+			request:  reqEvents[matched.requestIdx],
+			response: matched.response,
+		}
+		records = append(records, na.getRecordWithSinglePair(mps, mp, parser.GetProtocol(), attributes))
+	}
+	// 498 Case
+	for i, req := range reqEvents {
+		if _, matched := matchedRequestIdx[i]; matched || parsedReqMsgs[i].IsIgnored() {
+			continue
+		}
+		if multiplexed && mps.carryRequest(&pendingRequest{event: req, message: parsedReqMsgs[i]}, na.cfg.GetRequestTimeout()) {
+			carried = true
+			continue
+		}
+		mp := &messagePair{
+			request:  req,
+			response: nil,
+		}
+		records = append(records, na.getRecordWithSinglePair(mps, mp, parser.GetProtocol(), parsedReqMsgs[i].GetAttributes()))
+	}
+	if len(records) == 0 && !carried {
+		return nil, true
+	}
+	return records, false
+}
+
+// parsePipelinedRequests parses the messagePairs of a pipelined protocol like HTTP/1.1, whose
+// requests may be sent before the responses of the previous ones. The events are split into the
+// messages, and the responses are paired with the requests in order. The requests still waiting
+// for their responses are moved to the next pairs of the connection.
+func (na *NetworkAnalyzer) parsePipelinedRequests(mps *messagePairs, parser *protocol.ProtocolParser) []*model.DataGroup {
+	records := make([]*model.DataGroup, 0, len(mps.pendingRequests)+1)
+	if mps.hasDroppedEvents() {
+		// The messages can not be split without all the events, so the events are merged as
+		// the other protocols, and the requests moved here are taken as no response.
+		requestMsg := protocol.NewRequestMessage(mps.requests.getData())
+		mps.prepareMessage(requestMsg, int64(mps.getRquestSize()))
+		if !parser.ParseRequest(requestMsg) {
+			return nil
+		}
+		attributes := requestMsg.GetAttributes()
+		if mps.responses != nil {
+			responseMsg := protocol.NewResponseMessage(mps.responses.getData(), attributes)
+			mps.prepareMessage(responseMsg, int64(mps.getResponseSize()))
+			if !parser.ParseResponse(responseMsg) {
+				return nil
+			}
+		}
+		for _, pending := range mps.pendingRequests {
+			mp := &messagePair{request: pending.event, requestEnd: pending.last, requestSize: pending.size}
+			records = append(records, na.getRecordWithSinglePair(mps, mp, parser.GetProtocol(), pending.message.GetAttributes()))
+		}
+		return append(records, na.getRecords(mps, parser.GetProtocol(), attributes)...)
+	}
+
+	requests := make([]*pipelinedMessage, 0, len(mps.pendingRequests)+1)
+	for _, pending := range mps.pendingRequests {
+		requests = append(requests, &pipelinedMessage{first: pending.event, last: pending.last, size: pending.size, message: pending.message})
+	}
+	parsedRequests, ok := splitPipelinedMessages(mps.requests, func(data []byte, offset int, size int64) *protocol.PayloadMessage {
+		requestMsg := protocol.NewRequestMessage(data)
+		requestMsg.Offset = offset
+		mps.prepareMessage(requestMsg, size)
+		if !parser.ParseRequest(requestMsg) {
+			return nil
+		}
+		return requestMsg
+	})
+	if !ok {
+		// Parse failure
+		return nil
+	}
+	requests = append(requests, parsedRequests...)
+
+	var responses []*pipelinedMessage
+	if mps.responses != nil {
+		paired := 0
+		parsedResponses, ok := splitPipelinedMessages(mps.responses, func(data []byte, offset int, size int64) *protocol.PayloadMessage {
+			// The response is parsed with the attributes of its request, like the method.
+			attributes := model.NewAttributeMap()
+			if paired < len(requests) {
+				attributes = requests[paired].message.GetAttributes()
+			}
+			responseMsg := protocol.NewResponseMessage(data, attributes)
+			responseMsg.Offset = offset
+			mps.prepareMessage(responseMsg, size)
+			if !parser.ParseResponse(responseMsg) {
 				return nil
 			}
-			matchedRequestIdx[matchIdx] = true
+			if !responseMsg.IsIgnored() {
+				paired++
+			}
+			return responseMsg
+		})
+		if !ok {
+			// Parse failure
+			return nil
+		}
+		for _, response := range parsedResponses {
+			if !response.message.IsIgnored() {
+				responses = append(responses, response)
+			}
+		}
+	}
 
-			mp := &messagePair{
-				request:  mps.requests.getEvent(matchIdx),
-				response: resp,
+	if len(requests) == 1 && len(mps.pendingRequests) == 0 && (mps.responses == nil || len(responses) == 1) {
+		// Only one request, whose metrics are the same as the other protocols.
+		return na.getRecords(mps, parser.GetProtocol(), requests[0].message.GetAttributes())
+	}
+	for i, request := range requests {
+		mp := &messagePair{request: request.first, requestEnd: request.last, requestSize: request.size}
+		if i < len(responses) {
+			mp.response, mp.responseEnd, mp.responseSize = responses[i].first, responses[i].last, responses[i].size
+		} else if mps.carryRequest(&pendingRequest{event: request.first, message: request.message, last: request.last, size: request.size}, na.cfg.GetRequestTimeout()) {
+			continue
+		}
+		records = append(records, na.getRecordWithSinglePair(mps, mp, parser.GetProtocol(), request.message.GetAttributes()))
+	}
+	return records
+}
+
+// splitPipelinedMessages splits the events into the messages parsed by parse from the offsets.
+// The events which can not be parsed are taken as the following parts of the previous messages.
+// False is returned if the first event can not be parsed.
+func splitPipelinedMessages(evts *events, parse func(data []byte, offset int, size int64) *protocol.PayloadMessage) ([]*pipelinedMessage, bool) {
+	var messages []*pipelinedMessage
+	size := evts.size()
+	for i := 0; i < size; i++ {
+		evt := evts.getEvent(i)
+		data := evt.GetData()
+		first := len(messages)
+		var starts []int
+		for offset := 0; offset < len(data); {
+			message := parse(data, offset, evt.GetResVal())
+			if message == nil {
+				break
 			}
-			records = append(records, na.getRecordWithSinglePair(mps, mp, parser.GetProtocol(), responseMsg.GetAttributes()))
-		}
-		// 498 Case
-		reqSize := mps.requests.size()
-		for i := 0; i < reqSize; i++ {
-			req := mps.requests.getEvent(i)
-			if _, matched := matchedRequestIdx[i]; !matched {
-				mp := &messagePair{
-					request:  req,
-					response: nil,
-				}
-				records = append(records, na.getRecordWithSinglePair(mps, mp, parser.GetProtocol(), parsedReqMsgs[i].GetAttributes()))
+			messages = append(messages, &pipelinedMessage{first: evt, message: message})
+			starts = append(starts, offset)
+			if message.Offset <= offset {
+				break
+			}
+			offset = message.Offset
+		}
+		if len(starts) == 0 {
+			if i == 0 {
+				return nil, false
+			}
+			if len(messages) > 0 {
+				// The following part of a message, like a large body.
+				previous := messages[len(messages)-1]
+				previous.last = evt
+				previous.size += uint64(evt.GetResVal())
+			}
+			continue
+		}
+		for j := range starts {
+			end := int(evt.GetResVal())
+			if j+1 < len(starts) {
+				end = starts[j+1]
+			}
+			if end > starts[j] {
+				messages[first+j].size = uint64(end - starts[j])
 			}
 		}
-		return records
 	}
+	return messages, true
 }
 
 func (na *NetworkAnalyzer) getConnectFailRecords(mps *messagePairs) []*model.DataGroup {
@@ -559,7 +948,7 @@ func (na *NetworkAnalyzer) getRecords(mps *messagePairs, protocol string, attrib
 
 // getRecordWithSinglePair generates a record whose metrics are copied from the input messagePair,
 // instead of messagePairs. This is used only when there could be multiple real requests in messagePairs.
-// For now, only messagePairs with DNS protocol would run into this method.
+// For now, only messagePairs with DNS, HTTP/1.1 pipelining or HTTP/2 protocol would run into this method.
 func (na *NetworkAnalyzer) getRecordWithSinglePair(mps *messagePairs, mp *messagePair, protocol string, attributes *model.AttributeMap) *model.DataGroup {
 	evt := mp.request
 
@@ -583,12 +972,12 @@ func (na *NetworkAnalyzer) getRecordWithSinglePair(mps *messagePairs, mp *messag
 
 	labels.Merge(attributes)
 	// If no protocol error found, we check other errors
-	if !labels.GetBoolValue(constlabels.IsError) && mps.responses == nil {
+	if !labels.GetBoolValue(constlabels.IsError) && mp.response == nil {
 		labels.AddBoolValue(constlabels.IsError, true)
 		labels.AddIntValue(constlabels.ErrorType, int64(constlabels.NoResponse))
 	}
 
-	if nil != mps.natTuple && mps.responses != nil {
+	if nil != mps.natTuple && mp.response != nil {
 		labels.UpdateAddStringValue(constlabels.DnatIp, mps.natTuple.ReplSrcIP.String())
 		labels.UpdateAddIntValue(constlabels.DnatPort, int64(mps.natTuple.ReplSrcPort))
 	}
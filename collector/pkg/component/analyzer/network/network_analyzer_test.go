@@ -1,17 +1,26 @@
 package network
 
 import (
+	"bytes"
+	"context"
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/Kindling-project/kindling/collector/pkg/component"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
 	"github.com/Kindling-project/kindling/collector/pkg/component/consumer"
 	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constvalues"
 	"github.com/spf13/viper"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
 )
 
 func TestHttpProtocol(t *testing.T) {
@@ -22,6 +31,521 @@ func TestHttpProtocol(t *testing.T) {
 		"http/server-trace-normal.yml")
 }
 
+func TestApplyConfig(t *testing.T) {
+	na := &NetworkAnalyzer{
+		cfg:           NewDefaultConfig(),
+		dataGroupPool: NewDataGroupPool(),
+		nextConsumers: []consumer.Consumer{&NopProcessor{}},
+		telemetry:     component.NewDefaultTelemetryTools(),
+	}
+	na.initProtocolParsers(na.cfg)
+
+	newConfig := NewDefaultConfig()
+	newConfig.ProtocolParser = []string{"http"}
+	newConfig.ResponseSlowThreshold = 1000
+	if err := na.ApplyConfig(newConfig); err != nil {
+		t.Fatalf("Failed to apply config: %v", err)
+	}
+	if _, ok := na.protocolMap["mysql"]; ok || len(na.protocolMap) != 1 {
+		t.Errorf("Only http parser is expected, but get %v", na.protocolMap)
+	}
+	if got := na.getResponseSlowThreshold("http"); got != 1000 {
+		t.Errorf("[Check slow threshold] want=1000, got=%d", got)
+	}
+
+	invalidConfig := NewDefaultConfig()
+	invalidConfig.ProcRoot = "/host/proc"
+	if err := na.ApplyConfig(invalidConfig); err == nil {
+		t.Errorf("Expected an error when changing proc_root, but get nil")
+	}
+	if na.cfg != newConfig {
+		t.Errorf("The configuration should not be changed when an error happens")
+	}
+}
+
+func TestApplyConfig_Labels(t *testing.T) {
+	na := &NetworkAnalyzer{
+		cfg:           NewDefaultConfig(),
+		dataGroupPool: NewDataGroupPool(),
+		nextConsumers: []consumer.Consumer{&NopProcessor{}},
+		telemetry:     component.NewDefaultTelemetryTools(),
+	}
+	na.initProtocolParsers(na.cfg)
+
+	withLabels := func(labels ...LabelConfig) *Config {
+		cfg := NewDefaultConfig()
+		cfg.ProtocolConfigs[0].Labels = labels
+		return cfg
+	}
+	newConfig := withLabels(
+		LabelConfig{Name: "tenant", Header: "X-Tenant-Id"},
+		LabelConfig{Name: "user_agent", Header: "User-Agent"},
+		LabelConfig{Name: "graphql_operation", JsonPath: "$.operationName"},
+	)
+	if err := na.ApplyConfig(newConfig); err != nil {
+		t.Fatalf("Failed to apply config: %v", err)
+	}
+	if got := len(na.cfg.getHttpLabels()); got != 3 {
+		t.Errorf("Expected 3 HTTP labels, but get %d", got)
+	}
+
+	mysqlLabels := NewDefaultConfig()
+	mysqlLabels.ProtocolConfigs[4].Labels = []LabelConfig{{Name: "user", Header: "User"}}
+	for name, cfg := range map[string]*Config{
+		"invalid name":      withLabels(LabelConfig{Name: "x-tenant", Header: "X-Tenant-Id"}),
+		"built-in label":    withLabels(LabelConfig{Name: constlabels.ContentKey, Header: "X-Path"}),
+		"built-in address":  withLabels(LabelConfig{Name: constlabels.DstIp, Header: "X-Real-Ip"}),
+		"metric name":       withLabels(LabelConfig{Name: constvalues.RequestIo, Header: "Content-Length"}),
+		"duplicated name":   withLabels(LabelConfig{Name: "tenant", Header: "X-Tenant-Id"}, LabelConfig{Name: "tenant", Header: "Tenant"}),
+		"no source":         withLabels(LabelConfig{Name: "tenant"}),
+		"both sources":      withLabels(LabelConfig{Name: "tenant", Header: "X-Tenant-Id", JsonPath: "tenant"}),
+		"invalid json path": withLabels(LabelConfig{Name: "id", JsonPath: "$.ids[0]"}),
+		"not http":          mysqlLabels,
+	} {
+		if err := na.ApplyConfig(cfg); err == nil {
+			t.Errorf("[%s] Expected an error, but get nil", name)
+		}
+	}
+	if na.cfg != newConfig {
+		t.Errorf("The configuration should not be changed when an error happens")
+	}
+}
+
+type recordConsumer struct {
+	errorTypes []int64
+}
+
+func (c *recordConsumer) Consume(dataGroup *model.DataGroup) error {
+	// The data group is put back to the pool after being consumed, so only the label is kept.
+	c.errorTypes = append(c.errorTypes, dataGroup.Labels.GetIntValue(constlabels.ErrorType))
+	return nil
+}
+
+func TestShutdown(t *testing.T) {
+	recorder := &recordConsumer{}
+	na := NewNetworkAnalyzer(NewDefaultConfig(), component.NewDefaultTelemetryTools(), []consumer.Consumer{recorder}).(*NetworkAnalyzer)
+	if err := na.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	eventCommon := getEventCommon("protocol/testdata/http/server-event.yml")
+	trace := getTrace("protocol/testdata/http/server-trace-normal.yml")
+	// A request waiting for its response
+	trace.Responses = nil
+	requestPairs := trace.PrepareMessagePairs(eventCommon)
+	na.requestMonitor.Store(requestPairs.getKey(), requestPairs)
+	// A connection without any requests
+	eventCommon.Ctx.Fd.Num = 2
+	connectPairs := (&Trace{Connects: []TraceEvent{{Name: "connect", Timestamp: 100000000}}}).PrepareMessagePairs(eventCommon)
+	na.requestMonitor.Store(connectPairs.getKey(), connectPairs)
+
+	if err := na.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.errorTypes) != 1 || recorder.errorTypes[0] != int64(constlabels.NoResponse) {
+		t.Errorf("Expected one no-response record, but get error types %v", recorder.errorTypes)
+	}
+	na.requestMonitor.Range(func(k, v interface{}) bool {
+		t.Errorf("No message pairs are expected to be left, but get %v", k)
+		return true
+	})
+}
+
+type countConsumer struct {
+	count int64
+}
+
+func (c *countConsumer) Consume(dataGroup *model.DataGroup) error {
+	atomic.AddInt64(&c.count, 1)
+	return nil
+}
+
+// TestConsumeEventInParallel checks no pairs are lost or mixed up when the sockets are sharded
+// to several workers. Run it with -race to check the states shared by the sockets.
+func TestConsumeEventInParallel(t *testing.T) {
+	const (
+		sockets = 200
+		rounds  = 20
+	)
+	config := NewDefaultConfig()
+	config.EnableConntrack = false
+	counter := &countConsumer{}
+	na := NewNetworkAnalyzer(config, component.NewDefaultTelemetryTools(), []consumer.Consumer{counter}).(*NetworkAnalyzer)
+	if err := na.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	manager, err := analyzer.NewManager(na)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dispatcher := analyzer.NewDispatcher(manager, 8, na.telemetry.Logger)
+	newEvent := func(name string, fd int, timestamp uint64, data string) *model.KindlingEvent {
+		return &model.KindlingEvent{
+			Source:       model.Source_SYSCALL_EXIT,
+			Timestamp:    timestamp,
+			Name:         name,
+			Category:     model.Category_CAT_NET,
+			ParamsNumber: 3,
+			UserAttributes: [8]model.KeyValue{
+				{Key: "latency", ValueType: model.ValueType_UINT64, Value: Int64ToBytes(1000)},
+				{Key: "res", ValueType: model.ValueType_INT64, Value: Int64ToBytes(int64(len(data)))},
+				{Key: "data", ValueType: model.ValueType_BYTEBUF, Value: []byte(data)},
+			},
+			Ctx: model.Context{
+				ThreadInfo: model.Thread{Pid: 12345, Tid: 12345},
+				FdInfo: model.Fd{
+					Num:      int32(fd),
+					TypeFd:   model.FDType_FD_IPV4_SOCK,
+					Protocol: model.L4Proto_TCP,
+					Role:     true,
+					Sip:      []uint32{16777343},
+					Sport:    uint32(40000 + fd),
+					Dip:      []uint32{16777343},
+					Dport:    8080,
+				},
+			},
+		}
+	}
+	for i := 0; i < rounds; i++ {
+		ts := uint64(i+1) * 1000000
+		for fd := 0; fd < sockets; fd++ {
+			dispatcher.Dispatch(newEvent("read", fd, ts, "GET /test HTTP/1.1\r\n\r\n"))
+		}
+		for fd := 0; fd < sockets; fd++ {
+			dispatcher.Dispatch(newEvent("write", fd, ts+500000, "HTTP/1.1 200 OK\r\n\r\n"))
+		}
+	}
+	dispatcher.Close()
+	// The last pair of each socket is flushed when shutting down.
+	if err = na.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if counter.count != sockets*rounds {
+		t.Errorf("Expected %d pairs, but get %d", sockets*rounds, counter.count)
+	}
+}
+
+type grpcRecord struct {
+	protocol       string
+	streamId       int64
+	contentKey     string
+	httpStatusCode int64
+	grpcStatusCode int64
+	errorType      int64
+}
+
+type grpcConsumer struct {
+	records []grpcRecord
+}
+
+func (c *grpcConsumer) Consume(dataGroup *model.DataGroup) error {
+	// The data group is put back to the pool after being consumed, so only the labels are kept.
+	labels := dataGroup.Labels
+	c.records = append(c.records, grpcRecord{
+		protocol:       labels.GetStringValue(constlabels.Protocol),
+		streamId:       labels.GetIntValue(constlabels.Http2StreamId),
+		contentKey:     labels.GetStringValue(constlabels.ContentKey),
+		httpStatusCode: labels.GetIntValue(constlabels.HttpStatusCode),
+		grpcStatusCode: labels.GetIntValue(constlabels.GrpcStatusCode),
+		errorType:      labels.GetIntValue(constlabels.ErrorType),
+	})
+	return nil
+}
+
+// TestHttp2Multiplexing checks the requests multiplexed on an HTTP/2 connection are paired with
+// their responses, even if the responses are received after the following requests.
+func TestHttp2Multiplexing(t *testing.T) {
+	config := NewDefaultConfig()
+	config.EnableConntrack = false
+	config.ProtocolParser = append(config.ProtocolParser, "http2")
+	recorder := &grpcConsumer{}
+	na := NewNetworkAnalyzer(config, component.NewDefaultTelemetryTools(), []consumer.Consumer{recorder}).(*NetworkAnalyzer)
+	if err := na.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var headerBuf, frameBuf bytes.Buffer
+	clientEncoder, serverEncoder := hpack.NewEncoder(&headerBuf), hpack.NewEncoder(&headerBuf)
+	framer := http2.NewFramer(&frameBuf, nil)
+	writeHeaders := func(encoder *hpack.Encoder, streamId uint32, endStream bool, fields ...string) {
+		headerBuf.Reset()
+		for i := 0; i+1 < len(fields); i += 2 {
+			_ = encoder.WriteField(hpack.HeaderField{Name: fields[i], Value: fields[i+1]})
+		}
+		_ = framer.WriteHeaders(http2.HeadersFrameParam{StreamID: streamId, BlockFragment: headerBuf.Bytes(), EndStream: endStream, EndHeaders: true})
+	}
+	request := func(streamId uint32, method string) []byte {
+		frameBuf.Reset()
+		if streamId == 1 {
+			frameBuf.WriteString(http2.ClientPreface)
+			_ = framer.WriteSettings()
+		}
+		writeHeaders(clientEncoder, streamId, false, ":method", "POST", ":scheme", "http", ":path", "/helloworld.Greeter/"+method, "content-type", "application/grpc")
+		_ = framer.WriteData(streamId, true, []byte{0, 0, 0, 0, 0})
+		return append([]byte(nil), frameBuf.Bytes()...)
+	}
+	response := func(streamId uint32, withHeaders bool, grpcStatus string) []byte {
+		frameBuf.Reset()
+		if withHeaders {
+			writeHeaders(serverEncoder, streamId, false, ":status", "200", "content-type", "application/grpc")
+			_ = framer.WriteData(streamId, false, []byte{0, 0, 0, 0, 0})
+		}
+		if len(grpcStatus) > 0 {
+			writeHeaders(serverEncoder, streamId, true, "grpc-status", grpcStatus)
+		}
+		return append([]byte(nil), frameBuf.Bytes()...)
+	}
+	newEvent := func(name string, timestamp uint64, data []byte) *model.KindlingEvent {
+		return &model.KindlingEvent{
+			Source:       model.Source_SYSCALL_EXIT,
+			Timestamp:    timestamp,
+			Name:         name,
+			Category:     model.Category_CAT_NET,
+			ParamsNumber: 3,
+			UserAttributes: [8]model.KeyValue{
+				{Key: "latency", ValueType: model.ValueType_UINT64, Value: Int64ToBytes(1000)},
+				{Key: "res", ValueType: model.ValueType_INT64, Value: Int64ToBytes(int64(len(data)))},
+				{Key: "data", ValueType: model.ValueType_BYTEBUF, Value: data},
+			},
+			Ctx: model.Context{
+				ThreadInfo: model.Thread{Pid: 12345, Tid: 12345},
+				FdInfo: model.Fd{
+					Num:      3,
+					TypeFd:   model.FDType_FD_IPV4_SOCK,
+					Protocol: model.L4Proto_TCP,
+					Role:     true,
+					Sip:      []uint32{16777343},
+					Sport:    40000,
+					Dip:      []uint32{16777343},
+					Dport:    50051,
+				},
+			},
+		}
+	}
+
+	events := []*model.KindlingEvent{
+		newEvent("read", 1000000, request(1, "SayHello")),
+		newEvent("read", 2000000, request(3, "SayGoodbye")),
+		newEvent("write", 3000000, response(1, true, "0")),
+		newEvent("read", 4000000, request(5, "SayHello")),
+		// The response of the 3rd stream is sent after the 5th stream starts.
+		newEvent("write", 5000000, response(3, true, "5")),
+		// The trailers are sent in another write.
+		newEvent("write", 6000000, response(5, true, "")),
+		newEvent("write", 7000000, response(5, false, "5")),
+	}
+	for _, evt := range events {
+		if err := na.ConsumeEvent(evt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := na.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[int64]struct {
+		contentKey string
+		grpcStatus int64
+	}{
+		1: {"/helloworld.Greeter/SayHello", 0},
+		3: {"/helloworld.Greeter/SayGoodbye", 5},
+		5: {"/helloworld.Greeter/SayHello", 5},
+	}
+	if len(recorder.records) != len(want) {
+		t.Fatalf("Expected %d records, but get %d", len(want), len(recorder.records))
+	}
+	for _, record := range recorder.records {
+		expected, ok := want[record.streamId]
+		if !ok {
+			t.Errorf("Unexpected stream %d", record.streamId)
+			continue
+		}
+		delete(want, record.streamId)
+		checkStringEqual(t, constlabels.Protocol, "grpc", record.protocol)
+		checkStringEqual(t, constlabels.ContentKey, expected.contentKey, record.contentKey)
+		checkInt64Equal(t, constlabels.HttpStatusCode, 200, record.httpStatusCode)
+		checkInt64Equal(t, constlabels.GrpcStatusCode, expected.grpcStatus, record.grpcStatusCode)
+		if record.errorType == int64(constlabels.NoResponse) {
+			t.Errorf("The stream %d should not be taken as no response", record.streamId)
+		}
+	}
+}
+
+type httpRecord struct {
+	contentKey     string
+	httpStatusCode int64
+	errorType      int64
+	responseIo     int64
+}
+
+type httpConsumer struct {
+	records []httpRecord
+}
+
+func (c *httpConsumer) Consume(dataGroup *model.DataGroup) error {
+	// The data group is put back to the pool after being consumed, so only the values are kept.
+	labels := dataGroup.Labels
+	record := httpRecord{
+		contentKey:     labels.GetStringValue(constlabels.ContentKey),
+		httpStatusCode: labels.GetIntValue(constlabels.HttpStatusCode),
+		errorType:      labels.GetIntValue(constlabels.ErrorType),
+	}
+	if metric, ok := dataGroup.GetMetric(constvalues.ResponseIo); ok {
+		record.responseIo = metric.GetInt().Value
+	}
+	c.records = append(c.records, record)
+	return nil
+}
+
+// TestHttp1Pipelining checks the pipelined requests of HTTP/1.1 are split and paired with their
+// responses in order, even if the messages are sent by several events.
+func TestHttp1Pipelining(t *testing.T) {
+	config := NewDefaultConfig()
+	config.EnableConntrack = false
+	recorder := &httpConsumer{}
+	na := NewNetworkAnalyzer(config, component.NewDefaultTelemetryTools(), []consumer.Consumer{recorder}).(*NetworkAnalyzer)
+	if err := na.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	newEvent := func(name string, timestamp uint64, data string) *model.KindlingEvent {
+		return newTcpEvent(name, timestamp, 8080, data)
+	}
+
+	okResponse := "HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok"
+	chunkedResponse := "HTTP/1.1 404 Not Found\r\nTransfer-Encoding: chunked\r\n\r\n4\r\nmiss\r\n"
+	createdResponse := "HTTP/1.1 201 Created\r\nContent-Length: 0\r\n\r\n"
+	emptyResponse := "HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n"
+	errorResponse := "HTTP/1.1 500 Internal Server Error\r\nContent-Length: 0\r\n\r\n"
+	events := []*model.KindlingEvent{
+		newEvent("read", 1000000, "GET /a HTTP/1.1\r\nHost: localhost\r\n\r\nGET /b HTTP/1.1\r\nHost: localhost\r\n\r\n"),
+		newEvent("write", 2000000, okResponse+chunkedResponse),
+		// The last chunk is sent in another write.
+		newEvent("write", 3000000, "0\r\n\r\n"),
+		// The body is sent in another read.
+		newEvent("read", 4000000, "POST /c HTTP/1.1\r\nContent-Length: 4\r\n\r\n"),
+		newEvent("read", 4100000, "body"),
+		newEvent("read", 5000000, "GET /d HTTP/1.1\r\n\r\n"),
+		newEvent("write", 6000000, "HTTP/1.1 100 Continue\r\n\r\n"+createdResponse),
+		// The response of /d is received after /e is sent.
+		newEvent("read", 7000000, "GET /e HTTP/1.1\r\n\r\n"),
+		newEvent("write", 8000000, emptyResponse+errorResponse),
+	}
+	for _, evt := range events {
+		if err := na.ConsumeEvent(evt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := na.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []httpRecord{
+		{contentKey: "/a", httpStatusCode: 200, responseIo: int64(len(okResponse))},
+		{contentKey: "/b", httpStatusCode: 404, errorType: int64(constlabels.ProtocolError), responseIo: int64(len(chunkedResponse) + 5)},
+		{contentKey: "/c", httpStatusCode: 201, responseIo: int64(len(createdResponse))},
+		{contentKey: "/d", httpStatusCode: 200, responseIo: int64(len(emptyResponse))},
+		{contentKey: "/e", httpStatusCode: 500, errorType: int64(constlabels.ProtocolError), responseIo: int64(len(errorResponse))},
+	}
+	if !reflect.DeepEqual(recorder.records, want) {
+		t.Errorf("Expected records %+v, but get %+v", want, recorder.records)
+	}
+}
+
+// newTcpEvent returns the read or write event of the server listening on the port.
+func newTcpEvent(name string, timestamp uint64, port uint32, data string) *model.KindlingEvent {
+	return &model.KindlingEvent{
+		Source:       model.Source_SYSCALL_EXIT,
+		Timestamp:    timestamp,
+		Name:         name,
+		Category:     model.Category_CAT_NET,
+		ParamsNumber: 3,
+		UserAttributes: [8]model.KeyValue{
+			{Key: "latency", ValueType: model.ValueType_UINT64, Value: Int64ToBytes(1000)},
+			{Key: "res", ValueType: model.ValueType_INT64, Value: Int64ToBytes(int64(len(data)))},
+			{Key: "data", ValueType: model.ValueType_BYTEBUF, Value: []byte(data)},
+		},
+		Ctx: model.Context{
+			ThreadInfo: model.Thread{Pid: 12345, Tid: 12345},
+			FdInfo: model.Fd{
+				Num:      3,
+				TypeFd:   model.FDType_FD_IPV4_SOCK,
+				Protocol: model.L4Proto_TCP,
+				Role:     true,
+				Sip:      []uint32{16777343},
+				Sport:    40000,
+				Dip:      []uint32{16777343},
+				Dport:    port,
+			},
+		},
+	}
+}
+
+type protocolConsumer struct {
+	protocols []string
+}
+
+func (c *protocolConsumer) Consume(dataGroup *model.DataGroup) error {
+	c.protocols = append(c.protocols, dataGroup.Labels.GetStringValue(constlabels.Protocol))
+	return nil
+}
+
+// TestIgnoredMessagesOnUnknownPort checks the pairs with only the messages ignored by a parser,
+// like the pings of MQTT, are not taken as the protocol on the ports without static mappings.
+func TestIgnoredMessagesOnUnknownPort(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  string
+		response string
+	}{
+		{name: "mqtt ping", request: "\xc0\x00", response: "\xd0\x00"},
+		{name: "mqtt puback", request: "\x40\x02\x00\x05", response: "\x00\x00\x00\x05OK"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewDefaultConfig()
+			config.EnableConntrack = false
+			config.ProtocolParser = append(config.ProtocolParser, "mqtt")
+			recorder := &protocolConsumer{}
+			na := NewNetworkAnalyzer(config, component.NewDefaultTelemetryTools(), []consumer.Consumer{recorder}).(*NetworkAnalyzer)
+			if err := na.Start(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			const port = 7777
+			pairs := CACHE_ADD_THRESHOLD + 5
+			for i := 0; i < pairs; i++ {
+				timestamp := uint64(i+1) * 1000000
+				for _, evt := range []*model.KindlingEvent{
+					newTcpEvent("read", timestamp, port, tt.request),
+					newTcpEvent("write", timestamp+1000, port, tt.response),
+				} {
+					if err := na.ConsumeEvent(evt); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if err := na.Shutdown(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(recorder.protocols) != pairs {
+				t.Fatalf("Expected %d records, but get %d", pairs, len(recorder.protocols))
+			}
+			for _, protocolName := range recorder.protocols {
+				if protocolName != protocol.NOSUPPORT {
+					t.Errorf("Expected the protocol %s, but get %s", protocol.NOSUPPORT, protocolName)
+					break
+				}
+			}
+			parsers, _ := na.parserFactory.GetCachedParsersByPort(port)
+			for _, parser := range parsers {
+				if parser.GetProtocol() == protocol.MQTT {
+					t.Errorf("The port should not be cached as %s", protocol.MQTT)
+				}
+			}
+		})
+	}
+}
+
 func TestMySqlProtocol(t *testing.T) {
 	testProtocol(t, "mysql/server-event.yml",
 		"mysql/server-trace-query-split.yml",
@@ -82,7 +606,7 @@ func prepareNetworkAnalyzer() *NetworkAnalyzer {
 			nextConsumers: []consumer.Consumer{&NopProcessor{}},
 			telemetry:     component.NewDefaultTelemetryTools(),
 		}
-		na.Start()
+		na.Start(context.Background())
 	}
 	return na
 }
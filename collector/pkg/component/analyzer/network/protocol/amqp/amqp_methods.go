@@ -0,0 +1,95 @@
+package amqp
+
+// The methods parsed for their arguments
+const (
+	methodConnectionClose = classConnection<<16 | 50
+	methodChannelClose    = classChannel<<16 | 40
+	methodQueueDeclare    = classQueue<<16 | 10
+	methodQueueDeclareOk  = classQueue<<16 | 11
+	methodBasicConsume    = classBasic<<16 | 20
+	methodBasicPublish    = classBasic<<16 | 40
+	methodBasicReturn     = classBasic<<16 | 50
+	methodBasicDeliver    = classBasic<<16 | 60
+	methodBasicGet        = classBasic<<16 | 70
+	methodBasicGetOk      = classBasic<<16 | 71
+)
+
+var methodNames = map[uint32]string{
+	classConnection<<16 | 10: "connection.start",
+	classConnection<<16 | 11: "connection.start-ok",
+	classConnection<<16 | 20: "connection.secure",
+	classConnection<<16 | 21: "connection.secure-ok",
+	classConnection<<16 | 30: "connection.tune",
+	classConnection<<16 | 31: "connection.tune-ok",
+	classConnection<<16 | 40: "connection.open",
+	classConnection<<16 | 41: "connection.open-ok",
+	methodConnectionClose:    "connection.close",
+	classConnection<<16 | 51: "connection.close-ok",
+	classConnection<<16 | 60: "connection.blocked",
+	classConnection<<16 | 61: "connection.unblocked",
+
+	classChannel<<16 | 10: "channel.open",
+	classChannel<<16 | 11: "channel.open-ok",
+	classChannel<<16 | 20: "channel.flow",
+	classChannel<<16 | 21: "channel.flow-ok",
+	methodChannelClose:    "channel.close",
+	classChannel<<16 | 41: "channel.close-ok",
+
+	classExchange<<16 | 10: "exchange.declare",
+	classExchange<<16 | 11: "exchange.declare-ok",
+	classExchange<<16 | 20: "exchange.delete",
+	classExchange<<16 | 21: "exchange.delete-ok",
+	classExchange<<16 | 30: "exchange.bind",
+	classExchange<<16 | 31: "exchange.bind-ok",
+	classExchange<<16 | 40: "exchange.unbind",
+	classExchange<<16 | 51: "exchange.unbind-ok",
+
+	methodQueueDeclare:   "queue.declare",
+	methodQueueDeclareOk: "queue.declare-ok",
+	classQueue<<16 | 20:  "queue.bind",
+	classQueue<<16 | 21:  "queue.bind-ok",
+	classQueue<<16 | 30:  "queue.purge",
+	classQueue<<16 | 31:  "queue.purge-ok",
+	classQueue<<16 | 40:  "queue.delete",
+	classQueue<<16 | 41:  "queue.delete-ok",
+	classQueue<<16 | 50:  "queue.unbind",
+	classQueue<<16 | 51:  "queue.unbind-ok",
+
+	classBasic<<16 | 10:  "basic.qos",
+	classBasic<<16 | 11:  "basic.qos-ok",
+	methodBasicConsume:   "basic.consume",
+	classBasic<<16 | 21:  "basic.consume-ok",
+	classBasic<<16 | 30:  "basic.cancel",
+	classBasic<<16 | 31:  "basic.cancel-ok",
+	methodBasicPublish:   "basic.publish",
+	methodBasicReturn:    "basic.return",
+	methodBasicDeliver:   "basic.deliver",
+	methodBasicGet:       "basic.get",
+	methodBasicGetOk:     "basic.get-ok",
+	classBasic<<16 | 72:  "basic.get-empty",
+	classBasic<<16 | 80:  "basic.ack",
+	classBasic<<16 | 90:  "basic.reject",
+	classBasic<<16 | 100: "basic.recover-async",
+	classBasic<<16 | 110: "basic.recover",
+	classBasic<<16 | 111: "basic.recover-ok",
+	classBasic<<16 | 120: "basic.nack",
+
+	classConfirm<<16 | 10: "confirm.select",
+	classConfirm<<16 | 11: "confirm.select-ok",
+
+	classTx<<16 | 10: "tx.select",
+	classTx<<16 | 11: "tx.select-ok",
+	classTx<<16 | 20: "tx.commit",
+	classTx<<16 | 21: "tx.commit-ok",
+	classTx<<16 | 30: "tx.rollback",
+	classTx<<16 | 31: "tx.rollback-ok",
+}
+
+func (m *method) key() uint32 {
+	return uint32(m.classId)<<16 | uint32(m.methodId)
+}
+
+func getMethodName(classId uint16, methodId uint16) (string, bool) {
+	name, ok := methodNames[uint32(classId)<<16|uint32(methodId)]
+	return name, ok
+}
@@ -0,0 +1,197 @@
+package amqp
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+)
+
+const (
+	frameHeaderLength = 7
+	frameEnd          = 0xce
+	// maxFrameLength limits the frame_max negotiated, whose default is 128K in RabbitMQ.
+	maxFrameLength = 64 * 1024 * 1024
+
+	frameMethod    = 1
+	frameHeader    = 2
+	frameBody      = 3
+	frameHeartbeat = 8
+
+	classConnection = 10
+	classChannel    = 20
+	classExchange   = 40
+	classQueue      = 50
+	classBasic      = 60
+	classConfirm    = 85
+	classTx         = 90
+
+	replySuccess = 200
+	// defaultExchange is the name of the default exchange, which is empty in the protocol.
+	defaultExchange = "amq.default"
+)
+
+// protocolHeader is sent by the clients before any frames.
+var protocolHeader = []byte{'A', 'M', 'Q', 'P', 0, 0, 9, 1}
+
+/*
+NewAmqpParser parses the frames of AMQP 0-9-1, which is used by RabbitMQ.
+
+	Request                                           Response
+	/            |            \                      /       |        \
+	Basic.Publish Basic.Get    Queue.Declare          Basic.Deliver  Basic.GetOk  Channel.Close
+
+The clients usually write several frames at once, like the method, the content header and the
+body of Basic.Publish, so the frames of a message are parsed one by one and the first method
+frame is taken as the request or response.
+*/
+func NewAmqpParser() *protocol.ProtocolParser {
+	requestParser := protocol.CreatePkgParser(fastfailAmqp(), parseAmqpRequest())
+	responseParser := protocol.CreatePkgParser(fastfailAmqp(), parseAmqpResponse())
+
+	parser := protocol.NewProtocolParser(protocol.AMQP, requestParser, responseParser, nil)
+	parser.EnableMultiFrame()
+	return parser
+}
+
+func fastfailAmqp() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < message.Offset+frameHeaderLength
+	}
+}
+
+/*
+https://www.rabbitmq.com/resources/specs/amqp0-9-1.pdf
+
+	0      1         3             7                  size+7 size+8
+	+------+---------+-------------+  +------------+  +-----------+
+	| type | channel |    size     |  |  payload   |  | frame-end |
+	+------+---------+-------------+  +------------+  +-----------+
+
+The payload of a method frame starts with the class id and the method id.
+*/
+type method struct {
+	classId  uint16
+	methodId uint16
+	// arguments are shorter than the ones sent if the frame is truncated.
+	arguments []byte
+}
+
+// readFrame reads the frame at the offset of the message and moves the offset to the next frame.
+// The method is nil if the frame is not a method frame, and false is returned if the data is not
+// a frame.
+func readFrame(message *protocol.PayloadMessage) (*method, bool) {
+	data := message.Data[message.Offset:]
+	if message.Offset == 0 && bytes.HasPrefix(data, protocolHeader[:4]) {
+		if len(data) < len(protocolHeader) || !bytes.Equal(data[:len(protocolHeader)], protocolHeader) {
+			return nil, false
+		}
+		message.Offset += len(protocolHeader)
+		return nil, true
+	}
+	frameType := data[0]
+	size := int(binary.BigEndian.Uint32(data[3:]))
+	if size > maxFrameLength {
+		return nil, false
+	}
+	end := frameHeaderLength + size
+	if end < len(data) {
+		if data[end] != frameEnd {
+			return nil, false
+		}
+		message.Offset += end + 1
+	} else {
+		// The frame is truncated.
+		message.Offset = len(message.Data)
+	}
+	payload := data[frameHeaderLength:]
+	if len(payload) > size {
+		payload = payload[:size]
+	}
+	switch frameType {
+	case frameMethod:
+		if len(payload) < 4 {
+			return nil, end < len(data)
+		}
+		m := &method{
+			classId:   binary.BigEndian.Uint16(payload),
+			methodId:  binary.BigEndian.Uint16(payload[2:]),
+			arguments: payload[4:],
+		}
+		if _, ok := getMethodName(m.classId, m.methodId); !ok {
+			return nil, false
+		}
+		return m, true
+	case frameHeader, frameBody, frameHeartbeat:
+		// The frame of the other types is valid only if it's complete.
+		return nil, end < len(data)
+	}
+	return nil, false
+}
+
+// parseFrames parses a frame of the message each time, and the method is handled by the function.
+// The frames after the first one are not checked strictly, since they may be misaligned if the
+// message is truncated.
+func parseFrames(message *protocol.PayloadMessage, handle func(m *method)) (bool, bool) {
+	first := message.Offset == 0
+	m, ok := readFrame(message)
+	if !ok {
+		if first {
+			return false, true
+		}
+		return true, true
+	}
+	if m != nil {
+		handle(m)
+	}
+	return true, len(message.Data) < message.Offset+frameHeaderLength
+}
+
+// argumentReader reads the arguments of the methods. Once the arguments are exhausted, all the
+// following reads fail.
+type argumentReader struct {
+	data   []byte
+	offset int
+	failed bool
+}
+
+func (r *argumentReader) skip(n int) {
+	if r.failed || r.offset+n > len(r.data) {
+		r.failed = true
+		return
+	}
+	r.offset += n
+}
+
+func (r *argumentReader) readShort() int {
+	if r.failed || r.offset+2 > len(r.data) {
+		r.failed = true
+		return 0
+	}
+	v := binary.BigEndian.Uint16(r.data[r.offset:])
+	r.offset += 2
+	return int(v)
+}
+
+// readShortString reads the shortstr, which is prefixed by its length in one byte.
+func (r *argumentReader) readShortString() string {
+	if r.failed || r.offset >= len(r.data) {
+		r.failed = true
+		return ""
+	}
+	length := int(r.data[r.offset])
+	if r.offset+1+length > len(r.data) {
+		r.failed = true
+		return ""
+	}
+	s := string(r.data[r.offset+1 : r.offset+1+length])
+	r.offset += 1 + length
+	return s
+}
+
+func getExchangeName(exchange string) string {
+	if len(exchange) == 0 {
+		return defaultExchange
+	}
+	return exchange
+}
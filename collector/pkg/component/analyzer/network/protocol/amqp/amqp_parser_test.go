@@ -0,0 +1,241 @@
+package amqp
+
+import (
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/internal/testutil"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func shortString(s string) []byte {
+	return append([]byte{byte(len(s))}, s...)
+}
+
+func newFrame(frameType byte, payload ...[]byte) []byte {
+	data := testutil.Join(payload...)
+	return testutil.Join([]byte{frameType, 0, 1}, testutil.Int32(len(data)), data, []byte{frameEnd})
+}
+
+func methodFrame(key uint32, arguments ...[]byte) []byte {
+	return newFrame(frameMethod, append([][]byte{testutil.Int16(int(key >> 16)), testutil.Int16(int(key & 0xffff))}, arguments...)...)
+}
+
+func publish(exchange string, routingKey string) []byte {
+	return testutil.Join(
+		methodFrame(methodBasicPublish, testutil.Int16(0), shortString(exchange), shortString(routingKey), []byte{0}),
+		newFrame(frameHeader, testutil.Int16(classBasic), testutil.Int16(0), make([]byte, 8), testutil.Int16(0)),
+		newFrame(frameBody, []byte("hello")),
+	)
+}
+
+func closeFrame(key uint32, code int, text string) []byte {
+	return methodFrame(key, testutil.Int16(code), shortString(text), testutil.Int16(classBasic), testutil.Int16(40))
+}
+
+func TestParseRequest(t *testing.T) {
+	parser := NewAmqpParser()
+	tests := []struct {
+		name       string
+		data       []byte
+		method     string
+		contentKey string
+		replyCode  int64
+	}{
+		{
+			name:       "publish",
+			data:       publish("orders", "order.created"),
+			method:     "basic.publish",
+			contentKey: "basic.publish orders:order.created",
+		},
+		{
+			name:       "publish to the default exchange",
+			data:       testutil.Join(publish("", "tasks"), publish("orders", "order.created")),
+			method:     "basic.publish",
+			contentKey: "basic.publish amq.default:tasks",
+		},
+		{
+			name:       "publish truncated",
+			data:       publish("orders", "order.created")[:40],
+			method:     "basic.publish",
+			contentKey: "basic.publish orders:order.created",
+		},
+		{
+			name:       "get",
+			data:       methodFrame(methodBasicGet, testutil.Int16(0), shortString("tasks"), []byte{0}),
+			method:     "basic.get",
+			contentKey: "basic.get tasks",
+		},
+		{
+			name:       "declare queue",
+			data:       methodFrame(methodQueueDeclare, testutil.Int16(0), shortString("tasks"), []byte{2}, []byte{0, 0, 0, 0}),
+			method:     "queue.declare",
+			contentKey: "queue.declare tasks",
+		},
+		{
+			name:       "declare queue named by the server",
+			data:       methodFrame(methodQueueDeclare, testutil.Int16(0), shortString(""), []byte{8}, []byte{0, 0, 0, 0}),
+			method:     "queue.declare",
+			contentKey: "queue.declare",
+		},
+		{
+			name:       "close channel",
+			data:       closeFrame(methodChannelClose, 200, "Goodbye"),
+			method:     "channel.close",
+			contentKey: "channel.close",
+			replyCode:  200,
+		},
+		{
+			name:       "ack after heartbeat",
+			data:       testutil.Join(newFrame(frameHeartbeat), methodFrame(classBasic<<16|80, make([]byte, 8), []byte{0})),
+			method:     "basic.ack",
+			contentKey: "basic.ack",
+		},
+		{
+			name: "protocol header",
+			data: protocolHeader,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := protocol.NewRequestMessage(tt.data)
+			if !parser.ParseRequest(message) {
+				t.Fatalf("failed to parse the request")
+			}
+			if got := message.GetStringAttribute(constlabels.AmqpMethod); got != tt.method {
+				t.Errorf("method = %q, want %q", got, tt.method)
+			}
+			if got := message.GetStringAttribute(constlabels.ContentKey); got != tt.contentKey {
+				t.Errorf("content key = %q, want %q", got, tt.contentKey)
+			}
+			if got := message.GetIntAttribute(constlabels.AmqpReplyCode); got != tt.replyCode {
+				t.Errorf("reply code = %d, want %d", got, tt.replyCode)
+			}
+			if message.GetBoolAttribute(constlabels.IsError) {
+				t.Errorf("the request should not be an error")
+			}
+		})
+	}
+}
+
+func TestParseRequest_NotAmqp(t *testing.T) {
+	parser := NewAmqpParser()
+	noFrameEnd := methodFrame(methodBasicGet, testutil.Int16(0), shortString("tasks"), []byte{0})
+	noFrameEnd[len(noFrameEnd)-1] = 0
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "http", data: []byte("GET /index.html HTTP/1.1\r\n\r\n")},
+		{name: "amqp 1.0", data: []byte{'A', 'M', 'Q', 'P', 0, 1, 0, 0}},
+		{name: "unknown method", data: methodFrame(classBasic<<16 | 99)},
+		{name: "no frame end", data: noFrameEnd},
+		{name: "truncated body frame", data: newFrame(frameBody, []byte("hello"))[:10]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if parser.ParseRequest(protocol.NewRequestMessage(tt.data)) {
+				t.Errorf("the data should not be parsed as AMQP")
+			}
+		})
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	parser := NewAmqpParser()
+	deliver := testutil.Join(
+		methodFrame(methodBasicDeliver, shortString("ctag"), make([]byte, 8), []byte{0}, shortString("orders"), shortString("order.paid")),
+		newFrame(frameHeader, testutil.Int16(classBasic), testutil.Int16(0), make([]byte, 8), testutil.Int16(0)),
+		newFrame(frameBody, []byte("hello")),
+	)
+	tests := []struct {
+		name       string
+		request    []byte
+		data       []byte
+		contentKey string
+		routingKey string
+		queue      string
+		isError    bool
+		replyCode  int64
+		replyText  string
+	}{
+		{
+			name:       "deliver",
+			request:    methodFrame(classBasic<<16|80, make([]byte, 8), []byte{0}),
+			data:       deliver,
+			contentKey: "basic.deliver orders:order.paid",
+			routingKey: "order.paid",
+		},
+		{
+			name:       "get ok",
+			request:    methodFrame(methodBasicGet, testutil.Int16(0), shortString("tasks"), []byte{0}),
+			data:       methodFrame(methodBasicGetOk, make([]byte, 8), []byte{0}, shortString(""), shortString("tasks"), make([]byte, 4)),
+			contentKey: "basic.get tasks",
+			routingKey: "tasks",
+			queue:      "tasks",
+		},
+		{
+			name:       "declare ok",
+			request:    methodFrame(methodQueueDeclare, testutil.Int16(0), shortString(""), []byte{8}, []byte{0, 0, 0, 0}),
+			data:       methodFrame(methodQueueDeclareOk, shortString("amq.gen-JzTY20BRgKO"), make([]byte, 8)),
+			contentKey: "queue.declare",
+			queue:      "amq.gen-JzTY20BRgKO",
+		},
+		{
+			name:       "channel closed by the server",
+			request:    publish("missing", "order.created"),
+			data:       closeFrame(methodChannelClose, 404, "NOT_FOUND - no exchange 'missing' in vhost '/'"),
+			contentKey: "basic.publish missing:order.created",
+			routingKey: "order.created",
+			isError:    true,
+			replyCode:  404,
+			replyText:  "NOT_FOUND - no exchange 'missing' in vhost '/'",
+		},
+		{
+			name:       "channel closed",
+			request:    closeFrame(methodChannelClose, 200, "Goodbye"),
+			data:       methodFrame(classChannel<<16 | 41),
+			contentKey: "channel.close",
+			replyCode:  200,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := protocol.NewRequestMessage(tt.request)
+			if !parser.ParseRequest(request) {
+				t.Fatalf("failed to parse the request")
+			}
+			message := protocol.NewResponseMessage(tt.data, request.GetAttributes())
+			if !parser.ParseResponse(message) {
+				t.Fatalf("failed to parse the response")
+			}
+			if got := message.GetStringAttribute(constlabels.ContentKey); got != tt.contentKey {
+				t.Errorf("content key = %q, want %q", got, tt.contentKey)
+			}
+			if got := message.GetStringAttribute(constlabels.AmqpRoutingKey); got != tt.routingKey {
+				t.Errorf("routing key = %q, want %q", got, tt.routingKey)
+			}
+			if got := message.GetStringAttribute(constlabels.AmqpQueue); got != tt.queue {
+				t.Errorf("queue = %q, want %q", got, tt.queue)
+			}
+			if got := message.GetBoolAttribute(constlabels.IsError); got != tt.isError {
+				t.Errorf("is_error = %v, want %v", got, tt.isError)
+			}
+			if got := message.GetIntAttribute(constlabels.AmqpReplyCode); got != tt.replyCode {
+				t.Errorf("reply code = %d, want %d", got, tt.replyCode)
+			}
+			if got := message.GetStringAttribute(constlabels.AmqpReplyText); got != tt.replyText {
+				t.Errorf("reply text = %q, want %q", got, tt.replyText)
+			}
+		})
+	}
+}
+
+func TestParseResponse_ConnectionStart(t *testing.T) {
+	parser := NewAmqpParser()
+	message := protocol.NewResponseMessage(methodFrame(classConnection<<16|10, []byte{0, 9}), model.NewAttributeMap())
+	if !parser.ParseResponse(message) {
+		t.Fatalf("failed to parse the response")
+	}
+}
@@ -0,0 +1,76 @@
+package amqp
+
+import (
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+/*
+The content key is the method followed by its target, like the exchange and the routing key
+of Basic.Publish, or the queue of Basic.Get and Queue.Declare.
+
+	Basic.Publish    short reserved, shortstr exchange, shortstr routing-key, bit mandatory, ...
+	Basic.Get        short reserved, shortstr queue, bit no-ack
+	Basic.Consume    short reserved, shortstr queue, shortstr consumer-tag, ...
+	Queue.Declare    short reserved, shortstr queue, bit passive, ...
+*/
+func parseAmqpRequest() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		return parseFrames(message, func(m *method) {
+			if message.HasAttribute(constlabels.AmqpMethod) {
+				return
+			}
+			name, _ := getMethodName(m.classId, m.methodId)
+			message.AddStringAttribute(constlabels.AmqpMethod, name)
+			contentKey := name
+			r := &argumentReader{data: m.arguments}
+			switch m.key() {
+			case methodBasicPublish:
+				r.skip(2)
+				exchange := r.readShortString()
+				routingKey := r.readShortString()
+				if r.failed {
+					break
+				}
+				message.AddUtf8StringAttribute(constlabels.AmqpExchange, exchange)
+				message.AddUtf8StringAttribute(constlabels.AmqpRoutingKey, routingKey)
+				contentKey = name + " " + getExchangeName(exchange) + ":" + routingKey
+			case methodBasicGet, methodBasicConsume, methodQueueDeclare:
+				r.skip(2)
+				queue := r.readShortString()
+				if r.failed || len(queue) == 0 {
+					// The name of the queue declared may be generated by the server.
+					break
+				}
+				message.AddUtf8StringAttribute(constlabels.AmqpQueue, queue)
+				contentKey = name + " " + queue
+			case methodChannelClose, methodConnectionClose:
+				parseClose(message, r)
+			}
+			message.AddUtf8StringAttribute(constlabels.ContentKey, contentKey)
+		})
+	}
+}
+
+/*
+Channel.Close and Connection.Close carry the reason of closing.
+
+	short reply-code, shortstr reply-text, short class-id, short method-id
+
+The reply codes other than 200 are the errors, like 404 of publishing to an exchange not found.
+*/
+func parseClose(message *protocol.PayloadMessage, r *argumentReader) {
+	code := r.readShort()
+	text := r.readShortString()
+	if code == 0 {
+		return
+	}
+	message.AddIntAttribute(constlabels.AmqpReplyCode, int64(code))
+	if code != replySuccess {
+		message.AddBoolAttribute(constlabels.IsError, true)
+		message.AddIntAttribute(constlabels.ErrorType, int64(constlabels.ProtocolError))
+		if !r.failed {
+			message.AddUtf8StringAttribute(constlabels.AmqpReplyText, text)
+		}
+	}
+}
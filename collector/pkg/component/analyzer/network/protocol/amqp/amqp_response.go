@@ -0,0 +1,60 @@
+package amqp
+
+import (
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+/*
+The messages are pushed to the consumers by Basic.Deliver, so the delivery is taken as the
+content of the message pair unless the request publishes a message.
+
+	Basic.Deliver     shortstr consumer-tag, longlong delivery-tag, bit redelivered,
+	                  shortstr exchange, shortstr routing-key
+	Basic.GetOk       longlong delivery-tag, bit redelivered, shortstr exchange, shortstr routing-key, ...
+	Basic.Return      short reply-code, shortstr reply-text, shortstr exchange, shortstr routing-key
+	Queue.DeclareOk   shortstr queue, long message-count, long consumer-count
+
+The server closes the channel or the connection with the reply code if the request fails.
+*/
+func parseAmqpResponse() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		return parseFrames(message, func(m *method) {
+			r := &argumentReader{data: m.arguments}
+			switch m.key() {
+			case methodBasicDeliver:
+				r.readShortString()
+				r.skip(9)
+				parseDelivery(message, r, true)
+			case methodBasicGetOk:
+				r.skip(9)
+				parseDelivery(message, r, false)
+			case methodBasicReturn:
+				if !message.HasAttribute(constlabels.AmqpReplyCode) {
+					parseClose(message, r)
+				}
+			case methodQueueDeclareOk:
+				if queue := r.readShortString(); !r.failed && !message.HasAttribute(constlabels.AmqpQueue) {
+					message.AddUtf8StringAttribute(constlabels.AmqpQueue, queue)
+				}
+			case methodChannelClose, methodConnectionClose:
+				if !message.HasAttribute(constlabels.AmqpReplyCode) {
+					parseClose(message, r)
+				}
+			}
+		})
+	}
+}
+
+func parseDelivery(message *protocol.PayloadMessage, r *argumentReader, deliver bool) {
+	exchange := r.readShortString()
+	routingKey := r.readShortString()
+	if r.failed || message.HasAttribute(constlabels.AmqpExchange) {
+		return
+	}
+	message.AddUtf8StringAttribute(constlabels.AmqpExchange, exchange)
+	message.AddUtf8StringAttribute(constlabels.AmqpRoutingKey, routingKey)
+	if deliver {
+		message.AddUtf8StringAttribute(constlabels.ContentKey, "basic.deliver "+getExchangeName(exchange)+":"+routingKey)
+	}
+}
@@ -0,0 +1,270 @@
+package cassandra
+
+import (
+	"encoding/binary"
+)
+
+const (
+	frameHeaderLength = 9
+	// maxFrameLength is the default native_transport_max_frame_size of Cassandra.
+	maxFrameLength = 256 * 1024 * 1024
+
+	minVersion       = 3
+	maxVersion       = 5
+	versionResponse  = 0x80
+	versionMask      = 0x7f
+	flagCompression  = 0x01
+	flagTracing      = 0x02
+	flagCustomLoad   = 0x04
+	flagWarning      = 0x08
+	flagBeta         = 0x10
+	knownFrameFlags  = flagCompression | flagTracing | flagCustomLoad | flagWarning | flagBeta
+	tracingIdLength  = 16
+	segmentHeaderLen = 6
+	segmentCrcLength = 4
+	// maxSegmentPayloadLength is the limit of the payload of a segment, which is 17 bits.
+	maxSegmentPayloadLength = 128*1024 - 1
+)
+
+/*
+https://github.com/apache/cassandra/blob/trunk/doc/native_protocol_v4.spec
+
+	0         8        16        24        32         40
+	+---------+---------+---------+---------+---------+
+	| version |  flags  |      stream       | opcode  |
+	+---------+---------+---------+---------+---------+
+	|                length                 |
+	+---------+---------+---------+---------+
+	|                                       |
+	.            ...  body ...              .
+	.                                       .
+	+----------------------------------------
+*/
+type frame struct {
+	version  byte
+	flags    byte
+	streamId int16
+	opcode   byte
+	// body is shorter than the length in the header if the frame is truncated.
+	body []byte
+}
+
+// readFrames reads the frames of a message. The frames of v5 are wrapped in segments after the
+// connection is established, which are unwrapped first. False is returned if the data is not
+// CQL frames.
+func readFrames(data []byte, isResponse bool) ([]frame, bool) {
+	if len(data) < frameHeaderLength {
+		return nil, false
+	}
+	if validVersion(data[0], isResponse) {
+		if frames, ok := readEnvelopes(data, isResponse); ok {
+			return frames, true
+		}
+	}
+	// The segments of v5, whose first byte may look like a version as well.
+	var frames []frame
+	for len(data) >= segmentHeaderLen {
+		payload, selfContained, next, ok := readSegment(data)
+		if !ok {
+			return nil, false
+		}
+		if selfContained || len(frames) == 0 {
+			// The segments carrying a part of a large frame are not parsed except the first one.
+			envelopes, ok := readEnvelopes(payload, isResponse)
+			if !ok {
+				return nil, false
+			}
+			frames = append(frames, envelopes...)
+		}
+		if next >= len(data) {
+			break
+		}
+		data = data[next:]
+	}
+	return frames, len(frames) > 0
+}
+
+func validVersion(version byte, isResponse bool) bool {
+	if isResponse != (version&versionResponse != 0) {
+		return false
+	}
+	version &= versionMask
+	return version >= minVersion && version <= maxVersion
+}
+
+func readEnvelopes(data []byte, isResponse bool) ([]frame, bool) {
+	var frames []frame
+	offset := 0
+	for offset+frameHeaderLength <= len(data) {
+		header := data[offset : offset+frameHeaderLength]
+		f := frame{
+			version:  header[0],
+			flags:    header[1],
+			streamId: int16(binary.BigEndian.Uint16(header[2:])),
+			opcode:   header[4],
+		}
+		length := int(binary.BigEndian.Uint32(header[5:]))
+		if !validVersion(f.version, isResponse) || f.flags&^knownFrameFlags != 0 ||
+			!validOpcode(f.opcode, isResponse) || length > maxFrameLength {
+			return nil, false
+		}
+		offset += frameHeaderLength
+		end := offset + length
+		if end > len(data) {
+			f.body = data[offset:]
+		} else {
+			f.body = data[offset:end]
+		}
+		frames = append(frames, f)
+		offset = end
+	}
+	return frames, len(frames) > 0
+}
+
+func validOpcode(opcode byte, isResponse bool) bool {
+	switch opcode {
+	case opStartup, opOptions, opQuery, opPrepare, opExecute, opRegister, opBatch, opAuthResponse:
+		return !isResponse
+	case opError, opReady, opAuthenticate, opSupported, opResult, opEvent, opAuthChallenge, opAuthSuccess:
+		return isResponse
+	}
+	return false
+}
+
+/*
+https://github.com/apache/cassandra/blob/trunk/doc/native_protocol_v5.spec#L231
+
+The header of an uncompressed segment is 3 bytes in little endian followed by its CRC24.
+
+	payload length (17 bits) | self-contained flag (1 bit) | padding (6 bits)
+
+The payload is followed by its CRC32, which is not checked because the payload may be truncated.
+The compressed segments are not supported.
+*/
+func readSegment(data []byte) (payload []byte, selfContained bool, next int, ok bool) {
+	header := uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16
+	crc := uint32(data[3]) | uint32(data[4])<<8 | uint32(data[5])<<16
+	if crc24(header, 3) != crc {
+		return nil, false, 0, false
+	}
+	length := int(header & maxSegmentPayloadLength)
+	selfContained = header&(1<<17) != 0
+	end := segmentHeaderLen + length
+	if end > len(data) {
+		return data[segmentHeaderLen:], selfContained, len(data), true
+	}
+	return data[segmentHeaderLen:end], selfContained, end + segmentCrcLength, true
+}
+
+// crc24 is the CRC24 of the lowest bytes of the value used by Cassandra.
+func crc24(value uint32, length int) uint32 {
+	crc := uint32(0x875060)
+	for i := 0; i < length; i++ {
+		crc ^= (value & 0xff) << 16
+		value >>= 8
+		for j := 0; j < 8; j++ {
+			crc <<= 1
+			if crc&0x1000000 != 0 {
+				crc ^= 0x1974f0b
+			}
+		}
+	}
+	return crc & 0xffffff
+}
+
+// bodyReader reads the notations of the body, like [int] and [string]. Once the body is
+// exhausted, all the following reads fail.
+type bodyReader struct {
+	data   []byte
+	offset int
+	failed bool
+}
+
+func (r *bodyReader) readN(n int) []byte {
+	if r.failed || n < 0 || r.offset+n > len(r.data) {
+		r.failed = true
+		return nil
+	}
+	ret := r.data[r.offset : r.offset+n]
+	r.offset += n
+	return ret
+}
+
+func (r *bodyReader) readByte() byte {
+	if b := r.readN(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *bodyReader) readShort() int {
+	if b := r.readN(2); b != nil {
+		return int(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (r *bodyReader) readInt() int {
+	if b := r.readN(4); b != nil {
+		return int(int32(binary.BigEndian.Uint32(b)))
+	}
+	return 0
+}
+
+func (r *bodyReader) readString() string {
+	return string(r.readN(r.readShort()))
+}
+
+// readLongString returns the available part of the string if it is truncated.
+func (r *bodyReader) readLongString() (string, bool) {
+	length := r.readInt()
+	if r.failed || length < 0 {
+		r.failed = true
+		return "", false
+	}
+	if r.offset+length > len(r.data) {
+		ret := string(r.data[r.offset:])
+		r.failed = true
+		return ret, len(ret) > 0
+	}
+	return string(r.readN(length)), true
+}
+
+func (r *bodyReader) readShortBytes() []byte {
+	return r.readN(r.readShort())
+}
+
+// skipBytesMap skips the [bytes map], which is the custom payload.
+func (r *bodyReader) skipBytesMap() {
+	n := r.readShort()
+	for i := 0; i < n && !r.failed; i++ {
+		r.readString()
+		if length := r.readInt(); length > 0 {
+			r.readN(length)
+		}
+	}
+}
+
+func (r *bodyReader) skipStringList() {
+	n := r.readShort()
+	for i := 0; i < n && !r.failed; i++ {
+		r.readString()
+	}
+}
+
+// newBodyReader skips the fields before the body indicated by the flags.
+func newBodyReader(f *frame, isResponse bool) *bodyReader {
+	r := &bodyReader{data: f.body}
+	if isResponse {
+		if f.flags&flagTracing != 0 {
+			r.readN(tracingIdLength)
+		}
+		if f.flags&flagWarning != 0 {
+			r.skipStringList()
+		}
+	}
+	if f.flags&flagCustomLoad != 0 {
+		r.skipBytesMap()
+	}
+	return r
+}
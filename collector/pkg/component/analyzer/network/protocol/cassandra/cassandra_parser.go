@@ -0,0 +1,135 @@
+package cassandra
+
+import (
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+const (
+	opError         = 0x00
+	opStartup       = 0x01
+	opReady         = 0x02
+	opAuthenticate  = 0x03
+	opOptions       = 0x05
+	opSupported     = 0x06
+	opQuery         = 0x07
+	opResult        = 0x08
+	opPrepare       = 0x09
+	opExecute       = 0x0a
+	opRegister      = 0x0b
+	opEvent         = 0x0c
+	opBatch         = 0x0d
+	opAuthChallenge = 0x0e
+	opAuthResponse  = 0x0f
+	opAuthSuccess   = 0x10
+
+	resultVoid         = 0x0001
+	resultRows         = 0x0002
+	resultSetKeyspace  = 0x0003
+	resultPrepared     = 0x0004
+	resultSchemaChange = 0x0005
+
+	// maxStatements limits the statements kept for a connection.
+	maxStatements = 1000
+)
+
+/*
+NewCassandraParser parses the frames of the CQL native protocol v3, v4 and v5.
+
+	Request                                   Response
+	/      |       |      \                   /      \
+	QUERY  PREPARE EXECUTE BATCH              ERROR  RESULT
+
+The drivers send many requests on a connection concurrently, so the requests and responses are
+paired by the stream ids. Only the first frame of a message is taken as the request or response,
+while the other frames are only used to know the keyspace and the prepared statements.
+
+The statements are prepared once and executed by their ids later, so the statements prepared are
+kept for each connection, and the statement of an EXECUTE frame is known if it is prepared after
+the connection is seen.
+*/
+func NewCassandraParser() *protocol.ProtocolParser {
+	requestParser := protocol.CreatePkgParser(fastfailCassandraRequest(), parseCassandraRequest())
+	responseParser := protocol.CreatePkgParser(fastfailCassandraResponse(), parseCassandraResponse())
+
+	parser := protocol.NewProtocolParser(protocol.CASSANDRA, requestParser, responseParser, cassandraPair())
+	parser.EnableMultiplexing()
+	return parser
+}
+
+func cassandraPair() protocol.PairMatch {
+	return func(requests []*protocol.PayloadMessage, response *protocol.PayloadMessage) int {
+		streamId := response.GetIntAttribute(constlabels.CassandraStreamId)
+		for i, request := range requests {
+			if request.IsIgnored() || !request.HasAttribute(constlabels.CassandraStreamId) {
+				continue
+			}
+			if request.GetIntAttribute(constlabels.CassandraStreamId) == streamId {
+				return i
+			}
+		}
+		return -1
+	}
+}
+
+// session keeps the states of a connection.
+type session struct {
+	keyspace string
+	// preparing are the statements being prepared, keyed by the stream ids of the requests.
+	preparing map[int16]string
+	// statements are the statements prepared, keyed by their ids.
+	statements map[string]string
+}
+
+func getSession(states *protocol.ConnectionStates, create bool) *session {
+	if s, ok := states.Get(protocol.CASSANDRA).(*session); ok {
+		return s
+	}
+	if !create {
+		return nil
+	}
+	s := &session{
+		preparing:  make(map[int16]string),
+		statements: make(map[string]string),
+	}
+	states.Set(protocol.CASSANDRA, s)
+	return s
+}
+
+func (s *session) getKeyspace() string {
+	if s == nil {
+		return ""
+	}
+	return s.keyspace
+}
+
+func (s *session) getStatement(id []byte) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	statement, ok := s.statements[string(id)]
+	return statement, ok
+}
+
+func (s *session) prepare(streamId int16, statement string) {
+	if len(s.preparing) >= maxStatements {
+		// The responses are missed, so the ones left are useless.
+		s.preparing = make(map[int16]string)
+	}
+	s.preparing[streamId] = statement
+}
+
+func (s *session) prepared(streamId int16, id []byte) {
+	if s == nil {
+		return
+	}
+	statement, ok := s.preparing[streamId]
+	if !ok {
+		return
+	}
+	delete(s.preparing, streamId)
+	if _, exist := s.statements[string(id)]; !exist && len(s.statements) >= maxStatements {
+		return
+	}
+	s.statements[string(id)] = statement
+}
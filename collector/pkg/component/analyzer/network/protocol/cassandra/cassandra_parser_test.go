@@ -0,0 +1,286 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/internal/testutil"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func newFrame(version byte, flags byte, streamId int16, opcode byte, body ...[]byte) []byte {
+	data := testutil.Join(body...)
+	header := append([]byte{version, flags}, testutil.Int16(int(streamId))...)
+	header = append(append(header, opcode), testutil.Int32(len(data))...)
+	return append(header, data...)
+}
+
+// newSegment wraps the frames in an uncompressed self-contained segment of v5.
+func newSegment(payload []byte) []byte {
+	header := uint32(len(payload)) | 1<<17
+	crc := crc24(header, 3)
+	data := []byte{byte(header), byte(header >> 8), byte(header >> 16), byte(crc), byte(crc >> 8), byte(crc >> 16)}
+	// The CRC32 of the payload is not checked.
+	return append(append(data, payload...), 0, 0, 0, 0)
+}
+
+func queryFrame(streamId int16, statement string) []byte {
+	return newFrame(4, 0, streamId, opQuery, testutil.String32(statement), testutil.Int16(1), []byte{0})
+}
+
+func TestParseRequest(t *testing.T) {
+	parser := NewCassandraParser()
+	tests := []struct {
+		name       string
+		data       []byte
+		statement  string
+		contentKey string
+	}{
+		{
+			name:       "query",
+			data:       queryFrame(5, "SELECT * FROM shop.orders WHERE id = ?"),
+			statement:  "SELECT * FROM shop.orders WHERE id = ?",
+			contentKey: "select shop.orders",
+		},
+		{
+			name:       "quoted names",
+			data:       queryFrame(5, `INSERT INTO "Shop"."Orders" (id) VALUES (1)`),
+			statement:  `INSERT INTO "Shop"."Orders" (id) VALUES (1)`,
+			contentKey: "insert Shop.Orders",
+		},
+		{
+			name:       "schema change",
+			data:       queryFrame(5, "CREATE TABLE IF NOT EXISTS shop.users (id int PRIMARY KEY)"),
+			statement:  "CREATE TABLE IF NOT EXISTS shop.users (id int PRIMARY KEY)",
+			contentKey: "create shop.users",
+		},
+		{
+			name:       "no table",
+			data:       queryFrame(5, "CREATE KEYSPACE shop WITH replication = {'class': 'SimpleStrategy'}"),
+			statement:  "CREATE KEYSPACE shop WITH replication = {'class': 'SimpleStrategy'}",
+			contentKey: "create",
+		},
+		{
+			name:       "use",
+			data:       queryFrame(5, "USE shop"),
+			statement:  "USE shop",
+			contentKey: "use shop",
+		},
+		{
+			name:       "prepare",
+			data:       newFrame(4, 0, 5, opPrepare, testutil.String32("DELETE FROM shop.carts WHERE id = ?")),
+			statement:  "DELETE FROM shop.carts WHERE id = ?",
+			contentKey: "delete shop.carts",
+		},
+		{
+			name: "batch",
+			data: newFrame(4, 0, 5, opBatch, []byte{0}, testutil.Int16(2),
+				[]byte{0}, testutil.String32("UPDATE shop.stock SET count = 1 WHERE id = 1"), testutil.Int16(0),
+				[]byte{0}, testutil.String32("UPDATE shop.stock SET count = 2 WHERE id = 2"), testutil.Int16(0),
+				testutil.Int16(1), []byte{0}),
+			statement:  "UPDATE shop.stock SET count = 1 WHERE id = 1",
+			contentKey: "update shop.stock",
+		},
+		{
+			name: "custom payload",
+			data: newFrame(4, flagCustomLoad, 5, opQuery, testutil.Int16(1), testutil.String16("key"), testutil.Int32(2), []byte{1, 2},
+				testutil.String32("TRUNCATE TABLE shop.orders"), testutil.Int16(1), []byte{0}),
+			statement:  "TRUNCATE TABLE shop.orders",
+			contentKey: "truncate shop.orders",
+		},
+		{
+			name:       "v5 segment",
+			data:       newSegment(newFrame(5, 0, 5, opQuery, testutil.String32("SELECT id FROM shop.orders"), testutil.Int16(1), []byte{0}, testutil.Int32(0))),
+			statement:  "SELECT id FROM shop.orders",
+			contentKey: "select shop.orders",
+		},
+		{
+			name:       "truncated",
+			data:       queryFrame(5, "SELECT * FROM shop.orders WHERE id = ?")[:40],
+			statement:  "SELECT * FROM shop.orders W",
+			contentKey: "select shop.orders",
+		},
+		{
+			name: "compressed",
+			data: newFrame(4, flagCompression, 5, opQuery, []byte{0, 0, 0, 10, 1, 2, 3, 4}),
+		},
+		{
+			name: "options",
+			data: newFrame(4, 0, 5, opOptions),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := protocol.NewRequestMessage(tt.data)
+			if !parser.ParseRequest(message) {
+				t.Fatalf("failed to parse the request")
+			}
+			if got := message.GetIntAttribute(constlabels.CassandraStreamId); got != 5 {
+				t.Errorf("stream id = %d, want 5", got)
+			}
+			if got := message.GetStringAttribute(constlabels.Sql); got != tt.statement {
+				t.Errorf("statement = %q, want %q", got, tt.statement)
+			}
+			if got := message.GetStringAttribute(constlabels.ContentKey); got != tt.contentKey {
+				t.Errorf("content key = %q, want %q", got, tt.contentKey)
+			}
+		})
+	}
+}
+
+func TestParseRequest_NotCassandra(t *testing.T) {
+	parser := NewCassandraParser()
+	badSegment := newSegment(queryFrame(5, "SELECT * FROM shop.orders"))
+	badSegment[3] ^= 0xff
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "http", data: []byte("GET /index.html HTTP/1.1\r\n\r\n")},
+		{name: "response", data: newFrame(0x84, 0, 5, opResult, testutil.Int32(resultVoid))},
+		{name: "unsupported version", data: newFrame(2, 0, 5, opQuery, testutil.String32("SELECT * FROM shop.orders"))},
+		{name: "unknown flags", data: newFrame(4, 0x40, 5, opQuery, testutil.String32("SELECT * FROM shop.orders"))},
+		{name: "unknown opcode", data: newFrame(4, 0, 5, 0x20)},
+		{name: "bad segment", data: badSegment},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if parser.ParseRequest(protocol.NewRequestMessage(tt.data)) {
+				t.Errorf("the data should not be parsed as CQL")
+			}
+		})
+	}
+}
+
+func TestParseRequest_Session(t *testing.T) {
+	parser := NewCassandraParser()
+	states := protocol.NewConnectionStates()
+	parseRequest := func(data []byte) *protocol.PayloadMessage {
+		message := protocol.NewRequestMessage(data)
+		message.SetConnectionStates(states)
+		if !parser.ParseRequest(message) {
+			t.Fatalf("failed to parse the request")
+		}
+		return message
+	}
+	parseResponse := func(data []byte) {
+		message := protocol.NewResponseMessage(data, model.NewAttributeMap())
+		message.SetConnectionStates(states)
+		if !parser.ParseResponse(message) {
+			t.Fatalf("failed to parse the response")
+		}
+	}
+
+	parseRequest(queryFrame(1, "USE shop"))
+	parseResponse(newFrame(0x84, 0, 1, opResult, testutil.Int32(resultSetKeyspace), testutil.String16("shop")))
+	message := parseRequest(queryFrame(2, "SELECT * FROM orders"))
+	if got := message.GetStringAttribute(constlabels.ContentKey); got != "select shop.orders" {
+		t.Errorf("content key = %q, want the one with the keyspace", got)
+	}
+
+	id := []byte{0xca, 0xfe}
+	parseRequest(newFrame(4, 0, 3, opPrepare, testutil.String32("SELECT * FROM orders WHERE id = ?")))
+	parseResponse(newFrame(0x84, 0, 3, opResult, testutil.Int32(resultPrepared), testutil.String16(string(id))))
+	message = parseRequest(newFrame(4, 0, 4, opExecute, testutil.String16(string(id)), testutil.Int16(1), []byte{0}))
+	if got := message.GetStringAttribute(constlabels.Sql); got != "SELECT * FROM orders WHERE id = ?" {
+		t.Errorf("statement = %q, want the prepared one", got)
+	}
+	if got := message.GetStringAttribute(constlabels.ContentKey); got != "select shop.orders" {
+		t.Errorf("content key = %q, want the one of the prepared statement", got)
+	}
+	message = parseRequest(newFrame(4, 0, 5, opBatch, []byte{1}, testutil.Int16(1), []byte{1}, testutil.String16(string(id)), testutil.Int16(0),
+		testutil.Int16(1), []byte{0}))
+	if got := message.GetStringAttribute(constlabels.ContentKey); got != "select shop.orders" {
+		t.Errorf("content key = %q, want the one of the prepared statement", got)
+	}
+
+	message = parseRequest(newFrame(4, 0, 6, opExecute, testutil.String16("\xbe\xef"), testutil.Int16(1), []byte{0}))
+	if message.HasAttribute(constlabels.Sql) {
+		t.Errorf("the statement prepared before the connection is seen should be unknown")
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	parser := NewCassandraParser()
+	tests := []struct {
+		name      string
+		data      []byte
+		ignored   bool
+		isError   bool
+		errorCode int64
+		errorMsg  string
+	}{
+		{
+			name: "void",
+			data: newFrame(0x84, 0, 5, opResult, testutil.Int32(resultVoid)),
+		},
+		{
+			name:      "invalid query",
+			data:      newFrame(0x84, 0, 5, opError, testutil.Int32(0x2200), testutil.String16("unconfigured table orders")),
+			isError:   true,
+			errorCode: 0x2200,
+			errorMsg:  "unconfigured table orders",
+		},
+		{
+			name: "tracing and warnings",
+			data: newFrame(0x84, flagTracing|flagWarning, 5, opError, make([]byte, tracingIdLength),
+				testutil.Int16(1), testutil.String16("Aggregation query used without partition key"),
+				testutil.Int32(0x1200), testutil.String16("Operation timed out")),
+			isError:   true,
+			errorCode: 0x1200,
+			errorMsg:  "Operation timed out",
+		},
+		{
+			name:      "v5 segment",
+			data:      newSegment(newFrame(0x85, 0, 5, opError, testutil.Int32(0x1000), testutil.String16("Cannot achieve consistency level"))),
+			isError:   true,
+			errorCode: 0x1000,
+			errorMsg:  "Cannot achieve consistency level",
+		},
+		{
+			name:    "event",
+			data:    newFrame(0x84, 0, -1, opEvent, testutil.String16("STATUS_CHANGE")),
+			ignored: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := protocol.NewResponseMessage(tt.data, model.NewAttributeMap())
+			if !parser.ParseResponse(message) {
+				t.Fatalf("failed to parse the response")
+			}
+			if got := message.IsIgnored(); got != tt.ignored {
+				t.Errorf("ignored = %v, want %v", got, tt.ignored)
+			}
+			if got := message.GetBoolAttribute(constlabels.IsError); got != tt.isError {
+				t.Errorf("is_error = %v, want %v", got, tt.isError)
+			}
+			if got := message.GetIntAttribute(constlabels.CassandraErrorCode); got != tt.errorCode {
+				t.Errorf("error code = %d, want %d", got, tt.errorCode)
+			}
+			if got := message.GetStringAttribute(constlabels.CassandraErrorMsg); got != tt.errorMsg {
+				t.Errorf("error message = %q, want %q", got, tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestPairMatch(t *testing.T) {
+	parser := NewCassandraParser()
+	var requests []*protocol.PayloadMessage
+	for _, streamId := range []int16{11, 12, 13} {
+		request := protocol.NewRequestMessage(queryFrame(streamId, "SELECT * FROM shop.orders"))
+		if !parser.ParseRequest(request) {
+			t.Fatalf("failed to parse the request")
+		}
+		requests = append(requests, request)
+	}
+	response := protocol.NewResponseMessage(newFrame(0x84, 0, 12, opResult, testutil.Int32(resultVoid)), model.NewAttributeMap())
+	if !parser.ParseResponse(response) {
+		t.Fatalf("failed to parse the response")
+	}
+	if got := parser.PairMatch(requests, response); got != 1 {
+		t.Errorf("pair match = %d, want 1", got)
+	}
+}
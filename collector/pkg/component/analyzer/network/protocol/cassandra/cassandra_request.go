@@ -0,0 +1,94 @@
+package cassandra
+
+import (
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func fastfailCassandraRequest() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < frameHeaderLength
+	}
+}
+
+/*
+The statement is in the body of the frames.
+
+	QUERY     [long string] the statement, followed by the parameters
+	PREPARE   [long string] the statement, followed by the flags in v5
+	EXECUTE   [short bytes] the id of the prepared statement, followed by the parameters
+	BATCH     [byte] the type of the batch
+	          [short] the number of the statements, followed by the statements
+	          [byte] 0 if the statement is [long string], or 1 if it's the id in [short bytes]
+	          ...    the values of the statement
+
+The first statement of a batch is taken.
+*/
+func parseCassandraRequest() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		frames, ok := readFrames(message.Data, false)
+		if !ok {
+			return false, true
+		}
+		states := message.GetConnectionStates()
+		var statement string
+		var found bool
+		for i := range frames {
+			f := &frames[i]
+			if f.flags&flagCompression != 0 {
+				// The compressed body is not decoded.
+				continue
+			}
+			text, ok := readStatement(f, states)
+			if i == 0 {
+				statement, found = text, ok
+			}
+		}
+
+		message.AddIntAttribute(constlabels.CassandraStreamId, int64(frames[0].streamId))
+		if found {
+			message.AddUtf8StringAttribute(constlabels.Sql, statement)
+			keyspace := getSession(states, false).getKeyspace()
+			if contentKey := getContentKey(statement, keyspace); len(contentKey) > 0 {
+				message.AddUtf8StringAttribute(constlabels.ContentKey, contentKey)
+			}
+		}
+		return true, true
+	}
+}
+
+func readStatement(f *frame, states *protocol.ConnectionStates) (string, bool) {
+	r := newBodyReader(f, false)
+	switch f.opcode {
+	case opQuery:
+		return r.readLongString()
+	case opPrepare:
+		statement, ok := r.readLongString()
+		if ok && !r.failed {
+			getSession(states, true).prepare(f.streamId, statement)
+		}
+		return statement, ok
+	case opExecute:
+		id := r.readShortBytes()
+		if r.failed {
+			return "", false
+		}
+		return getSession(states, false).getStatement(id)
+	case opBatch:
+		r.readByte()
+		if n := r.readShort(); n == 0 || r.failed {
+			return "", false
+		}
+		switch r.readByte() {
+		case 0:
+			return r.readLongString()
+		case 1:
+			id := r.readShortBytes()
+			if r.failed {
+				return "", false
+			}
+			return getSession(states, false).getStatement(id)
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,76 @@
+package cassandra
+
+import (
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+// eventStreamId is the stream id of the EVENT frames pushed by the server.
+const eventStreamId = -1
+
+func fastfailCassandraResponse() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < frameHeaderLength
+	}
+}
+
+/*
+The body of the frames are prefixed by the tracing id, the warnings and the custom payload if
+the flags are set.
+
+	ERROR     [int] the error code, like 0x2200 for the invalid query
+	          [string] the error message
+	RESULT    [int] the kind of the result
+	          Set_keyspace [string] the keyspace
+	          Prepared     [short bytes] the id of the statement prepared
+*/
+func parseCassandraResponse() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		frames, ok := readFrames(message.Data, true)
+		if !ok {
+			return false, true
+		}
+		states := message.GetConnectionStates()
+		for i := range frames {
+			f := &frames[i]
+			if f.flags&flagCompression != 0 || f.opcode != opResult {
+				continue
+			}
+			readResult(f, states)
+		}
+
+		f := &frames[0]
+		if f.streamId == eventStreamId {
+			message.Ignore()
+		}
+		message.AddIntAttribute(constlabels.CassandraStreamId, int64(f.streamId))
+		if f.opcode == opError && f.flags&flagCompression == 0 {
+			r := newBodyReader(f, true)
+			code := r.readInt()
+			if r.failed {
+				return true, true
+			}
+			message.AddBoolAttribute(constlabels.IsError, true)
+			message.AddIntAttribute(constlabels.ErrorType, int64(constlabels.ProtocolError))
+			message.AddIntAttribute(constlabels.CassandraErrorCode, int64(code))
+			if msg := r.readString(); !r.failed {
+				message.AddUtf8StringAttribute(constlabels.CassandraErrorMsg, msg)
+			}
+		}
+		return true, true
+	}
+}
+
+func readResult(f *frame, states *protocol.ConnectionStates) {
+	r := newBodyReader(f, true)
+	switch r.readInt() {
+	case resultSetKeyspace:
+		if keyspace := r.readString(); !r.failed && len(keyspace) > 0 {
+			getSession(states, true).keyspace = keyspace
+		}
+	case resultPrepared:
+		if id := r.readShortBytes(); !r.failed {
+			getSession(states, false).prepared(f.streamId, id)
+		}
+	}
+}
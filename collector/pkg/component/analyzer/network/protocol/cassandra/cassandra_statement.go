@@ -0,0 +1,189 @@
+package cassandra
+
+import (
+	"strings"
+)
+
+// maxStatementTokens limits the tokens read from a statement to find its table.
+const maxStatementTokens = 64
+
+/*
+getContentKey returns the operation followed by the table of the statement, like
+"select shop.orders". The keyspace of the connection is used if the table is not qualified,
+and the operation only is returned if the statement operates no tables.
+
+	SELECT ... FROM [keyspace.]table
+	INSERT INTO [keyspace.]table
+	UPDATE [keyspace.]table
+	DELETE ... FROM [keyspace.]table
+	TRUNCATE [TABLE] [keyspace.]table
+	CREATE | ALTER | DROP TABLE [IF [NOT] EXISTS] [keyspace.]table
+	USE keyspace
+	BEGIN [UNLOGGED | COUNTER] BATCH statement; ... APPLY BATCH
+*/
+func getContentKey(statement string, keyspace string) string {
+	tokens := tokenize(statement)
+	if len(tokens) > 0 && tokens[0] == "begin" {
+		// Take the first statement of the batch.
+		for i, token := range tokens {
+			if token == "batch" {
+				tokens = tokens[i+1:]
+				break
+			}
+		}
+	}
+	if len(tokens) == 0 || !isKeyword(tokens[0]) {
+		return ""
+	}
+	operation := tokens[0]
+	var name []string
+	switch operation {
+	case "select", "delete":
+		name = nameAfter(tokens, "from")
+	case "insert":
+		name = nameAfter(tokens, "into")
+	case "update":
+		name = readName(tokens[1:])
+	case "truncate":
+		if len(tokens) > 1 && tokens[1] == "table" {
+			name = readName(tokens[2:])
+		} else {
+			name = readName(tokens[1:])
+		}
+	case "create", "alter", "drop":
+		if len(tokens) > 1 && (tokens[1] == "table" || tokens[1] == "columnfamily") {
+			name = readName(skipIfExists(tokens[2:]))
+		}
+	case "use":
+		if name = readName(tokens[1:]); len(name) == 1 {
+			return operation + " " + name[0]
+		}
+		return operation
+	}
+	switch len(name) {
+	case 1:
+		if len(keyspace) > 0 {
+			return operation + " " + keyspace + "." + name[0]
+		}
+		return operation + " " + name[0]
+	case 2:
+		return operation + " " + name[0] + "." + name[1]
+	}
+	return operation
+}
+
+func nameAfter(tokens []string, keyword string) []string {
+	for i, token := range tokens {
+		if token == keyword {
+			return readName(tokens[i+1:])
+		}
+	}
+	return nil
+}
+
+func skipIfExists(tokens []string) []string {
+	if len(tokens) > 0 && tokens[0] == "if" {
+		for i, token := range tokens {
+			if token == "exists" {
+				return tokens[i+1:]
+			}
+		}
+	}
+	return tokens
+}
+
+// readName reads the name like "table", "keyspace.table" or "\"Keyspace\".\"Table\"".
+func readName(tokens []string) []string {
+	if len(tokens) == 0 || !isName(tokens[0]) {
+		return nil
+	}
+	if len(tokens) >= 3 && tokens[1] == "." && isName(tokens[2]) {
+		return []string{unquote(tokens[0]), unquote(tokens[2])}
+	}
+	return []string{unquote(tokens[0])}
+}
+
+// tokenize splits the beginning of the statement into the identifiers, the quoted names and the
+// other characters. The unquoted identifiers are case-insensitive, so they are in lower case.
+// The string literals and the comments are skipped.
+func tokenize(statement string) []string {
+	var tokens []string
+	for i := 0; i < len(statement) && len(tokens) < maxStatementTokens; {
+		c := statement[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '-' && strings.HasPrefix(statement[i:], "--"),
+			c == '/' && strings.HasPrefix(statement[i:], "//"):
+			if end := strings.IndexByte(statement[i:], '\n'); end >= 0 {
+				i += end + 1
+			} else {
+				i = len(statement)
+			}
+		case c == '/' && strings.HasPrefix(statement[i:], "/*"):
+			if end := strings.Index(statement[i+2:], "*/"); end >= 0 {
+				i += end + 4
+			} else {
+				i = len(statement)
+			}
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(statement) {
+				if statement[end] == c {
+					// The quotes are escaped by doubling them.
+					if end+1 < len(statement) && statement[end+1] == c {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			if c == '"' {
+				tokens = append(tokens, statement[i:end])
+			}
+			i = end + 1
+		case isIdentifierChar(c):
+			end := i + 1
+			for end < len(statement) && isIdentifierChar(statement[end]) {
+				end++
+			}
+			tokens = append(tokens, strings.ToLower(statement[i:end]))
+			i = end
+		default:
+			tokens = append(tokens, statement[i:i+1])
+			i++
+		}
+	}
+	return tokens
+}
+
+func isIdentifierChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_'
+}
+
+func isKeyword(token string) bool {
+	for i := 0; i < len(token); i++ {
+		if c := token[i]; c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return len(token) > 0
+}
+
+func isName(token string) bool {
+	if len(token) == 0 {
+		return false
+	}
+	if token[0] == '"' {
+		return len(token) > 1
+	}
+	return isIdentifierChar(token[0])
+}
+
+func unquote(name string) string {
+	if len(name) > 0 && name[0] == '"' {
+		return strings.ReplaceAll(name[1:], `""`, `"`)
+	}
+	return name
+}
@@ -0,0 +1,62 @@
+package protocol
+
+// ConnectionStates keeps the states of a connection which are needed to parse its later
+// messages, like the header tables of HTTP/2. The message pairs of a connection are parsed
+// one by one in order, so the states are never used concurrently.
+//
+// All methods are safe to be called on a nil *ConnectionStates, which means the states are
+// not kept for the connection. The parsers should still work without them.
+type ConnectionStates struct {
+	values map[string]interface{}
+	missed int
+}
+
+func NewConnectionStates() *ConnectionStates {
+	return &ConnectionStates{}
+}
+
+// Get returns nil if there is no state with the key.
+func (states *ConnectionStates) Get(key string) interface{} {
+	if states == nil {
+		return nil
+	}
+	return states.values[key]
+}
+
+func (states *ConnectionStates) Set(key string, value interface{}) {
+	if states == nil {
+		return
+	}
+	if states.values == nil {
+		states.values = make(map[string]interface{})
+	}
+	states.values[key] = value
+}
+
+func (states *ConnectionStates) Delete(key string) {
+	if states == nil {
+		return
+	}
+	delete(states.values, key)
+}
+
+func (states *ConnectionStates) IsEmpty() bool {
+	return states == nil || len(states.values) == 0
+}
+
+// AddMissed records that some messages of the connection are not parsed.
+func (states *ConnectionStates) AddMissed() {
+	if states == nil {
+		return
+	}
+	states.missed++
+}
+
+// Missed returns how many times the messages of the connection are missed. The parsers whose
+// states depend on every message should reset their states once it changes.
+func (states *ConnectionStates) Missed() int {
+	if states == nil {
+		return 0
+	}
+	return states.missed
+}
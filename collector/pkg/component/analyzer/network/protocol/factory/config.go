@@ -1,7 +1,10 @@
 package factory
 
+import "github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/http"
+
 type config struct {
 	urlClusteringMethod string
+	httpLabels          []http.Label
 }
 
 func newDefaultConfig() *config {
@@ -17,3 +20,10 @@ func WithUrlClusteringMethod(urlClusteringMethod string) Option {
 		cfg.urlClusteringMethod = urlClusteringMethod
 	}
 }
+
+// WithHttpLabels sets the labels extracted from the HTTP messages.
+func WithHttpLabels(labels []http.Label) Option {
+	return func(cfg *config) {
+		cfg.httpLabels = labels
+	}
+}
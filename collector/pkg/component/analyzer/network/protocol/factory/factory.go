@@ -4,13 +4,24 @@ import (
 	"sync"
 
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/amqp"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/cassandra"
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/dns"
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/dubbo"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/fastcgi"
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/generic"
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/http"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/http2"
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/kafka"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/memcached"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/mongodb"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/mqtt"
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/mysql"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/postgresql"
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/redis"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/rocketmq"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/thrift"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/zookeeper"
 )
 
 type ParserFactory struct {
@@ -30,11 +41,22 @@ func NewParserFactory(options ...Option) *ParserFactory {
 	for _, option := range options {
 		option(factory.config)
 	}
-	factory.protocolParsers[protocol.HTTP] = http.NewHttpParser(factory.config.urlClusteringMethod)
+	factory.protocolParsers[protocol.HTTP] = http.NewHttpParser(factory.config.urlClusteringMethod, factory.config.httpLabels...)
+	factory.protocolParsers[protocol.HTTP2] = http2.NewHttp2Parser(factory.config.urlClusteringMethod)
+	factory.protocolParsers[protocol.FASTCGI] = fastcgi.NewFastcgiParser(factory.config.urlClusteringMethod)
 	factory.protocolParsers[protocol.KAFKA] = kafka.NewKafkaParser()
+	factory.protocolParsers[protocol.ROCKETMQ] = rocketmq.NewRocketmqParser()
+	factory.protocolParsers[protocol.AMQP] = amqp.NewAmqpParser()
+	factory.protocolParsers[protocol.MQTT] = mqtt.NewMqttParser()
 	factory.protocolParsers[protocol.MYSQL] = mysql.NewMysqlParser()
+	factory.protocolParsers[protocol.POSTGRESQL] = postgresql.NewPostgresqlParser()
+	factory.protocolParsers[protocol.MONGODB] = mongodb.NewMongodbParser()
+	factory.protocolParsers[protocol.CASSANDRA] = cassandra.NewCassandraParser()
 	factory.protocolParsers[protocol.REDIS] = redis.NewRedisParser()
+	factory.protocolParsers[protocol.MEMCACHED] = memcached.NewMemcachedParser()
+	factory.protocolParsers[protocol.ZOOKEEPER] = zookeeper.NewZookeeperParser()
 	factory.protocolParsers[protocol.DUBBO] = dubbo.NewDubboParser()
+	factory.protocolParsers[protocol.THRIFT] = thrift.NewThriftParser()
 	factory.protocolParsers[protocol.DNS] = dns.NewDnsParser()
 	factory.protocolParsers[protocol.NOSUPPORT] = generic.NewGenericParser()
 
@@ -57,45 +79,43 @@ func (f *ParserFactory) GetCachedParsersByPort(port uint32) ([]*protocol.Protoco
 	return parser, ok
 }
 
+// AddCachedParser caches the parser for the port, keeping the generic parser last. The slices
+// returned by GetCachedParsersByPort are copied rather than modified, because they may be in use
+// by other goroutines.
 func (f *ParserFactory) AddCachedParser(port uint32, parser *protocol.ProtocolParser) {
 	f.mutex.Lock()
-	if val := f.cachePortParsersMap[port]; val == nil {
-		parsers := make([]*protocol.ProtocolParser, 0)
-		parsers = append(parsers, parser)
-		f.cachePortParsersMap[port] = parsers
-	} else {
-		exist := false
-		for _, value := range val {
-			if value == parser {
-				exist = true
-				break
-			}
-		}
-		genericParser := f.GetGenericParser()
-		if !exist {
-			// Make sure Generic is last
-			if len(val) > 0 && val[len(val)-1] == genericParser {
-				parsers := append(val[0:len(val)-1], parser)
-				parsers = append(parsers, genericParser)
-				f.cachePortParsersMap[port] = parsers
-			} else {
-				parsers := append(val, parser)
-				f.cachePortParsersMap[port] = parsers
-			}
+	defer f.mutex.Unlock()
+	val := f.cachePortParsersMap[port]
+	for _, value := range val {
+		if value == parser {
+			return
 		}
 	}
-	f.mutex.Unlock()
+	parsers := make([]*protocol.ProtocolParser, 0, len(val)+1)
+	genericParser := f.GetGenericParser()
+	// Make sure Generic is last
+	if len(val) > 0 && val[len(val)-1] == genericParser {
+		parsers = append(parsers, val[:len(val)-1]...)
+		parsers = append(parsers, parser, genericParser)
+	} else {
+		parsers = append(parsers, val...)
+		parsers = append(parsers, parser)
+	}
+	f.cachePortParsersMap[port] = parsers
 }
 
 func (f *ParserFactory) RemoveCachedParser(port uint32, parser *protocol.ProtocolParser) {
 	f.mutex.Lock()
-	if val, ok := f.cachePortParsersMap[port]; ok {
-		for i, value := range val {
-			if value == parser {
-				val = append(val[:i], val[i+1:]...)
-				f.cachePortParsersMap[port] = val
-			}
+	defer f.mutex.Unlock()
+	val, ok := f.cachePortParsersMap[port]
+	if !ok {
+		return
+	}
+	parsers := make([]*protocol.ProtocolParser, 0, len(val))
+	for _, value := range val {
+		if value != parser {
+			parsers = append(parsers, value)
 		}
 	}
-	f.mutex.Unlock()
+	f.cachePortParsersMap[port] = parsers
 }
@@ -0,0 +1,126 @@
+package fastcgi
+
+import (
+	"encoding/binary"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/urlclustering"
+)
+
+const (
+	version1     = 1
+	headerLength = 8
+
+	typeBeginRequest = 1
+	typeAbortRequest = 2
+	typeEndRequest   = 3
+	typeParams       = 4
+	typeStdin        = 5
+	typeStdout       = 6
+	typeStderr       = 7
+
+	roleResponder  = 1
+	roleAuthorizer = 2
+	roleFilter     = 3
+
+	beginRequestBodyLength = 8
+	endRequestBodyLength   = 8
+
+	// statusRequestComplete is the protocolStatus of END_REQUEST when the request is served.
+	statusRequestComplete = 0
+)
+
+var protocolStatuses = map[int64]string{
+	0: "REQUEST_COMPLETE",
+	1: "CANT_MPX_CONN",
+	2: "OVERLOADED",
+	3: "UNKNOWN_ROLE",
+}
+
+/*
+NewFastcgiParser parses the records of FastCGI, which is mostly used between the web servers
+and PHP-FPM.
+
+	Request                                 Response
+	BEGIN_REQUEST, PARAMS..., STDIN...      STDOUT..., STDERR..., END_REQUEST
+
+REQUEST_URI in PARAMS is clustered as the content key like HTTP. The response code is the
+Status header in STDOUT, which is 200 if it is absent, and the statuses of END_REQUEST are kept
+if the record is captured. The web servers send one request at a time on a connection, so the
+requests and responses are paired in order. Only the connections over TCP are parsed, because
+the events of the Unix domain sockets don't reach the parsers.
+*/
+func NewFastcgiParser(urlClusteringMethod string) *protocol.ProtocolParser {
+	var method urlclustering.ClusteringMethod
+	switch urlClusteringMethod {
+	case "alphabet":
+		method = urlclustering.NewAlphabeticalClusteringMethod()
+	case "noparam":
+		method = urlclustering.NewNoParamClusteringMethod()
+	default:
+		method = urlclustering.NewAlphabeticalClusteringMethod()
+	}
+	requestParser := protocol.CreatePkgParser(fastfailFastcgiRequest(), parseFastcgiRequest(method))
+	responseParser := protocol.CreatePkgParser(fastfailFastcgiResponse(), parseFastcgiResponse())
+
+	return protocol.NewProtocolParser(protocol.FASTCGI, requestParser, responseParser, nil)
+}
+
+type record struct {
+	recordType int
+	requestId  uint16
+	// content may be truncated if it is the last record captured.
+	content []byte
+}
+
+/*
+readRecords reads the records until the data ends or an invalid header is met.
+
+	byte     version
+	byte     type
+	uint16   requestId
+	uint16   contentLength
+	byte     paddingLength
+	byte     reserved
+	bytes    contentData and paddingData
+*/
+func readRecords(data []byte) []record {
+	var records []record
+	for len(data) >= headerLength && data[0] == version1 {
+		r := record{
+			recordType: int(data[1]),
+			requestId:  binary.BigEndian.Uint16(data[2:]),
+		}
+		contentLength := int(binary.BigEndian.Uint16(data[4:]))
+		paddingLength := int(data[6])
+		data = data[headerLength:]
+		if len(data) < contentLength {
+			r.content = data
+			return append(records, r)
+		}
+		r.content = data[:contentLength]
+		records = append(records, r)
+		if len(data) < contentLength+paddingLength {
+			return records
+		}
+		data = data[contentLength+paddingLength:]
+	}
+	return records
+}
+
+// readStream joins the contents of the records of the type, because a stream like PARAMS or
+// STDOUT may be split into several records, which may be interleaved with STDERR.
+func readStream(records []record, recordType int) []byte {
+	var stream []byte
+	for _, r := range records {
+		if r.recordType != recordType {
+			continue
+		}
+		if len(r.content) == 0 {
+			// The empty record ends the stream.
+			break
+		}
+		stream = append(stream, r.content...)
+	}
+	return stream
+}
@@ -0,0 +1,196 @@
+package fastcgi
+
+import (
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/internal/testutil"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func newRecord(recordType byte, requestId uint16, content []byte, padding int) []byte {
+	return testutil.Join([]byte{version1, recordType}, testutil.Int16(int(requestId)),
+		testutil.Int16(len(content)), []byte{byte(padding), 0}, content, make([]byte, padding))
+}
+
+func beginRequest(role uint16) []byte {
+	return newRecord(typeBeginRequest, 1, testutil.Join(testutil.Int16(int(role)), make([]byte, 6)), 0)
+}
+
+func endRequest(appStatus uint32, protocolStatus byte) []byte {
+	return newRecord(typeEndRequest, 1, testutil.Join(testutil.Int32(int(appStatus)), []byte{protocolStatus, 0, 0, 0}), 0)
+}
+
+func param(name string, value string) []byte {
+	var data []byte
+	for _, length := range []int{len(name), len(value)} {
+		if length < 128 {
+			data = append(data, byte(length))
+		} else {
+			data = append(data, byte(length>>24)|0x80, byte(length>>16), byte(length>>8), byte(length))
+		}
+	}
+	data = append(data, name...)
+	return append(data, value...)
+}
+
+func request(params ...[]byte) []byte {
+	return testutil.Join(beginRequest(roleResponder), newRecord(typeParams, 1, testutil.Join(params...), 0),
+		newRecord(typeParams, 1, nil, 0), newRecord(typeStdin, 1, nil, 0))
+}
+
+func TestParseFastcgi(t *testing.T) {
+	parser := NewFastcgiParser("alphabet")
+	longValue := string(make([]byte, 200))
+	tests := []struct {
+		name           string
+		request        []byte
+		response       []byte
+		contentKey     string
+		method         string
+		uri            string
+		scriptFilename string
+		statusCode     int64
+		protocolStatus int64
+		isError        bool
+		errorMsg       string
+	}{
+		{
+			name: "get",
+			request: request(param("SCRIPT_FILENAME", "/var/www/html/index.php"), param("REQUEST_METHOD", "GET"),
+				param("REQUEST_URI", "/users/123?page=1")),
+			response: testutil.Join(newRecord(typeStdout, 1, []byte("Content-type: text/html; charset=UTF-8\r\n\r\nhello"), 3),
+				newRecord(typeStdout, 1, nil, 0), endRequest(0, statusRequestComplete)),
+			contentKey:     "/users/*",
+			method:         "GET",
+			uri:            "/users/123?page=1",
+			scriptFilename: "/var/www/html/index.php",
+			statusCode:     200,
+		},
+		{
+			name: "status header",
+			request: request(param("REQUEST_METHOD", "POST"), param("REQUEST_URI", "/login"),
+				param("HTTP_COOKIE", longValue)),
+			response: testutil.Join(newRecord(typeStdout, 1, []byte("X-Powered-By: PHP/8.1\r\nStatus: 302 Found\r\nLocation: /\r\n\r\n"), 0),
+				endRequest(0, statusRequestComplete)),
+			contentKey: "/login",
+			method:     "POST",
+			uri:        "/login",
+			statusCode: 302,
+		},
+		{
+			name:    "primary script unknown",
+			request: request(param("REQUEST_METHOD", "GET"), param("REQUEST_URI", "/missing.php")),
+			response: testutil.Join(newRecord(typeStderr, 1, []byte("Primary script unknown"), 2),
+				newRecord(typeStdout, 1, []byte("Status: 404 Not Found\r\nContent-type: text/html\r\n\r\nFile not found.\n"), 0),
+				endRequest(0, statusRequestComplete)),
+			contentKey: "/*",
+			method:     "GET",
+			uri:        "/missing.php",
+			statusCode: 404,
+			isError:    true,
+			errorMsg:   "Primary script unknown",
+		},
+		{
+			name:           "overloaded",
+			request:        request(param("REQUEST_METHOD", "GET"), param("REQUEST_URI", "/")),
+			response:       endRequest(0, 2),
+			contentKey:     "/",
+			method:         "GET",
+			uri:            "/",
+			protocolStatus: 2,
+			isError:        true,
+			errorMsg:       "OVERLOADED",
+		},
+		{
+			name:       "truncated stdout",
+			request:    request(param("REQUEST_METHOD", "GET"), param("REQUEST_URI", "/")),
+			response:   newRecord(typeStdout, 1, []byte("Status: 500 Internal Server Error\r\nContent-type: text/html\r\n\r\n"), 0)[:30],
+			contentKey: "/",
+			method:     "GET",
+			uri:        "/",
+			statusCode: 500,
+			isError:    true,
+		},
+		{
+			name:       "no uri",
+			request:    request(param("REQUEST_METHOD", "GET")),
+			response:   newRecord(typeStdout, 1, []byte("\r\n"), 0),
+			contentKey: "*",
+			method:     "GET",
+			statusCode: 200,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := protocol.NewRequestMessage(tt.request)
+			if !parser.ParseRequest(request) {
+				t.Fatalf("failed to parse the request")
+			}
+			message := protocol.NewResponseMessage(tt.response, request.GetAttributes())
+			if !parser.ParseResponse(message) {
+				t.Fatalf("failed to parse the response")
+			}
+			if got := message.GetStringAttribute(constlabels.ContentKey); got != tt.contentKey {
+				t.Errorf("content key = %q, want %q", got, tt.contentKey)
+			}
+			if got := message.GetStringAttribute(constlabels.FastcgiMethod); got != tt.method {
+				t.Errorf("method = %q, want %q", got, tt.method)
+			}
+			if got := message.GetStringAttribute(constlabels.FastcgiRequestUri); got != tt.uri {
+				t.Errorf("uri = %q, want %q", got, tt.uri)
+			}
+			if got := message.GetStringAttribute(constlabels.FastcgiScriptFilename); got != tt.scriptFilename {
+				t.Errorf("script filename = %q, want %q", got, tt.scriptFilename)
+			}
+			if got := message.GetIntAttribute(constlabels.FastcgiStatusCode); got != tt.statusCode {
+				t.Errorf("status code = %d, want %d", got, tt.statusCode)
+			}
+			if got := message.GetIntAttribute(constlabels.FastcgiProtocolStatus); got != tt.protocolStatus {
+				t.Errorf("protocol status = %d, want %d", got, tt.protocolStatus)
+			}
+			if got := message.GetBoolAttribute(constlabels.IsError); got != tt.isError {
+				t.Errorf("is_error = %v, want %v", got, tt.isError)
+			}
+			if got := message.GetStringAttribute(constlabels.FastcgiErrorMsg); got != tt.errorMsg {
+				t.Errorf("error msg = %q, want %q", got, tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestParseRequest_TruncatedParams(t *testing.T) {
+	parser := NewFastcgiParser("noparam")
+	data := request(param("REQUEST_METHOD", "GET"), param("REQUEST_URI", "/orders/list?id=1"))
+	message := protocol.NewRequestMessage(data[:len(data)-len("st?id=1")-16])
+	if !parser.ParseRequest(message) {
+		t.Fatalf("failed to parse the request")
+	}
+	if got := message.GetStringAttribute(constlabels.FastcgiRequestUri); got != "/orders/li" {
+		t.Errorf("uri = %q, want /orders/li", got)
+	}
+	if got := message.GetStringAttribute(constlabels.ContentKey); got != "/orders/li" {
+		t.Errorf("content key = %q, want /orders/li", got)
+	}
+}
+
+func TestParseRequest_NotFastcgi(t *testing.T) {
+	parser := NewFastcgiParser("alphabet")
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "http", data: []byte("GET /index.html HTTP/1.1\r\n\r\n")},
+		{name: "params first", data: testutil.Join(newRecord(typeParams, 1, param("REQUEST_METHOD", "GET"), 0), beginRequest(roleResponder))},
+		{name: "unknown role", data: testutil.Join(beginRequest(4), newRecord(typeParams, 1, nil, 0))},
+		{name: "management record", data: testutil.Join(newRecord(typeBeginRequest, 0, make([]byte, 8), 0), newRecord(typeParams, 0, nil, 0))},
+		{name: "unknown version", data: append([]byte{2}, beginRequest(roleResponder)[1:]...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if parser.ParseRequest(protocol.NewRequestMessage(tt.data)) {
+				t.Errorf("the data should not be parsed as FastCGI")
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package fastcgi
+
+import (
+	"encoding/binary"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+	"github.com/Kindling-project/kindling/collector/pkg/urlclustering"
+)
+
+func fastfailFastcgiRequest() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < headerLength+beginRequestBodyLength
+	}
+}
+
+/*
+The request starts with BEGIN_REQUEST, which is followed by the PARAMS stream.
+
+	BEGIN_REQUEST   uint16 role, byte flags and 5 reserved bytes
+	PARAMS          the name-value pairs like the CGI environment variables
+*/
+func parseFastcgiRequest(urlClusteringMethod urlclustering.ClusteringMethod) protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		records := readRecords(message.Data)
+		if len(records) == 0 || records[0].recordType != typeBeginRequest || records[0].requestId == 0 {
+			return false, true
+		}
+		begin := records[0].content
+		if len(begin) != beginRequestBodyLength {
+			return false, true
+		}
+		role := binary.BigEndian.Uint16(begin)
+		if role != roleResponder && role != roleAuthorizer && role != roleFilter {
+			return false, true
+		}
+
+		params := readParams(readStream(records, typeParams))
+		if method := params["REQUEST_METHOD"]; len(method) > 0 {
+			message.AddStringAttribute(constlabels.FastcgiMethod, method)
+		}
+		if filename := params["SCRIPT_FILENAME"]; len(filename) > 0 {
+			message.AddUtf8StringAttribute(constlabels.FastcgiScriptFilename, filename)
+		}
+		uri := params["REQUEST_URI"]
+		if len(uri) > 0 {
+			message.AddUtf8StringAttribute(constlabels.FastcgiRequestUri, uri)
+		}
+		contentKey := urlClusteringMethod.Clustering(uri)
+		if len(contentKey) == 0 {
+			contentKey = "*"
+		}
+		message.AddUtf8StringAttribute(constlabels.ContentKey, contentKey)
+		return true, true
+	}
+}
+
+/*
+readParams reads the name-value pairs. The lengths are 1 byte if they are less than 128, or 4
+bytes with the highest bit set. The last value may be truncated.
+
+	length   the length of the name
+	length   the length of the value
+	bytes    the name
+	bytes    the value
+*/
+func readParams(data []byte) map[string]string {
+	params := make(map[string]string)
+	for len(data) > 0 {
+		nameLength, n := readLength(data)
+		if n == 0 {
+			return params
+		}
+		data = data[n:]
+		valueLength, n := readLength(data)
+		if n == 0 || len(data) < n+nameLength {
+			return params
+		}
+		name := string(data[n : n+nameLength])
+		data = data[n+nameLength:]
+		if len(data) < valueLength {
+			params[name] = string(data)
+			return params
+		}
+		params[name] = string(data[:valueLength])
+		data = data[valueLength:]
+	}
+	return params
+}
+
+// readLength returns the length and the number of bytes read, which is 0 if it fails.
+func readLength(data []byte) (int, int) {
+	if len(data) < 1 {
+		return 0, 0
+	}
+	if data[0]&0x80 == 0 {
+		return int(data[0]), 1
+	}
+	if len(data) < 4 {
+		return 0, 0
+	}
+	return int(binary.BigEndian.Uint32(data) & 0x7fffffff), 4
+}
@@ -0,0 +1,109 @@
+package fastcgi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func fastfailFastcgiResponse() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < headerLength
+	}
+}
+
+/*
+The response is the STDOUT and STDERR streams ended with END_REQUEST.
+
+	STDOUT        the CGI response, which is the headers like "Status: 404 Not Found" and the body
+	STDERR        the error logs of the application
+	END_REQUEST   uint32 appStatus, byte protocolStatus and 3 reserved bytes
+*/
+func parseFastcgiResponse() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		records := readRecords(message.Data)
+		if len(records) == 0 || records[0].requestId == 0 {
+			return false, true
+		}
+		if t := records[0].recordType; t != typeStdout && t != typeStderr && t != typeEndRequest {
+			return false, true
+		}
+
+		var statusCode int64
+		if stdout := readStream(records, typeStdout); len(stdout) > 0 {
+			statusCode = readStatus(stdout)
+			message.AddIntAttribute(constlabels.FastcgiStatusCode, statusCode)
+		}
+		isError := statusCode >= 400
+		var errorMsg string
+		for _, r := range records {
+			if r.recordType != typeEndRequest || len(r.content) < endRequestBodyLength {
+				continue
+			}
+			appStatus := int64(binary.BigEndian.Uint32(r.content))
+			protocolStatus := int64(r.content[4])
+			message.AddIntAttribute(constlabels.FastcgiAppStatus, appStatus)
+			message.AddIntAttribute(constlabels.FastcgiProtocolStatus, protocolStatus)
+			if protocolStatus != statusRequestComplete {
+				isError = true
+				errorMsg = protocolStatuses[protocolStatus]
+			} else if appStatus != 0 {
+				isError = true
+			}
+			break
+		}
+		if !isError {
+			return true, true
+		}
+		if len(errorMsg) == 0 {
+			errorMsg = firstLine(readStream(records, typeStderr))
+		}
+		message.AddBoolAttribute(constlabels.IsError, true)
+		message.AddIntAttribute(constlabels.ErrorType, int64(constlabels.ProtocolError))
+		if len(errorMsg) > 0 {
+			message.AddUtf8StringAttribute(constlabels.FastcgiErrorMsg, errorMsg)
+		}
+		return true, true
+	}
+}
+
+// readStatus reads the Status header of the CGI response, which is 200 if it is absent.
+func readStatus(stdout []byte) int64 {
+	for len(stdout) > 0 {
+		var line []byte
+		if end := bytes.IndexByte(stdout, '\n'); end >= 0 {
+			line, stdout = stdout[:end], stdout[end+1:]
+		} else {
+			line, stdout = stdout, nil
+		}
+		line = bytes.TrimRight(line, "\r")
+		if len(line) == 0 {
+			// The headers end.
+			break
+		}
+		colon := bytes.IndexByte(line, ':')
+		if colon < 0 || !bytes.EqualFold(line[:colon], []byte("Status")) {
+			continue
+		}
+		value := bytes.TrimLeft(line[colon+1:], " \t")
+		if len(value) < 3 {
+			return 0
+		}
+		code, err := strconv.ParseInt(string(value[:3]), 10, 0)
+		if err != nil || code < 100 || code > 999 {
+			return 0
+		}
+		return code
+	}
+	return 200
+}
+
+func firstLine(data []byte) string {
+	if end := bytes.IndexAny(data, "\r\n"); end >= 0 {
+		data = data[:end]
+	}
+	return string(data)
+}
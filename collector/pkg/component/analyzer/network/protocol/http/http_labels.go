@@ -0,0 +1,192 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+)
+
+// maxLabelValueLength limits the values extracted as labels.
+const maxLabelValueLength = 128
+
+// Label is a value extracted from the HTTP messages, which is added as the attribute Name.
+// It is read from either the header or the JSON body.
+type Label struct {
+	Name string
+	// Header is the name of the header, which is case-insensitive.
+	Header string
+	// JsonPath is the path of the field in the JSON body, like "$.operationName".
+	JsonPath string
+	// Response tells the value is read from the response instead of the request.
+	Response bool
+}
+
+type labelExtractor struct {
+	name     string
+	header   string
+	path     []string
+	response bool
+}
+
+func newLabelExtractors(labels []Label) []labelExtractor {
+	extractors := make([]labelExtractor, 0, len(labels))
+	for _, label := range labels {
+		extractor := labelExtractor{
+			name:     label.Name,
+			header:   strings.ToLower(label.Header),
+			response: label.Response,
+		}
+		if len(label.JsonPath) > 0 {
+			path, err := ParseJsonPath(label.JsonPath)
+			if err != nil {
+				continue
+			}
+			extractor.path = path
+		}
+		extractors = append(extractors, extractor)
+	}
+	return extractors
+}
+
+// ParseJsonPath splits the path into the keys of the nested objects. Only the keys separated by
+// dots are supported, and the leading "$." is optional.
+func ParseJsonPath(path string) ([]string, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if len(path) == 0 {
+		return nil, fmt.Errorf("empty json path")
+	}
+	keys := strings.Split(path, ".")
+	for _, key := range keys {
+		if len(key) == 0 || strings.ContainsAny(key, "[]*") {
+			return nil, fmt.Errorf("unsupported json path: %s", path)
+		}
+	}
+	return keys, nil
+}
+
+/*
+extractLabels adds the values of the labels of the request or the response. The body is read
+from bodyStart to end only if it is framed by Content-Length, and it could be truncated after
+the extracted fields.
+*/
+func extractLabels(message *protocol.PayloadMessage, extractors []labelExtractor, response bool,
+	headers map[string]string, bodyStart int, end int) {
+	var body []byte
+	for i := range extractors {
+		extractor := &extractors[i]
+		if extractor.response != response {
+			continue
+		}
+		var value string
+		if extractor.path == nil {
+			value = headers[extractor.header]
+		} else {
+			if body == nil {
+				body = jsonBody(message, headers, bodyStart, end)
+			}
+			value = jsonField(body, extractor.path)
+		}
+		if len(value) > maxLabelValueLength {
+			value = truncateLabelValue(value)
+		}
+		if len(value) > 0 {
+			message.AddUtf8StringAttribute(extractor.name, value)
+		}
+	}
+}
+
+// truncateLabelValue cuts the value to maxLabelValueLength bytes at most, without splitting a
+// multi-byte UTF-8 character.
+func truncateLabelValue(value string) string {
+	end := maxLabelValueLength
+	for end > 0 && !utf8.RuneStart(value[end]) {
+		end--
+	}
+	return value[:end]
+}
+
+func jsonBody(message *protocol.PayloadMessage, headers map[string]string, bodyStart int, end int) []byte {
+	if bodyStart == protocol.EOF || bodyStart >= len(message.Data) {
+		return []byte{}
+	}
+	if _, ok := headers["transfer-encoding"]; ok {
+		return []byte{}
+	}
+	if end > len(message.Data) || end < bodyStart {
+		end = len(message.Data)
+	}
+	return message.Data[bodyStart:end]
+}
+
+// jsonField returns the scalar at the path of the JSON object, or an empty string if it is not
+// found. The tokens following the field are not read.
+func jsonField(data []byte, path []string) string {
+	if len(data) == 0 {
+		return ""
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	for _, key := range path {
+		if !seekKey(decoder, key) {
+			return ""
+		}
+	}
+	token, err := decoder.Token()
+	if err != nil {
+		return ""
+	}
+	switch value := token.(type) {
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case bool:
+		return strconv.FormatBool(value)
+	}
+	return ""
+}
+
+// seekKey reads the object starting at the next token till the key.
+func seekKey(decoder *json.Decoder, key string) bool {
+	token, err := decoder.Token()
+	if err != nil || token != json.Delim('{') {
+		return false
+	}
+	for decoder.More() {
+		token, err = decoder.Token()
+		if err != nil {
+			return false
+		}
+		if name, ok := token.(string); ok && name == key {
+			return true
+		}
+		if !skipValue(decoder) {
+			return false
+		}
+	}
+	return false
+}
+
+func skipValue(decoder *json.Decoder) bool {
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return true
+		}
+	}
+}
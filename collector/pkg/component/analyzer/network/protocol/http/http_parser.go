@@ -1,13 +1,17 @@
 package http
 
 import (
+	"bytes"
+	"strconv"
 	"strings"
 
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
 	"github.com/Kindling-project/kindling/collector/pkg/urlclustering"
 )
 
-func NewHttpParser(urlClusteringMethod string) *protocol.ProtocolParser {
+// NewHttpParser creates the parser of HTTP/1.x. The values of the labels are extracted from the
+// messages as their attributes.
+func NewHttpParser(urlClusteringMethod string, labels ...Label) *protocol.ProtocolParser {
 	var method urlclustering.ClusteringMethod
 	switch urlClusteringMethod {
 	case "alphabet":
@@ -17,12 +21,17 @@ func NewHttpParser(urlClusteringMethod string) *protocol.ProtocolParser {
 	default:
 		method = urlclustering.NewAlphabeticalClusteringMethod()
 	}
-	requestParser := protocol.CreatePkgParser(fastfailHttpRequest(), parseHttpRequest(method))
-	responseParser := protocol.CreatePkgParser(fastfailHttpResponse(), parseHttpResponse())
+	extractors := newLabelExtractors(labels)
+	requestParser := protocol.CreatePkgParser(fastfailHttpRequest(), parseHttpRequest(method, extractors))
+	responseParser := protocol.CreatePkgParser(fastfailHttpResponse(), parseHttpResponse(extractors))
 
-	return protocol.NewProtocolParser(protocol.HTTP, requestParser, responseParser, nil)
+	parser := protocol.NewProtocolParser(protocol.HTTP, requestParser, responseParser, nil)
+	parser.EnablePipelining()
+	return parser
 }
 
+var crlf = []byte("\r\n")
+
 /*
 Requet-Line\r\n
 Key:Value\r\n
@@ -30,23 +39,106 @@ Key:Value\r\n
 Key:Value\r\n
 \r\n
 Data
+
+The headers are read from the start line at message.Offset. The offset where the body starts is
+returned, or EOF if the headers are not complete in the captured data.
 */
-func parseHeaders(message *protocol.PayloadMessage) map[string]string {
+func parseHeaders(message *protocol.PayloadMessage) (map[string]string, int) {
 	header := make(map[string]string)
 
-	from, data := message.ReadUntilCRLF(0)
-	if data == nil {
-		return header
+	_, from, ok := readLine(message.Data, message.Offset)
+	if !ok {
+		return header, protocol.EOF
 	}
 	for {
-		from, data = message.ReadUntilCRLF(from)
-		if data == nil {
-			return header
+		var data []byte
+		data, from, ok = readLine(message.Data, from)
+		if !ok {
+			return header, protocol.EOF
 		}
-		if position := strings.Index(string(data), ":"); position > 0 && position < len(data)-1 {
-			header[strings.ToLower(string(data[0:position]))] = string(data[position+1])
+		if len(data) == 0 {
+			return header, from
+		}
+		if position := bytes.IndexByte(data, ':'); position > 0 {
+			header[strings.ToLower(string(data[0:position]))] = strings.TrimSpace(string(data[position+1:]))
 			continue
 		}
-		return header
+		return header, protocol.EOF
+	}
+}
+
+// readLine returns the line starting at from without "\r\n", and the offset following it. False
+// is returned if the line is not complete.
+func readLine(data []byte, from int) ([]byte, int, bool) {
+	if from >= len(data) {
+		return nil, from, false
+	}
+	end := bytes.Index(data[from:], crlf)
+	if end < 0 {
+		return nil, from, false
+	}
+	return data[from : from+end], from + end + 2, true
+}
+
+/*
+bodyEnd returns the offset where the body starting at bodyStart ends.
+
+	Transfer-Encoding: chunked    Size\r\nData\r\n...0\r\nTrailers\r\n\r\n
+	Content-Length: n             n bytes
+	Neither                       No body for requests, or till the connection is closed
+
+The rest of the message is taken as the body if its end can not be found in the captured data.
+*/
+func bodyEnd(message *protocol.PayloadMessage, headers map[string]string, bodyStart int, tillClose bool) int {
+	rest := len(message.Data)
+	if message.Size > rest {
+		rest = message.Size
+	}
+	if bodyStart == protocol.EOF {
+		return rest
+	}
+	if encoding, ok := headers["transfer-encoding"]; ok && strings.HasSuffix(strings.ToLower(encoding), "chunked") {
+		return chunksEnd(message.Data, bodyStart, rest)
+	}
+	if length, ok := headers["content-length"]; ok {
+		contentLength, err := strconv.Atoi(length)
+		if err != nil || contentLength < 0 {
+			return rest
+		}
+		return bodyStart + contentLength
+	}
+	if tillClose {
+		return rest
+	}
+	return bodyStart
+}
+
+func chunksEnd(data []byte, offset int, rest int) int {
+	for {
+		line, next, ok := readLine(data, offset)
+		if !ok {
+			return rest
+		}
+		if extension := bytes.IndexByte(line, ';'); extension >= 0 {
+			line = line[:extension]
+		}
+		size, err := strconv.ParseInt(string(bytes.TrimSpace(line)), 16, 64)
+		if err != nil || size < 0 || size > int64(rest) {
+			return rest
+		}
+		offset = next
+		if size == 0 {
+			// The trailers end with an empty line.
+			for {
+				line, offset, ok = readLine(data, offset)
+				if !ok {
+					return rest
+				}
+				if len(line) == 0 {
+					return offset
+				}
+			}
+		}
+		offset += int(size) + len(crlf)
 	}
 }
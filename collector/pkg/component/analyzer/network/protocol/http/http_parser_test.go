@@ -1,7 +1,11 @@
 package http
 
 import (
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
 	"github.com/Kindling-project/kindling/collector/pkg/model"
@@ -138,3 +142,238 @@ func TestParseHttpResponse_GetPayLoad(t *testing.T) {
 		})
 	}
 }
+
+func TestParseHttp_Framing(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  bool
+		data     string
+		offset   int
+		method   string
+		size     int
+		wantEnd  int
+		ignored  bool
+		wantCode int64
+	}{
+		{
+			name:    "request without body",
+			request: true,
+			data:    "GET /a HTTP/1.1\r\nHost: localhost\r\n\r\nGET /b HTTP/1.1\r\n\r\n",
+			wantEnd: 36,
+		},
+		{
+			name:    "request at offset",
+			request: true,
+			data:    "GET /a HTTP/1.1\r\nHost: localhost\r\n\r\nPOST /b HTTP/1.1\r\nContent-Length: 4\r\n\r\nbodyGET /c HTTP/1.1\r\n\r\n",
+			offset:  36,
+			wantEnd: 79,
+		},
+		{
+			name:    "request body in following events",
+			request: true,
+			data:    "POST /b HTTP/1.1\r\nContent-Length: 1000\r\n\r\n",
+			wantEnd: 1042,
+		},
+		{
+			name:     "content length",
+			data:     "HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nokHTTP/1.1 200 OK\r\n",
+			wantEnd:  40,
+			wantCode: 200,
+		},
+		{
+			name:     "chunked",
+			data:     "HTTP/1.1 200 OK\r\nTransfer-Encoding: gzip, chunked\r\n\r\n4;ext=1\r\nwiki\r\n5\r\npedia\r\n0\r\nExpires: 0\r\n\r\nHTTP/1.1",
+			wantEnd:  95,
+			wantCode: 200,
+		},
+		{
+			name:     "chunks truncated",
+			data:     "HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\n\r\n400\r\nxxxx",
+			size:     2000,
+			wantEnd:  2000,
+			wantCode: 200,
+		},
+		{
+			name:     "till close",
+			data:     "HTTP/1.0 200 OK\r\nConnection: close\r\n\r\nbody",
+			wantEnd:  42,
+			wantCode: 200,
+		},
+		{
+			name:     "head",
+			data:     "HTTP/1.1 200 OK\r\nContent-Length: 100\r\n\r\n",
+			method:   "HEAD",
+			wantEnd:  40,
+			wantCode: 200,
+		},
+		{
+			name:     "not modified",
+			data:     "HTTP/1.1 304 Not Modified\r\nETag: \"abc\"\r\n\r\nHTTP/1.1 200 OK\r\n",
+			wantEnd:  42,
+			wantCode: 304,
+		},
+		{
+			name:    "continue",
+			data:    "HTTP/1.1 100 Continue\r\n\r\nHTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n",
+			wantEnd: 25,
+			ignored: true,
+		},
+	}
+	parser := NewHttpParser("")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var message *protocol.PayloadMessage
+			if tt.request {
+				message = protocol.NewRequestMessage([]byte(tt.data))
+			} else {
+				attributes := model.NewAttributeMap()
+				if len(tt.method) > 0 {
+					attributes.AddStringValue(constlabels.HttpMethod, tt.method)
+				}
+				message = protocol.NewResponseMessage([]byte(tt.data), attributes)
+			}
+			message.Offset = tt.offset
+			message.Size = tt.size
+			var ok bool
+			if tt.request {
+				ok = parser.ParseRequest(message)
+			} else {
+				ok = parser.ParseResponse(message)
+			}
+			if !ok {
+				t.Fatalf("failed to parse the message")
+			}
+			if message.Offset != tt.wantEnd {
+				t.Errorf("end = %d, want %d", message.Offset, tt.wantEnd)
+			}
+			if message.IsIgnored() != tt.ignored {
+				t.Errorf("ignored = %v, want %v", message.IsIgnored(), tt.ignored)
+			}
+			if got := message.GetIntAttribute(constlabels.HttpStatusCode); got != tt.wantCode {
+				t.Errorf("status code = %d, want %d", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestParseHttpRequest_TraceHeader(t *testing.T) {
+	message := protocol.NewRequestMessage([]byte("GET /test HTTP/1.1\r\nX-B3-TraceId: 80f198ee56343ba8\r\n\r\n"))
+	if !NewHttpParser("").ParseRequest(message) {
+		t.Fatalf("failed to parse the request")
+	}
+	if got := message.GetStringAttribute(constlabels.HttpApmTraceId); got != "80f198ee56343ba8" {
+		t.Errorf("trace id = %q, want %q", got, "80f198ee56343ba8")
+	}
+}
+
+func TestParseHttp_Labels(t *testing.T) {
+	parser := NewHttpParser("",
+		Label{Name: "tenant", Header: "X-Tenant-Id"},
+		Label{Name: "host", Header: "host"},
+		Label{Name: "operation", JsonPath: "$.operationName"},
+		Label{Name: "user_id", JsonPath: "variables.user.id"},
+		Label{Name: "content_type", Header: "Content-Type", Response: true},
+		Label{Name: "code", JsonPath: "errors.code", Response: true},
+	)
+	requestBody := `{"query": "query GetUser($id: ID!) { user(id: $id) { name } }", "variables": {"tags": [1, {"id": 2}], "user": {"id": 42}}, "operationName": "GetUser"}`
+	request := protocol.NewRequestMessage([]byte("POST /graphql HTTP/1.1\r\nHost: example.com\r\nX-Tenant-Id:  acme \r\n" +
+		"Content-Length: " + strconv.Itoa(len(requestBody)) + "\r\n\r\n" + requestBody))
+	if !parser.ParseRequest(request) {
+		t.Fatalf("failed to parse the request")
+	}
+	// The response body is truncated after the extracted field.
+	response := protocol.NewResponseMessage([]byte("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Length: 1000\r\n\r\n"+
+		`{"errors": {"code": "NOT_FOUND", "message": "user`), request.GetAttributes())
+	if !parser.ParseResponse(response) {
+		t.Fatalf("failed to parse the response")
+	}
+	want := map[string]string{
+		"tenant":       "acme",
+		"host":         "example.com",
+		"operation":    "GetUser",
+		"user_id":      "42",
+		"content_type": "application/json",
+		"code":         "NOT_FOUND",
+	}
+	for name, value := range want {
+		if got := response.GetStringAttribute(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestParseHttp_LabelsNotFound(t *testing.T) {
+	parser := NewHttpParser("",
+		Label{Name: "tenant", Header: "X-Tenant-Id"},
+		Label{Name: "operation", JsonPath: "operationName"},
+	)
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "no body", data: "GET /graphql HTTP/1.1\r\n\r\n"},
+		{name: "not json", data: "POST /graphql HTTP/1.1\r\nContent-Length: 11\r\n\r\noperationName"},
+		{name: "not object", data: "POST /graphql HTTP/1.1\r\nContent-Length: 17\r\n\r\n[\"operationName\"]"},
+		{name: "object field", data: "POST /graphql HTTP/1.1\r\nContent-Length: 25\r\n\r\n{\"operationName\": {\"a\": 1}}"},
+		{name: "chunked", data: "POST /graphql HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n1e\r\n{\"operationName\": \"GetUser\"}\r\n0\r\n\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := protocol.NewRequestMessage([]byte(tt.data))
+			if !parser.ParseRequest(message) {
+				t.Fatalf("failed to parse the request")
+			}
+			for _, name := range []string{"tenant", "operation"} {
+				if message.HasAttribute(name) {
+					t.Errorf("%s should not be extracted, but get %q", name, message.GetStringAttribute(name))
+				}
+			}
+		})
+	}
+}
+
+func TestParseHttp_LabelsTruncated(t *testing.T) {
+	parser := NewHttpParser("",
+		Label{Name: "tenant", Header: "X-Tenant-Id"},
+		Label{Name: "operation", JsonPath: "operationName"},
+	)
+	// The 128th byte is in the middle of "é" and "名".
+	tenant := strings.Repeat("a", maxLabelValueLength-1) + "é"
+	operation := strings.Repeat("名", maxLabelValueLength/3+1)
+	body := `{"operationName": "` + operation + `"}`
+	message := protocol.NewRequestMessage([]byte("POST /graphql HTTP/1.1\r\nX-Tenant-Id: " + tenant + "\r\n" +
+		"Content-Length: " + strconv.Itoa(len(body)) + "\r\n\r\n" + body))
+	if !parser.ParseRequest(message) {
+		t.Fatalf("failed to parse the request")
+	}
+	want := map[string]string{
+		"tenant":    tenant[:maxLabelValueLength-1],
+		"operation": strings.Repeat("名", maxLabelValueLength/3),
+	}
+	for name, value := range want {
+		got := message.GetStringAttribute(name)
+		if got != value || !utf8.ValidString(got) {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestParseJsonPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    []string
+		wantErr bool
+	}{
+		{path: "$.operationName", want: []string{"operationName"}},
+		{path: "variables.user.id", want: []string{"variables", "user", "id"}},
+		{path: "$", wantErr: true},
+		{path: "$.a..b", wantErr: true},
+		{path: "$.ids[0]", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseJsonPath(tt.path)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseJsonPath(%q) = %v, %v, want %v", tt.path, got, err, tt.want)
+		}
+	}
+}
@@ -26,10 +26,13 @@ Request line
 	\r\n
 Request header
 Request body
+
+The request starts at message.Offset, and message.Offset is set where it ends.
 */
-func parseHttpRequest(urlClusteringMethod urlclustering.ClusteringMethod) protocol.ParsePkgFn {
+func parseHttpRequest(urlClusteringMethod urlclustering.ClusteringMethod, extractors []labelExtractor) protocol.ParsePkgFn {
 	return func(message *protocol.PayloadMessage) (bool, bool) {
-		offset, method := message.ReadUntilBlankWithLength(message.Offset, 8)
+		start := message.Offset
+		offset, method := message.ReadUntilBlankWithLength(start, 8)
 
 		if !httpMethodsList[string(method)] {
 			if message.Data[offset-1] != ' ' || message.Data[offset] != '/' {
@@ -45,7 +48,7 @@ func parseHttpRequest(urlClusteringMethod urlclustering.ClusteringMethod) protoc
 
 		_, url := message.ReadUntilBlank(offset)
 
-		headers := parseHeaders(message)
+		headers, bodyStart := parseHeaders(message)
 		traceType, traceId := tools.ParseTraceHeader(headers)
 		if len(traceType) > 0 && len(traceId) > 0 {
 			message.AddStringAttribute(constlabels.HttpApmTraceType, traceType)
@@ -54,13 +57,16 @@ func parseHttpRequest(urlClusteringMethod urlclustering.ClusteringMethod) protoc
 
 		message.AddStringAttribute(constlabels.HttpMethod, string(method))
 		message.AddByteArrayUtf8Attribute(constlabels.HttpUrl, url)
-		message.AddByteArrayUtf8Attribute(constlabels.HttpRequestPayload, message.GetData(0, protocol.GetHttpPayLoadLength()))
+		message.AddByteArrayUtf8Attribute(constlabels.HttpRequestPayload, message.GetData(start, protocol.GetHttpPayLoadLength()))
 
 		contentKey := urlClusteringMethod.Clustering(string(url))
 		if len(contentKey) == 0 {
 			contentKey = "*"
 		}
 		message.AddUtf8StringAttribute(constlabels.ContentKey, contentKey)
+		end := bodyEnd(message, headers, bodyStart, false)
+		extractLabels(message, extractors, false, headers, bodyStart, end)
+		message.Offset = end
 		return true, true
 	}
 }
@@ -26,18 +26,19 @@ func fastfailHttpResponse() protocol.FastFailFn {
 			return true
 		}
 		offset, version := message.ReadUntilBlankWithLength(message.Offset, 9)
-		if !httpVersoinList[string(version)] || message.Data[offset-1] != ' ' {
-			return true
-		}
-
-		message.Offset = offset
-		return false
+		return !httpVersoinList[string(version)] || message.Data[offset-1] != ' '
 	}
 }
 
-func parseHttpResponse() protocol.ParsePkgFn {
+/*
+The response starts at message.Offset, and message.Offset is set where it ends. The interim
+responses like "100 Continue" are ignored, which are followed by the final ones.
+*/
+func parseHttpResponse(extractors []labelExtractor) protocol.ParsePkgFn {
 	return func(message *protocol.PayloadMessage) (bool, bool) {
-		_, statusCode := message.ReadUntilBlankWithLength(message.Offset, 6)
+		start := message.Offset
+		offset, _ := message.ReadUntilBlankWithLength(start, 9)
+		_, statusCode := message.ReadUntilBlankWithLength(offset, 6)
 		statusCodeI, err := strconv.ParseInt(string(statusCode), 10, 0)
 		if err != nil {
 			return false, true
@@ -47,8 +48,14 @@ func parseHttpResponse() protocol.ParsePkgFn {
 			statusCodeI = 0
 		}
 
+		headers, bodyStart := parseHeaders(message)
+		if statusCodeI >= 100 && statusCodeI < 200 && statusCodeI != 101 {
+			message.Offset = bodyEnd(message, headers, bodyStart, false)
+			message.Ignore()
+			return true, true
+		}
+
 		if !message.HasAttribute(constlabels.HttpApmTraceType) {
-			headers := parseHeaders(message)
 			traceType, traceId := tools.ParseTraceHeader(headers)
 			if len(traceType) > 0 && len(traceId) > 0 {
 				message.AddStringAttribute(constlabels.HttpApmTraceType, traceType)
@@ -57,11 +64,21 @@ func parseHttpResponse() protocol.ParsePkgFn {
 		}
 
 		message.AddIntAttribute(constlabels.HttpStatusCode, statusCodeI)
-		message.AddByteArrayUtf8Attribute(constlabels.HttpResponsePayload, message.GetData(0, protocol.GetHttpPayLoadLength()))
+		message.AddByteArrayUtf8Attribute(constlabels.HttpResponsePayload, message.GetData(start, protocol.GetHttpPayLoadLength()))
 		if statusCodeI >= 400 {
 			message.AddBoolAttribute(constlabels.IsError, true)
 			message.AddIntAttribute(constlabels.ErrorType, int64(constlabels.ProtocolError))
 		}
+
+		// The responses of HEAD, 1xx, 204 and 304 have no body.
+		var end int
+		if message.GetStringAttribute(constlabels.HttpMethod) == "HEAD" || statusCodeI == 101 || statusCodeI == 204 || statusCodeI == 304 {
+			end = bodyEnd(message, nil, bodyStart, false)
+		} else {
+			end = bodyEnd(message, headers, bodyStart, true)
+		}
+		extractLabels(message, extractors, true, headers, bodyStart, end)
+		message.Offset = end
 		return true, true
 	}
 }
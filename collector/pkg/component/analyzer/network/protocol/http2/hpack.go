@@ -0,0 +1,265 @@
+package http2
+
+import (
+	"golang.org/x/net/http2/hpack"
+)
+
+const (
+	// defaultTableSize is the initial size of the dynamic table, see RFC 7540 section 6.5.2.
+	defaultTableSize = 4096
+	// entryOverhead is added to the size of each entry, see RFC 7541 section 4.1.
+	entryOverhead = 32
+	// maxStringLength limits the strings decoded, which are usually much shorter.
+	maxStringLength = 16 * 1024
+)
+
+type headerField struct {
+	name  string
+	value string
+}
+
+// hpackTable is the dynamic table of HPACK in one direction of a connection. Only the entries
+// inserted by the header blocks we have seen are known. They are the newest ones, so their
+// indexes are right even if the older entries are unknown, as long as no header blocks are missed
+// after them. The table must be reset once any header block may be missed. After that the fields
+// referring to the unknown entries are skipped, but a wrong field is never returned.
+type hpackTable struct {
+	// entries are sorted from the newest to the oldest.
+	entries []headerField
+	size    int
+	maxSize int
+}
+
+func newHpackTable() *hpackTable {
+	return &hpackTable{maxSize: defaultTableSize}
+}
+
+// reset forgets all the entries. The entries inserted later are still indexed right.
+func (table *hpackTable) reset() {
+	table.entries = nil
+	table.size = 0
+}
+
+func (table *hpackTable) add(field headerField) {
+	size := len(field.name) + len(field.value) + entryOverhead
+	if size > table.maxSize {
+		// Such an entry empties the table, see RFC 7541 section 4.4.
+		table.reset()
+		return
+	}
+	table.entries = append(table.entries, headerField{})
+	copy(table.entries[1:], table.entries)
+	table.entries[0] = field
+	table.size += size
+	table.evict()
+}
+
+func (table *hpackTable) setMaxSize(size int) {
+	table.maxSize = size
+	table.evict()
+}
+
+func (table *hpackTable) evict() {
+	for table.size > table.maxSize && len(table.entries) > 0 {
+		last := table.entries[len(table.entries)-1]
+		table.size -= len(last.name) + len(last.value) + entryOverhead
+		table.entries = table.entries[:len(table.entries)-1]
+	}
+}
+
+func (table *hpackTable) lookup(index uint64) (headerField, bool) {
+	if index == 0 {
+		return headerField{}, false
+	}
+	if index <= uint64(len(staticTable)) {
+		return staticTable[index-1], true
+	}
+	index -= uint64(len(staticTable)) + 1
+	if index < uint64(len(table.entries)) {
+		return table.entries[index], true
+	}
+	return headerField{}, false
+}
+
+// decode decodes the header block and calls emit with the fields known. The table is updated by
+// the fields with incremental indexing. False is returned if the block is truncated or invalid,
+// in which case the entries inserted by the rest of the block are missed.
+func (table *hpackTable) decode(block []byte, emit func(field headerField)) bool {
+	for len(block) > 0 {
+		b := block[0]
+		switch {
+		case b&0x80 != 0:
+			// Indexed Header Field, see RFC 7541 section 6.1.
+			index, n, ok := readInteger(block, 7)
+			if !ok || index == 0 {
+				return false
+			}
+			block = block[n:]
+			// The entries with empty names are inserted by the fields whose names are unknown.
+			if field, ok := table.lookup(index); ok && field.name != "" {
+				emit(field)
+			}
+		case b&0xe0 == 0x20:
+			// Dynamic Table Size Update, see RFC 7541 section 6.3.
+			size, n, ok := readInteger(block, 5)
+			if !ok || size > 1<<24 {
+				return false
+			}
+			block = block[n:]
+			table.setMaxSize(int(size))
+		default:
+			// Literal Header Field with Incremental Indexing uses 6 bits for the index, and the
+			// ones without Indexing or Never Indexed use 4 bits, see RFC 7541 section 6.2.
+			indexing := b&0xc0 == 0x40
+			prefix := uint8(4)
+			if indexing {
+				prefix = 6
+			}
+			index, n, ok := readInteger(block, prefix)
+			if !ok {
+				return false
+			}
+			block = block[n:]
+			var field headerField
+			known := true
+			if index == 0 {
+				if field.name, n, ok = readString(block); !ok {
+					return false
+				}
+				block = block[n:]
+			} else {
+				var name headerField
+				name, known = table.lookup(index)
+				field.name = name.name
+				known = known && name.name != ""
+			}
+			if field.value, n, ok = readString(block); !ok {
+				return false
+			}
+			block = block[n:]
+			if !known {
+				// The name refers to an unknown entry. The field is still inserted, so that
+				// the indexes of the known entries are kept right, but it is never emitted.
+				field.name = ""
+			}
+			if indexing {
+				table.add(field)
+			}
+			if known {
+				emit(field)
+			}
+		}
+	}
+	return true
+}
+
+// readInteger decodes an integer with an N-bit prefix, see RFC 7541 section 5.1.
+func readInteger(data []byte, prefix uint8) (value uint64, n int, ok bool) {
+	if len(data) == 0 {
+		return 0, 0, false
+	}
+	max := uint64(1)<<prefix - 1
+	value = uint64(data[0]) & max
+	if value < max {
+		return value, 1, true
+	}
+	var shift uint
+	for i := 1; i < len(data); i++ {
+		b := data[i]
+		value += uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return value, i + 1, true
+		}
+		shift += 7
+		if shift >= 63 {
+			return 0, 0, false
+		}
+	}
+	return 0, 0, false
+}
+
+// readString decodes a string literal, see RFC 7541 section 5.2.
+func readString(data []byte) (value string, n int, ok bool) {
+	if len(data) == 0 {
+		return "", 0, false
+	}
+	huffman := data[0]&0x80 != 0
+	length, n, ok := readInteger(data, 7)
+	if !ok || length > maxStringLength || uint64(len(data)-n) < length {
+		return "", 0, false
+	}
+	raw := data[n : n+int(length)]
+	if !huffman {
+		return string(raw), n + int(length), true
+	}
+	value, err := hpack.HuffmanDecodeToString(raw)
+	if err != nil {
+		return "", 0, false
+	}
+	return value, n + int(length), true
+}
+
+// staticTable is defined in RFC 7541 Appendix A.
+var staticTable = [...]headerField{
+	{":authority", ""},
+	{":method", "GET"},
+	{":method", "POST"},
+	{":path", "/"},
+	{":path", "/index.html"},
+	{":scheme", "http"},
+	{":scheme", "https"},
+	{":status", "200"},
+	{":status", "204"},
+	{":status", "206"},
+	{":status", "304"},
+	{":status", "400"},
+	{":status", "404"},
+	{":status", "500"},
+	{"accept-charset", ""},
+	{"accept-encoding", "gzip, deflate"},
+	{"accept-language", ""},
+	{"accept-ranges", ""},
+	{"accept", ""},
+	{"access-control-allow-origin", ""},
+	{"age", ""},
+	{"allow", ""},
+	{"authorization", ""},
+	{"cache-control", ""},
+	{"content-disposition", ""},
+	{"content-encoding", ""},
+	{"content-language", ""},
+	{"content-length", ""},
+	{"content-location", ""},
+	{"content-range", ""},
+	{"content-type", ""},
+	{"cookie", ""},
+	{"date", ""},
+	{"etag", ""},
+	{"expect", ""},
+	{"expires", ""},
+	{"from", ""},
+	{"host", ""},
+	{"if-match", ""},
+	{"if-modified-since", ""},
+	{"if-none-match", ""},
+	{"if-range", ""},
+	{"if-unmodified-since", ""},
+	{"last-modified", ""},
+	{"link", ""},
+	{"location", ""},
+	{"max-forwards", ""},
+	{"proxy-authenticate", ""},
+	{"proxy-authorization", ""},
+	{"range", ""},
+	{"referer", ""},
+	{"refresh", ""},
+	{"retry-after", ""},
+	{"server", ""},
+	{"set-cookie", ""},
+	{"strict-transport-security", ""},
+	{"transfer-encoding", ""},
+	{"user-agent", ""},
+	{"vary", ""},
+	{"via", ""},
+	{"www-authenticate", ""},
+}
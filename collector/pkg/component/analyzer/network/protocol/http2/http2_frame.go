@@ -0,0 +1,252 @@
+package http2
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const (
+	frameHeaderLength = 9
+
+	frameData         = 0x0
+	frameHeaders      = 0x1
+	framePriority     = 0x2
+	frameRstStream    = 0x3
+	frameSettings     = 0x4
+	framePushPromise  = 0x5
+	framePing         = 0x6
+	frameGoAway       = 0x7
+	frameWindowUpdate = 0x8
+	frameContinuation = 0x9
+
+	flagEndStream  = 0x1
+	flagAck        = 0x1
+	flagEndHeaders = 0x4
+	flagPadded     = 0x8
+	flagPriority   = 0x20
+)
+
+var clientPreface = []byte("PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n")
+
+/*
+https://www.rfc-editor.org/rfc/rfc7540#section-4.1
+
+	+-----------------------------------------------+
+	|                 Length (24)                   |
+	+---------------+---------------+---------------+
+	|   Type (8)    |   Flags (8)   |
+	+-+-------------+---------------+-------------------------------+
+	|R|                 Stream Identifier (31)                      |
+	+=+=============================================================+
+	|                   Frame Payload (0...)                      ...
+	+---------------------------------------------------------------+
+*/
+type frame struct {
+	frameType byte
+	flags     byte
+	streamId  uint32
+	length    int
+	// payload is shorter than length if the frame is incomplete.
+	payload []byte
+}
+
+func (f *frame) complete() bool {
+	return len(f.payload) == f.length
+}
+
+// valid checks the frame header against the rules of RFC 7540 section 6. The frames of unknown
+// types and the undefined flags are rejected as well, because the data of other protocols should
+// not be taken as HTTP/2.
+func (f *frame) valid() bool {
+	if f.streamId&0x80000000 != 0 {
+		return false
+	}
+	switch f.frameType {
+	case frameData:
+		return f.streamId != 0 && f.flags&^(flagEndStream|flagPadded) == 0
+	case frameHeaders:
+		return f.streamId != 0 && f.flags&^(flagEndStream|flagEndHeaders|flagPadded|flagPriority) == 0
+	case framePriority:
+		return f.streamId != 0 && f.length == 5 && f.flags == 0
+	case frameRstStream:
+		return f.streamId != 0 && f.length == 4 && f.flags == 0
+	case frameSettings:
+		return f.streamId == 0 && f.length%6 == 0 && (f.flags == 0 || f.flags == flagAck && f.length == 0)
+	case framePushPromise:
+		return f.streamId != 0 && f.flags&^(flagEndHeaders|flagPadded) == 0
+	case framePing:
+		return f.streamId == 0 && f.length == 8 && f.flags&^flagAck == 0
+	case frameGoAway:
+		return f.streamId == 0 && f.length >= 8 && f.flags == 0
+	case frameWindowUpdate:
+		return f.length == 4 && f.flags == 0
+	case frameContinuation:
+		return f.streamId != 0 && f.flags&^flagEndHeaders == 0
+	}
+	return false
+}
+
+// isControl returns true if the frame doesn't carry any data or headers.
+func (f *frame) isControl() bool {
+	switch f.frameType {
+	case frameData, frameHeaders, framePushPromise, frameContinuation:
+		return false
+	}
+	return true
+}
+
+type frames struct {
+	preface bool
+	list    []frame
+	// next is the offset where the frame after the last one starts, which is larger than the
+	// length of the data if the last frame is incomplete.
+	next int
+	// partialHeader is true if the data ends in the middle of a frame header.
+	partialHeader bool
+}
+
+// readFrames splits the data into frames after skipping the connection preface. Only the last
+// frame could be incomplete, because a message may be truncated or split into several writes.
+// False is returned if any frame header is invalid, which means the data is not HTTP/2 or it
+// doesn't start at a frame.
+func readFrames(data []byte) (*frames, bool) {
+	ret := &frames{}
+	offset := 0
+	if bytes.HasPrefix(data, clientPreface) {
+		ret.preface = true
+		offset = len(clientPreface)
+	}
+	for offset < len(data) {
+		if len(data)-offset < frameHeaderLength {
+			ret.partialHeader = true
+			break
+		}
+		header := data[offset : offset+frameHeaderLength]
+		f := frame{
+			length:    int(header[0])<<16 | int(header[1])<<8 | int(header[2]),
+			frameType: header[3],
+			flags:     header[4],
+			streamId:  binary.BigEndian.Uint32(header[5:]),
+		}
+		if !f.valid() {
+			return nil, false
+		}
+		offset += frameHeaderLength
+		end := offset + f.length
+		if end > len(data) {
+			f.payload = data[offset:]
+		} else {
+			f.payload = data[offset:end]
+		}
+		ret.list = append(ret.list, f)
+		offset = end
+	}
+	if len(ret.list) == 0 && !ret.preface {
+		return nil, false
+	}
+	ret.next = offset
+	return ret, true
+}
+
+// isControl returns true if all the frames are complete control frames, which are small and
+// strictly checked, so they are safe to be taken as HTTP/2 without any headers.
+func (fs *frames) isControl() bool {
+	if fs.partialHeader || len(fs.list) == 0 {
+		return false
+	}
+	for i := range fs.list {
+		if !fs.list[i].isControl() || !fs.list[i].complete() {
+			return false
+		}
+	}
+	return true
+}
+
+type headerBlock struct {
+	streamId  uint32
+	endStream bool
+	// push is true if the block is carried by a PUSH_PROMISE frame.
+	push     bool
+	fragment []byte
+	complete bool
+}
+
+// headerBlocks joins the HEADERS and PUSH_PROMISE frames with their CONTINUATION frames. False
+// is returned if a CONTINUATION frame is out of place.
+func (fs *frames) headerBlocks() ([]headerBlock, bool) {
+	var blocks []headerBlock
+	var current *headerBlock
+	for i := range fs.list {
+		f := &fs.list[i]
+		if current != nil {
+			if f.frameType != frameContinuation || f.streamId != current.streamId {
+				return nil, false
+			}
+			// Copy the fragment, or it would overwrite the frames following it.
+			fragment := make([]byte, 0, len(current.fragment)+len(f.payload))
+			fragment = append(append(fragment, current.fragment...), f.payload...)
+			current.fragment = fragment
+			current.complete = f.complete()
+			if f.flags&flagEndHeaders != 0 || !f.complete() {
+				blocks = append(blocks, *current)
+				current = nil
+			}
+			continue
+		}
+		switch f.frameType {
+		case frameHeaders, framePushPromise:
+		case frameContinuation:
+			return nil, false
+		default:
+			continue
+		}
+		block := headerBlock{
+			streamId:  f.streamId,
+			endStream: f.frameType == frameHeaders && f.flags&flagEndStream != 0,
+			push:      f.frameType == framePushPromise,
+			complete:  f.complete(),
+		}
+		payload := f.payload
+		padding := 0
+		if f.flags&flagPadded != 0 {
+			if len(payload) < 1 {
+				block.complete = false
+				blocks = append(blocks, block)
+				continue
+			}
+			padding = int(payload[0])
+			payload = payload[1:]
+		}
+		skip := 0
+		if f.frameType == frameHeaders && f.flags&flagPriority != 0 {
+			skip = 5
+		} else if f.frameType == framePushPromise {
+			// The promised stream identifier
+			skip = 4
+		}
+		if len(payload) < skip {
+			block.complete = false
+			blocks = append(blocks, block)
+			continue
+		}
+		payload = payload[skip:]
+		if block.complete {
+			if padding > len(payload) {
+				return nil, false
+			}
+			payload = payload[:len(payload)-padding]
+		}
+		block.fragment = payload
+		if f.flags&flagEndHeaders != 0 || !block.complete {
+			blocks = append(blocks, block)
+		} else {
+			current = &block
+		}
+	}
+	if current != nil {
+		// The CONTINUATION frames are not in the data.
+		current.complete = false
+		blocks = append(blocks, *current)
+	}
+	return blocks, true
+}
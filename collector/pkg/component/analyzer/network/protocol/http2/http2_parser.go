@@ -0,0 +1,212 @@
+package http2
+
+import (
+	"strings"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+	"github.com/Kindling-project/kindling/collector/pkg/urlclustering"
+)
+
+/*
+NewHttp2Parser parses the HTTP/2 messages and the gRPC calls carried by them.
+
+The requests and responses of the streams on a connection are paired by the stream identifiers.
+Only the first stream started in a message is taken as the request or response of the message,
+and the frames of other streams are only used to keep the header tables of HPACK.
+
+The header tables are kept for each connection. Once a message may be missed or truncated, the
+table is reset, and the headers referring to the entries inserted before are not decoded any
+more. For example, the :path of a gRPC method called before the reset is unknown in the
+following calls of the same connection.
+*/
+func NewHttp2Parser(urlClusteringMethod string) *protocol.ProtocolParser {
+	var method urlclustering.ClusteringMethod
+	switch urlClusteringMethod {
+	case "alphabet":
+		method = urlclustering.NewAlphabeticalClusteringMethod()
+	case "noparam":
+		method = urlclustering.NewNoParamClusteringMethod()
+	default:
+		method = urlclustering.NewAlphabeticalClusteringMethod()
+	}
+	requestParser := protocol.CreatePkgParser(fastfailHttp2Request(), parseHttp2Request(method))
+	responseParser := protocol.CreatePkgParser(fastfailHttp2Response(), parseHttp2Response())
+
+	parser := protocol.NewProtocolParser(protocol.HTTP2, requestParser, responseParser, http2Pair())
+	parser.EnableMultiplexing()
+	return parser
+}
+
+func http2Pair() protocol.PairMatch {
+	return func(requests []*protocol.PayloadMessage, response *protocol.PayloadMessage) int {
+		streamId := response.GetIntAttribute(constlabels.Http2StreamId)
+		for i, request := range requests {
+			if request.IsIgnored() || !request.HasAttribute(constlabels.Http2StreamId) {
+				continue
+			}
+			if request.GetIntAttribute(constlabels.Http2StreamId) == streamId {
+				return i
+			}
+		}
+		return -1
+	}
+}
+
+// connection keeps the states of both directions of an HTTP/2 connection.
+type connection struct {
+	request  *direction
+	response *direction
+	// missed is the count of the missed messages when the tables were reset last time.
+	missed int
+	// grpc is true once a message with the content type of gRPC is seen. It is kept after the
+	// tables are reset, because the content type may refer to an entry inserted before.
+	grpc bool
+}
+
+func newConnection() *connection {
+	return &connection{
+		request:  &direction{table: newHpackTable()},
+		response: &direction{table: newHpackTable()},
+	}
+}
+
+type direction struct {
+	table *hpackTable
+	// remaining is the size of the rest of the last frame, which is expected at the beginning
+	// of the next message, because a frame could be split into several writes.
+	remaining int
+}
+
+func (d *direction) reset() {
+	d.table.reset()
+	d.remaining = 0
+}
+
+type headers struct {
+	streamId  uint32
+	endStream bool
+	push      bool
+	fields    map[string]string
+}
+
+// isGrpc returns true if the headers are of gRPC. The connection is taken as gRPC if the content
+// type is not decoded.
+func (h *headers) isGrpc(conn *connection) bool {
+	contentType, exist := h.fields["content-type"]
+	if !exist {
+		return conn.grpc
+	}
+	if strings.HasPrefix(contentType, "application/grpc") {
+		conn.grpc = true
+		return true
+	}
+	return false
+}
+
+// readMessage reads the frames of the message and decodes the header blocks in order. The
+// message is ignored if it is of a known connection but has no frames to parse. False is
+// returned if the message is not HTTP/2.
+func readMessage(message *protocol.PayloadMessage, isRequest bool) ([]*headers, *connection, bool) {
+	states := message.GetConnectionStates()
+	conn, known := states.Get(protocol.HTTP2).(*connection)
+	if !known {
+		conn = newConnection()
+		conn.missed = states.Missed()
+	} else if missed := states.Missed(); conn.missed != missed {
+		conn.request.reset()
+		conn.response.reset()
+		conn.missed = missed
+	}
+	dir := conn.response
+	pseudoHeader := ":status"
+	if isRequest {
+		dir = conn.request
+		pseudoHeader = ":method"
+	}
+
+	data := message.Data
+	size := message.Size
+	if size < len(data) {
+		size = len(data)
+	}
+	skipped := 0
+	if dir.remaining > 0 {
+		if dir.remaining >= size {
+			// The whole message is in the middle of a frame.
+			dir.remaining -= size
+			message.Ignore()
+			return nil, conn, true
+		}
+		if dir.remaining > len(data) {
+			dir.reset()
+			message.Ignore()
+			return nil, conn, true
+		}
+		skipped = dir.remaining
+		data = data[skipped:]
+		dir.remaining = 0
+	}
+
+	fs, ok := readFrames(data)
+	var blocks []headerBlock
+	if ok {
+		blocks, ok = fs.headerBlocks()
+	}
+	if !ok {
+		if !known {
+			return nil, conn, false
+		}
+		// The message doesn't start at a frame, so the frames before it have been missed.
+		dir.reset()
+		message.Ignore()
+		return nil, conn, true
+	}
+
+	ret := make([]*headers, 0, len(blocks))
+	hasPseudoHeader := false
+	for _, block := range blocks {
+		h := &headers{
+			streamId:  block.streamId,
+			endStream: block.endStream,
+			push:      block.push,
+			fields:    make(map[string]string),
+		}
+		decoded := dir.table.decode(block.fragment, func(field headerField) {
+			if _, exist := h.fields[field.name]; !exist {
+				h.fields[field.name] = field.value
+			}
+		})
+		if !decoded || !block.complete {
+			dir.table.reset()
+		}
+		if _, exist := h.fields[pseudoHeader]; exist {
+			hasPseudoHeader = true
+		}
+		ret = append(ret, h)
+	}
+
+	// Check whether the frames following the data are missed.
+	captured := skipped + len(data)
+	switch {
+	case fs.partialHeader:
+		dir.reset()
+	case fs.next > len(data):
+		rest := fs.next - len(data)
+		if uncaptured := size - captured; rest >= uncaptured {
+			dir.remaining = rest - uncaptured
+		} else {
+			dir.reset()
+		}
+	case size > captured:
+		dir.reset()
+	}
+
+	if !known {
+		if !fs.preface && !fs.isControl() && !hasPseudoHeader {
+			return nil, conn, false
+		}
+		states.Set(protocol.HTTP2, conn)
+	}
+	return ret, conn, true
+}
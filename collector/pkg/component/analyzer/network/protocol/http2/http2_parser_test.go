@@ -0,0 +1,296 @@
+package http2
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constvalues"
+	xhttp2 "golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+// endpoint writes the frames of one direction of a connection.
+type endpoint struct {
+	headerBuf bytes.Buffer
+	encoder   *hpack.Encoder
+	frameBuf  bytes.Buffer
+	framer    *xhttp2.Framer
+}
+
+func newEndpoint() *endpoint {
+	e := &endpoint{}
+	e.encoder = hpack.NewEncoder(&e.headerBuf)
+	e.framer = xhttp2.NewFramer(&e.frameBuf, nil)
+	return e
+}
+
+func (e *endpoint) headers(t *testing.T, streamId uint32, endStream bool, fields ...string) {
+	e.headerBuf.Reset()
+	for i := 0; i+1 < len(fields); i += 2 {
+		if err := e.encoder.WriteField(hpack.HeaderField{Name: fields[i], Value: fields[i+1]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := e.framer.WriteHeaders(xhttp2.HeadersFrameParam{
+		StreamID:      streamId,
+		BlockFragment: e.headerBuf.Bytes(),
+		EndStream:     endStream,
+		EndHeaders:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (e *endpoint) data(t *testing.T, streamId uint32, endStream bool, data []byte) {
+	if err := e.framer.WriteData(streamId, endStream, data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// flush returns the frames written since the last flush.
+func (e *endpoint) flush() []byte {
+	ret := append([]byte(nil), e.frameBuf.Bytes()...)
+	e.frameBuf.Reset()
+	return ret
+}
+
+func grpcRequest(t *testing.T, client *endpoint, streamId uint32, path string) {
+	client.headers(t, streamId, false,
+		":method", "POST",
+		":scheme", "http",
+		":path", path,
+		":authority", "localhost:50051",
+		"content-type", "application/grpc",
+		"te", "trailers",
+	)
+	client.data(t, streamId, true, []byte{0, 0, 0, 0, 2, 0x0a, 0x00})
+}
+
+func grpcResponse(t *testing.T, server *endpoint, streamId uint32, grpcStatus string) {
+	server.headers(t, streamId, false, ":status", "200", "content-type", "application/grpc")
+	server.data(t, streamId, false, []byte{0, 0, 0, 0, 0})
+	server.headers(t, streamId, true, "grpc-status", grpcStatus, "grpc-message", "")
+}
+
+func parseRequest(t *testing.T, parser *protocol.ProtocolParser, states *protocol.ConnectionStates, data []byte) *protocol.PayloadMessage {
+	message := protocol.NewRequestMessage(data)
+	message.Size = len(data)
+	message.SetConnectionStates(states)
+	if !parser.ParseRequest(message) {
+		t.Fatalf("failed to parse the request")
+	}
+	return message
+}
+
+func parseResponse(t *testing.T, parser *protocol.ProtocolParser, states *protocol.ConnectionStates, data []byte) *protocol.PayloadMessage {
+	message := protocol.NewResponseMessage(data, model.NewAttributeMap())
+	message.Size = len(data)
+	message.SetConnectionStates(states)
+	if !parser.ParseResponse(message) {
+		t.Fatalf("failed to parse the response")
+	}
+	return message
+}
+
+func TestHttp2Parser_Grpc(t *testing.T) {
+	parser := NewHttp2Parser("alphabet")
+	states := protocol.NewConnectionStates()
+	client, server := newEndpoint(), newEndpoint()
+
+	client.frameBuf.Write(clientPreface)
+	if err := client.framer.WriteSettings(); err != nil {
+		t.Fatal(err)
+	}
+	grpcRequest(t, client, 1, "/helloworld.Greeter/SayHello")
+	request := parseRequest(t, parser, states, client.flush())
+	if request.IsIgnored() {
+		t.Fatalf("the request is ignored")
+	}
+	if got := request.GetIntAttribute(constlabels.Http2StreamId); got != 1 {
+		t.Errorf("stream id = %d, want 1", got)
+	}
+	if got := request.GetStringAttribute(constlabels.Protocol); got != constvalues.ProtocolGrpc {
+		t.Errorf("protocol = %s, want grpc", got)
+	}
+	if got := request.GetStringAttribute(constlabels.HttpMethod); got != "POST" {
+		t.Errorf("method = %s, want POST", got)
+	}
+	if got := request.GetStringAttribute(constlabels.ContentKey); got != "/helloworld.Greeter/SayHello" {
+		t.Errorf("content key = %s", got)
+	}
+
+	if err := server.framer.WriteSettings(); err != nil {
+		t.Fatal(err)
+	}
+	grpcResponse(t, server, 1, "5")
+	response := parseResponse(t, parser, states, server.flush())
+	if got := parser.PairMatch([]*protocol.PayloadMessage{request}, response); got != 0 {
+		t.Errorf("pair match = %d, want 0", got)
+	}
+	if got := response.GetIntAttribute(constlabels.HttpStatusCode); got != 200 {
+		t.Errorf("status code = %d, want 200", got)
+	}
+	if got := response.GetIntAttribute(constlabels.GrpcStatusCode); got != 5 {
+		t.Errorf("grpc status code = %d, want 5", got)
+	}
+	if !response.GetBoolAttribute(constlabels.IsError) {
+		t.Errorf("the response with a non-zero grpc-status should be an error")
+	}
+
+	// The second call is encoded with the entries of the dynamic tables.
+	grpcRequest(t, client, 3, "/helloworld.Greeter/SayHello")
+	request = parseRequest(t, parser, states, client.flush())
+	if got := request.GetStringAttribute(constlabels.ContentKey); got != "/helloworld.Greeter/SayHello" {
+		t.Errorf("content key = %s in the second call", got)
+	}
+	grpcResponse(t, server, 3, "0")
+	response = parseResponse(t, parser, states, server.flush())
+	if got := response.GetIntAttribute(constlabels.Http2StreamId); got != 3 {
+		t.Errorf("stream id = %d, want 3", got)
+	}
+	if got := response.GetIntAttribute(constlabels.GrpcStatusCode); got != 0 || !response.HasAttribute(constlabels.GrpcStatusCode) {
+		t.Errorf("grpc status code = %d, want 0", got)
+	}
+	if response.GetBoolAttribute(constlabels.IsError) {
+		t.Errorf("the response with grpc-status 0 should not be an error")
+	}
+}
+
+func TestHttp2Parser_Http(t *testing.T) {
+	parser := NewHttp2Parser("alphabet")
+	client, server := newEndpoint(), newEndpoint()
+
+	client.frameBuf.Write(clientPreface)
+	client.headers(t, 1, true, ":method", "GET", ":scheme", "https", ":path", "/api/users/123?id=1", ":authority", "example.com")
+	request := parseRequest(t, parser, nil, client.flush())
+	if got := request.GetStringAttribute(constlabels.Protocol); got != "" {
+		t.Errorf("protocol = %s, want empty", got)
+	}
+	if got := request.GetStringAttribute(constlabels.HttpUrl); got != "/api/users/123?id=1" {
+		t.Errorf("url = %s", got)
+	}
+	if got := request.GetStringAttribute(constlabels.ContentKey); got != "/api/users/*" {
+		t.Errorf("content key = %s, want /api/users/*", got)
+	}
+
+	server.headers(t, 1, true, ":status", "404")
+	response := parseResponse(t, parser, nil, server.flush())
+	if got := response.GetIntAttribute(constlabels.HttpStatusCode); got != 404 {
+		t.Errorf("status code = %d, want 404", got)
+	}
+	if !response.GetBoolAttribute(constlabels.IsError) {
+		t.Errorf("the response with status 404 should be an error")
+	}
+}
+
+func TestHttp2Parser_ControlFrames(t *testing.T) {
+	parser := NewHttp2Parser("alphabet")
+	states := protocol.NewConnectionStates()
+	server := newEndpoint()
+	if err := server.framer.WriteSettingsAck(); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.framer.WritePing(true, [8]byte{}); err != nil {
+		t.Fatal(err)
+	}
+	response := parseResponse(t, parser, states, server.flush())
+	if !response.IsIgnored() {
+		t.Errorf("the control frames should be ignored")
+	}
+	if states.IsEmpty() {
+		t.Errorf("the connection should be recognized as HTTP/2")
+	}
+}
+
+func TestHttp2Parser_NotHttp2(t *testing.T) {
+	parser := NewHttp2Parser("alphabet")
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "http1", data: []byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n")},
+		{name: "redis", data: []byte("*1\r\n$4\r\nPING\r\n")},
+		{name: "data frame", data: []byte{0, 0, 1, frameData, 0, 0, 0, 0, 1, 'a'}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states := protocol.NewConnectionStates()
+			message := protocol.NewRequestMessage(tt.data)
+			message.SetConnectionStates(states)
+			if parser.ParseRequest(message) {
+				t.Errorf("the data should not be parsed as HTTP/2")
+			}
+			if !states.IsEmpty() {
+				t.Errorf("the states should not be kept")
+			}
+		})
+	}
+}
+
+func TestHttp2Parser_Missed(t *testing.T) {
+	parser := NewHttp2Parser("alphabet")
+	states := protocol.NewConnectionStates()
+	client := newEndpoint()
+
+	client.frameBuf.Write(clientPreface)
+	grpcRequest(t, client, 1, "/helloworld.Greeter/SayHello")
+	parseRequest(t, parser, states, client.flush())
+
+	// The second call is missed, so the table is reset before the third one.
+	grpcRequest(t, client, 3, "/helloworld.Greeter/SayHello")
+	client.flush()
+	states.AddMissed()
+
+	grpcRequest(t, client, 5, "/helloworld.Greeter/SayHello")
+	request := parseRequest(t, parser, states, client.flush())
+	if got := request.GetIntAttribute(constlabels.Http2StreamId); got != 5 {
+		t.Errorf("stream id = %d, want 5", got)
+	}
+	if request.HasAttribute(constlabels.ContentKey) {
+		t.Errorf("the path referring to the entries before the reset should not be decoded")
+	}
+
+	// The entries inserted after the reset are decoded.
+	grpcRequest(t, client, 7, "/helloworld.Greeter/SayGoodbye")
+	parseRequest(t, parser, states, client.flush())
+	grpcRequest(t, client, 9, "/helloworld.Greeter/SayGoodbye")
+	request = parseRequest(t, parser, states, client.flush())
+	if got := request.GetStringAttribute(constlabels.ContentKey); got != "/helloworld.Greeter/SayGoodbye" {
+		t.Errorf("content key = %s after the reset", got)
+	}
+}
+
+func TestHttp2Parser_Truncated(t *testing.T) {
+	parser := NewHttp2Parser("alphabet")
+	states := protocol.NewConnectionStates()
+	client := newEndpoint()
+
+	client.frameBuf.Write(clientPreface)
+	grpcRequest(t, client, 1, "/helloworld.Greeter/SayHello")
+	client.data(t, 1, false, make([]byte, 1000))
+	data := client.flush()
+	message := protocol.NewRequestMessage(data[:200])
+	message.Size = len(data)
+	message.SetConnectionStates(states)
+	if !parser.ParseRequest(message) {
+		t.Fatalf("failed to parse the truncated request")
+	}
+	if got := message.GetStringAttribute(constlabels.ContentKey); got != "/helloworld.Greeter/SayHello" {
+		t.Errorf("content key = %s", got)
+	}
+
+	// The frames not captured are skipped at the beginning of the next message.
+	client.data(t, 1, true, make([]byte, 10))
+	grpcRequest(t, client, 3, "/helloworld.Greeter/SayHello")
+	request := parseRequest(t, parser, states, client.flush())
+	if got := request.GetIntAttribute(constlabels.Http2StreamId); got != 3 {
+		t.Errorf("stream id = %d, want 3", got)
+	}
+	if got := request.GetStringAttribute(constlabels.ContentKey); got != "/helloworld.Greeter/SayHello" {
+		t.Errorf("content key = %s after the truncated message", got)
+	}
+}
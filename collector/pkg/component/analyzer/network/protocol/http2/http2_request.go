@@ -0,0 +1,78 @@
+package http2
+
+import (
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/tools"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constvalues"
+	"github.com/Kindling-project/kindling/collector/pkg/urlclustering"
+)
+
+func fastfailHttp2Request() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) == 0
+	}
+}
+
+/*
+The request is the first HEADERS frame with the pseudo-header :method, which starts a stream
+from the client. The streams started by the client have odd identifiers.
+
+	:method = POST
+	:scheme = http
+	:path = /helloworld.Greeter/SayHello
+	:authority = localhost:50051
+	content-type = application/grpc
+	te = trailers
+*/
+func parseHttp2Request(urlClusteringMethod urlclustering.ClusteringMethod) protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		blocks, conn, ok := readMessage(message, true)
+		if !ok {
+			return false, true
+		}
+		var request *headers
+		for _, block := range blocks {
+			if _, exist := block.fields[":method"]; exist && !block.push && block.streamId%2 == 1 {
+				request = block
+				break
+			}
+		}
+		if request == nil {
+			// Only the control frames or the data of the requests started before.
+			message.Ignore()
+			return true, true
+		}
+
+		message.AddIntAttribute(constlabels.Http2StreamId, int64(request.streamId))
+		message.AddStringAttribute(constlabels.HttpMethod, request.fields[":method"])
+		traceType, traceId := tools.ParseTraceHeader(request.fields)
+		if len(traceType) > 0 && len(traceId) > 0 {
+			message.AddStringAttribute(constlabels.HttpApmTraceType, traceType)
+			message.AddStringAttribute(constlabels.HttpApmTraceId, traceId)
+		}
+		grpc := request.isGrpc(conn)
+		if grpc {
+			message.AddStringAttribute(constlabels.Protocol, constvalues.ProtocolGrpc)
+		}
+
+		path, exist := request.fields[":path"]
+		if !exist {
+			// The path refers to an entry of the header table which is unknown.
+			return true, true
+		}
+		message.AddUtf8StringAttribute(constlabels.HttpUrl, path)
+		var contentKey string
+		if grpc {
+			// The path of gRPC is /{service}/{method}
+			contentKey = path
+		} else {
+			contentKey = urlClusteringMethod.Clustering(path)
+		}
+		if len(contentKey) == 0 {
+			contentKey = "*"
+		}
+		message.AddUtf8StringAttribute(constlabels.ContentKey, contentKey)
+		return true, true
+	}
+}
@@ -0,0 +1,94 @@
+package http2
+
+import (
+	"strconv"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constvalues"
+)
+
+func fastfailHttp2Response() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) == 0
+	}
+}
+
+/*
+The response is the first HEADERS frame with the pseudo-header :status. The status of gRPC is
+sent in the trailers, which is the last HEADERS frame of the stream, or in the only HEADERS
+frame if there is no response message.
+
+	:status = 200
+	content-type = application/grpc
+	...
+	grpc-status = 0
+	grpc-message =
+
+The trailers may be sent in another message, which is taken as a response without :status.
+*/
+func parseHttp2Response() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		blocks, conn, ok := readMessage(message, false)
+		if !ok {
+			return false, true
+		}
+		var response *headers
+		for _, block := range blocks {
+			if block.push {
+				continue
+			}
+			if _, exist := block.fields[":status"]; exist {
+				response = block
+				break
+			}
+			if response == nil && block.endStream {
+				// The trailers of a stream whose headers have been sent before.
+				response = block
+			}
+		}
+		if response == nil {
+			message.Ignore()
+			return true, true
+		}
+
+		message.AddIntAttribute(constlabels.Http2StreamId, int64(response.streamId))
+		if status, exist := response.fields[":status"]; exist {
+			statusCode, err := strconv.ParseInt(status, 10, 0)
+			if err != nil {
+				return false, true
+			}
+			message.AddIntAttribute(constlabels.HttpStatusCode, statusCode)
+			if statusCode >= 400 {
+				message.AddBoolAttribute(constlabels.IsError, true)
+				message.AddIntAttribute(constlabels.ErrorType, int64(constlabels.ProtocolError))
+			}
+		}
+		if _, exist := response.fields[":status"]; exist && response.isGrpc(conn) {
+			message.AddStringAttribute(constlabels.Protocol, constvalues.ProtocolGrpc)
+		}
+
+		// Find the trailers of the same stream.
+		for _, block := range blocks {
+			if block.streamId != response.streamId || block.push {
+				continue
+			}
+			grpcStatus, exist := block.fields["grpc-status"]
+			if !exist {
+				continue
+			}
+			statusCode, err := strconv.ParseInt(grpcStatus, 10, 0)
+			if err != nil {
+				continue
+			}
+			message.AddStringAttribute(constlabels.Protocol, constvalues.ProtocolGrpc)
+			message.AddIntAttribute(constlabels.GrpcStatusCode, statusCode)
+			if statusCode != 0 {
+				message.AddBoolAttribute(constlabels.IsError, true)
+				message.AddIntAttribute(constlabels.ErrorType, int64(constlabels.ProtocolError))
+			}
+			break
+		}
+		return true, true
+	}
+}
@@ -0,0 +1,53 @@
+// Package testutil provides the builders of the binary messages shared by the tests of the
+// protocol parsers.
+package testutil
+
+// Join concatenates the data into a new slice.
+func Join(data ...[]byte) []byte {
+	var ret []byte
+	for _, d := range data {
+		ret = append(ret, d...)
+	}
+	return ret
+}
+
+// Int16 returns the lower 2 bytes of v in big endian.
+func Int16(v int) []byte {
+	return []byte{byte(v >> 8), byte(v)}
+}
+
+// Int32 returns the lower 4 bytes of v in big endian.
+func Int32(v int) []byte {
+	return []byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+}
+
+// Int64 returns v in big endian.
+func Int64(v int64) []byte {
+	return append(Int32(int(v>>32)), Int32(int(v))...)
+}
+
+// Int32LE returns the lower 4 bytes of v in little endian.
+func Int32LE(v int) []byte {
+	return []byte{byte(v), byte(v >> 8), byte(v >> 16), byte(v >> 24)}
+}
+
+// Varint returns v encoded in 7 bits per byte with the most significant bit set on all the bytes
+// but the last one, like the varints of protobuf and Thrift or the remaining length of MQTT.
+func Varint(v uint64) []byte {
+	var data []byte
+	for v >= 0x80 {
+		data = append(data, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(data, byte(v))
+}
+
+// String16 returns s prefixed by its length in 2 bytes of big endian.
+func String16(s string) []byte {
+	return append(Int16(len(s)), s...)
+}
+
+// String32 returns s prefixed by its length in 4 bytes of big endian.
+func String32(s string) []byte {
+	return append(Int32(len(s)), s...)
+}
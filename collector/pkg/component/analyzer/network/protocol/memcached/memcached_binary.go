@@ -0,0 +1,165 @@
+package memcached
+
+import (
+	"encoding/binary"
+)
+
+const (
+	binaryHeaderLength = 24
+	magicRequest       = 0x80
+	magicResponse      = 0x81
+	// maxBodyLength is the default item_size_max of Memcached with the key and extras.
+	maxBodyLength = 2 * 1024 * 1024
+
+	binaryNoError      = 0x0000
+	binaryKeyNotFound  = 0x0001
+	binaryKeyExists    = 0x0002
+	binaryNotStored    = 0x0005
+	binaryAuthContinue = 0x0009
+)
+
+/*
+https://github.com/memcached/memcached/wiki/BinaryProtocolRevamped
+
+	Byte/     0       |       1       |       2       |       3       |
+	   /              |               |               |               |
+	  |0 1 2 3 4 5 6 7|0 1 2 3 4 5 6 7|0 1 2 3 4 5 6 7|0 1 2 3 4 5 6 7|
+	  +---------------+---------------+---------------+---------------+
+	 0| Magic         | Opcode        | Key length                    |
+	  +---------------+---------------+---------------+---------------+
+	 4| Extras length | Data type     | vbucket id / Status           |
+	  +---------------+---------------+---------------+---------------+
+	 8| Total body length                                             |
+	  +---------------+---------------+---------------+---------------+
+	12| Opaque                                                        |
+	  +---------------+---------------+---------------+---------------+
+	16| CAS                                                           |
+	  |                                                               |
+	  +---------------+---------------+---------------+---------------+
+*/
+type binaryHeader struct {
+	opcode    byte
+	keyLength int
+	status    uint16
+	bodyLen   int
+	opaque    uint32
+}
+
+func readBinaryHeader(data []byte, magic byte) (*binaryHeader, bool) {
+	if len(data) < binaryHeaderLength || data[0] != magic {
+		return nil, false
+	}
+	h := &binaryHeader{
+		opcode:    data[1],
+		keyLength: int(binary.BigEndian.Uint16(data[2:])),
+		status:    binary.BigEndian.Uint16(data[6:]),
+		bodyLen:   int(binary.BigEndian.Uint32(data[8:])),
+		opaque:    binary.BigEndian.Uint32(data[12:]),
+	}
+	extrasLength := int(data[4])
+	if _, ok := binaryOpcodes[h.opcode]; !ok || data[5] != 0 ||
+		extrasLength+h.keyLength > h.bodyLen || h.bodyLen > maxBodyLength {
+		return nil, false
+	}
+	return h, true
+}
+
+// readBinaryHeaders reads the headers of the packets written together, like the quiet gets
+// followed by a noop.
+func readBinaryHeaders(data []byte, magic byte) []*binaryHeader {
+	var headers []*binaryHeader
+	for len(data) >= binaryHeaderLength {
+		h, ok := readBinaryHeader(data, magic)
+		if !ok {
+			break
+		}
+		headers = append(headers, h)
+		if binaryHeaderLength+h.bodyLen >= len(data) {
+			break
+		}
+		data = data[binaryHeaderLength+h.bodyLen:]
+	}
+	return headers
+}
+
+type binaryOpcode struct {
+	command string
+	// quiet is true if no response is sent for a miss of a get, or for the success of the others.
+	quiet bool
+}
+
+var binaryOpcodes = map[byte]binaryOpcode{
+	0x00: {"get", false},
+	0x01: {"set", false},
+	0x02: {"add", false},
+	0x03: {"replace", false},
+	0x04: {"delete", false},
+	0x05: {"incr", false},
+	0x06: {"decr", false},
+	0x07: {"quit", false},
+	0x08: {"flush_all", false},
+	0x09: {"get", true},
+	0x0a: {"noop", false},
+	0x0b: {"version", false},
+	0x0c: {"get", false},
+	0x0d: {"get", true},
+	0x0e: {"append", false},
+	0x0f: {"prepend", false},
+	0x10: {"stats", false},
+	0x11: {"set", true},
+	0x12: {"add", true},
+	0x13: {"replace", true},
+	0x14: {"delete", true},
+	0x15: {"incr", true},
+	0x16: {"decr", true},
+	0x17: {"quit", true},
+	0x18: {"flush_all", true},
+	0x19: {"append", true},
+	0x1a: {"prepend", true},
+	0x1b: {"verbosity", false},
+	0x1c: {"touch", false},
+	0x1d: {"gat", false},
+	0x1e: {"gat", true},
+	0x20: {"sasl_list_mechs", false},
+	0x21: {"sasl_auth", false},
+	0x22: {"sasl_step", false},
+}
+
+func getBinaryStatus(status uint16) string {
+	switch status {
+	case binaryNoError, binaryAuthContinue:
+		return statusHit
+	case binaryKeyNotFound, binaryKeyExists, binaryNotStored:
+		return statusMiss
+	}
+	return statusError
+}
+
+// getBinaryErrorMsg returns the message of the status defined by the protocol.
+func getBinaryErrorMsg(status uint16) string {
+	switch status {
+	case 0x0003:
+		return "Value too large"
+	case 0x0004:
+		return "Invalid arguments"
+	case 0x0006:
+		return "Incr/Decr on non-numeric value"
+	case 0x0007:
+		return "The vbucket belongs to another server"
+	case 0x0008:
+		return "Authentication error"
+	case 0x0081:
+		return "Unknown command"
+	case 0x0082:
+		return "Out of memory"
+	case 0x0083:
+		return "Not supported"
+	case 0x0084:
+		return "Internal error"
+	case 0x0085:
+		return "Busy"
+	case 0x0086:
+		return "Temporary failure"
+	}
+	return ""
+}
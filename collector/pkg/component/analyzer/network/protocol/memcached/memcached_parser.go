@@ -0,0 +1,29 @@
+package memcached
+
+import (
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+)
+
+// The status of the responses, which is the result of looking up or storing the keys.
+const (
+	statusHit   = "hit"
+	statusMiss  = "miss"
+	statusError = "error"
+)
+
+/*
+NewMemcachedParser parses the commands of the text and binary protocols of Memcached.
+
+	Request                               Response
+	/       \                             /     |      \
+	text    binary(0x80)                  hit   miss   error
+
+The command is the content key, and the reply is mapped to the status. The key is found or the
+command succeeds if it's a hit, while the key is not found or not stored if it's a miss.
+*/
+func NewMemcachedParser() *protocol.ProtocolParser {
+	requestParser := protocol.CreatePkgParser(fastfailMemcachedRequest(), parseMemcachedRequest())
+	responseParser := protocol.CreatePkgParser(fastfailMemcachedResponse(), parseMemcachedResponse())
+
+	return protocol.NewProtocolParser(protocol.MEMCACHED, requestParser, responseParser, nil)
+}
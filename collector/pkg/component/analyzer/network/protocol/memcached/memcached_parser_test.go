@@ -0,0 +1,182 @@
+package memcached
+
+import (
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/internal/testutil"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func binaryPacket(magic byte, opcode byte, status uint16, opaque uint32, key string, value string) []byte {
+	return testutil.Join([]byte{magic, opcode}, testutil.Int16(len(key)), []byte{0, 0}, testutil.Int16(int(status)),
+		testutil.Int32(len(key)+len(value)), testutil.Int32(int(opaque)), make([]byte, 8), []byte(key), []byte(value))
+}
+
+func TestParseMemcached(t *testing.T) {
+	parser := NewMemcachedParser()
+	tests := []struct {
+		name       string
+		request    []byte
+		response   []byte
+		contentKey string
+		status     string
+		errorMsg   string
+	}{
+		{
+			name:       "text get hit",
+			request:    []byte("get user:1\r\n"),
+			response:   []byte("VALUE user:1 0 5\r\nhello\r\nEND\r\n"),
+			contentKey: "get",
+			status:     statusHit,
+		},
+		{
+			name:       "text gets miss",
+			request:    []byte("gets user:1 user:2\r\n"),
+			response:   []byte("END\r\n"),
+			contentKey: "gets",
+			status:     statusMiss,
+		},
+		{
+			name:       "text set stored",
+			request:    []byte("set user:1 0 0 5\r\nhello\r\n"),
+			response:   []byte("STORED\r\n"),
+			contentKey: "set",
+			status:     statusHit,
+		},
+		{
+			name:       "text add not stored",
+			request:    []byte("add user:1 0 0 5\r\nhello\r\n"),
+			response:   []byte("NOT_STORED\r\n"),
+			contentKey: "add",
+			status:     statusMiss,
+		},
+		{
+			name:       "text delete not found",
+			request:    []byte("delete user:1\r\n"),
+			response:   []byte("NOT_FOUND\r\n"),
+			contentKey: "delete",
+			status:     statusMiss,
+		},
+		{
+			name:       "text incr",
+			request:    []byte("incr counter 1\r\n"),
+			response:   []byte("42\r\n"),
+			contentKey: "incr",
+			status:     statusHit,
+		},
+		{
+			name:       "text flush disabled",
+			request:    []byte("flush_all\r\n"),
+			response:   []byte("ERROR\r\n"),
+			contentKey: "flush_all",
+			status:     statusError,
+			errorMsg:   "ERROR",
+		},
+		{
+			name:       "text client error",
+			request:    []byte("set user:1 0 0 abc\r\n"),
+			response:   []byte("CLIENT_ERROR bad command line format\r\n"),
+			contentKey: "set",
+			status:     statusError,
+			errorMsg:   "CLIENT_ERROR bad command line format",
+		},
+		{
+			name:       "meta get miss",
+			request:    []byte("mg user:1 v\r\n"),
+			response:   []byte("EN\r\n"),
+			contentKey: "mg",
+			status:     statusMiss,
+		},
+		{
+			name:       "binary get hit",
+			request:    binaryPacket(magicRequest, 0x00, 0, 7, "user:1", ""),
+			response:   binaryPacket(magicResponse, 0x00, binaryNoError, 7, "", "hello"),
+			contentKey: "get",
+			status:     statusHit,
+		},
+		{
+			name:       "binary get miss",
+			request:    binaryPacket(magicRequest, 0x00, 0, 7, "user:1", ""),
+			response:   binaryPacket(magicResponse, 0x00, binaryKeyNotFound, 7, "", "Not found"),
+			contentKey: "get",
+			status:     statusMiss,
+		},
+		{
+			name: "binary quiet get miss",
+			request: testutil.Join(
+				binaryPacket(magicRequest, 0x09, 0, 1, "user:1", ""),
+				binaryPacket(magicRequest, 0x0a, 0, 2, "", ""),
+			),
+			response:   binaryPacket(magicResponse, 0x0a, binaryNoError, 2, "", ""),
+			contentKey: "get",
+			status:     statusMiss,
+		},
+		{
+			name: "binary quiet get hit",
+			request: testutil.Join(
+				binaryPacket(magicRequest, 0x09, 0, 1, "user:1", ""),
+				binaryPacket(magicRequest, 0x0a, 0, 2, "", ""),
+			),
+			response: testutil.Join(
+				binaryPacket(magicResponse, 0x09, binaryNoError, 1, "", "hello"),
+				binaryPacket(magicResponse, 0x0a, binaryNoError, 2, "", ""),
+			),
+			contentKey: "get",
+			status:     statusHit,
+		},
+		{
+			name:       "binary incr on non-numeric value",
+			request:    binaryPacket(magicRequest, 0x05, 0, 3, "user:1", ""),
+			response:   binaryPacket(magicResponse, 0x05, 0x0006, 3, "", "Non-numeric server-side value for incr or decr"),
+			contentKey: "incr",
+			status:     statusError,
+			errorMsg:   "Incr/Decr on non-numeric value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := protocol.NewRequestMessage(tt.request)
+			if !parser.ParseRequest(request) {
+				t.Fatalf("failed to parse the request")
+			}
+			message := protocol.NewResponseMessage(tt.response, request.GetAttributes())
+			if !parser.ParseResponse(message) {
+				t.Fatalf("failed to parse the response")
+			}
+			if got := message.GetStringAttribute(constlabels.ContentKey); got != tt.contentKey {
+				t.Errorf("content key = %q, want %q", got, tt.contentKey)
+			}
+			if got := message.GetStringAttribute(constlabels.MemcachedStatus); got != tt.status {
+				t.Errorf("status = %q, want %q", got, tt.status)
+			}
+			if got := message.GetBoolAttribute(constlabels.IsError); got != (tt.status == statusError) {
+				t.Errorf("is_error = %v, want %v", got, tt.status == statusError)
+			}
+			if got := message.GetStringAttribute(constlabels.MemcachedErrorMsg); got != tt.errorMsg {
+				t.Errorf("error msg = %q, want %q", got, tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestParseRequest_NotMemcached(t *testing.T) {
+	parser := NewMemcachedParser()
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "http", data: []byte("GET /index.html HTTP/1.1\r\n\r\n")},
+		{name: "redis", data: []byte("*2\r\n$3\r\nGET\r\n$6\r\nuser:1\r\n")},
+		{name: "unknown text command", data: []byte("hello world\r\n")},
+		{name: "unknown opcode", data: binaryPacket(magicRequest, 0xff, 0, 1, "user:1", "")},
+		{name: "truncated binary header", data: binaryPacket(magicRequest, 0x00, 0, 1, "user:1", "")[:16]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if parser.ParseRequest(protocol.NewRequestMessage(tt.data)) {
+				t.Errorf("the data should not be parsed as Memcached")
+			}
+		})
+	}
+}
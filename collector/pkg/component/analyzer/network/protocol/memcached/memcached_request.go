@@ -0,0 +1,74 @@
+package memcached
+
+import (
+	"bytes"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+// textCommands are the commands of the text protocol, including the meta commands.
+var textCommands = map[string]bool{
+	"get": true, "gets": true, "gat": true, "gats": true,
+	"set": true, "add": true, "replace": true, "append": true, "prepend": true, "cas": true,
+	"delete": true, "incr": true, "decr": true, "touch": true,
+	"stats": true, "flush_all": true, "version": true, "verbosity": true, "quit": true,
+	"mg": true, "ms": true, "md": true, "ma": true, "mn": true, "me": true,
+}
+
+// isRetrieval returns whether the reply END of the command means the keys are not found.
+func isRetrieval(command string) bool {
+	switch command {
+	case "get", "gets", "gat", "gats":
+		return true
+	}
+	return false
+}
+
+func fastfailMemcachedRequest() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < 4
+	}
+}
+
+/*
+The text commands are lines of the command and its arguments, and the storage commands are
+followed by the data blocks.
+
+	get <key>*\r\n
+	set <key> <flags> <exptime> <bytes> [noreply]\r\n<data block>\r\n
+	delete <key> [noreply]\r\n
+
+The binary commands start with the magic 0x80.
+*/
+func parseMemcachedRequest() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		if message.Data[0] == magicRequest {
+			headers := readBinaryHeaders(message.Data, magicRequest)
+			if len(headers) == 0 {
+				return false, true
+			}
+			h := headers[0]
+			message.AddIntAttribute(constlabels.MemcachedOpcode, int64(h.opcode))
+			message.AddIntAttribute(constlabels.MemcachedOpaque, int64(h.opaque))
+			message.AddStringAttribute(constlabels.ContentKey, binaryOpcodes[h.opcode].command)
+			return true, true
+		}
+
+		_, line := message.ReadUntilCRLF(0)
+		if line == nil {
+			return false, true
+		}
+		command := line
+		if index := bytes.IndexByte(line, ' '); index >= 0 {
+			command = line[:index]
+		}
+		// The commands are case sensitive, which also keeps the HTTP requests away.
+		name := string(command)
+		if !textCommands[name] {
+			return false, true
+		}
+		message.AddStringAttribute(constlabels.ContentKey, name)
+		return true, true
+	}
+}
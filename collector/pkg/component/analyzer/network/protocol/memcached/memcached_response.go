@@ -0,0 +1,118 @@
+package memcached
+
+import (
+	"bytes"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func fastfailMemcachedResponse() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < 2
+	}
+}
+
+/*
+The status of the text replies
+
+	hit     VALUE <key> <flags> <bytes> [<cas unique>]\r\n, STORED, DELETED, TOUCHED, OK,
+	        VERSION, STAT, the value of incr/decr, or the meta replies HD, VA and MN
+	miss    END without any values, NOT_STORED, EXISTS, NOT_FOUND, or the meta replies EN, NF,
+	        NS and EX
+	error   ERROR, CLIENT_ERROR <error>, SERVER_ERROR <error>
+*/
+func parseMemcachedResponse() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		if message.Data[0] == magicResponse {
+			return parseBinaryResponse(message), true
+		}
+
+		_, line := message.ReadUntilCRLF(0)
+		if line == nil {
+			return false, true
+		}
+		word := line
+		if index := bytes.IndexByte(line, ' '); index >= 0 {
+			word = line[:index]
+		}
+		var status string
+		switch string(word) {
+		case "VALUE", "STORED", "DELETED", "TOUCHED", "OK", "RESET", "VERSION", "STAT", "HD", "VA", "MN", "ME":
+			status = statusHit
+		case "END":
+			if isRetrieval(message.GetStringAttribute(constlabels.ContentKey)) {
+				status = statusMiss
+			} else {
+				status = statusHit
+			}
+		case "NOT_STORED", "EXISTS", "NOT_FOUND", "EN", "NF", "NS", "EX":
+			status = statusMiss
+		case "ERROR", "CLIENT_ERROR", "SERVER_ERROR":
+			status = statusError
+		default:
+			if !isNumber(word) {
+				return false, true
+			}
+			// The value of incr/decr
+			status = statusHit
+		}
+		message.AddStringAttribute(constlabels.MemcachedStatus, status)
+		if status == statusError {
+			message.AddBoolAttribute(constlabels.IsError, true)
+			message.AddIntAttribute(constlabels.ErrorType, int64(constlabels.ProtocolError))
+			message.AddByteArrayUtf8Attribute(constlabels.MemcachedErrorMsg, line)
+		}
+		return true, true
+	}
+}
+
+// parseBinaryResponse takes the response with the opaque of the request. If it's not found, the
+// request is quiet, which means a miss of a get or the success of the others.
+func parseBinaryResponse(message *protocol.PayloadMessage) bool {
+	headers := readBinaryHeaders(message.Data, magicResponse)
+	if len(headers) == 0 {
+		return false
+	}
+	var status string
+	var errorMsg string
+	if !message.HasAttribute(constlabels.MemcachedOpaque) {
+		status = getBinaryStatus(headers[0].status)
+		errorMsg = getBinaryErrorMsg(headers[0].status)
+	} else {
+		opaque := uint32(message.GetIntAttribute(constlabels.MemcachedOpaque))
+		for _, h := range headers {
+			if h.opaque == opaque {
+				status = getBinaryStatus(h.status)
+				errorMsg = getBinaryErrorMsg(h.status)
+				break
+			}
+		}
+		if len(status) == 0 {
+			opcode := binaryOpcodes[byte(message.GetIntAttribute(constlabels.MemcachedOpcode))]
+			if opcode.quiet && isRetrieval(opcode.command) {
+				status = statusMiss
+			} else {
+				status = statusHit
+			}
+		}
+	}
+	message.AddStringAttribute(constlabels.MemcachedStatus, status)
+	if status == statusError {
+		message.AddBoolAttribute(constlabels.IsError, true)
+		message.AddIntAttribute(constlabels.ErrorType, int64(constlabels.ProtocolError))
+		if len(errorMsg) > 0 {
+			message.AddStringAttribute(constlabels.MemcachedErrorMsg, errorMsg)
+		}
+	}
+	return true
+}
+
+func isNumber(word []byte) bool {
+	for _, c := range word {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return len(word) > 0
+}
@@ -0,0 +1,179 @@
+package mongodb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+const (
+	bsonDouble     = 0x01
+	bsonString     = 0x02
+	bsonDocument   = 0x03
+	bsonArray      = 0x04
+	bsonBinary     = 0x05
+	bsonUndefined  = 0x06
+	bsonObjectId   = 0x07
+	bsonBoolean    = 0x08
+	bsonDatetime   = 0x09
+	bsonNull       = 0x0a
+	bsonRegex      = 0x0b
+	bsonDBPointer  = 0x0c
+	bsonJavaScript = 0x0d
+	bsonSymbol     = 0x0e
+	bsonCodeWScope = 0x0f
+	bsonInt32      = 0x10
+	bsonTimestamp  = 0x11
+	bsonInt64      = 0x12
+	bsonDecimal128 = 0x13
+	bsonMinKey     = 0xff
+	bsonMaxKey     = 0x7f
+
+	minDocumentLength = 5
+)
+
+type bsonElement struct {
+	kind  byte
+	name  string
+	value []byte
+}
+
+/*
+readDocument reads the elements of a document. The elements before the end of the data are
+returned if the document is truncated. False is returned if the document is malformed.
+
+https://bsonspec.org/spec.html
+
+	document ::= int32 e_list "\x00"
+	e_list   ::= element e_list | ""
+	element  ::= byte e_name value
+*/
+func readDocument(data []byte) ([]bsonElement, bool) {
+	if len(data) < 4 {
+		return nil, false
+	}
+	length := int(int32(binary.LittleEndian.Uint32(data)))
+	if length < minDocumentLength {
+		return nil, false
+	}
+	if length < len(data) {
+		data = data[:length]
+	}
+	var elements []bsonElement
+	offset := 4
+	for offset < len(data) {
+		kind := data[offset]
+		if kind == 0 {
+			return elements, offset == length-1
+		}
+		end := bytes.IndexByte(data[offset+1:], 0)
+		if end < 0 {
+			// Truncated
+			return elements, len(data) < length
+		}
+		name := string(data[offset+1 : offset+1+end])
+		offset += end + 2
+		size, ok := valueSize(kind, data[offset:])
+		if !ok {
+			return nil, false
+		}
+		if offset+size > len(data) {
+			// Truncated
+			return elements, len(data) < length
+		}
+		elements = append(elements, bsonElement{kind: kind, name: name, value: data[offset : offset+size]})
+		offset += size
+	}
+	return elements, len(data) < length
+}
+
+// valueSize returns the size of the value. The size could exceed the data if it is truncated,
+// and false is returned if the type is unknown or the size is invalid.
+func valueSize(kind byte, data []byte) (int, bool) {
+	switch kind {
+	case bsonUndefined, bsonNull, bsonMinKey, bsonMaxKey:
+		return 0, true
+	case bsonBoolean:
+		return 1, true
+	case bsonInt32:
+		return 4, true
+	case bsonDouble, bsonDatetime, bsonTimestamp, bsonInt64:
+		return 8, true
+	case bsonObjectId:
+		return 12, true
+	case bsonDecimal128:
+		return 16, true
+	case bsonString, bsonJavaScript, bsonSymbol:
+		return sizedValue(data, 4, 1)
+	case bsonDocument, bsonArray, bsonCodeWScope:
+		return sizedValue(data, 0, minDocumentLength)
+	case bsonBinary:
+		return sizedValue(data, 5, 0)
+	case bsonDBPointer:
+		size, ok := sizedValue(data, 4, 1)
+		return size + 12, ok
+	case bsonRegex:
+		// Two strings terminated by zero
+		first := bytes.IndexByte(data, 0)
+		if first < 0 {
+			return len(data) + 1, true
+		}
+		second := bytes.IndexByte(data[first+1:], 0)
+		if second < 0 {
+			return len(data) + 1, true
+		}
+		return first + second + 2, true
+	}
+	return 0, false
+}
+
+// sizedValue returns the size of the value starting with an int32 length, which is extra bytes
+// shorter than the size.
+func sizedValue(data []byte, extra int, minLength int) (int, bool) {
+	if len(data) < 4 {
+		return 4 + extra, true
+	}
+	length := int(int32(binary.LittleEndian.Uint32(data)))
+	if length < minLength {
+		return 0, false
+	}
+	return length + extra, true
+}
+
+func (e *bsonElement) stringValue() (string, bool) {
+	if e.kind != bsonString || len(e.value) < 5 {
+		return "", false
+	}
+	return string(e.value[4 : len(e.value)-1]), true
+}
+
+// numberValue converts the numeric values to int64, and the booleans to 0 or 1.
+func (e *bsonElement) numberValue() (int64, bool) {
+	switch e.kind {
+	case bsonDouble:
+		return int64(math.Float64frombits(binary.LittleEndian.Uint64(e.value))), true
+	case bsonInt32:
+		return int64(int32(binary.LittleEndian.Uint32(e.value))), true
+	case bsonInt64:
+		return int64(binary.LittleEndian.Uint64(e.value)), true
+	case bsonBoolean:
+		return int64(e.value[0]), true
+	}
+	return 0, false
+}
+
+func (e *bsonElement) documentValue() ([]bsonElement, bool) {
+	if e.kind != bsonDocument && e.kind != bsonArray {
+		return nil, false
+	}
+	return readDocument(e.value)
+}
+
+func findElement(elements []bsonElement, name string) *bsonElement {
+	for i := range elements {
+		if elements[i].name == name {
+			return &elements[i]
+		}
+	}
+	return nil
+}
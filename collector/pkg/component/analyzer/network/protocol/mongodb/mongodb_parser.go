@@ -0,0 +1,148 @@
+package mongodb
+
+import (
+	"encoding/binary"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+const (
+	headerLength = 16
+	// maxMessageLength is the default maxMessageSizeBytes of MongoDB.
+	maxMessageLength = 48000000
+	// maxNameLength is the limit of the namespaces.
+	maxNameLength = 255
+
+	opReply       = 1
+	opUpdate      = 2001
+	opInsert      = 2002
+	opQuery       = 2004
+	opGetMore     = 2005
+	opDelete      = 2006
+	opKillCursors = 2007
+	opCompressed  = 2012
+	opMsg         = 2013
+
+	flagChecksumPresent = 1 << 0
+	flagMoreToCome      = 1 << 1
+	flagExhaustAllowed  = 1 << 16
+)
+
+/*
+NewMongodbParser parses the messages of the MongoDB wire protocol.
+
+	Request                                  Response
+	/            \                           /         \
+	OP_MSG       OP_QUERY/OP_GET_MORE        OP_MSG    OP_REPLY
+
+The responses are paired with the requests by the responseTo in their headers, which is the
+requestID of the request, so the operations pipelined on a connection are paired correctly.
+The requests expecting no responses, like the ones with the flag moreToCome, are ignored.
+*/
+func NewMongodbParser() *protocol.ProtocolParser {
+	requestParser := protocol.CreatePkgParser(fastfailMongodbRequest(), parseMongodbRequest())
+	responseParser := protocol.CreatePkgParser(fastfailMongodbResponse(), parseMongodbResponse())
+
+	parser := protocol.NewProtocolParser(protocol.MONGODB, requestParser, responseParser, mongodbPair())
+	parser.EnableMultiplexing()
+	return parser
+}
+
+func mongodbPair() protocol.PairMatch {
+	return func(requests []*protocol.PayloadMessage, response *protocol.PayloadMessage) int {
+		requestId := response.GetIntAttribute(constlabels.MongodbRequestId)
+		for i, request := range requests {
+			if request.IsIgnored() || !request.HasAttribute(constlabels.MongodbRequestId) {
+				continue
+			}
+			if request.GetIntAttribute(constlabels.MongodbRequestId) == requestId {
+				return i
+			}
+		}
+		return -1
+	}
+}
+
+/*
+https://www.mongodb.com/docs/manual/reference/mongodb-wire-protocol/#standard-message-header
+
+	int32   messageLength
+	int32   requestID
+	int32   responseTo
+	int32   opCode
+*/
+type header struct {
+	messageLength int
+	requestId     int32
+	responseTo    int32
+	opCode        int32
+}
+
+func readHeader(data []byte) (*header, bool) {
+	if len(data) < headerLength {
+		return nil, false
+	}
+	h := &header{
+		messageLength: int(int32(binary.LittleEndian.Uint32(data))),
+		requestId:     int32(binary.LittleEndian.Uint32(data[4:])),
+		responseTo:    int32(binary.LittleEndian.Uint32(data[8:])),
+		opCode:        int32(binary.LittleEndian.Uint32(data[12:])),
+	}
+	if h.messageLength < headerLength || h.messageLength > maxMessageLength {
+		return nil, false
+	}
+	return h, true
+}
+
+// body returns the message without the header, which is shorter than the message if the data
+// is truncated.
+func (h *header) body(data []byte) []byte {
+	if len(data) > h.messageLength {
+		data = data[:h.messageLength]
+	}
+	return data[headerLength:]
+}
+
+/*
+https://github.com/mongodb/specifications/blob/master/source/message/OP_MSG.rst
+
+	uint32      flagBits
+	Sections[]  sections
+	optional<uint32> checksum
+
+The section of kind 0 is the body of the command, while the ones of kind 1 are the documents
+sequences, like the documents inserted. The body is read only.
+*/
+func readOpMsgBody(data []byte) (uint32, []bsonElement, bool) {
+	if len(data) < 5 {
+		return 0, nil, false
+	}
+	flags := binary.LittleEndian.Uint32(data)
+	if flags&^(flagChecksumPresent|flagMoreToCome|flagExhaustAllowed) != 0 {
+		return 0, nil, false
+	}
+	offset := 4
+	for offset < len(data) {
+		kind := data[offset]
+		offset++
+		switch kind {
+		case 0:
+			elements, ok := readDocument(data[offset:])
+			return flags, elements, ok
+		case 1:
+			if len(data)-offset < 4 {
+				return flags, nil, true
+			}
+			size := int(int32(binary.LittleEndian.Uint32(data[offset:])))
+			if size < 4 {
+				return 0, nil, false
+			}
+			offset += size
+		default:
+			return 0, nil, false
+		}
+	}
+	// The body is not captured.
+	return flags, nil, true
+}
@@ -0,0 +1,236 @@
+package mongodb
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/internal/testutil"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+// element is a BSON element used to build the documents in the tests.
+type element struct {
+	name  string
+	value interface{}
+}
+
+func document(elements ...element) []byte {
+	var list []byte
+	for _, e := range elements {
+		var kind byte
+		var value []byte
+		switch v := e.value.(type) {
+		case string:
+			kind = bsonString
+			value = append(append(testutil.Int32LE(len(v)+1), v...), 0)
+		case int32:
+			kind = bsonInt32
+			value = testutil.Int32LE(int(v))
+		case float64:
+			kind = bsonDouble
+			value = make([]byte, 8)
+			binary.LittleEndian.PutUint64(value, math.Float64bits(v))
+		case []byte:
+			kind = bsonDocument
+			value = v
+		case [][]byte:
+			kind = bsonArray
+			var items []element
+			for i, item := range v {
+				items = append(items, element{name: string(rune('0' + i)), value: item})
+			}
+			value = document(items...)
+		}
+		list = append(append(append(append(list, kind), e.name...), 0), value...)
+	}
+	return append(append(testutil.Int32LE(len(list)+5), list...), 0)
+}
+
+func newMessage(requestId int32, responseTo int32, opCode int32, body ...[]byte) []byte {
+	data := testutil.Join(body...)
+	header := append(append(append(testutil.Int32LE(len(data)+headerLength), testutil.Int32LE(int(requestId))...),
+		testutil.Int32LE(int(responseTo))...), testutil.Int32LE(int(opCode))...)
+	return append(header, data...)
+}
+
+func newOpMsg(requestId int32, responseTo int32, flags int32, body []byte) []byte {
+	return newMessage(requestId, responseTo, opMsg, testutil.Int32LE(int(flags)), []byte{0}, body)
+}
+
+func TestParseRequest(t *testing.T) {
+	parser := NewMongodbParser()
+	tests := []struct {
+		name       string
+		data       []byte
+		ignored    bool
+		contentKey string
+	}{
+		{
+			name:       "find",
+			data:       newOpMsg(7, 0, 0, document(element{"find", "orders"}, element{"filter", document()}, element{"$db", "test"})),
+			contentKey: "find orders",
+		},
+		{
+			name: "getMore",
+			data: newOpMsg(7, 0, 0, document(element{"getMore", int32(1)}, element{"collection", "orders"},
+				element{"$db", "test"})),
+			contentKey: "getMore orders",
+		},
+		{
+			name: "insert with document sequence",
+			data: newMessage(7, 0, opMsg, testutil.Int32LE(0),
+				append(append(append([]byte{1}, testutil.Int32LE(4+len("documents")+1+len(document()))...), "documents\x00"...), document()...),
+				[]byte{0}, document(element{"insert", "orders"}, element{"$db", "test"})),
+			contentKey: "insert orders",
+		},
+		{
+			name:       "ping",
+			data:       newOpMsg(7, 0, 0, document(element{"ping", int32(1)}, element{"$db", "admin"})),
+			contentKey: "ping",
+		},
+		{
+			name:       "unacknowledged write",
+			data:       newOpMsg(7, 0, flagMoreToCome, document(element{"delete", "orders"}, element{"$db", "test"})),
+			ignored:    true,
+			contentKey: "delete orders",
+		},
+		{
+			name: "legacy command",
+			data: newMessage(7, 0, opQuery, testutil.Int32LE(0), []byte("admin.$cmd\x00"), testutil.Int32LE(0), testutil.Int32LE(-1),
+				document(element{"isMaster", int32(1)})),
+			contentKey: "isMaster",
+		},
+		{
+			name:       "legacy query",
+			data:       newMessage(7, 0, opQuery, testutil.Int32LE(0), []byte("test.orders\x00"), testutil.Int32LE(0), testutil.Int32LE(0), document()),
+			contentKey: "find orders",
+		},
+		{
+			name: "truncated",
+			data: newOpMsg(7, 0, 0, document(element{"aggregate", "orders"},
+				element{"pipeline", [][]byte{document(element{"$match", document(element{"status", "A"})})}}))[:50],
+			contentKey: "aggregate orders",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := protocol.NewRequestMessage(tt.data)
+			if !parser.ParseRequest(message) {
+				t.Fatalf("failed to parse the request")
+			}
+			if got := message.IsIgnored(); got != tt.ignored {
+				t.Errorf("ignored = %v, want %v", got, tt.ignored)
+			}
+			if got := message.GetIntAttribute(constlabels.MongodbRequestId); got != 7 {
+				t.Errorf("request id = %d, want 7", got)
+			}
+			if got := message.GetStringAttribute(constlabels.ContentKey); got != tt.contentKey {
+				t.Errorf("content key = %q, want %q", got, tt.contentKey)
+			}
+		})
+	}
+}
+
+func TestParseRequest_NotMongodb(t *testing.T) {
+	parser := NewMongodbParser()
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "http", data: []byte("GET /index.html HTTP/1.1\r\n\r\n")},
+		{name: "response", data: newOpMsg(8, 7, 0, document(element{"ok", float64(1)}))},
+		{name: "unknown flags", data: newOpMsg(7, 0, 4, document(element{"find", "orders"}))},
+		{name: "unknown opcode", data: newMessage(7, 0, 1000, document())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if parser.ParseRequest(protocol.NewRequestMessage(tt.data)) {
+				t.Errorf("the data should not be parsed as MongoDB request")
+			}
+		})
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	parser := NewMongodbParser()
+	tests := []struct {
+		name      string
+		data      []byte
+		isError   bool
+		errorCode int64
+		errorMsg  string
+	}{
+		{
+			name: "ok",
+			data: newOpMsg(8, 7, 0, document(element{"cursor", document(element{"id", int32(0)})}, element{"ok", float64(1)})),
+		},
+		{
+			name: "command error",
+			data: newOpMsg(8, 7, 0, document(element{"ok", float64(0)}, element{"errmsg", "ns not found"},
+				element{"code", int32(26)}, element{"codeName", "NamespaceNotFound"})),
+			isError:   true,
+			errorCode: 26,
+			errorMsg:  "ns not found",
+		},
+		{
+			name: "write error",
+			data: newOpMsg(8, 7, 0, document(element{"n", int32(0)},
+				element{"writeErrors", [][]byte{document(element{"index", int32(0)}, element{"code", int32(11000)},
+					element{"errmsg", "E11000 duplicate key error"})}},
+				element{"ok", float64(1)})),
+			isError:   true,
+			errorCode: 11000,
+			errorMsg:  "E11000 duplicate key error",
+		},
+		{
+			name: "legacy query failure",
+			data: newMessage(8, 7, opReply, testutil.Int32LE(int(replyFlagQueryFailure)), make([]byte, 8), testutil.Int32LE(0), testutil.Int32LE(1),
+				document(element{"$err", "not authorized"}, element{"code", int32(13)})),
+			isError:   true,
+			errorCode: 13,
+			errorMsg:  "not authorized",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := protocol.NewResponseMessage(tt.data, model.NewAttributeMap())
+			if !parser.ParseResponse(message) {
+				t.Fatalf("failed to parse the response")
+			}
+			if got := message.GetIntAttribute(constlabels.MongodbRequestId); got != 7 {
+				t.Errorf("request id = %d, want 7", got)
+			}
+			if got := message.GetBoolAttribute(constlabels.IsError); got != tt.isError {
+				t.Errorf("is_error = %v, want %v", got, tt.isError)
+			}
+			if got := message.GetIntAttribute(constlabels.MongodbErrorCode); got != tt.errorCode {
+				t.Errorf("error code = %d, want %d", got, tt.errorCode)
+			}
+			if got := message.GetStringAttribute(constlabels.MongodbErrorMsg); got != tt.errorMsg {
+				t.Errorf("error message = %q, want %q", got, tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestPairMatch(t *testing.T) {
+	parser := NewMongodbParser()
+	var requests []*protocol.PayloadMessage
+	for _, requestId := range []int32{11, 12, 13} {
+		request := protocol.NewRequestMessage(newOpMsg(requestId, 0, 0, document(element{"find", "orders"})))
+		if !parser.ParseRequest(request) {
+			t.Fatalf("failed to parse the request")
+		}
+		requests = append(requests, request)
+	}
+	response := protocol.NewResponseMessage(newOpMsg(21, 12, 0, document(element{"ok", float64(1)})), model.NewAttributeMap())
+	if !parser.ParseResponse(response) {
+		t.Fatalf("failed to parse the response")
+	}
+	if got := parser.PairMatch(requests, response); got != 1 {
+		t.Errorf("pair match = %d, want 1", got)
+	}
+}
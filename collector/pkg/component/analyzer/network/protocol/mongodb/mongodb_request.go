@@ -0,0 +1,141 @@
+package mongodb
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func fastfailMongodbRequest() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < headerLength
+	}
+}
+
+/*
+The content key is the command name followed by the collection, like "find orders". The command
+name is the first key of the command document, whose value is the collection for most commands.
+
+	{ find: "orders", filter: { status: "A" }, $db: "test" }
+*/
+func parseMongodbRequest() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		h, ok := readHeader(message.Data)
+		if !ok || h.responseTo != 0 {
+			return false, true
+		}
+		body := h.body(message.Data)
+		var contentKey string
+		switch h.opCode {
+		case opMsg:
+			flags, elements, ok := readOpMsgBody(body)
+			if !ok {
+				return false, true
+			}
+			if flags&flagMoreToCome != 0 {
+				// No response is expected.
+				message.Ignore()
+			}
+			contentKey = getCommand(elements)
+		case opQuery:
+			contentKey, ok = parseOpQuery(body)
+			if !ok {
+				return false, true
+			}
+		case opGetMore:
+			// int32 ZERO, cstring fullCollectionName, int32 numberToReturn, int64 cursorID
+			if len(body) < 5 {
+				return false, true
+			}
+			if collection, ok := readCString(body[4:]); ok {
+				contentKey = "getMore " + trimDatabase(collection)
+			}
+		case opCompressed:
+			// The compressed message is not decoded.
+		case opInsert, opUpdate, opDelete, opKillCursors:
+			// The legacy operations expecting no responses
+			message.Ignore()
+		default:
+			return false, true
+		}
+		message.AddIntAttribute(constlabels.MongodbRequestId, int64(h.requestId))
+		if len(contentKey) > 0 {
+			message.AddUtf8StringAttribute(constlabels.ContentKey, contentKey)
+		}
+		return true, true
+	}
+}
+
+/*
+	int32     flags
+	cstring   fullCollectionName
+	int32     numberToSkip
+	int32     numberToReturn
+	document  query
+	[ document  returnFieldsSelector; ]
+
+The commands are sent to the collection "{database}.$cmd".
+*/
+func parseOpQuery(body []byte) (string, bool) {
+	if len(body) < 5 {
+		return "", false
+	}
+	fullCollectionName, ok := readCString(body[4:])
+	if !ok {
+		return "", len(body) < 4+maxNameLength
+	}
+	collection := trimDatabase(fullCollectionName)
+	if collection != "$cmd" {
+		return "find " + collection, true
+	}
+	offset := 4 + len(fullCollectionName) + 1 + 8
+	if offset >= len(body) {
+		return "", true
+	}
+	elements, ok := readDocument(body[offset:])
+	if !ok {
+		return "", false
+	}
+	return getCommand(elements), true
+}
+
+func getCommand(elements []bsonElement) string {
+	if len(elements) == 0 {
+		return ""
+	}
+	command := &elements[0]
+	if command.name == "$query" || command.name == "query" {
+		// The command wrapped with the read preference, like { $query: { ... }, $readPreference: { ... } }
+		if inner, ok := command.documentValue(); ok {
+			return getCommand(inner)
+		}
+	}
+	if collection, ok := command.stringValue(); ok && len(collection) > 0 {
+		return command.name + " " + collection
+	}
+	// Like { getMore: NumberLong(...), collection: "orders" }
+	if element := findElement(elements, "collection"); element != nil {
+		if collection, ok := element.stringValue(); ok {
+			return command.name + " " + collection
+		}
+	}
+	return command.name
+}
+
+func readCString(data []byte) (string, bool) {
+	end := bytes.IndexByte(data, 0)
+	if end < 0 {
+		return "", false
+	}
+	return string(data[:end]), true
+}
+
+// trimDatabase returns the collection of the namespace "{database}.{collection}".
+func trimDatabase(namespace string) string {
+	if index := strings.IndexByte(namespace, '.'); index >= 0 {
+		return namespace[index+1:]
+	}
+	return namespace
+}
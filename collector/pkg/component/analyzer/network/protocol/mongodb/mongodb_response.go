@@ -0,0 +1,119 @@
+package mongodb
+
+import (
+	"encoding/binary"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+const (
+	replyFlagQueryFailure = 1 << 1
+	// replyHeaderLength is the length of the fields of OP_REPLY before the documents.
+	replyHeaderLength = 20
+)
+
+func fastfailMongodbResponse() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < headerLength
+	}
+}
+
+/*
+The reply of a command is a document with the field ok, which is 0 if the command fails.
+
+	{ ok: 0, errmsg: "ns not found", code: 26, codeName: "NamespaceNotFound" }
+
+The write commands may fail partially, in which case the errors are in writeErrors.
+
+	{ n: 0, writeErrors: [ { index: 0, code: 11000, errmsg: "E11000 duplicate key error ..." } ], ok: 1 }
+*/
+func parseMongodbResponse() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		h, ok := readHeader(message.Data)
+		if !ok || h.responseTo == 0 {
+			return false, true
+		}
+		body := h.body(message.Data)
+		switch h.opCode {
+		case opMsg:
+			_, elements, ok := readOpMsgBody(body)
+			if !ok {
+				return false, true
+			}
+			parseReply(message, elements, false)
+		case opReply:
+			/*
+				int32     responseFlags
+				int64     cursorID
+				int32     startingFrom
+				int32     numberReturned
+				document* documents
+			*/
+			if len(body) < 4 {
+				return false, true
+			}
+			flags := binary.LittleEndian.Uint32(body)
+			var elements []bsonElement
+			if len(body) > replyHeaderLength {
+				if elements, ok = readDocument(body[replyHeaderLength:]); !ok {
+					return false, true
+				}
+			}
+			parseReply(message, elements, flags&replyFlagQueryFailure != 0)
+		case opCompressed:
+			// The compressed message is not decoded.
+		default:
+			return false, true
+		}
+		message.AddIntAttribute(constlabels.MongodbRequestId, int64(h.responseTo))
+		return true, true
+	}
+}
+
+func parseReply(message *protocol.PayloadMessage, elements []bsonElement, failed bool) {
+	if okElement := findElement(elements, "ok"); okElement != nil {
+		if value, isNumber := okElement.numberValue(); isNumber && value == 0 {
+			failed = true
+		}
+	}
+	errorElements := elements
+	if !failed {
+		// Only the first error is taken.
+		for _, name := range []string{"writeErrors", "writeConcernError"} {
+			element := findElement(elements, name)
+			if element == nil {
+				continue
+			}
+			errors, _ := element.documentValue()
+			if name == "writeErrors" {
+				if len(errors) == 0 {
+					continue
+				}
+				errors, _ = errors[0].documentValue()
+			}
+			errorElements = errors
+			failed = true
+			break
+		}
+	}
+	if !failed {
+		return
+	}
+
+	message.AddBoolAttribute(constlabels.IsError, true)
+	message.AddIntAttribute(constlabels.ErrorType, int64(constlabels.ProtocolError))
+	if code := findElement(errorElements, "code"); code != nil {
+		if value, ok := code.numberValue(); ok {
+			message.AddIntAttribute(constlabels.MongodbErrorCode, value)
+		}
+	}
+	for _, name := range []string{"errmsg", "$err"} {
+		if errmsg := findElement(errorElements, name); errmsg != nil {
+			if value, ok := errmsg.stringValue(); ok {
+				message.AddUtf8StringAttribute(constlabels.MongodbErrorMsg, value)
+				break
+			}
+		}
+	}
+}
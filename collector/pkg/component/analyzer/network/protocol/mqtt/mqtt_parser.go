@@ -0,0 +1,211 @@
+package mqtt
+
+import (
+	"encoding/binary"
+	"unicode/utf8"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+const (
+	typeConnect     = 1
+	typeConnack     = 2
+	typePublish     = 3
+	typePuback      = 4
+	typePubrec      = 5
+	typePubrel      = 6
+	typePubcomp     = 7
+	typeSubscribe   = 8
+	typeSuback      = 9
+	typeUnsubscribe = 10
+	typeUnsuback    = 11
+	typePingreq     = 12
+	typePingresp    = 13
+	typeDisconnect  = 14
+	typeAuth        = 15
+
+	// maxRemainingLength is the largest length encoded in 4 bytes.
+	maxRemainingLength = 268435455
+
+	// packetIdConnect is taken by CONNECT and CONNACK, which have no packet identifiers. The
+	// packet identifiers of the other packets are non-zero.
+	packetIdConnect = 0
+
+	version31  = 3
+	version311 = 4
+	version5   = 5
+	versionKey = "mqtt_version"
+)
+
+var packetTypes = [...]string{
+	"", "CONNECT", "CONNACK", "PUBLISH", "PUBACK", "PUBREC", "PUBREL", "PUBCOMP",
+	"SUBSCRIBE", "SUBACK", "UNSUBSCRIBE", "UNSUBACK", "PINGREQ", "PINGRESP", "DISCONNECT", "AUTH",
+}
+
+/*
+NewMqttParser parses the control packets of MQTT 3.1, 3.1.1 and 5.0.
+
+	Request                                 Response
+	/        |         \                    /        |        \
+	CONNECT  PUBLISH   SUBSCRIBE  ...       CONNACK  PUBACK   SUBACK  ...
+
+The responses are paired with the requests by the packet identifiers, so the PUBLISH of QoS 1
+and 2 is paired with its PUBACK or PUBREC. CONNECT and CONNACK are paired with each other. The
+PUBLISH of QoS 0, the pings and the messages delivered by the servers expect no responses from
+the servers, which are ignored.
+*/
+func NewMqttParser() *protocol.ProtocolParser {
+	requestParser := protocol.CreatePkgParser(fastfailMqttRequest(), parseMqttRequest())
+	responseParser := protocol.CreatePkgParser(fastfailMqttResponse(), parseMqttResponse())
+
+	parser := protocol.NewProtocolParser(protocol.MQTT, requestParser, responseParser, mqttPair())
+	parser.EnableMultiplexing()
+	return parser
+}
+
+func mqttPair() protocol.PairMatch {
+	return func(requests []*protocol.PayloadMessage, response *protocol.PayloadMessage) int {
+		packetId := response.GetIntAttribute(constlabels.MqttPacketId)
+		for i, request := range requests {
+			if request.IsIgnored() || !request.HasAttribute(constlabels.MqttPacketId) {
+				continue
+			}
+			if request.GetIntAttribute(constlabels.MqttPacketId) == packetId {
+				return i
+			}
+		}
+		return -1
+	}
+}
+
+/*
+The fixed header
+
+	bits 7-4      the packet type
+	bits 3-0      the flags, which are DUP, QoS and RETAIN of PUBLISH
+	1-4 bytes     the remaining length, 7 bits in each byte with the continuation bit
+*/
+type fixedHeader struct {
+	packetType byte
+	flags      byte
+	// body is the variable header and the payload, which may be truncated.
+	body []byte
+}
+
+func (h *fixedHeader) qos() byte {
+	return (h.flags >> 1) & 0x03
+}
+
+func readFixedHeader(data []byte) (*fixedHeader, bool) {
+	if len(data) < 2 {
+		return nil, false
+	}
+	h := &fixedHeader{packetType: data[0] >> 4, flags: data[0] & 0x0f}
+	if h.packetType == 0 {
+		return nil, false
+	}
+	switch h.packetType {
+	case typePublish:
+		if h.qos() == 3 {
+			return nil, false
+		}
+	case typePubrel, typeSubscribe, typeUnsubscribe:
+		if h.flags != 0x02 {
+			return nil, false
+		}
+	default:
+		if h.flags != 0 {
+			return nil, false
+		}
+	}
+
+	length, multiplier := 0, 1
+	offset := 1
+	for {
+		if offset >= len(data) || offset > 4 {
+			return nil, false
+		}
+		b := data[offset]
+		offset++
+		length += int(b&0x7f) * multiplier
+		if b&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+	if length > maxRemainingLength {
+		return nil, false
+	}
+	h.body = data[offset:]
+	if len(h.body) > length {
+		h.body = h.body[:length]
+	}
+	if !isValidLength(h.packetType, length) {
+		return nil, false
+	}
+	return h, true
+}
+
+// isValidLength checks the remaining lengths of the packets with fixed sizes.
+func isValidLength(packetType byte, length int) bool {
+	switch packetType {
+	case typePingreq, typePingresp:
+		return length == 0
+	case typeConnack, typePuback, typePubrec, typePubrel, typePubcomp, typeUnsuback:
+		return length >= 2
+	case typeConnect, typePublish, typeSubscribe, typeSuback, typeUnsubscribe:
+		return length >= 3
+	}
+	return true
+}
+
+// readString reads the UTF-8 string prefixed by the 2-byte length.
+func readString(data []byte) (string, []byte, bool) {
+	if len(data) < 2 {
+		return "", nil, false
+	}
+	length := int(binary.BigEndian.Uint16(data))
+	if len(data) < 2+length {
+		return "", nil, false
+	}
+	s := data[2 : 2+length]
+	if !utf8.Valid(s) {
+		return "", nil, false
+	}
+	return string(s), data[2+length:], true
+}
+
+// readPacketId reads the packet identifier, which must be non-zero.
+func readPacketId(data []byte) (uint16, bool) {
+	if len(data) < 2 {
+		return 0, false
+	}
+	packetId := binary.BigEndian.Uint16(data)
+	return packetId, packetId != 0
+}
+
+// skipProperties skips the properties of MQTT 5.0, which start with the length in the variable
+// byte integer.
+func skipProperties(data []byte) ([]byte, bool) {
+	length, multiplier := 0, 1
+	for i := 0; i < 4 && i < len(data); i++ {
+		length += int(data[i]&0x7f) * multiplier
+		if data[i]&0x80 == 0 {
+			if len(data) < i+1+length {
+				return nil, false
+			}
+			return data[i+1+length:], true
+		}
+		multiplier *= 128
+	}
+	return nil, false
+}
+
+// getVersion returns the protocol level of CONNECT, which is 3.1.1 if the CONNECT is not seen.
+func getVersion(message *protocol.PayloadMessage) byte {
+	if version, ok := message.GetConnectionStates().Get(versionKey).(byte); ok {
+		return version
+	}
+	return version311
+}
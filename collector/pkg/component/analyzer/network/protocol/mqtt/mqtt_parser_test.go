@@ -0,0 +1,229 @@
+package mqtt
+
+import (
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/internal/testutil"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func packet(first byte, body ...[]byte) []byte {
+	data := testutil.Join(body...)
+	return testutil.Join([]byte{first}, testutil.Varint(uint64(len(data))), data)
+}
+
+func connect(version byte) []byte {
+	return packet(typeConnect<<4, testutil.String16("MQTT"), []byte{version, 0x02}, testutil.Int16(60), testutil.String16("gateway-1"))
+}
+
+func publish(qos byte, topic string, packetId int) []byte {
+	if qos == 0 {
+		return packet(typePublish<<4, testutil.String16(topic), []byte("23.5"))
+	}
+	return packet(typePublish<<4|qos<<1, testutil.String16(topic), testutil.Int16(packetId), []byte("23.5"))
+}
+
+func TestParseMqtt(t *testing.T) {
+	parser := NewMqttParser()
+	tests := []struct {
+		name       string
+		version    byte
+		request    []byte
+		response   []byte
+		contentKey string
+		packetType string
+		packetId   int64
+		reasonCode int64
+		isError    bool
+	}{
+		{
+			name:       "connect accepted",
+			request:    connect(version311),
+			response:   packet(typeConnack<<4, []byte{0, 0}),
+			packetType: "CONNACK",
+		},
+		{
+			name:       "connect not authorized",
+			request:    connect(version311),
+			response:   packet(typeConnack<<4, []byte{0, 5}),
+			packetType: "CONNACK",
+			reasonCode: 5,
+			isError:    true,
+		},
+		{
+			name:       "connect 5.0 bad user name or password",
+			request:    connect(version5),
+			response:   packet(typeConnack<<4, []byte{0, 0x86, 0}),
+			packetType: "CONNACK",
+			reasonCode: 0x86,
+			isError:    true,
+		},
+		{
+			name:       "publish qos 1",
+			request:    publish(1, "sensors/1/temp", 10),
+			response:   packet(typePuback<<4, testutil.Int16(10)),
+			contentKey: "sensors/1/temp",
+			packetType: "PUBACK",
+			packetId:   10,
+		},
+		{
+			name:       "publish qos 2",
+			request:    publish(2, "sensors/1/temp", 11),
+			response:   packet(typePubrec<<4, testutil.Int16(11)),
+			contentKey: "sensors/1/temp",
+			packetType: "PUBREC",
+			packetId:   11,
+		},
+		{
+			name:       "publish 5.0 not authorized",
+			version:    version5,
+			request:    packet(typePublish<<4|1<<1, testutil.String16("sensors/1/temp"), testutil.Int16(12), []byte{0}, []byte("23.5")),
+			response:   packet(typePuback<<4, testutil.Int16(12), []byte{0x87, 0}),
+			contentKey: "sensors/1/temp",
+			packetType: "PUBACK",
+			packetId:   12,
+			reasonCode: 0x87,
+			isError:    true,
+		},
+		{
+			name:       "subscribe",
+			request:    packet(typeSubscribe<<4|0x02, testutil.Int16(13), testutil.String16("sensors/+/temp"), []byte{1}),
+			response:   packet(typeSuback<<4, testutil.Int16(13), []byte{1}),
+			contentKey: "sensors/+/temp",
+			packetType: "SUBACK",
+			packetId:   13,
+		},
+		{
+			name:       "subscribe failed",
+			request:    packet(typeSubscribe<<4|0x02, testutil.Int16(14), testutil.String16("$SYS/#"), []byte{0}),
+			response:   packet(typeSuback<<4, testutil.Int16(14), []byte{0x80}),
+			contentKey: "$SYS/#",
+			packetType: "SUBACK",
+			packetId:   14,
+			reasonCode: 0x80,
+			isError:    true,
+		},
+		{
+			name:       "subscribe 5.0",
+			version:    version5,
+			request:    packet(typeSubscribe<<4|0x02, testutil.Int16(15), []byte{2, 0x0b, 1}, testutil.String16("alerts/#"), []byte{1}),
+			response:   packet(typeSuback<<4, testutil.Int16(15), []byte{0}, []byte{1, 0x97}),
+			contentKey: "alerts/#",
+			packetType: "SUBACK",
+			packetId:   15,
+			reasonCode: 0x97,
+			isError:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states := protocol.NewConnectionStates()
+			if tt.version != 0 {
+				states.Set(versionKey, tt.version)
+			}
+			request := protocol.NewRequestMessage(tt.request)
+			request.SetConnectionStates(states)
+			if !parser.ParseRequest(request) {
+				t.Fatalf("failed to parse the request")
+			}
+			response := protocol.NewResponseMessage(tt.response, model.NewAttributeMap())
+			response.SetConnectionStates(states)
+			if !parser.ParseResponse(response) {
+				t.Fatalf("failed to parse the response")
+			}
+			if parser.PairMatch([]*protocol.PayloadMessage{request}, response) != 0 {
+				t.Fatalf("the response is not paired with the request")
+			}
+			if got := request.GetStringAttribute(constlabels.ContentKey); got != tt.contentKey {
+				t.Errorf("content key = %q, want %q", got, tt.contentKey)
+			}
+			if got := response.GetStringAttribute(constlabels.MqttPacketType); got != tt.packetType {
+				t.Errorf("packet type = %q, want %q", got, tt.packetType)
+			}
+			if got := response.GetIntAttribute(constlabels.MqttPacketId); got != tt.packetId {
+				t.Errorf("packet id = %d, want %d", got, tt.packetId)
+			}
+			if got := response.GetIntAttribute(constlabels.MqttReasonCode); got != tt.reasonCode {
+				t.Errorf("reason code = %d, want %d", got, tt.reasonCode)
+			}
+			if got := response.GetBoolAttribute(constlabels.IsError); got != tt.isError {
+				t.Errorf("is_error = %v, want %v", got, tt.isError)
+			}
+		})
+	}
+}
+
+func TestParseMqtt_Ignored(t *testing.T) {
+	parser := NewMqttParser()
+	requests := [][]byte{
+		publish(0, "sensors/1/temp", 0),
+		packet(typePingreq << 4),
+		packet(typePuback<<4, testutil.Int16(3)),
+		packet(typeDisconnect << 4),
+	}
+	for _, data := range requests {
+		message := protocol.NewRequestMessage(data)
+		if !parser.ParseRequest(message) {
+			t.Fatalf("failed to parse the request %v", data)
+		}
+		if !message.IsIgnored() {
+			t.Errorf("the request %v should be ignored", data)
+		}
+	}
+	responses := [][]byte{
+		publish(1, "commands/gateway-1", 3),
+		packet(typePingresp << 4),
+		packet(typePubcomp<<4, testutil.Int16(11)),
+	}
+	for _, data := range responses {
+		message := protocol.NewResponseMessage(data, model.NewAttributeMap())
+		if !parser.ParseResponse(message) {
+			t.Fatalf("failed to parse the response %v", data)
+		}
+		if !message.IsIgnored() {
+			t.Errorf("the response %v should be ignored", data)
+		}
+	}
+}
+
+func TestParseRequest_Connect(t *testing.T) {
+	parser := NewMqttParser()
+	states := protocol.NewConnectionStates()
+	message := protocol.NewRequestMessage(connect(version5))
+	message.SetConnectionStates(states)
+	if !parser.ParseRequest(message) {
+		t.Fatalf("failed to parse the request")
+	}
+	if got := states.Get(versionKey); got != byte(version5) {
+		t.Errorf("version = %v, want %v", got, version5)
+	}
+	if got := message.GetStringAttribute(constlabels.MqttPacketType); got != "CONNECT" {
+		t.Errorf("packet type = %q, want CONNECT", got)
+	}
+}
+
+func TestParseRequest_NotMqtt(t *testing.T) {
+	parser := NewMqttParser()
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "http", data: []byte("GET /index.html HTTP/1.1\r\n\r\n")},
+		{name: "unknown protocol name", data: packet(typeConnect<<4, testutil.String16("AMQP"), []byte{4, 0x02}, testutil.Int16(60))},
+		{name: "qos 3", data: packet(typePublish<<4|3<<1, testutil.String16("a"), testutil.Int16(1))},
+		{name: "subscribe flags", data: packet(typeSubscribe<<4, testutil.Int16(1), testutil.String16("a"), []byte{0})},
+		{name: "ping with body", data: packet(typePingreq<<4, []byte{0})},
+		{name: "publish without packet id", data: packet(typePublish<<4|1<<1, testutil.String16("a"), testutil.Int16(0))},
+		{name: "puback without packet id", data: packet(typePuback<<4, testutil.Int16(0))},
+		{name: "response type", data: packet(typeSuback<<4, testutil.Int16(1), []byte{0})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if parser.ParseRequest(protocol.NewRequestMessage(tt.data)) {
+				t.Errorf("the data should not be parsed as MQTT")
+			}
+		})
+	}
+}
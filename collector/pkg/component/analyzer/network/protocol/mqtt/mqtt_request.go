@@ -0,0 +1,103 @@
+package mqtt
+
+import (
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func fastfailMqttRequest() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < 2
+	}
+}
+
+/*
+The topic is the content key of PUBLISH and SUBSCRIBE.
+
+	CONNECT      string protocolName, byte protocolLevel, byte flags, uint16 keepAlive, ...
+	PUBLISH      string topic, [uint16 packetId if QoS > 0], [properties], payload
+	SUBSCRIBE    uint16 packetId, [properties], (string topicFilter, byte options)+
+	UNSUBSCRIBE  uint16 packetId, [properties], (string topicFilter)+
+*/
+func parseMqttRequest() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		h, ok := readFixedHeader(message.Data)
+		if !ok {
+			return false, true
+		}
+		switch h.packetType {
+		case typeConnect:
+			version, ok := readConnect(h.body)
+			if !ok {
+				return false, true
+			}
+			message.GetConnectionStates().Set(versionKey, version)
+			message.AddIntAttribute(constlabels.MqttPacketId, packetIdConnect)
+		case typePublish:
+			topic, rest, ok := readString(h.body)
+			if !ok || len(topic) == 0 {
+				return false, true
+			}
+			message.AddUtf8StringAttribute(constlabels.ContentKey, topic)
+			message.AddIntAttribute(constlabels.MqttQos, int64(h.qos()))
+			if h.qos() == 0 {
+				// No response is expected.
+				message.Ignore()
+				break
+			}
+			packetId, ok := readPacketId(rest)
+			if !ok {
+				return false, true
+			}
+			message.AddIntAttribute(constlabels.MqttPacketId, int64(packetId))
+		case typeSubscribe, typeUnsubscribe:
+			packetId, ok := readPacketId(h.body)
+			if !ok {
+				return false, true
+			}
+			rest := h.body[2:]
+			if getVersion(message) == version5 {
+				if rest, ok = skipProperties(rest); !ok {
+					return false, true
+				}
+			}
+			topic, _, ok := readString(rest)
+			if !ok || len(topic) == 0 {
+				return false, true
+			}
+			message.AddUtf8StringAttribute(constlabels.ContentKey, topic)
+			message.AddIntAttribute(constlabels.MqttPacketId, int64(packetId))
+		case typePuback, typePubrec, typePubrel, typePubcomp:
+			if _, ok := readPacketId(h.body); !ok {
+				return false, true
+			}
+			// The acknowledgements of the messages delivered by the servers expect no responses.
+			// The pairs with only the ignored packets don't tell the protocol, so a port is not
+			// taken as MQTT until a CONNECT, PUBLISH or SUBSCRIBE is paired.
+			message.Ignore()
+		case typePingreq, typeDisconnect, typeAuth:
+			// The heartbeats expect no responses, or their responses are not counted in the latency.
+			message.Ignore()
+		default:
+			return false, true
+		}
+		message.AddStringAttribute(constlabels.MqttPacketType, packetTypes[h.packetType])
+		return true, true
+	}
+}
+
+// readConnect returns the protocol level of "MQTT" for 3.1.1 and 5.0, or "MQIsdp" for 3.1.
+func readConnect(body []byte) (byte, bool) {
+	name, rest, ok := readString(body)
+	if !ok || len(rest) < 1 {
+		return 0, false
+	}
+	version := rest[0]
+	switch {
+	case name == "MQTT" && (version == version311 || version == version5):
+	case name == "MQIsdp" && version == version31:
+	default:
+		return 0, false
+	}
+	return version, true
+}
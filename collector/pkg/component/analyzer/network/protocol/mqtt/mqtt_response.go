@@ -0,0 +1,101 @@
+package mqtt
+
+import (
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func fastfailMqttResponse() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < 2
+	}
+}
+
+/*
+The codes of the responses are failures if they are not 0 in CONNACK, or not less than 0x80 in
+the others.
+
+	CONNACK   byte flags, byte returnCode, [properties]
+	PUBACK    uint16 packetId, [byte reasonCode, properties]
+	PUBREC    uint16 packetId, [byte reasonCode, properties]
+	SUBACK    uint16 packetId, [properties], (byte returnCode)+
+	UNSUBACK  uint16 packetId, [properties, (byte reasonCode)+]
+
+The reason codes of PUBACK and PUBREC are only sent by MQTT 5.0, and omitted if the remaining
+length is 2.
+*/
+func parseMqttResponse() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		h, ok := readFixedHeader(message.Data)
+		if !ok {
+			return false, true
+		}
+		var code int
+		switch h.packetType {
+		case typeConnack:
+			if len(h.body) < 2 {
+				return false, true
+			}
+			message.AddIntAttribute(constlabels.MqttPacketId, packetIdConnect)
+			code = int(h.body[1])
+		case typePuback, typePubrec:
+			packetId, ok := readPacketId(h.body)
+			if !ok {
+				return false, true
+			}
+			message.AddIntAttribute(constlabels.MqttPacketId, int64(packetId))
+			if len(h.body) > 2 {
+				code = int(h.body[2])
+			}
+		case typeSuback, typeUnsuback:
+			packetId, ok := readPacketId(h.body)
+			if !ok {
+				return false, true
+			}
+			message.AddIntAttribute(constlabels.MqttPacketId, int64(packetId))
+			code = readFailureCode(h.packetType, h.body[2:], getVersion(message))
+		case typePublish, typePubrel, typePubcomp, typePingresp, typeDisconnect, typeAuth:
+			// The messages delivered by the servers, the following packets of QoS 2 and the
+			// heartbeats
+			message.Ignore()
+			return true, true
+		default:
+			return false, true
+		}
+		message.AddStringAttribute(constlabels.MqttPacketType, packetTypes[h.packetType])
+		message.AddIntAttribute(constlabels.MqttReasonCode, int64(code))
+		if isFailure(h.packetType, code) {
+			message.AddBoolAttribute(constlabels.IsError, true)
+			message.AddIntAttribute(constlabels.ErrorType, int64(constlabels.ProtocolError))
+		}
+		return true, true
+	}
+}
+
+// readFailureCode returns the first failure code of the topic filters, or 0 if all of them
+// succeed.
+func readFailureCode(packetType byte, codes []byte, version byte) int {
+	if version != version5 {
+		if packetType == typeUnsuback {
+			return 0
+		}
+	} else {
+		var ok bool
+		if codes, ok = skipProperties(codes); !ok {
+			return 0
+		}
+	}
+	for _, code := range codes {
+		if code >= 0x80 {
+			return int(code)
+		}
+	}
+	return 0
+}
+
+func isFailure(packetType byte, code int) bool {
+	if packetType == typeConnack {
+		return code != 0
+	}
+	return code >= 0x80
+}
@@ -4,15 +4,40 @@ import (
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
 )
 
+const (
+	comQuit        = 0x01
+	comQuery       = 0x03
+	comStmtPrepare = 0x16
+	comStmtExecute = 0x17
+	comStmtClose   = 0x19
+
+	packetHeaderLength = 4
+	// stmtClosePacketLength is the length of COM_STMT_CLOSE with the header and the statement id.
+	stmtClosePacketLength = packetHeaderLength + 5
+	// prepareOkPayloadLength is the length of the first packet of COM_STMT_PREPARE_OK.
+	prepareOkPayloadLength = 12
+
+	// pendingPrepareKey keeps the statement prepared by the request until its response tells the id.
+	pendingPrepareKey = "mysql_pending_prepare"
+	// maxStatements limits the prepared statements kept for a connection.
+	maxStatements = 256
+)
+
 /*
       Request                                         Response
        /            \                                            /     |    \
 prepare   query                                err   ok  eof
+
+The statements prepared by COM_STMT_PREPARE are kept for the connection with the ids replied by
+COM_STMT_PREPARE_OK, so COM_STMT_EXECUTE has the SQL and the content key of its statement. They
+are removed by COM_STMT_CLOSE and COM_QUIT, and dropped with the states of the connection, which
+are removed when the socket is connected again or reused, or idle for a long time.
 */
 func NewMysqlParser() *protocol.ProtocolParser {
 	requestParser := protocol.CreatePkgParser(fastfailMysqlRequest(), parseMysqlRequest())
 	requestParser.Add(fastfailMysqlPrepare(), parseMysqlPrepare())
 	requestParser.Add(fastfailMysqlQuery(), parseMysqlQuery())
+	requestParser.Add(fastfailMysqlExecute(), parseMysqlExecute())
 
 	responseParser := protocol.CreatePkgParser(fastfailMysqlResponse(), parseMysqlResponse())
 	responseParser.Add(fastfailMysqlErr(), parseMysqlErr())
@@ -22,3 +47,48 @@ func NewMysqlParser() *protocol.ProtocolParser {
 
 	return protocol.NewProtocolParser(protocol.MYSQL, requestParser, responseParser, nil)
 }
+
+// statement is a prepared statement with its content key.
+type statement struct {
+	sql        string
+	contentKey string
+}
+
+// statements are the prepared statements of a connection, keyed by their ids.
+type statements struct {
+	stmts map[uint32]*statement
+}
+
+func getStatements(states *protocol.ConnectionStates, create bool) *statements {
+	if stmts, ok := states.Get(protocol.MYSQL).(*statements); ok {
+		return stmts
+	}
+	if !create {
+		return nil
+	}
+	stmts := &statements{stmts: make(map[uint32]*statement)}
+	states.Set(protocol.MYSQL, stmts)
+	return stmts
+}
+
+func (stmts *statements) get(id uint32) (*statement, bool) {
+	if stmts == nil {
+		return nil, false
+	}
+	stmt, ok := stmts.stmts[id]
+	return stmt, ok
+}
+
+func (stmts *statements) put(id uint32, stmt *statement) {
+	if _, exist := stmts.stmts[id]; !exist && len(stmts.stmts) >= maxStatements {
+		return
+	}
+	stmts.stmts[id] = stmt
+}
+
+func (stmts *statements) remove(id uint32) {
+	if stmts == nil {
+		return
+	}
+	delete(stmts.stmts, id)
+}
@@ -0,0 +1,160 @@
+package mysql
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func packet(sequence byte, payload ...byte) []byte {
+	return append([]byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), sequence}, payload...)
+}
+
+func withId(command byte, id uint32, rest ...byte) []byte {
+	payload := []byte{command, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint32(payload[1:], id)
+	return packet(0, append(payload, rest...)...)
+}
+
+func prepareOk(id uint32) []byte {
+	payload := []byte{0x00, 0, 0, 0, 0, 1, 0, 1, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint32(payload[1:], id)
+	return packet(1, payload...)
+}
+
+var okPacket = packet(1, 0x00, 0, 0, 2, 0, 0, 0)
+
+type exchange struct {
+	request  []byte
+	response []byte
+	// parsed is false if the request is not taken as a MySQL request.
+	parsed     bool
+	sql        string
+	contentKey string
+}
+
+func runExchanges(t *testing.T, exchanges []exchange) {
+	parser := NewMysqlParser()
+	states := protocol.NewConnectionStates()
+	for i, e := range exchanges {
+		request := protocol.NewRequestMessage(e.request)
+		request.SetConnectionStates(states)
+		if got := parser.ParseRequest(request); got != e.parsed {
+			t.Fatalf("[%d] parsed = %v, want %v", i, got, e.parsed)
+		}
+		if !e.parsed {
+			continue
+		}
+		if e.response != nil {
+			response := protocol.NewResponseMessage(e.response, request.GetAttributes())
+			response.SetConnectionStates(states)
+			if !parser.ParseResponse(response) {
+				t.Fatalf("[%d] failed to parse the response", i)
+			}
+		}
+		if got := request.GetStringAttribute(constlabels.Sql); got != e.sql {
+			t.Errorf("[%d] sql = %q, want %q", i, got, e.sql)
+		}
+		if got := request.GetStringAttribute(constlabels.ContentKey); got != e.contentKey {
+			t.Errorf("[%d] content key = %q, want %q", i, got, e.contentKey)
+		}
+	}
+}
+
+func TestParseMysql_PreparedStatement(t *testing.T) {
+	sql := "SELECT name FROM users WHERE id = ?"
+	runExchanges(t, []exchange{
+		{
+			request:    packet(0, append([]byte{comStmtPrepare}, sql...)...),
+			response:   prepareOk(7),
+			parsed:     true,
+			sql:        sql,
+			contentKey: "select users *",
+		},
+		{
+			request:    withId(comStmtExecute, 7, 0, 1, 0, 0, 0),
+			response:   okPacket,
+			parsed:     true,
+			sql:        sql,
+			contentKey: "select users *",
+		},
+		{
+			// The statement prepared before the capture is unknown.
+			request: withId(comStmtExecute, 8, 0, 1, 0, 0, 0),
+			parsed:  false,
+		},
+		{
+			// COM_STMT_CLOSE is written with the following query.
+			request:    append(withId(comStmtClose, 7), packet(0, append([]byte{comQuery}, "select 1 from dual"...)...)...),
+			response:   okPacket,
+			parsed:     true,
+			sql:        "select 1 from dual",
+			contentKey: "select dual *",
+		},
+		{
+			request: withId(comStmtExecute, 7, 0, 1, 0, 0, 0),
+			parsed:  false,
+		},
+	})
+}
+
+func TestParseMysql_CloseAndQuit(t *testing.T) {
+	sql := "UPDATE users SET name = ? WHERE id = ?"
+	runExchanges(t, []exchange{
+		{
+			request:    packet(0, append([]byte{comStmtPrepare}, sql...)...),
+			response:   prepareOk(1),
+			parsed:     true,
+			sql:        sql,
+			contentKey: "update users *",
+		},
+		{
+			request:    packet(0, append([]byte{comStmtPrepare}, sql...)...),
+			response:   packet(1, 0xff, 0x28, 0x04, '#', '4', '2', '0', '0', '0', 'e', 'r', 'r'),
+			parsed:     true,
+			sql:        sql,
+			contentKey: "update users *",
+		},
+		{
+			request: withId(comStmtClose, 1),
+			parsed:  false,
+		},
+		{
+			request: withId(comStmtExecute, 1, 0, 1, 0, 0, 0),
+			parsed:  false,
+		},
+		{
+			request:    packet(0, append([]byte{comStmtPrepare}, sql...)...),
+			response:   prepareOk(2),
+			parsed:     true,
+			sql:        sql,
+			contentKey: "update users *",
+		},
+		{
+			request: packet(0, comQuit),
+			parsed:  false,
+		},
+		{
+			request: withId(comStmtExecute, 2, 0, 1, 0, 0, 0),
+			parsed:  false,
+		},
+	})
+}
+
+func TestStatements_Limit(t *testing.T) {
+	states := protocol.NewConnectionStates()
+	stmts := getStatements(states, true)
+	for i := 0; i < maxStatements+10; i++ {
+		stmts.put(uint32(i), &statement{sql: "select 1"})
+	}
+	if len(stmts.stmts) != maxStatements {
+		t.Errorf("statements = %d, want %d", len(stmts.stmts), maxStatements)
+	}
+	stmts.remove(0)
+	stmts.put(uint32(maxStatements+20), &statement{sql: "select 2"})
+	if _, ok := stmts.get(uint32(maxStatements + 20)); !ok {
+		t.Errorf("the statement should be kept after another is removed")
+	}
+}
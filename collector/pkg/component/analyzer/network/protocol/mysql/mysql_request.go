@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"encoding/binary"
 	"strings"
 
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
@@ -19,12 +20,41 @@ func fastfailMysqlRequest() protocol.FastFailFn {
 	}
 }
 
+/*
+COM_STMT_CLOSE and COM_QUIT expect no responses, so they are not taken as requests. The statements
+closed are removed, and COM_STMT_CLOSE may be written with the following command.
+
+===== PayLoad =====
+1              COM_STMT_CLOSE<0x19>
+int<4>         the statement id
+*/
 func parseMysqlRequest() protocol.ParsePkgFn {
 	return func(message *protocol.PayloadMessage) (bool, bool) {
+		states := message.GetConnectionStates()
+		states.Delete(pendingPrepareKey)
+		for message.Data[message.Offset+packetHeaderLength] == comStmtClose {
+			if payloadLength(message.Data[message.Offset:]) != stmtClosePacketLength-packetHeaderLength ||
+				len(message.Data) < message.Offset+stmtClosePacketLength {
+				return false, true
+			}
+			getStatements(states, false).remove(binary.LittleEndian.Uint32(message.Data[message.Offset+5:]))
+			message.Offset += stmtClosePacketLength
+			if len(message.Data) < message.Offset+5 {
+				return false, true
+			}
+		}
+		if message.Data[message.Offset+packetHeaderLength] == comQuit {
+			states.Delete(protocol.MYSQL)
+			return false, true
+		}
 		return true, false
 	}
 }
 
+func payloadLength(data []byte) int {
+	return int(data[0]) | int(data[1])<<8 | int(data[2])<<16
+}
+
 /*
 ===== PayLoad =====
 1              COM_STMT_PREPARE<0x16>
@@ -32,18 +62,20 @@ string[EOF]    the query to prepare
 */
 func fastfailMysqlPrepare() protocol.FastFailFn {
 	return func(message *protocol.PayloadMessage) bool {
-		return message.Data[4] != 22
+		return message.Data[message.Offset+4] != comStmtPrepare
 	}
 }
 
 func parseMysqlPrepare() protocol.ParsePkgFn {
 	return func(message *protocol.PayloadMessage) (bool, bool) {
-		sql := string(message.Data[5:])
+		sql := string(message.Data[message.Offset+5:])
 		if !isSql(sql) {
 			return false, true
 		}
-		message.AddUtf8StringAttribute(constlabels.Sql, sql)
-		message.AddUtf8StringAttribute(constlabels.ContentKey, tools.SQL_MERGER.ParseStatement(sql))
+		stmt := &statement{sql: sql, contentKey: tools.SQL_MERGER.ParseStatement(sql)}
+		message.AddUtf8StringAttribute(constlabels.Sql, stmt.sql)
+		message.AddUtf8StringAttribute(constlabels.ContentKey, stmt.contentKey)
+		message.GetConnectionStates().Set(pendingPrepareKey, stmt)
 		return true, true
 	}
 }
@@ -55,13 +87,13 @@ string[EOF]    the query the server shall execute
 */
 func fastfailMysqlQuery() protocol.FastFailFn {
 	return func(message *protocol.PayloadMessage) bool {
-		return message.Data[4] != 3
+		return message.Data[message.Offset+4] != comQuery
 	}
 }
 
 func parseMysqlQuery() protocol.ParsePkgFn {
 	return func(message *protocol.PayloadMessage) (bool, bool) {
-		sql := string(message.Data[5:])
+		sql := string(message.Data[message.Offset+5:])
 		if !isSql(sql) {
 			return false, true
 		}
@@ -72,6 +104,35 @@ func parseMysqlQuery() protocol.ParsePkgFn {
 	}
 }
 
+/*
+===== PayLoad =====
+1              COM_STMT_EXECUTE<0x17>
+int<4>         the statement id
+int<1>         flags
+int<4>         iteration count, which is always 1
+...            the parameters
+
+Only the statements whose COM_STMT_PREPARE_OK are captured are known.
+*/
+func fastfailMysqlExecute() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return message.Data[message.Offset+4] != comStmtExecute || len(message.Data) < message.Offset+9
+	}
+}
+
+func parseMysqlExecute() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		id := binary.LittleEndian.Uint32(message.Data[message.Offset+5:])
+		stmt, ok := getStatements(message.GetConnectionStates(), false).get(id)
+		if !ok {
+			return false, true
+		}
+		message.AddUtf8StringAttribute(constlabels.Sql, stmt.sql)
+		message.AddUtf8StringAttribute(constlabels.ContentKey, stmt.contentKey)
+		return true, true
+	}
+}
+
 var sqlPrefixs = []string{
 	"select",
 	"insert",
@@ -18,8 +18,27 @@ func fastfailMysqlResponse() protocol.FastFailFn {
 	}
 }
 
+/*
+The first packet of COM_STMT_PREPARE_OK tells the id of the statement prepared by the request.
+
+===== PayLoad =====
+int<1>	status(0x00)
+int<4>	statement_id
+int<2>	num_columns
+int<2>	num_params
+int<1>	reserved_1(0x00)
+int<2>	warning_count
+*/
 func parseMysqlResponse() protocol.ParsePkgFn {
 	return func(message *protocol.PayloadMessage) (bool, bool) {
+		states := message.GetConnectionStates()
+		if stmt, ok := states.Get(pendingPrepareKey).(*statement); ok {
+			states.Delete(pendingPrepareKey)
+			data := message.Data
+			if payloadLength(data) == prepareOkPayloadLength && len(data) >= 9 && data[4] == 0x00 {
+				getStatements(states, true).put(binary.LittleEndian.Uint32(data[5:]), stmt)
+			}
+		}
 		return true, false
 	}
 }
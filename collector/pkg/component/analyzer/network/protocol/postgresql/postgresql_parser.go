@@ -0,0 +1,133 @@
+package postgresql
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+)
+
+const (
+	messageHeaderLength = 5
+	// maxMessageLength is the limit of PostgreSQL, which also rejects the data of the text
+	// protocols, because the first byte of the length would be a printable character.
+	maxMessageLength = 1 << 30
+
+	// frontendTypes are the types of the messages sent by the client after the startup.
+	frontendTypes = "BCDEFHPQSXcdfp"
+	// backendTypes are the types of the messages sent by the server.
+	backendTypes = "123ACDEGHIKNRSTVWZcdnstv"
+
+	// maxStatements limits the prepared statements kept for a connection.
+	maxStatements = 1000
+)
+
+/*
+NewPostgresqlParser parses the messages of the PostgreSQL protocol version 3.
+
+	Request                                  Response
+	/      \                                 /      \
+	Query  Parse/Bind/Execute               Error   Others
+
+The startup messages are not parsed, so the first request of a connection is not recognized.
+The statements prepared by Parse are kept for the connection, so the SQL of the statements
+bound later by name is known.
+*/
+func NewPostgresqlParser() *protocol.ProtocolParser {
+	requestParser := protocol.CreatePkgParser(fastfailPostgresqlRequest(), parsePostgresqlRequest())
+	responseParser := protocol.CreatePkgParser(fastfailPostgresqlResponse(), parsePostgresqlResponse())
+	return protocol.NewProtocolParser(protocol.POSTGRESQL, requestParser, responseParser, nil)
+}
+
+/*
+https://www.postgresql.org/docs/current/protocol-overview.html#PROTOCOL-MESSAGE-CONCEPTS
+
+	byte1   type
+	int32   length, including itself
+	payload
+*/
+type message struct {
+	msgType byte
+	// payload is shorter than the length if the message is truncated.
+	payload  []byte
+	complete bool
+}
+
+// readMessages splits the data into messages. Only the last message could be truncated. False
+// is returned if any message header is invalid.
+func readMessages(data []byte, types string) ([]message, bool) {
+	var messages []message
+	offset := 0
+	for offset+messageHeaderLength <= len(data) {
+		msgType := data[offset]
+		length := int(binary.BigEndian.Uint32(data[offset+1 : offset+messageHeaderLength]))
+		if strings.IndexByte(types, msgType) < 0 || length < 4 || length > maxMessageLength {
+			return nil, false
+		}
+		start := offset + messageHeaderLength
+		end := offset + 1 + length
+		msg := message{msgType: msgType, complete: end <= len(data)}
+		if msg.complete {
+			msg.payload = data[start:end]
+		} else {
+			msg.payload = data[start:]
+		}
+		messages = append(messages, msg)
+		offset = end
+	}
+	return messages, len(messages) > 0
+}
+
+// readCString reads a string terminated by zero. False is returned if the terminator is not
+// found, and the rest of the data is returned.
+func readCString(data []byte, offset int) (string, int, bool) {
+	if offset >= len(data) {
+		return "", len(data), false
+	}
+	end := bytes.IndexByte(data[offset:], 0)
+	if end < 0 {
+		return string(data[offset:]), len(data), false
+	}
+	return string(data[offset : offset+end]), offset + end + 1, true
+}
+
+// statements are the SQL of the prepared statements of a connection, keyed by their names.
+// The unnamed statement is kept with the empty name.
+type statements struct {
+	sqls map[string]string
+}
+
+func getStatements(states *protocol.ConnectionStates, create bool) *statements {
+	if stmts, ok := states.Get(protocol.POSTGRESQL).(*statements); ok {
+		return stmts
+	}
+	if !create {
+		return nil
+	}
+	stmts := &statements{sqls: make(map[string]string)}
+	states.Set(protocol.POSTGRESQL, stmts)
+	return stmts
+}
+
+func (stmts *statements) get(name string) (string, bool) {
+	if stmts == nil {
+		return "", false
+	}
+	sql, ok := stmts.sqls[name]
+	return sql, ok
+}
+
+func (stmts *statements) put(name string, sql string) {
+	if _, exist := stmts.sqls[name]; !exist && len(stmts.sqls) >= maxStatements {
+		return
+	}
+	stmts.sqls[name] = sql
+}
+
+func (stmts *statements) remove(name string) {
+	if stmts == nil {
+		return
+	}
+	delete(stmts.sqls, name)
+}
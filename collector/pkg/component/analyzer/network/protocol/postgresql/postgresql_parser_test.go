@@ -0,0 +1,181 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/internal/testutil"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func newMessage(msgType byte, fields ...interface{}) []byte {
+	var payload []byte
+	for _, field := range fields {
+		switch v := field.(type) {
+		case string:
+			payload = append(append(payload, v...), 0)
+		case byte:
+			payload = append(payload, v)
+		case int16:
+			payload = append(payload, testutil.Int16(int(v))...)
+		case int32:
+			payload = append(payload, testutil.Int32(int(v))...)
+		}
+	}
+	return testutil.Join([]byte{msgType}, testutil.Int32(len(payload)+4), payload)
+}
+
+func TestParseRequest(t *testing.T) {
+	parser := NewPostgresqlParser()
+	tests := []struct {
+		name       string
+		data       []byte
+		sql        string
+		contentKey string
+	}{
+		{
+			name:       "simple query",
+			data:       newMessage('Q', "SELECT * FROM users WHERE id = 1"),
+			sql:        "SELECT * FROM users WHERE id = 1",
+			contentKey: "select users *",
+		},
+		{
+			name: "extended query",
+			data: testutil.Join(
+				newMessage('P', "", "UPDATE orders SET state = $1 WHERE id = $2", int16(0)),
+				newMessage('B', "", "", int16(0), int16(2), int32(1), byte('1'), int32(1), byte('2'), int16(0)),
+				newMessage('D', byte('P'), ""),
+				newMessage('E', "", int32(0)),
+				newMessage('S'),
+			),
+			sql:        "UPDATE orders SET state = $1 WHERE id = $2",
+			contentKey: "update orders *",
+		},
+		{
+			name:       "truncated query",
+			data:       newMessage('Q', "INSERT INTO logs VALUES ('a long message')")[:30],
+			sql:        "INSERT INTO logs VALUES (",
+			contentKey: "insert logs *",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := protocol.NewRequestMessage(tt.data)
+			if !parser.ParseRequest(message) {
+				t.Fatalf("failed to parse the request")
+			}
+			if got := message.GetStringAttribute(constlabels.Sql); got != tt.sql {
+				t.Errorf("sql = %q, want %q", got, tt.sql)
+			}
+			if got := message.GetStringAttribute(constlabels.ContentKey); got != tt.contentKey {
+				t.Errorf("content key = %q, want %q", got, tt.contentKey)
+			}
+		})
+	}
+}
+
+func TestParseRequest_NotPostgresql(t *testing.T) {
+	parser := NewPostgresqlParser()
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "http", data: []byte("POST /api HTTP/1.1\r\n\r\n")},
+		{name: "redis", data: []byte("*1\r\n$4\r\nPING\r\n")},
+		{name: "startup", data: []byte{0, 0, 0, 8, 4, 210, 22, 47}},
+		{name: "sync only", data: newMessage('S')},
+		{name: "binary query", data: newMessage('Q', "\x01\x02\x03")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if parser.ParseRequest(protocol.NewRequestMessage(tt.data)) {
+				t.Errorf("the data should not be parsed as PostgreSQL")
+			}
+		})
+	}
+}
+
+func TestParseRequest_PreparedStatement(t *testing.T) {
+	parser := NewPostgresqlParser()
+	states := protocol.NewConnectionStates()
+	parse := func(data []byte) *protocol.PayloadMessage {
+		message := protocol.NewRequestMessage(data)
+		message.SetConnectionStates(states)
+		if !parser.ParseRequest(message) {
+			t.Fatalf("failed to parse the request")
+		}
+		return message
+	}
+
+	parse(testutil.Join(
+		newMessage('P', "stmt_1", "DELETE FROM sessions WHERE id = $1", int16(0)),
+		newMessage('S'),
+	))
+	message := parse(testutil.Join(
+		newMessage('B', "", "stmt_1", int16(0), int16(1), int32(1), byte('1'), int16(0)),
+		newMessage('E', "", int32(0)),
+		newMessage('S'),
+	))
+	if got := message.GetStringAttribute(constlabels.ContentKey); got != "delete sessions *" {
+		t.Errorf("content key = %q, want the one of the prepared statement", got)
+	}
+
+	parse(testutil.Join(newMessage('C', byte('S'), "stmt_1"), newMessage('S')))
+	message = parse(testutil.Join(
+		newMessage('B', "", "stmt_1", int16(0), int16(0), int16(0)),
+		newMessage('E', "", int32(0)),
+	))
+	if message.HasAttribute(constlabels.Sql) {
+		t.Errorf("the closed statement should be removed")
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	parser := NewPostgresqlParser()
+	tests := []struct {
+		name     string
+		data     []byte
+		isError  bool
+		sqlState string
+		errorMsg string
+	}{
+		{
+			name: "rows",
+			data: testutil.Join(
+				newMessage('T', int16(1), "id", int32(0), int16(0), int32(23), int16(4), int32(-1), int16(0)),
+				newMessage('D', int16(1), int32(1), byte('1')),
+				newMessage('C', "SELECT 1"),
+				newMessage('Z', byte('I')),
+			),
+		},
+		{
+			name: "error",
+			data: testutil.Join(
+				newMessage('E', byte('S'), "ERROR", byte('V'), "ERROR", byte('C'), "42P01",
+					byte('M'), `relation "user" does not exist`, byte(0)),
+				newMessage('Z', byte('I')),
+			),
+			isError:  true,
+			sqlState: "42P01",
+			errorMsg: `relation "user" does not exist`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := protocol.NewResponseMessage(tt.data, model.NewAttributeMap())
+			if !parser.ParseResponse(message) {
+				t.Fatalf("failed to parse the response")
+			}
+			if got := message.GetBoolAttribute(constlabels.IsError); got != tt.isError {
+				t.Errorf("is_error = %v, want %v", got, tt.isError)
+			}
+			if got := message.GetStringAttribute(constlabels.SqlState); got != tt.sqlState {
+				t.Errorf("sql state = %q, want %q", got, tt.sqlState)
+			}
+			if got := message.GetStringAttribute(constlabels.SqlErrMsg); got != tt.errorMsg {
+				t.Errorf("error message = %q, want %q", got, tt.errorMsg)
+			}
+		})
+	}
+}
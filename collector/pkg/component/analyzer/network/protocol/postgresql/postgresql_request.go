@@ -0,0 +1,113 @@
+package postgresql
+
+import (
+	"strings"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/mysql/tools"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func fastfailPostgresqlRequest() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < messageHeaderLength || strings.IndexByte(frontendTypes, message.Data[0]) < 0
+	}
+}
+
+/*
+The simple query is sent in one message, while the extended query is sent in several messages,
+which are usually written together.
+
+	Query('Q')      string  the query
+	Parse('P')      string  the name of the prepared statement, empty for the unnamed one
+	                string  the query
+	                int16   the number of the parameter types, followed by the types
+	Bind('B')       string  the name of the portal
+	                string  the name of the prepared statement
+	                ...     the parameters
+	Execute('E')    string  the name of the portal
+	                int32   the maximum number of rows
+	Close('C')      byte1   'S' for a prepared statement or 'P' for a portal
+	                string  the name
+*/
+func parsePostgresqlRequest() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		messages, ok := readMessages(message.Data, frontendTypes)
+		if !ok {
+			return false, true
+		}
+		states := message.GetConnectionStates()
+		var sql string
+		found, query := false, false
+		for _, msg := range messages {
+			switch msg.msgType {
+			case 'Q':
+				text, _, terminated := readCString(msg.payload, 0)
+				if msg.complete && !terminated || !isText(text) {
+					return false, true
+				}
+				if !found {
+					sql, found = text, true
+				}
+				query = true
+			case 'P':
+				name, offset, terminated := readCString(msg.payload, 0)
+				query = true
+				if !terminated {
+					if msg.complete {
+						return false, true
+					}
+					continue
+				}
+				text, _, terminated := readCString(msg.payload, offset)
+				if msg.complete && !terminated || !isText(text) {
+					return false, true
+				}
+				if terminated {
+					getStatements(states, true).put(name, text)
+				}
+				if !found {
+					sql, found = text, true
+				}
+			case 'B':
+				_, offset, portalTerminated := readCString(msg.payload, 0)
+				name, _, terminated := readCString(msg.payload, offset)
+				if portalTerminated && terminated && !found {
+					sql, found = getStatements(states, false).get(name)
+				}
+				query = true
+			case 'C':
+				if len(msg.payload) > 0 && msg.payload[0] == 'S' {
+					if name, _, terminated := readCString(msg.payload, 1); terminated {
+						getStatements(states, false).remove(name)
+					}
+				}
+				query = true
+			case 'D', 'E', 'F':
+				query = true
+			}
+		}
+		if !query {
+			return false, true
+		}
+		if found {
+			message.AddUtf8StringAttribute(constlabels.Sql, sql)
+			message.AddUtf8StringAttribute(constlabels.ContentKey, tools.SQL_MERGER.ParseStatement(sql))
+		}
+		return true, true
+	}
+}
+
+// isText checks the beginning of the query doesn't contain any control characters.
+func isText(text string) bool {
+	if len(text) > 64 {
+		text = text[:64]
+	}
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c < 0x20 && c != '\t' && c != '\n' && c != '\r' || c == 0x7f {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package postgresql
+
+import (
+	"strings"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func fastfailPostgresqlResponse() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < messageHeaderLength || strings.IndexByte(backendTypes, message.Data[0]) < 0
+	}
+}
+
+/*
+The response is a sequence of messages ending with ReadyForQuery('Z'). Only ErrorResponse('E')
+is parsed, which is a list of fields terminated by a zero byte.
+
+	byte1   the field type, like 'S' for the severity, 'C' for the SQLSTATE code and 'M' for
+	        the message
+	string  the field value
+*/
+func parsePostgresqlResponse() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		messages, ok := readMessages(message.Data, backendTypes)
+		if !ok {
+			return false, true
+		}
+		for _, msg := range messages {
+			if msg.msgType != 'E' {
+				continue
+			}
+			var sqlState, errorMessage string
+			offset := 0
+			for offset < len(msg.payload) && msg.payload[offset] != 0 {
+				field := msg.payload[offset]
+				value, next, _ := readCString(msg.payload, offset+1)
+				switch field {
+				case 'C':
+					sqlState = value
+				case 'M':
+					errorMessage = value
+				}
+				offset = next
+			}
+			if len(sqlState) > 0 {
+				message.AddStringAttribute(constlabels.SqlState, sqlState)
+			}
+			if len(errorMessage) > 0 {
+				message.AddUtf8StringAttribute(constlabels.SqlErrMsg, errorMessage)
+			}
+			message.AddBoolAttribute(constlabels.IsError, true)
+			message.AddIntAttribute(constlabels.ErrorType, int64(constlabels.ProtocolError))
+			break
+		}
+		return true, true
+	}
+}
@@ -1,13 +1,24 @@
 package protocol
 
 const (
-	HTTP      = "http"
-	DNS       = "dns"
-	KAFKA     = "kafka"
-	MYSQL     = "mysql"
-	REDIS     = "redis"
-	DUBBO     = "dubbo"
-	NOSUPPORT = "NOSUPPORT"
+	HTTP       = "http"
+	HTTP2      = "http2"
+	DNS        = "dns"
+	KAFKA      = "kafka"
+	ROCKETMQ   = "rocketmq"
+	AMQP       = "amqp"
+	MQTT       = "mqtt"
+	MYSQL      = "mysql"
+	POSTGRESQL = "postgresql"
+	MONGODB    = "mongodb"
+	CASSANDRA  = "cassandra"
+	REDIS      = "redis"
+	MEMCACHED  = "memcached"
+	ZOOKEEPER  = "zookeeper"
+	DUBBO      = "dubbo"
+	THRIFT     = "thrift"
+	FASTCGI    = "fastcgi"
+	NOSUPPORT  = "NOSUPPORT"
 )
 
 var payloadLength map[string]int = map[string]int{}
@@ -28,9 +28,14 @@ var (
 )
 
 type PayloadMessage struct {
-	Data         []byte
-	Offset       int
+	Data   []byte
+	Offset int
+	// Size is the size of the message sent or received, which is larger than the length of Data
+	// if the data captured from a syscall is truncated. Zero means the size is unknown.
+	Size         int
 	attributeMap *model.AttributeMap
+	states       *ConnectionStates
+	ignored      bool
 }
 
 func NewRequestMessage(data []byte) *PayloadMessage {
@@ -49,6 +54,27 @@ func NewResponseMessage(data []byte, attributeMap *model.AttributeMap) *PayloadM
 	}
 }
 
+// SetConnectionStates attaches the states of the connection the message belongs to.
+func (message *PayloadMessage) SetConnectionStates(states *ConnectionStates) {
+	message.states = states
+}
+
+// GetConnectionStates returns nil if the states are not kept for the connection.
+func (message *PayloadMessage) GetConnectionStates() *ConnectionStates {
+	return message.states
+}
+
+// Ignore marks the message as carrying no requests or responses, like the control frames of
+// HTTP/2. It is respected by the parsers pairing messages with PairMatch, and no records are
+// generated for it.
+func (message *PayloadMessage) Ignore() {
+	message.ignored = true
+}
+
+func (message *PayloadMessage) IsIgnored() bool {
+	return message.ignored
+}
+
 func (message *PayloadMessage) IsComplete() bool {
 	return len(message.Data) <= message.Offset
 }
@@ -369,6 +395,8 @@ type PairMatch func(requests []*PayloadMessage, response *PayloadMessage) int
 type ProtocolParser struct {
 	protocol       string
 	multiFrames    bool
+	multiplexed    bool
+	pipelined      bool
 	requestParser  PkgParser
 	responseParser PkgParser
 	pairMatch      PairMatch
@@ -389,6 +417,30 @@ func (parser *ProtocolParser) EnableMultiFrame() {
 	parser.multiFrames = true
 }
 
+// EnableMultiplexing marks the protocol as sending the requests and responses of several streams
+// on a connection concurrently, like HTTP/2. The requests and responses are paired by PairMatch,
+// so PairMatch must be set. Unlike DNS, the responses which match no requests are ignored, and the
+// requests which match no responses wait for them in the next message pairs of the connection.
+func (parser *ProtocolParser) EnableMultiplexing() {
+	parser.multiplexed = true
+}
+
+func (parser *ProtocolParser) Multiplexed() bool {
+	return parser.multiplexed
+}
+
+// EnablePipelining marks the protocol as sending the requests on a connection before the
+// responses of the previous ones, like HTTP/1.1. The responses are paired with the requests in
+// order, so PairMatch is not used. Several messages may be sent by one event, or a message may be
+// sent by several events, so the parsers must set Offset where the message parsed ends.
+func (parser *ProtocolParser) EnablePipelining() {
+	parser.pipelined = true
+}
+
+func (parser *ProtocolParser) Pipelined() bool {
+	return parser.pipelined
+}
+
 func (parser *ProtocolParser) GetProtocol() string {
 	return parser.protocol
 }
@@ -477,11 +529,19 @@ func (parser *ProtocolParser) AddPortCount(port uint32) uint32 {
 	key := strconv.Itoa(int(port))
 	if val, ok := parser.portCounter.Get(key); ok {
 		return atomic.AddUint32(val.(*uint32), 1)
-	} else {
-		count := uint32(1)
-		parser.portCounter.Set(key, &count)
-		return count
 	}
+	// The counter is created under the lock of the map, so the counts are not lost when
+	// the first events of the port are parsed concurrently.
+	var count uint32
+	parser.portCounter.Upsert(key, nil, func(exist bool, valueInMap interface{}, newValue interface{}) interface{} {
+		counter, _ := valueInMap.(*uint32)
+		if !exist || counter == nil {
+			counter = new(uint32)
+		}
+		count = atomic.AddUint32(counter, 1)
+		return counter
+	})
+	return count
 }
 
 func (parser *ProtocolParser) ResetPort(port uint32) {
@@ -4,20 +4,26 @@ import (
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
 )
 
+const (
+	// commandsKey keeps the names of the commands in the requests for pairing the replies.
+	commandsKey = "redis_commands"
+	// maxCommands limits the commands kept for a pipeline.
+	maxCommands = 128
+)
+
+/*
+NewRedisParser parses the commands and the replies of RESP2 and RESP3.
+
+The clients may pipeline several commands in a write, and the replies come in the same order.
+The commands written together are parsed as one request, whose first command is the content key
+and whose size is set as redis_pipeline_size if there are more than one. The replies are paired
+with the commands in order, and the first error is kept with the command it replies to. The
+queued commands of MULTI are paired with the elements of the reply of EXEC. The push messages
+of RESP3 are not replies and skipped.
+*/
 func NewRedisParser() *protocol.ProtocolParser {
 	requestParser := protocol.CreatePkgParser(fastfailRedisRequest(), parseRedisRequest())
-	requestParser.Add(fastfailRedisArray(), parseRedisArray())
-	requestParser.Add(fastfailRedisBulkString(), parseRedisBulkString())
-	requestParser.Add(fastfailRedisInteger(), parseRedisInteger())
-
 	responseParser := protocol.CreatePkgParser(fastfailResponse(), parseResponse())
-	responseParser.Add(fastfailRedisArray(), parseRedisArray())
-	responseParser.Add(fastfailRedisBulkString(), parseRedisBulkString())
-	responseParser.Add(fastfailRedisInteger(), parseRedisInteger())
-	responseParser.Add(fastfailRedisSimpleString(), parseRedisSimpleString())
-	responseParser.Add(fastfailRedisError(), parseRedisError())
 
-	redisParser := protocol.NewProtocolParser(protocol.REDIS, requestParser, responseParser, nil)
-	redisParser.EnableMultiFrame()
-	return redisParser
+	return protocol.NewProtocolParser(protocol.REDIS, requestParser, responseParser, nil)
 }
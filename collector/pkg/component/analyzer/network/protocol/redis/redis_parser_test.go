@@ -0,0 +1,199 @@
+package redis
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func command(args ...string) string {
+	var b strings.Builder
+	b.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		b.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n" + arg + "\r\n")
+	}
+	return b.String()
+}
+
+func TestParseRedis(t *testing.T) {
+	parser := NewRedisParser()
+	tests := []struct {
+		name         string
+		request      string
+		response     string
+		sql          string
+		pipelineSize int64
+		errorMsg     string
+		errorCommand string
+	}{
+		{
+			name:     "get",
+			request:  command("get", "key"),
+			response: "$3\r\nabc\r\n",
+			sql:      "get",
+		},
+		{
+			name:     "subcommand",
+			request:  command("CLIENT", "SETNAME", "app"),
+			response: "+OK\r\n",
+			sql:      "CLIENT SETNAME",
+		},
+		{
+			name:     "error",
+			request:  command("INCR", "name"),
+			response: "-ERR value is not an integer or out of range\r\n",
+			sql:      "INCR",
+			errorMsg: "ERR value is not an integer or out of range",
+		},
+		{
+			name:         "pipeline",
+			request:      command("SET", "a", "1") + command("INCR", "a") + command("LPUSH", "a", "x") + command("GET", "a"),
+			response:     "+OK\r\n:2\r\n-WRONGTYPE Operation against a key holding the wrong kind of value\r\n$1\r\n2\r\n",
+			sql:          "SET",
+			pipelineSize: 4,
+			errorMsg:     "WRONGTYPE Operation against a key holding the wrong kind of value",
+			errorCommand: "LPUSH",
+		},
+		{
+			name: "transaction",
+			request: command("MULTI") + command("SET", "a", "x") + command("INCR", "a") +
+				command("GET", "a") + command("EXEC"),
+			response: "+OK\r\n+QUEUED\r\n+QUEUED\r\n+QUEUED\r\n" +
+				"*3\r\n+OK\r\n-ERR value is not an integer or out of range\r\n$1\r\nx\r\n",
+			sql:          "MULTI",
+			pipelineSize: 5,
+			errorMsg:     "ERR value is not an integer or out of range",
+			errorCommand: "INCR",
+		},
+		{
+			name:         "transaction aborted by watch",
+			request:      command("MULTI") + command("SET", "a", "x") + command("EXEC"),
+			response:     "+OK\r\n+QUEUED\r\n*-1\r\n",
+			sql:          "MULTI",
+			pipelineSize: 3,
+		},
+		{
+			name:    "hello",
+			request: command("HELLO", "3"),
+			response: "%3\r\n+server\r\n$5\r\nredis\r\n+version\r\n$5\r\n7.2.0\r\n" +
+				"+modules\r\n*0\r\n",
+			sql: "HELLO",
+		},
+		{
+			name:         "resp3 types",
+			request:      command("EXISTS", "a") + command("ZSCORE", "z", "m") + command("SMEMBERS", "s") + command("HGET", "h", "f"),
+			response:     "#t\r\n,3.14\r\n~2\r\n+a\r\n+b\r\n_\r\n",
+			sql:          "EXISTS",
+			pipelineSize: 4,
+		},
+		{
+			name:         "resp3 bulk error",
+			request:      command("GET", "a") + command("FCALL", "f", "0"),
+			response:     "=15\r\ntxt:Some string\r\n!21\r\nSYNTAX invalid syntax\r\n",
+			sql:          "GET",
+			pipelineSize: 2,
+			errorMsg:     "SYNTAX invalid syntax",
+			errorCommand: "FCALL",
+		},
+		{
+			name:         "resp3 push and attribute",
+			request:      command("GET", "a") + command("DEL", "b"),
+			response:     ">2\r\n$10\r\ninvalidate\r\n*1\r\n$1\r\na\r\n|1\r\n+ttl\r\n:3600\r\n$1\r\n1\r\n-ERR no such key\r\n",
+			sql:          "GET",
+			pipelineSize: 2,
+			errorMsg:     "ERR no such key",
+			errorCommand: "DEL",
+		},
+		{
+			name:         "truncated",
+			request:      command("SET", "a", strings.Repeat("x", 100)) + command("GET", "a"),
+			response:     "+OK\r\n$100\r\nxxxx",
+			sql:          "SET",
+			pipelineSize: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states := protocol.NewConnectionStates()
+			request := protocol.NewRequestMessage([]byte(tt.request))
+			request.SetConnectionStates(states)
+			if !parser.ParseRequest(request) {
+				t.Fatalf("failed to parse the request")
+			}
+			message := protocol.NewResponseMessage([]byte(tt.response), request.GetAttributes())
+			message.SetConnectionStates(states)
+			if !parser.ParseResponse(message) {
+				t.Fatalf("failed to parse the response")
+			}
+			if got := message.GetStringAttribute(constlabels.Sql); got != tt.sql {
+				t.Errorf("sql = %q, want %q", got, tt.sql)
+			}
+			if got := message.GetIntAttribute(constlabels.RedisPipelineSize); got != tt.pipelineSize {
+				t.Errorf("pipeline size = %d, want %d", got, tt.pipelineSize)
+			}
+			if got := message.GetStringAttribute(constlabels.RedisErrMsg); got != tt.errorMsg {
+				t.Errorf("error msg = %q, want %q", got, tt.errorMsg)
+			}
+			if got := message.GetStringAttribute(constlabels.RedisErrorCommand); got != tt.errorCommand {
+				t.Errorf("error command = %q, want %q", got, tt.errorCommand)
+			}
+		})
+	}
+}
+
+func TestParseRequest_TruncatedPipeline(t *testing.T) {
+	parser := NewRedisParser()
+	data := command("SET", "a", "1") + command("GET", "a") + command("GET", "b")
+	message := protocol.NewRequestMessage([]byte(data[:len(data)-5]))
+	if !parser.ParseRequest(message) {
+		t.Fatalf("failed to parse the request")
+	}
+	if got := message.GetIntAttribute(constlabels.RedisPipelineSize); got != 3 {
+		t.Errorf("pipeline size = %d, want 3", got)
+	}
+}
+
+func TestParseRequest_NotRedis(t *testing.T) {
+	parser := NewRedisParser()
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "http", data: "GET /index.html HTTP/1.1\r\n\r\n"},
+		{name: "reply", data: "+OK\r\n"},
+		{name: "empty array", data: "*0\r\n"},
+		{name: "not bulk strings", data: "*2\r\n:1\r\n:2\r\n"},
+		{name: "wrong length", data: "*1\r\n$2\r\nGET\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if parser.ParseRequest(protocol.NewRequestMessage([]byte(tt.data))) {
+				t.Errorf("the data should not be parsed as Redis")
+			}
+		})
+	}
+}
+
+func TestParseResponse_NotRedis(t *testing.T) {
+	parser := NewRedisParser()
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "http", data: "HTTP/1.1 200 OK\r\n\r\n"},
+		{name: "invalid integer", data: ":abc\r\n"},
+		{name: "invalid boolean", data: "#x\r\n"},
+		{name: "null map", data: "%-1\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if parser.ParseResponse(protocol.NewResponseMessage([]byte(tt.data), model.NewAttributeMap())) {
+				t.Errorf("the data should not be parsed as Redis")
+			}
+		})
+	}
+}
@@ -2,24 +2,68 @@ package redis
 
 import (
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
 )
 
 /*
-For Integers the first byte of the reply is ":"
-For Bulk Strings the first byte of the reply is "$"
-For Arrays the first byte of the reply is "*"
+The clients send the commands as arrays of bulk strings, whose first byte is "*".
 */
 func fastfailRedisRequest() protocol.FastFailFn {
 	return func(message *protocol.PayloadMessage) bool {
-		keyword := message.Data[message.Offset]
-		return keyword != '*' &&
-			keyword != '$' &&
-			keyword != ':'
+		return len(message.Data) <= message.Offset || message.Data[message.Offset] != typeArray
 	}
 }
 
+/*
+The pipelined commands are read one by one.
+
+	*2\r\n$3\r\nGET\r\n$1\r\na\r\n*3\r\n$3\r\nSET\r\n$1\r\nb\r\n$1\r\n1\r\n
+*/
 func parseRedisRequest() protocol.ParsePkgFn {
 	return func(message *protocol.PayloadMessage) (bool, bool) {
-		return true, false
+		r := &reader{data: message.Data, offset: message.Offset}
+		var commands []string
+		for r.hasMore() {
+			name, subcommand, status := r.readCommand()
+			if status == readInvalid {
+				break
+			}
+			if len(commands) == 0 && len(name) > 0 {
+				if content := commandName(name, subcommand); len(content) > 0 {
+					message.AddUtf8StringAttribute(constlabels.Sql, content)
+				}
+			}
+			if len(commands) < maxCommands {
+				commands = append(commands, string(name))
+			}
+			if status == readTruncated {
+				break
+			}
+		}
+		if len(commands) == 0 {
+			return false, true
+		}
+		if len(commands) > 1 {
+			message.AddIntAttribute(constlabels.RedisPipelineSize, int64(len(commands)))
+		}
+		message.GetConnectionStates().Set(commandsKey, commands)
+		message.Offset = r.offset
+		return true, true
+	}
+}
+
+// commandName returns the command with its subcommand like "CLIENT SETNAME", or empty if it is
+// not a command of Redis.
+func commandName(name []byte, subcommand []byte) string {
+	if len(subcommand) > 0 {
+		withSubcommand := make([]byte, 0, len(name)+1+len(subcommand))
+		withSubcommand = append(append(append(withSubcommand, name...), ' '), subcommand...)
+		if IsRedisCommand(withSubcommand) {
+			return string(withSubcommand)
+		}
+	}
+	if IsRedisCommand(name) {
+		return string(name)
 	}
+	return ""
 }
@@ -0,0 +1,266 @@
+package redis
+
+import (
+	"strconv"
+)
+
+const (
+	readComplete = iota
+	// readTruncated means the data ends in the value, which is the last one captured.
+	readTruncated
+	readInvalid
+)
+
+const (
+	// RESP2
+	typeSimpleString = '+'
+	typeError        = '-'
+	typeInteger      = ':'
+	typeBulkString   = '$'
+	typeArray        = '*'
+	// RESP3
+	typeNull           = '_'
+	typeBoolean        = '#'
+	typeDouble         = ','
+	typeBigNumber      = '('
+	typeBulkError      = '!'
+	typeVerbatimString = '='
+	typeMap            = '%'
+	typeSet            = '~'
+	typeAttribute      = '|'
+	typePush           = '>'
+
+	// maxDepth limits the nested aggregates, like the replies of EXEC.
+	maxDepth = 16
+	// maxLength is the limit of the bulk strings and the aggregates, which is 512MB for strings.
+	maxLength = 512 * 1024 * 1024
+)
+
+// value is a RESP value read from the data.
+type value struct {
+	kind byte
+	// errorMsg is the message of the error, or the first error in the aggregate.
+	errorMsg []byte
+	// errorIndex is the index of the element carrying errorMsg in the aggregate, which is -1 if
+	// the value itself is the error.
+	errorIndex int
+}
+
+// reader reads the RESP values one by one. The data may be truncated in the end.
+type reader struct {
+	data   []byte
+	offset int
+}
+
+func (r *reader) hasMore() bool {
+	return r.offset < len(r.data)
+}
+
+// readLine returns the line without CRLF. It is truncated if the data ends before CRLF.
+func (r *reader) readLine() ([]byte, int) {
+	for i := r.offset; i < len(r.data); i++ {
+		if r.data[i] != '\r' {
+			continue
+		}
+		if i+1 == len(r.data) {
+			break
+		}
+		if r.data[i+1] != '\n' {
+			return nil, readInvalid
+		}
+		line := r.data[r.offset:i]
+		r.offset = i + 2
+		return line, readComplete
+	}
+	line := r.data[r.offset:]
+	r.offset = len(r.data)
+	return line, readTruncated
+}
+
+// readLength reads the length of a bulk string or an aggregate, which is -1 if it is null.
+func (r *reader) readLength() (int, int) {
+	line, status := r.readLine()
+	if status != readComplete {
+		return 0, status
+	}
+	length, err := strconv.Atoi(string(line))
+	if err != nil || length < -1 || length > maxLength {
+		return 0, readInvalid
+	}
+	return length, readComplete
+}
+
+// readBulk reads the bytes of a bulk string with the length, which are truncated if the data ends.
+func (r *reader) readBulk(length int) ([]byte, int) {
+	if len(r.data)-r.offset < length+2 {
+		bulk := r.data[r.offset:]
+		if len(bulk) > length {
+			bulk = bulk[:length]
+		}
+		r.offset = len(r.data)
+		return bulk, readTruncated
+	}
+	bulk := r.data[r.offset : r.offset+length]
+	if r.data[r.offset+length] != '\r' || r.data[r.offset+length+1] != '\n' {
+		return nil, readInvalid
+	}
+	r.offset += length + 2
+	return bulk, readComplete
+}
+
+/*
+readCommand reads a command sent by the clients, which is an array of bulk strings.
+
+	*2\r\n$3\r\nGET\r\n$3\r\nkey\r\n
+
+The name of the command is returned with the following argument, because some commands have
+subcommands like CLIENT SETNAME. The command is truncated if the data ends in it, and the name
+is empty if it is not read completely.
+*/
+func (r *reader) readCommand() (name []byte, subcommand []byte, status int) {
+	if r.data[r.offset] != typeArray {
+		return nil, nil, readInvalid
+	}
+	r.offset++
+	count, status := r.readLength()
+	if status != readComplete {
+		return nil, nil, status
+	}
+	if count < 1 {
+		return nil, nil, readInvalid
+	}
+	for i := 0; i < count; i++ {
+		if !r.hasMore() {
+			return name, subcommand, readTruncated
+		}
+		if r.data[r.offset] != typeBulkString {
+			return nil, nil, readInvalid
+		}
+		r.offset++
+		length, status := r.readLength()
+		if status == readComplete && length < 0 {
+			status = readInvalid
+		}
+		if status != readComplete {
+			return name, subcommand, status
+		}
+		arg, status := r.readBulk(length)
+		if status != readComplete {
+			return name, subcommand, status
+		}
+		switch i {
+		case 0:
+			name = arg
+		case 1:
+			subcommand = arg
+		}
+	}
+	return name, subcommand, readComplete
+}
+
+// readValue reads a value of RESP2 or RESP3. The attributes of RESP3 are skipped.
+func (r *reader) readValue(depth int) (*value, int) {
+	if depth > maxDepth || !r.hasMore() {
+		return nil, readInvalid
+	}
+	v := &value{kind: r.data[r.offset], errorIndex: -1}
+	r.offset++
+	switch v.kind {
+	case typeSimpleString, typeBigNumber, typeDouble:
+		_, status := r.readLine()
+		return v, status
+	case typeError:
+		line, status := r.readLine()
+		v.errorMsg = line
+		return v, status
+	case typeInteger:
+		line, status := r.readLine()
+		if status == readComplete {
+			if _, err := strconv.ParseInt(string(line), 10, 64); err != nil {
+				return nil, readInvalid
+			}
+		}
+		return v, status
+	case typeNull, typeBoolean:
+		line, status := r.readLine()
+		if status == readComplete && !isValidLine(v.kind, line) {
+			return nil, readInvalid
+		}
+		return v, status
+	case typeBulkString, typeBulkError, typeVerbatimString:
+		length, status := r.readLength()
+		if status != readComplete {
+			return v, status
+		}
+		if length < 0 {
+			if v.kind != typeBulkString {
+				return nil, readInvalid
+			}
+			return v, readComplete
+		}
+		bulk, status := r.readBulk(length)
+		if v.kind == typeBulkError {
+			v.errorMsg = bulk
+		}
+		return v, status
+	case typeArray, typeSet, typePush, typeMap:
+		count, status := r.readLength()
+		if status != readComplete {
+			return v, status
+		}
+		if count < 0 && v.kind != typeArray {
+			return nil, readInvalid
+		}
+		if v.kind == typeMap {
+			count *= 2
+		}
+		for i := 0; i < count; i++ {
+			if !r.hasMore() {
+				return v, readTruncated
+			}
+			element, status := r.readValue(depth + 1)
+			if status == readInvalid {
+				return nil, readInvalid
+			}
+			if v.errorMsg == nil && element.errorMsg != nil {
+				v.errorMsg = element.errorMsg
+				v.errorIndex = i
+			}
+			if status == readTruncated {
+				return v, readTruncated
+			}
+		}
+		return v, readComplete
+	case typeAttribute:
+		count, status := r.readLength()
+		if status != readComplete {
+			return v, status
+		}
+		if count < 0 {
+			return nil, readInvalid
+		}
+		for i := 0; i < count*2; i++ {
+			if !r.hasMore() {
+				return v, readTruncated
+			}
+			if _, status := r.readValue(depth + 1); status != readComplete {
+				return v, status
+			}
+		}
+		if !r.hasMore() {
+			return v, readTruncated
+		}
+		return r.readValue(depth)
+	}
+	return nil, readInvalid
+}
+
+func isValidLine(kind byte, line []byte) bool {
+	switch kind {
+	case typeNull:
+		return len(line) == 0
+	case typeBoolean:
+		return len(line) == 1 && (line[0] == 't' || line[0] == 'f')
+	}
+	return true
+}
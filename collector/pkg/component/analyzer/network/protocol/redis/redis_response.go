@@ -1,29 +1,95 @@
 package redis
 
 import (
+	"strings"
+
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
 )
 
 /*
-For Simple Strings the first byte of the reply is "+"
-For Errors the first byte of the reply is "-"
-For Integers the first byte of the reply is ":"
-For Bulk Strings the first byte of the reply is "$"
-For Arrays the first byte of the reply is "*"
+The first byte of the reply tells its type.
+
+	RESP2    "+" Simple Strings, "-" Errors, ":" Integers, "$" Bulk Strings and "*" Arrays
+	RESP3    "_" Null, "#" Booleans, "," Doubles, "(" Big Numbers, "!" Bulk Errors,
+	         "=" Verbatim Strings, "%" Maps, "~" Sets, "|" Attributes and ">" Pushes
 */
 func fastfailResponse() protocol.FastFailFn {
 	return func(message *protocol.PayloadMessage) bool {
-		keyword := message.Data[message.Offset]
-		return keyword != '+' &&
-			keyword != '-' &&
-			keyword != '*' &&
-			keyword != '$' &&
-			keyword != ':'
+		if len(message.Data) <= message.Offset {
+			return true
+		}
+		switch message.Data[message.Offset] {
+		case typeSimpleString, typeError, typeInteger, typeBulkString, typeArray,
+			typeNull, typeBoolean, typeDouble, typeBigNumber, typeBulkError, typeVerbatimString,
+			typeMap, typeSet, typeAttribute, typePush:
+			return false
+		}
+		return true
 	}
 }
 
 func parseResponse() protocol.ParsePkgFn {
 	return func(message *protocol.PayloadMessage) (bool, bool) {
-		return true, false
+		states := message.GetConnectionStates()
+		commands, _ := states.Get(commandsKey).([]string)
+		states.Delete(commandsKey)
+
+		r := &reader{data: message.Data, offset: message.Offset}
+		var (
+			replies  int
+			inMulti  bool
+			queued   []string
+			errorMsg []byte
+			errorCmd string
+		)
+		for r.hasMore() {
+			reply, status := r.readValue(0)
+			if status == readInvalid {
+				break
+			}
+			if reply.kind == typePush {
+				// The out-of-band data like the messages of the subscribed channels.
+				if status == readTruncated {
+					break
+				}
+				continue
+			}
+			var command string
+			if replies < len(commands) {
+				command = commands[replies]
+			}
+			replies++
+			switch {
+			case strings.EqualFold(command, "MULTI"):
+				inMulti, queued = true, nil
+			case strings.EqualFold(command, "EXEC"):
+				if errorMsg == nil && reply.errorIndex >= 0 && reply.errorIndex < len(queued) {
+					errorMsg, errorCmd = reply.errorMsg, queued[reply.errorIndex]
+				}
+				inMulti = false
+			case strings.EqualFold(command, "DISCARD"):
+				inMulti = false
+			case inMulti:
+				queued = append(queued, command)
+			}
+			if errorMsg == nil && reply.errorMsg != nil {
+				errorMsg, errorCmd = reply.errorMsg, command
+			}
+			if status == readTruncated {
+				break
+			}
+		}
+		if replies == 0 {
+			return false, true
+		}
+		if len(errorMsg) > 0 {
+			message.AddByteArrayUtf8Attribute(constlabels.RedisErrMsg, errorMsg)
+			if len(errorCmd) > 0 && len(commands) > 1 {
+				message.AddUtf8StringAttribute(constlabels.RedisErrorCommand, errorCmd)
+			}
+		}
+		message.Offset = r.offset
+		return true, true
 	}
 }
@@ -0,0 +1,114 @@
+package rocketmq
+
+import "strconv"
+
+// The request codes of RequestCode in the remoting protocol.
+const (
+	sendMessage            = 10
+	pullMessage            = 11
+	queryMessage           = 12
+	queryConsumerOffset    = 14
+	updateConsumerOffset   = 15
+	updateAndCreateTopic   = 17
+	searchOffsetByTime     = 29
+	getMaxOffset           = 30
+	getMinOffset           = 31
+	viewMessageById        = 33
+	heartBeat              = 34
+	unregisterClient       = 35
+	consumerSendMsgBack    = 36
+	endTransaction         = 37
+	getConsumerListByGroup = 38
+	checkTransactionState  = 39
+	notifyConsumerIdsChg   = 40
+	lockBatchMq            = 41
+	unlockBatchMq          = 42
+	registerBroker         = 103
+	unregisterBroker       = 104
+	getRouteInfoByTopic    = 105
+	getBrokerClusterInfo   = 106
+	sendMessageV2          = 310
+	sendBatchMessage       = 320
+	sendReplyMessage       = 324
+	sendReplyMessageV2     = 325
+	litePullMessage        = 361
+	popMessage             = 200050
+	ackMessage             = 200051
+	changeInvisibleTime    = 200053
+)
+
+var requestCodes = map[int]string{
+	sendMessage:            "SEND_MESSAGE",
+	pullMessage:            "PULL_MESSAGE",
+	queryMessage:           "QUERY_MESSAGE",
+	queryConsumerOffset:    "QUERY_CONSUMER_OFFSET",
+	updateConsumerOffset:   "UPDATE_CONSUMER_OFFSET",
+	updateAndCreateTopic:   "UPDATE_AND_CREATE_TOPIC",
+	searchOffsetByTime:     "SEARCH_OFFSET_BY_TIMESTAMP",
+	getMaxOffset:           "GET_MAX_OFFSET",
+	getMinOffset:           "GET_MIN_OFFSET",
+	viewMessageById:        "VIEW_MESSAGE_BY_ID",
+	heartBeat:              "HEART_BEAT",
+	unregisterClient:       "UNREGISTER_CLIENT",
+	consumerSendMsgBack:    "CONSUMER_SEND_MSG_BACK",
+	endTransaction:         "END_TRANSACTION",
+	getConsumerListByGroup: "GET_CONSUMER_LIST_BY_GROUP",
+	checkTransactionState:  "CHECK_TRANSACTION_STATE",
+	notifyConsumerIdsChg:   "NOTIFY_CONSUMER_IDS_CHANGED",
+	lockBatchMq:            "LOCK_BATCH_MQ",
+	unlockBatchMq:          "UNLOCK_BATCH_MQ",
+	registerBroker:         "REGISTER_BROKER",
+	unregisterBroker:       "UNREGISTER_BROKER",
+	getRouteInfoByTopic:    "GET_ROUTEINFO_BY_TOPIC",
+	getBrokerClusterInfo:   "GET_BROKER_CLUSTER_INFO",
+	sendMessageV2:          "SEND_MESSAGE_V2",
+	sendBatchMessage:       "SEND_BATCH_MESSAGE",
+	sendReplyMessage:       "SEND_REPLY_MESSAGE",
+	sendReplyMessageV2:     "SEND_REPLY_MESSAGE_V2",
+	litePullMessage:        "LITE_PULL_MESSAGE",
+	popMessage:             "POP_MESSAGE",
+	ackMessage:             "ACK_MESSAGE",
+	changeInvisibleTime:    "CHANGE_MESSAGE_INVISIBLETIME",
+}
+
+// getOperation returns the name of the request code, or the code itself if it's unknown.
+func getOperation(code int) string {
+	if name, ok := requestCodes[code]; ok {
+		return name
+	}
+	return strconv.Itoa(code)
+}
+
+// getTopic returns the topic in the extFields. The headers of SEND_MESSAGE_V2 shorten the names of
+// the fields to single letters, in which the topic is "b".
+func getTopic(code int, extFields map[string]string) string {
+	switch code {
+	case sendMessageV2, sendBatchMessage, sendReplyMessageV2:
+		return extFields["b"]
+	case consumerSendMsgBack:
+		return extFields["originTopic"]
+	}
+	return extFields["topic"]
+}
+
+// The response codes of ResponseCode in the remoting protocol.
+const (
+	responseSuccess      = 0
+	pullNotFound         = 19
+	pullRetryImmediately = 20
+	pullOffsetMoved      = 21
+	queryNotFound        = 22
+	noMessage            = 80
+	pollingTimeout       = 210
+)
+
+// isErrorCode returns whether the response code is an error. No messages found by the pulls are
+// the normal results of the long pollings, which are not errors.
+func isErrorCode(code int) bool {
+	switch code {
+	case responseSuccess, pullNotFound, pullRetryImmediately, pullOffsetMoved, queryNotFound,
+		noMessage, pollingTimeout:
+		return false
+	}
+	return true
+}
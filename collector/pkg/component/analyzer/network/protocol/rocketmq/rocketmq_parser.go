@@ -0,0 +1,268 @@
+package rocketmq
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+const (
+	// prefixLength is the length of the total length and the header length.
+	prefixLength = 8
+	// maxFrameLength is the default maximum frame size of the remoting server, which is 16M.
+	maxFrameLength = 16 * 1024 * 1024
+
+	serializeJson     = 0
+	serializeRocketmq = 1
+
+	flagResponse = 1 << 0
+	flagOneway   = 1 << 1
+
+	// maxLanguage is the largest language code of the clients, which is RUST.
+	maxLanguage = 12
+)
+
+/*
+NewRocketmqParser parses the commands of the RocketMQ remoting protocol.
+
+	Request                                    Response
+	/         |          \                     |
+	SEND_MESSAGE  PULL_MESSAGE  ...            the response code and the remark
+
+The clients send the requests on a connection concurrently, and the responses are paired with
+the requests by the opaque in their headers. The oneway requests, like the heartbeats of some
+clients, expect no responses and are ignored, as well as the requests sent by the brokers.
+*/
+func NewRocketmqParser() *protocol.ProtocolParser {
+	requestParser := protocol.CreatePkgParser(fastfailRocketmqRequest(), parseRocketmqRequest())
+	responseParser := protocol.CreatePkgParser(fastfailRocketmqResponse(), parseRocketmqResponse())
+
+	parser := protocol.NewProtocolParser(protocol.ROCKETMQ, requestParser, responseParser, rocketmqPair())
+	parser.EnableMultiplexing()
+	return parser
+}
+
+func rocketmqPair() protocol.PairMatch {
+	return func(requests []*protocol.PayloadMessage, response *protocol.PayloadMessage) int {
+		opaque := response.GetIntAttribute(constlabels.RocketmqOpaque)
+		for i, request := range requests {
+			if request.IsIgnored() || !request.HasAttribute(constlabels.RocketmqOpaque) {
+				continue
+			}
+			if request.GetIntAttribute(constlabels.RocketmqOpaque) == opaque {
+				return i
+			}
+		}
+		return -1
+	}
+}
+
+/*
+The frame of the remoting protocol
+
+	int32   the length of the frame, excluding itself
+	byte    the serialize type, 0 for JSON and 1 for ROCKETMQ
+	int24   the length of the header
+	bytes   the header
+	bytes   the body
+*/
+type header struct {
+	code      int
+	opaque    int32
+	flag      int
+	remark    string
+	extFields map[string]string
+}
+
+func (h *header) isResponse() bool {
+	return h.flag&flagResponse != 0
+}
+
+func (h *header) isOneway() bool {
+	return h.flag&flagOneway != 0
+}
+
+// readHeader reads the header of the first frame. The header may be truncated, in which case the
+// fields before the end are read, and false is returned if the code or opaque is unknown.
+func readHeader(data []byte) (*header, bool) {
+	if len(data) < prefixLength {
+		return nil, false
+	}
+	length := int(binary.BigEndian.Uint32(data))
+	serializeType := data[4]
+	headerLength := int(binary.BigEndian.Uint32(data[4:]) & 0xffffff)
+	if headerLength == 0 || length < headerLength+4 || length > maxFrameLength {
+		return nil, false
+	}
+	data = data[prefixLength:]
+	if len(data) > headerLength {
+		data = data[:headerLength]
+	}
+	var h *header
+	var ok bool
+	switch serializeType {
+	case serializeJson:
+		h, ok = readJsonHeader(data)
+	case serializeRocketmq:
+		h, ok = readRocketmqHeader(data)
+	}
+	if !ok || h.code < 0 || h.flag&^(flagResponse|flagOneway) != 0 {
+		return nil, false
+	}
+	return h, true
+}
+
+/*
+The header in JSON is serialized with the keys sorted, so the opaque follows the extFields.
+
+	{"code":10,"extFields":{"topic":"orders"},"flag":0,"language":"JAVA","opaque":1,...}
+*/
+func readJsonHeader(data []byte) (*header, bool) {
+	if len(data) == 0 || data[0] != '{' {
+		return nil, false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if _, err := decoder.Token(); err != nil {
+		return nil, false
+	}
+	h := &header{}
+	var hasCode, hasOpaque bool
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		key, ok := token.(string)
+		if !ok {
+			return nil, false
+		}
+		if key == "extFields" {
+			h.extFields = readJsonFields(decoder)
+			continue
+		}
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			break
+		}
+		switch key {
+		case "code":
+			h.code, hasCode = jsonInt(value)
+		case "opaque":
+			var opaque int
+			opaque, hasOpaque = jsonInt(value)
+			h.opaque = int32(opaque)
+		case "flag":
+			h.flag, _ = jsonInt(value)
+		case "remark":
+			h.remark, _ = value.(string)
+		}
+	}
+	return h, hasCode && hasOpaque
+}
+
+func jsonInt(value interface{}) (int, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	v, err := number.Int64()
+	return int(v), err == nil
+}
+
+// readJsonFields reads the extFields, whose values are all strings.
+func readJsonFields(decoder *json.Decoder) map[string]string {
+	fields := make(map[string]string)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return fields
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return fields
+		}
+		key, _ := token.(string)
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			return fields
+		}
+		if s, ok := value.(string); ok {
+			fields[key] = s
+		}
+	}
+	decoder.Token()
+	return fields
+}
+
+/*
+The header serialized in ROCKETMQ
+
+	int16   code
+	byte    language
+	int16   version
+	int32   opaque
+	int32   flag
+	int32   the length of the remark, followed by the remark
+	int32   the length of the extFields, followed by the fields
+	        int16 the length of the key, followed by the key
+	        int32 the length of the value, followed by the value
+*/
+func readRocketmqHeader(data []byte) (*header, bool) {
+	if len(data) < 13 || data[2] > maxLanguage {
+		return nil, false
+	}
+	h := &header{
+		code:      int(int16(binary.BigEndian.Uint16(data))),
+		opaque:    int32(binary.BigEndian.Uint32(data[5:])),
+		flag:      int(binary.BigEndian.Uint32(data[9:])),
+		extFields: make(map[string]string),
+	}
+	offset := 13
+	remark, ok := readBytes(data, &offset, 4)
+	if !ok {
+		return h, true
+	}
+	h.remark = string(remark)
+	fields, ok := readBytes(data, &offset, 4)
+	if !ok {
+		// The available part of the fields is read.
+		if offset+4 > len(data) {
+			return h, true
+		}
+		fields = data[offset+4:]
+	}
+	for i := 0; i < len(fields); {
+		key, ok := readBytes(fields, &i, 2)
+		if !ok {
+			break
+		}
+		value, ok := readBytes(fields, &i, 4)
+		if !ok {
+			break
+		}
+		h.extFields[string(key)] = string(value)
+	}
+	return h, true
+}
+
+// readBytes reads the bytes prefixed by their length in 2 or 4 bytes.
+func readBytes(data []byte, offset *int, size int) ([]byte, bool) {
+	if *offset+size > len(data) {
+		return nil, false
+	}
+	var length int
+	if size == 2 {
+		length = int(binary.BigEndian.Uint16(data[*offset:]))
+	} else {
+		length = int(int32(binary.BigEndian.Uint32(data[*offset:])))
+	}
+	start := *offset + size
+	if length < 0 || start+length > len(data) {
+		return nil, false
+	}
+	*offset = start + length
+	return data[start:*offset], true
+}
@@ -0,0 +1,214 @@
+package rocketmq
+
+import (
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/internal/testutil"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func newFrame(serializeType byte, header []byte, body string) []byte {
+	headerLength := testutil.Int32(len(header))
+	headerLength[0] = serializeType
+	data := append(testutil.Int32(4+len(header)+len(body)), headerLength...)
+	return append(append(data, header...), body...)
+}
+
+func jsonFrame(header string) []byte {
+	return newFrame(serializeJson, []byte(header), "body")
+}
+
+// rocketmqFrame builds the frame with the header serialized in ROCKETMQ, and the fields are the
+// keys followed by their values.
+func rocketmqFrame(code int, opaque int, flag int, remark string, fields ...string) []byte {
+	var extFields []byte
+	for i := 0; i+1 < len(fields); i += 2 {
+		extFields = append(append(extFields, testutil.String16(fields[i])...), testutil.String32(fields[i+1])...)
+	}
+	header := []byte{byte(code >> 8), byte(code), 0, 0, 1}
+	header = testutil.Join(header, testutil.Int32(opaque), testutil.Int32(flag), testutil.String32(remark), testutil.String32(string(extFields)))
+	return newFrame(serializeRocketmq, header, "body")
+}
+
+func TestParseRequest(t *testing.T) {
+	parser := NewRocketmqParser()
+	tests := []struct {
+		name      string
+		data      []byte
+		ignored   bool
+		operation string
+		topic     string
+	}{
+		{
+			name: "send message",
+			data: jsonFrame(`{"code":10,"extFields":{"producerGroup":"group","topic":"orders","queueId":"1"},` +
+				`"flag":0,"language":"JAVA","opaque":7,"serializeTypeCurrentRPC":"JSON","version":395}`),
+			operation: "SEND_MESSAGE",
+			topic:     "orders",
+		},
+		{
+			name: "send message v2",
+			data: jsonFrame(`{"code":310,"extFields":{"a":"group","b":"orders","c":"TBW102"},` +
+				`"flag":0,"language":"JAVA","opaque":7,"serializeTypeCurrentRPC":"JSON","version":395}`),
+			operation: "SEND_MESSAGE_V2",
+			topic:     "orders",
+		},
+		{
+			name:      "pull message in rocketmq serialization",
+			data:      rocketmqFrame(pullMessage, 7, 0, "", "consumerGroup", "group", "topic", "orders"),
+			operation: "PULL_MESSAGE",
+			topic:     "orders",
+		},
+		{
+			name:      "heartbeat",
+			data:      jsonFrame(`{"code":34,"flag":0,"language":"GO","opaque":7,"serializeTypeCurrentRPC":"JSON","version":317}`),
+			operation: "HEART_BEAT",
+		},
+		{
+			name:      "oneway",
+			data:      jsonFrame(`{"code":35,"flag":2,"language":"JAVA","opaque":7,"version":395}`),
+			ignored:   true,
+			operation: "UNREGISTER_CLIENT",
+		},
+		{
+			name:      "unknown code",
+			data:      jsonFrame(`{"code":9999,"flag":0,"language":"JAVA","opaque":7,"version":395}`),
+			operation: "9999",
+		},
+		{
+			name: "truncated after opaque",
+			data: jsonFrame(`{"code":11,"extFields":{"topic":"orders"},"flag":0,"language":"JAVA","opaque":7,` +
+				`"serializeTypeCurrentRPC":"JSON","version":395}`)[:120],
+			operation: "PULL_MESSAGE",
+			topic:     "orders",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := protocol.NewRequestMessage(tt.data)
+			if !parser.ParseRequest(message) {
+				t.Fatalf("failed to parse the request")
+			}
+			if got := message.IsIgnored(); got != tt.ignored {
+				t.Errorf("ignored = %v, want %v", got, tt.ignored)
+			}
+			if got := message.GetIntAttribute(constlabels.RocketmqOpaque); got != 7 {
+				t.Errorf("opaque = %d, want 7", got)
+			}
+			if got := message.GetStringAttribute(constlabels.Operation); got != tt.operation {
+				t.Errorf("operation = %q, want %q", got, tt.operation)
+			}
+			if got := message.GetStringAttribute(constlabels.Topic); got != tt.topic {
+				t.Errorf("topic = %q, want %q", got, tt.topic)
+			}
+		})
+	}
+}
+
+func TestParseRequest_NotRocketmq(t *testing.T) {
+	parser := NewRocketmqParser()
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "http", data: []byte("GET /index.html HTTP/1.1\r\n\r\n")},
+		{name: "response", data: jsonFrame(`{"code":0,"flag":1,"language":"JAVA","opaque":7,"version":395}`)},
+		{name: "no opaque", data: jsonFrame(`{"code":10,"flag":0,"language":"JAVA"}`)},
+		{name: "not json", data: newFrame(serializeJson, []byte("[1,2,3]"), "")},
+		{name: "unknown serialization", data: newFrame(2, []byte(`{"code":10,"opaque":7}`), "")},
+		{name: "truncated header", data: jsonFrame(`{"code":10,"extFields":{"topic":"orders"},"flag":0,"opaque":7}`)[:40]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if parser.ParseRequest(protocol.NewRequestMessage(tt.data)) {
+				t.Errorf("the data should not be parsed as RocketMQ request")
+			}
+		})
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	parser := NewRocketmqParser()
+	tests := []struct {
+		name      string
+		data      []byte
+		ignored   bool
+		isError   bool
+		errorCode int64
+		errorMsg  string
+	}{
+		{
+			name: "success",
+			data: jsonFrame(`{"code":0,"extFields":{"msgId":"0A0A"},"flag":1,"language":"JAVA","opaque":7,"version":395}`),
+		},
+		{
+			name:      "pull not found",
+			data:      rocketmqFrame(pullNotFound, 7, flagResponse, "no new message"),
+			errorCode: pullNotFound,
+		},
+		{
+			name: "topic not exist",
+			data: jsonFrame(`{"code":17,"flag":1,"language":"JAVA","opaque":7,` +
+				`"remark":"topic[orders] not exist, apply first please!","version":395}`),
+			isError:   true,
+			errorCode: 17,
+			errorMsg:  "topic[orders] not exist, apply first please!",
+		},
+		{
+			name:      "system busy",
+			data:      rocketmqFrame(2, 7, flagResponse, "[PCBUSY_CLEAN_QUEUE]broker busy"),
+			isError:   true,
+			errorCode: 2,
+			errorMsg:  "[PCBUSY_CLEAN_QUEUE]broker busy",
+		},
+		{
+			name:    "request from broker",
+			data:    jsonFrame(`{"code":39,"extFields":{"topic":"orders"},"flag":2,"language":"JAVA","opaque":7,"version":395}`),
+			ignored: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := protocol.NewResponseMessage(tt.data, model.NewAttributeMap())
+			if !parser.ParseResponse(message) {
+				t.Fatalf("failed to parse the response")
+			}
+			if got := message.IsIgnored(); got != tt.ignored {
+				t.Errorf("ignored = %v, want %v", got, tt.ignored)
+			}
+			if got := message.GetIntAttribute(constlabels.RocketmqOpaque); got != 7 {
+				t.Errorf("opaque = %d, want 7", got)
+			}
+			if got := message.GetBoolAttribute(constlabels.IsError); got != tt.isError {
+				t.Errorf("is_error = %v, want %v", got, tt.isError)
+			}
+			if got := message.GetIntAttribute(constlabels.RocketmqErrorCode); got != tt.errorCode {
+				t.Errorf("error code = %d, want %d", got, tt.errorCode)
+			}
+			if got := message.GetStringAttribute(constlabels.RocketmqErrorMsg); got != tt.errorMsg {
+				t.Errorf("error message = %q, want %q", got, tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestPairMatch(t *testing.T) {
+	parser := NewRocketmqParser()
+	var requests []*protocol.PayloadMessage
+	for _, opaque := range []int{11, 12, 13} {
+		request := protocol.NewRequestMessage(rocketmqFrame(sendMessage, opaque, 0, "", "topic", "orders"))
+		if !parser.ParseRequest(request) {
+			t.Fatalf("failed to parse the request")
+		}
+		requests = append(requests, request)
+	}
+	response := protocol.NewResponseMessage(rocketmqFrame(responseSuccess, 12, flagResponse, ""), model.NewAttributeMap())
+	if !parser.ParseResponse(response) {
+		t.Fatalf("failed to parse the response")
+	}
+	if got := parser.PairMatch(requests, response); got != 1 {
+		t.Errorf("pair match = %d, want 1", got)
+	}
+}
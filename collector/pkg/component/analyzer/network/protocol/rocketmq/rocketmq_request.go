@@ -0,0 +1,37 @@
+package rocketmq
+
+import (
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func fastfailRocketmqRequest() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < prefixLength
+	}
+}
+
+/*
+The topic is in the extFields of the header, like the one of SEND_MESSAGE.
+
+	{"code":10,"extFields":{"producerGroup":"group","topic":"orders",...},"flag":0,"opaque":1,...}
+*/
+func parseRocketmqRequest() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		h, ok := readHeader(message.Data)
+		if !ok || h.isResponse() {
+			return false, true
+		}
+		if h.isOneway() {
+			// No response is expected.
+			message.Ignore()
+		}
+		message.AddIntAttribute(constlabels.RocketmqOpaque, int64(h.opaque))
+		message.AddIntAttribute(constlabels.RocketmqRequestCode, int64(h.code))
+		message.AddUtf8StringAttribute(constlabels.Operation, getOperation(h.code))
+		if topic := getTopic(h.code, h.extFields); len(topic) > 0 {
+			message.AddUtf8StringAttribute(constlabels.Topic, topic)
+		}
+		return true, true
+	}
+}
@@ -0,0 +1,41 @@
+package rocketmq
+
+import (
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func fastfailRocketmqResponse() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < prefixLength
+	}
+}
+
+/*
+The code of the response is the result, and the remark describes the error.
+
+	{"code":17,"flag":1,"opaque":1,"remark":"topic[orders] not exist, apply first please!",...}
+*/
+func parseRocketmqResponse() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		h, ok := readHeader(message.Data)
+		if !ok {
+			return false, true
+		}
+		message.AddIntAttribute(constlabels.RocketmqOpaque, int64(h.opaque))
+		if !h.isResponse() {
+			// The requests sent by the brokers, like CHECK_TRANSACTION_STATE.
+			message.Ignore()
+			return true, true
+		}
+		message.AddIntAttribute(constlabels.RocketmqErrorCode, int64(h.code))
+		if isErrorCode(h.code) {
+			message.AddBoolAttribute(constlabels.IsError, true)
+			message.AddIntAttribute(constlabels.ErrorType, int64(constlabels.ProtocolError))
+			if len(h.remark) > 0 {
+				message.AddUtf8StringAttribute(constlabels.RocketmqErrorMsg, h.remark)
+			}
+		}
+		return true, true
+	}
+}
@@ -0,0 +1,118 @@
+package thrift
+
+import (
+	"encoding/binary"
+)
+
+const (
+	binaryTypeStop   = 0
+	binaryTypeI32    = 8
+	binaryTypeString = 11
+
+	compactTypeI32    = 5
+	compactTypeBinary = 8
+
+	fieldIdMessage = 1
+	fieldIdType    = 2
+)
+
+/*
+TApplicationException is the struct in the EXCEPTION replies.
+
+	struct TApplicationException {
+	  1: string message
+	  2: i32 type
+	}
+
+The fields are returned as far as they are read, for the body may be truncated.
+*/
+func readApplicationException(m *message) (string, int32) {
+	if m.compact {
+		return readCompactException(m.body)
+	}
+	return readBinaryException(m.body)
+}
+
+// The field header of the binary protocol is the type byte and the int16 field id.
+func readBinaryException(data []byte) (msg string, errType int32) {
+	for len(data) >= 3 && data[0] != binaryTypeStop {
+		fieldType := data[0]
+		id := int16(binary.BigEndian.Uint16(data[1:]))
+		data = data[3:]
+		switch {
+		case id == fieldIdMessage && fieldType == binaryTypeString:
+			if len(data) < 4 {
+				return
+			}
+			length := int(int32(binary.BigEndian.Uint32(data)))
+			if length < 0 {
+				return
+			}
+			data = data[4:]
+			if len(data) < length {
+				msg = string(data)
+				return
+			}
+			msg = string(data[:length])
+			data = data[length:]
+		case id == fieldIdType && fieldType == binaryTypeI32:
+			if len(data) < 4 {
+				return
+			}
+			errType = int32(binary.BigEndian.Uint32(data))
+			data = data[4:]
+		default:
+			return
+		}
+	}
+	return
+}
+
+// The field header of the compact protocol is the delta of the field id in the highest 4 bits
+// and the type in the lowest 4 bits. The i32 values are zigzag varints.
+func readCompactException(data []byte) (msg string, errType int32) {
+	var lastId int64
+	for len(data) >= 1 && data[0] != binaryTypeStop {
+		fieldType := data[0] & 0x0f
+		delta := int64(data[0] >> 4)
+		data = data[1:]
+		id := lastId + delta
+		if delta == 0 {
+			v, n := readVarint(data)
+			if n == 0 {
+				return
+			}
+			id = zigzag(v)
+			data = data[n:]
+		}
+		lastId = id
+		switch {
+		case id == fieldIdMessage && fieldType == compactTypeBinary:
+			length, n := readVarint(data)
+			if n == 0 {
+				return
+			}
+			data = data[n:]
+			if uint64(len(data)) < length {
+				msg = string(data)
+				return
+			}
+			msg = string(data[:length])
+			data = data[length:]
+		case id == fieldIdType && fieldType == compactTypeI32:
+			v, n := readVarint(data)
+			if n == 0 {
+				return
+			}
+			errType = int32(zigzag(v))
+			data = data[n:]
+		default:
+			return
+		}
+	}
+	return
+}
+
+func zigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
@@ -0,0 +1,217 @@
+package thrift
+
+import (
+	"encoding/binary"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+const (
+	// maxFrameLength is the default maximum frame size of TFramedTransport.
+	maxFrameLength = 16384000
+	// maxNameLength limits the method names to tell the messages from the other protocols.
+	maxNameLength = 256
+
+	binaryVersionMask = 0xffff0000
+	binaryVersion1    = 0x80010000
+	compactProtocolId = 0x82
+	compactVersion    = 1
+
+	messageCall      = 1
+	messageReply     = 2
+	messageException = 3
+	messageOneway    = 4
+)
+
+var messageTypes = [...]string{"", "CALL", "REPLY", "EXCEPTION", "ONEWAY"}
+
+/*
+NewThriftParser parses the messages of Apache Thrift in the binary and compact protocols, with or
+without TFramedTransport.
+
+	Request                     Response
+	/      \                    /      \
+	CALL   ONEWAY               REPLY  EXCEPTION
+
+The method is the content key, and the replies are paired with the calls by the seqid. The
+EXCEPTION replies carry TApplicationException, which are errors. The ONEWAY calls expect no
+replies and are ignored.
+*/
+func NewThriftParser() *protocol.ProtocolParser {
+	requestParser := protocol.CreatePkgParser(fastfailThriftRequest(), parseThriftRequest())
+	responseParser := protocol.CreatePkgParser(fastfailThriftResponse(), parseThriftResponse())
+
+	parser := protocol.NewProtocolParser(protocol.THRIFT, requestParser, responseParser, thriftPair())
+	parser.EnableMultiplexing()
+	return parser
+}
+
+func thriftPair() protocol.PairMatch {
+	return func(requests []*protocol.PayloadMessage, response *protocol.PayloadMessage) int {
+		seqId := response.GetIntAttribute(constlabels.ThriftSeqId)
+		for i, request := range requests {
+			if request.IsIgnored() || !request.HasAttribute(constlabels.ThriftSeqId) {
+				continue
+			}
+			if request.GetIntAttribute(constlabels.ThriftSeqId) == seqId {
+				return i
+			}
+		}
+		return -1
+	}
+}
+
+type message struct {
+	compact     bool
+	messageType int
+	name        string
+	seqId       int32
+	// body is the struct of the arguments or the result, which may be truncated.
+	body []byte
+}
+
+// readMessage reads the message unframed first, or following the frame size of TFramedTransport.
+func readMessage(data []byte) (*message, bool) {
+	if m, ok := readUnframedMessage(data); ok {
+		return m, true
+	}
+	if len(data) < 4 {
+		return nil, false
+	}
+	size := int(int32(binary.BigEndian.Uint32(data)))
+	if size <= 0 || size > maxFrameLength {
+		return nil, false
+	}
+	return readUnframedMessage(data[4:])
+}
+
+func readUnframedMessage(data []byte) (*message, bool) {
+	if len(data) < 1 {
+		return nil, false
+	}
+	if data[0] == compactProtocolId {
+		return readCompactMessage(data)
+	}
+	if data[0]&0x80 != 0 {
+		return readStrictBinaryMessage(data)
+	}
+	return readBinaryMessage(data)
+}
+
+/*
+The strict binary protocol
+
+	int32    the version 0x8001 with the message type in the lowest byte
+	string   the name, which is an int32 length and the bytes
+	int32    seqid
+*/
+func readStrictBinaryMessage(data []byte) (*message, bool) {
+	if len(data) < 8 {
+		return nil, false
+	}
+	version := binary.BigEndian.Uint32(data)
+	if version&binaryVersionMask != binaryVersion1 {
+		return nil, false
+	}
+	m := &message{messageType: int(version & 0xff)}
+	return m, m.readBinaryNameAndSeqId(data[4:], false)
+}
+
+/*
+The old binary protocol without the version
+
+	string   the name
+	byte     the message type
+	int32    seqid
+*/
+func readBinaryMessage(data []byte) (*message, bool) {
+	m := &message{}
+	return m, m.readBinaryNameAndSeqId(data, true)
+}
+
+func (m *message) readBinaryNameAndSeqId(data []byte, typeAfterName bool) bool {
+	if len(data) < 4 {
+		return false
+	}
+	length := int(int32(binary.BigEndian.Uint32(data)))
+	if length < 0 || length > maxNameLength || len(data) < 4+length {
+		return false
+	}
+	m.name = string(data[4 : 4+length])
+	data = data[4+length:]
+	if typeAfterName {
+		if len(data) < 1 {
+			return false
+		}
+		m.messageType = int(data[0])
+		data = data[1:]
+	}
+	if len(data) < 4 {
+		return false
+	}
+	m.seqId = int32(binary.BigEndian.Uint32(data))
+	m.body = data[4:]
+	return m.isValid()
+}
+
+/*
+The compact protocol
+
+	byte      the protocol id 0x82
+	byte      the message type in the highest 3 bits, and the version in the lowest 5 bits
+	varint    seqid
+	varint    the length of the name
+	bytes     the name
+*/
+func readCompactMessage(data []byte) (*message, bool) {
+	if len(data) < 4 || data[1]&0x1f != compactVersion {
+		return nil, false
+	}
+	m := &message{compact: true, messageType: int(data[1] >> 5)}
+	seqId, n := readVarint(data[2:])
+	if n == 0 {
+		return nil, false
+	}
+	m.seqId = int32(seqId)
+	data = data[2+n:]
+	length, n := readVarint(data)
+	if n == 0 || length > maxNameLength || uint64(len(data)) < uint64(n)+length {
+		return nil, false
+	}
+	m.name = string(data[n : n+int(length)])
+	m.body = data[n+int(length):]
+	return m, m.isValid()
+}
+
+// readVarint returns the value and the number of bytes read, which is 0 if it fails.
+func readVarint(data []byte) (uint64, int) {
+	var value uint64
+	for i := 0; i < len(data) && i < 10; i++ {
+		value |= uint64(data[i]&0x7f) << (7 * i)
+		if data[i]&0x80 == 0 {
+			return value, i + 1
+		}
+	}
+	return 0, 0
+}
+
+// isValid checks the message type and the name, which is an identifier, or prefixed by the
+// service name and ":" in TMultiplexedProtocol.
+func (m *message) isValid() bool {
+	if m.messageType < messageCall || m.messageType > messageOneway {
+		return false
+	}
+	if len(m.name) == 0 {
+		// Some servers reply the exceptions with empty names, like the unknown methods.
+		return m.messageType == messageException
+	}
+	for i := 0; i < len(m.name); i++ {
+		c := m.name[i]
+		if !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') &&
+			c != '_' && c != '.' && c != ':' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,185 @@
+package thrift
+
+import (
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/internal/testutil"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func binaryMessage(messageType int, name string, seqId int32, body ...[]byte) []byte {
+	return testutil.Join(append([][]byte{testutil.Int32(int(binaryVersion1 | messageType)), testutil.Int32(len(name)), []byte(name), testutil.Int32(int(seqId))}, body...)...)
+}
+
+func oldBinaryMessage(messageType int, name string, seqId int32) []byte {
+	return testutil.Join(testutil.Int32(len(name)), []byte(name), []byte{byte(messageType)}, testutil.Int32(int(seqId)), []byte{binaryTypeStop})
+}
+
+func compactMessage(messageType int, name string, seqId int32, body ...[]byte) []byte {
+	return testutil.Join(append([][]byte{{compactProtocolId, byte(messageType<<5 | compactVersion)}, testutil.Varint(uint64(uint32(seqId))), testutil.Varint(uint64(len(name))), []byte(name)}, body...)...)
+}
+
+func framed(data []byte) []byte {
+	return append(testutil.Int32(len(data)), data...)
+}
+
+func binaryException(msg string, errType int32) []byte {
+	return testutil.Join([]byte{binaryTypeString, 0, 1}, testutil.Int32(len(msg)), []byte(msg),
+		[]byte{binaryTypeI32, 0, 2}, testutil.Int32(int(errType)), []byte{binaryTypeStop})
+}
+
+func compactException(msg string, errType int32) []byte {
+	return testutil.Join([]byte{1<<4 | compactTypeBinary}, testutil.Varint(uint64(len(msg))), []byte(msg),
+		[]byte{1<<4 | compactTypeI32}, testutil.Varint(uint64(errType<<1)), []byte{binaryTypeStop})
+}
+
+func TestParseThrift(t *testing.T) {
+	parser := NewThriftParser()
+	tests := []struct {
+		name        string
+		request     []byte
+		response    []byte
+		contentKey  string
+		messageType string
+		seqId       int64
+		isError     bool
+		errorType   int64
+		errorMsg    string
+	}{
+		{
+			name:        "binary",
+			request:     binaryMessage(messageCall, "getUser", 1, []byte{binaryTypeStop}),
+			response:    binaryMessage(messageReply, "getUser", 1, []byte{binaryTypeStop}),
+			contentKey:  "getUser",
+			messageType: "REPLY",
+			seqId:       1,
+		},
+		{
+			name:        "binary framed",
+			request:     framed(binaryMessage(messageCall, "UserService:getUser", 2, []byte{binaryTypeStop})),
+			response:    framed(binaryMessage(messageReply, "UserService:getUser", 2, []byte{binaryTypeStop})),
+			contentKey:  "UserService:getUser",
+			messageType: "REPLY",
+			seqId:       2,
+		},
+		{
+			name:        "binary without the version",
+			request:     oldBinaryMessage(messageCall, "ping", 3),
+			response:    oldBinaryMessage(messageReply, "ping", 3),
+			contentKey:  "ping",
+			messageType: "REPLY",
+			seqId:       3,
+		},
+		{
+			name:        "binary exception",
+			request:     binaryMessage(messageCall, "deleteUser", 4, []byte{binaryTypeStop}),
+			response:    binaryMessage(messageException, "deleteUser", 4, binaryException("Internal error processing deleteUser", 6)),
+			contentKey:  "deleteUser",
+			messageType: "EXCEPTION",
+			seqId:       4,
+			isError:     true,
+			errorType:   6,
+			errorMsg:    "Internal error processing deleteUser",
+		},
+		{
+			name:        "compact",
+			request:     compactMessage(messageCall, "getUser", 300, []byte{binaryTypeStop}),
+			response:    compactMessage(messageReply, "getUser", 300, []byte{binaryTypeStop}),
+			contentKey:  "getUser",
+			messageType: "REPLY",
+			seqId:       300,
+		},
+		{
+			name:        "compact framed exception",
+			request:     framed(compactMessage(messageCall, "listUsers", 5, []byte{binaryTypeStop})),
+			response:    framed(compactMessage(messageException, "listUsers", 5, compactException("Invalid method name: 'listUsers'", 1))),
+			contentKey:  "listUsers",
+			messageType: "EXCEPTION",
+			seqId:       5,
+			isError:     true,
+			errorType:   1,
+			errorMsg:    "Invalid method name: 'listUsers'",
+		},
+		{
+			name:        "truncated exception",
+			request:     binaryMessage(messageCall, "deleteUser", 6, []byte{binaryTypeStop}),
+			response:    binaryMessage(messageException, "deleteUser", 6, binaryException("Internal error processing deleteUser", 6)[:20]),
+			contentKey:  "deleteUser",
+			messageType: "EXCEPTION",
+			seqId:       6,
+			isError:     true,
+			errorMsg:    "Internal erro",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := protocol.NewRequestMessage(tt.request)
+			if !parser.ParseRequest(request) {
+				t.Fatalf("failed to parse the request")
+			}
+			response := protocol.NewResponseMessage(tt.response, model.NewAttributeMap())
+			if !parser.ParseResponse(response) {
+				t.Fatalf("failed to parse the response")
+			}
+			if parser.PairMatch([]*protocol.PayloadMessage{request}, response) != 0 {
+				t.Fatalf("the response is not paired with the request")
+			}
+			if got := request.GetStringAttribute(constlabels.ContentKey); got != tt.contentKey {
+				t.Errorf("content key = %q, want %q", got, tt.contentKey)
+			}
+			if got := response.GetStringAttribute(constlabels.ThriftMessageType); got != tt.messageType {
+				t.Errorf("message type = %q, want %q", got, tt.messageType)
+			}
+			if got := response.GetIntAttribute(constlabels.ThriftSeqId); got != tt.seqId {
+				t.Errorf("seqid = %d, want %d", got, tt.seqId)
+			}
+			if got := response.GetBoolAttribute(constlabels.IsError); got != tt.isError {
+				t.Errorf("is_error = %v, want %v", got, tt.isError)
+			}
+			if got := response.GetIntAttribute(constlabels.ThriftErrorType); got != tt.errorType {
+				t.Errorf("error type = %d, want %d", got, tt.errorType)
+			}
+			if got := response.GetStringAttribute(constlabels.ThriftErrorMsg); got != tt.errorMsg {
+				t.Errorf("error msg = %q, want %q", got, tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestParseRequest_Oneway(t *testing.T) {
+	parser := NewThriftParser()
+	message := protocol.NewRequestMessage(framed(binaryMessage(messageOneway, "log", 7, []byte{binaryTypeStop})))
+	if !parser.ParseRequest(message) {
+		t.Fatalf("failed to parse the request")
+	}
+	if !message.IsIgnored() {
+		t.Errorf("the oneway call should be ignored")
+	}
+	if got := message.GetStringAttribute(constlabels.ThriftMessageType); got != "ONEWAY" {
+		t.Errorf("message type = %q, want ONEWAY", got)
+	}
+}
+
+func TestParseRequest_NotThrift(t *testing.T) {
+	parser := NewThriftParser()
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "http", data: []byte("GET /index.html HTTP/1.1\r\n\r\n")},
+		{name: "reply", data: binaryMessage(messageReply, "getUser", 1, []byte{binaryTypeStop})},
+		{name: "unknown version", data: append([]byte{0x80, 0x02, 0, 1}, binaryMessage(messageCall, "getUser", 1)[4:]...)},
+		{name: "unknown message type", data: binaryMessage(5, "getUser", 1)},
+		{name: "invalid name", data: binaryMessage(messageCall, "get user", 1)},
+		{name: "compact version", data: []byte{compactProtocolId, messageCall<<5 | 2, 1, 7, 'g', 'e', 't', 'U', 's', 'e', 'r'}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if parser.ParseRequest(protocol.NewRequestMessage(tt.data)) {
+				t.Errorf("the data should not be parsed as Thrift")
+			}
+		})
+	}
+}
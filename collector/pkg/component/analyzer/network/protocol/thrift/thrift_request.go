@@ -0,0 +1,29 @@
+package thrift
+
+import (
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func fastfailThriftRequest() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < 8
+	}
+}
+
+func parseThriftRequest() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		m, ok := readMessage(message.Data)
+		if !ok || (m.messageType != messageCall && m.messageType != messageOneway) {
+			return false, true
+		}
+		if m.messageType == messageOneway {
+			// No reply is expected.
+			message.Ignore()
+		}
+		message.AddStringAttribute(constlabels.ThriftMessageType, messageTypes[m.messageType])
+		message.AddIntAttribute(constlabels.ThriftSeqId, int64(m.seqId))
+		message.AddStringAttribute(constlabels.ContentKey, m.name)
+		return true, true
+	}
+}
@@ -0,0 +1,33 @@
+package thrift
+
+import (
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func fastfailThriftResponse() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < 8
+	}
+}
+
+func parseThriftResponse() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		m, ok := readMessage(message.Data)
+		if !ok || (m.messageType != messageReply && m.messageType != messageException) {
+			return false, true
+		}
+		message.AddStringAttribute(constlabels.ThriftMessageType, messageTypes[m.messageType])
+		message.AddIntAttribute(constlabels.ThriftSeqId, int64(m.seqId))
+		if m.messageType == messageException {
+			msg, errType := readApplicationException(m)
+			message.AddBoolAttribute(constlabels.IsError, true)
+			message.AddIntAttribute(constlabels.ErrorType, int64(constlabels.ProtocolError))
+			message.AddIntAttribute(constlabels.ThriftErrorType, int64(errType))
+			if len(msg) > 0 {
+				message.AddUtf8StringAttribute(constlabels.ThriftErrorMsg, msg)
+			}
+		}
+		return true, true
+	}
+}
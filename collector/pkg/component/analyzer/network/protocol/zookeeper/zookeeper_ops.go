@@ -0,0 +1,85 @@
+package zookeeper
+
+// opTypes are the names of the operations sent by the clients, which are defined in ZooDefs.OpCode.
+var opTypes = map[int32]string{
+	1:   "create",
+	2:   "delete",
+	3:   "exists",
+	4:   "getData",
+	5:   "setData",
+	6:   "getACL",
+	7:   "setACL",
+	8:   "getChildren",
+	9:   "sync",
+	11:  "ping",
+	12:  "getChildren2",
+	13:  "check",
+	14:  "multi",
+	15:  "create2",
+	16:  "reconfig",
+	17:  "checkWatches",
+	18:  "removeWatches",
+	19:  "createContainer",
+	20:  "deleteContainer",
+	21:  "createTTL",
+	22:  "multiRead",
+	100: "auth",
+	101: "setWatches",
+	102: "sasl",
+	103: "getEphemerals",
+	104: "getAllChildrenNumber",
+	105: "setWatches2",
+	106: "addWatch",
+	107: "whoAmI",
+	-11: "closeSession",
+}
+
+const (
+	opExists  = 3
+	opPing    = 11
+	errNoNode = -101
+)
+
+// errorCodes are the names of the error codes defined in KeeperException.Code.
+var errorCodes = map[int32]string{
+	-1:   "SYSTEMERROR",
+	-2:   "RUNTIMEINCONSISTENCY",
+	-3:   "DATAINCONSISTENCY",
+	-4:   "CONNECTIONLOSS",
+	-5:   "MARSHALLINGERROR",
+	-6:   "UNIMPLEMENTED",
+	-7:   "OPERATIONTIMEOUT",
+	-8:   "BADARGUMENTS",
+	-13:  "NEWCONFIGNOQUORUM",
+	-14:  "RECONFIGINPROGRESS",
+	-15:  "UNKNOWNSESSION",
+	-100: "APIERROR",
+	-101: "NONODE",
+	-102: "NOAUTH",
+	-103: "BADVERSION",
+	-108: "NOCHILDRENFOREPHEMERALS",
+	-110: "NODEEXISTS",
+	-111: "NOTEMPTY",
+	-112: "SESSIONEXPIRED",
+	-113: "INVALIDCALLBACK",
+	-114: "INVALIDACL",
+	-115: "AUTHFAILED",
+	-118: "SESSIONMOVED",
+	-119: "NOTREADONLY",
+	-120: "EPHEMERALONLOCALSESSION",
+	-121: "NOWATCHER",
+	-122: "REQUESTTIMEOUT",
+	-123: "RECONFIGDISABLED",
+	-124: "SESSIONCLOSEDREQUIRESASLAUTH",
+	-125: "QUOTAEXCEEDED",
+	-127: "THROTTLEDOP",
+}
+
+// isValidXid returns whether the xid is numbered by the clients or reserved for the requests.
+func isValidXid(xid int32) bool {
+	switch xid {
+	case xidPing, xidAuth, xidSetWatches:
+		return true
+	}
+	return xid > 0
+}
@@ -0,0 +1,129 @@
+package zookeeper
+
+import (
+	"encoding/binary"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+const (
+	// maxPacketLength is the default jute.maxbuffer of the servers and the clients.
+	maxPacketLength = 0xfffff
+
+	requestHeaderLength = 8
+	replyHeaderLength   = 16
+
+	// The lengths of ConnectRequest and ConnectResponse without and with the readOnly flag.
+	connectRequestLength  = 44
+	connectResponseLength = 36
+	passwordLength        = 16
+	// The offsets of the password lengths in ConnectRequest and ConnectResponse
+	connectRequestPasswordOffset  = 24
+	connectResponsePasswordOffset = 16
+
+	// xidConnect is the xid taken by the connection handshake, which has no headers. The clients
+	// number the other requests from 1.
+	xidConnect      = 0
+	xidNotification = -1
+	xidPing         = -2
+	xidAuth         = -4
+	xidSetWatches   = -8
+
+	pendingExistsKey = "zookeeper_pending_exists"
+	// maxPendingExists bounds the xids kept for the replies which are not captured.
+	maxPendingExists = 64
+)
+
+/*
+NewZookeeperParser parses the packets of the ZooKeeper client protocol.
+
+	Request                                Response
+	/         |         \                  |
+	connect   getData   create   ...       xid, zxid and err
+
+The clients send the requests on a connection without waiting for the replies, which are paired
+with the requests by the xid. The pings and the watch notifications sent by the servers are
+ignored, so they are not counted in the latency.
+*/
+func NewZookeeperParser() *protocol.ProtocolParser {
+	requestParser := protocol.CreatePkgParser(fastfailZookeeperRequest(), parseZookeeperRequest())
+	responseParser := protocol.CreatePkgParser(fastfailZookeeperResponse(), parseZookeeperResponse())
+
+	parser := protocol.NewProtocolParser(protocol.ZOOKEEPER, requestParser, responseParser, zookeeperPair())
+	parser.EnableMultiplexing()
+	return parser
+}
+
+func zookeeperPair() protocol.PairMatch {
+	return func(requests []*protocol.PayloadMessage, response *protocol.PayloadMessage) int {
+		xid := response.GetIntAttribute(constlabels.ZookeeperXid)
+		for i, request := range requests {
+			if request.IsIgnored() || !request.HasAttribute(constlabels.ZookeeperXid) {
+				continue
+			}
+			if request.GetIntAttribute(constlabels.ZookeeperXid) == xid {
+				return i
+			}
+		}
+		return -1
+	}
+}
+
+// readPacket returns the packet following the length, which may be truncated.
+func readPacket(data []byte) ([]byte, int, bool) {
+	if len(data) < 4 {
+		return nil, 0, false
+	}
+	length := int(int32(binary.BigEndian.Uint32(data)))
+	if length <= 0 || length > maxPacketLength {
+		return nil, 0, false
+	}
+	packet := data[4:]
+	if len(packet) > length {
+		packet = packet[:length]
+	}
+	return packet, length, true
+}
+
+/*
+The handshake of a new or reconnected session
+
+	ConnectRequest    int32 protocolVersion, int64 lastZxidSeen, int32 timeOut, int64 sessionId,
+	                  buffer passwd, [bool readOnly]
+	ConnectResponse   int32 protocolVersion, int32 timeOut, int64 sessionId, buffer passwd,
+	                  [bool readOnly]
+
+The password is always 16 bytes, even for a new session.
+*/
+func isConnect(packet []byte, length int, expected int, passwordOffset int) bool {
+	if length != expected && length != expected+1 {
+		return false
+	}
+	if len(packet) < passwordOffset+4 {
+		return false
+	}
+	return binary.BigEndian.Uint32(packet) == 0 &&
+		binary.BigEndian.Uint32(packet[passwordOffset:]) == passwordLength
+}
+
+// addPendingExists keeps the xid of exists, whose reply of NONODE is not an error. The replies are
+// parsed without the attributes of the requests, so the xids are kept in the connection states.
+func addPendingExists(states *protocol.ConnectionStates, xid int32) {
+	pending, _ := states.Get(pendingExistsKey).(map[int32]bool)
+	if pending == nil || len(pending) >= maxPendingExists {
+		pending = make(map[int32]bool)
+		states.Set(pendingExistsKey, pending)
+	}
+	pending[xid] = true
+}
+
+// removePendingExists returns whether the reply is of exists.
+func removePendingExists(states *protocol.ConnectionStates, xid int32) bool {
+	pending, _ := states.Get(pendingExistsKey).(map[int32]bool)
+	if !pending[xid] {
+		return false
+	}
+	delete(pending, xid)
+	return true
+}
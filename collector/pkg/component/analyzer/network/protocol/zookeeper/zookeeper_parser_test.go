@@ -0,0 +1,187 @@
+package zookeeper
+
+import (
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol/internal/testutil"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func packet(fields ...[]byte) []byte {
+	body := testutil.Join(fields...)
+	return append(testutil.Int32(len(body)), body...)
+}
+
+func request(xid int32, opType int32, path string) []byte {
+	if len(path) == 0 {
+		return packet(testutil.Int32(int(xid)), testutil.Int32(int(opType)))
+	}
+	return packet(testutil.Int32(int(xid)), testutil.Int32(int(opType)), testutil.String32(path), []byte{1})
+}
+
+func reply(xid int32, zxid int64, err int32, body ...[]byte) []byte {
+	return packet(append([][]byte{testutil.Int32(int(xid)), testutil.Int64(zxid), testutil.Int32(int(err))}, body...)...)
+}
+
+var (
+	connectRequest = packet(testutil.Int32(0), testutil.Int64(0), testutil.Int32(30000), testutil.Int64(0),
+		testutil.Int32(int(passwordLength)), make([]byte, passwordLength), []byte{0})
+	connectResponse = packet(testutil.Int32(0), testutil.Int32(30000), testutil.Int64(0x1000000a5b30003),
+		testutil.Int32(int(passwordLength)), make([]byte, passwordLength), []byte{0})
+)
+
+func TestParseZookeeper(t *testing.T) {
+	parser := NewZookeeperParser()
+	tests := []struct {
+		name       string
+		request    []byte
+		response   []byte
+		contentKey string
+		xid        int64
+		zxid       int64
+		errCode    int64
+		isError    bool
+		errorMsg   string
+	}{
+		{
+			name:       "connect",
+			request:    connectRequest,
+			response:   connectResponse,
+			contentKey: "connect",
+			xid:        xidConnect,
+		},
+		{
+			name:       "getData",
+			request:    request(1, 4, "/brokers/ids/1"),
+			response:   reply(1, 0x100000012, 0, testutil.String32("{\"host\":\"kafka-0\"}")),
+			contentKey: "getData",
+			xid:        1,
+			zxid:       0x100000012,
+		},
+		{
+			name:       "create node exists",
+			request:    packet(testutil.Int32(2), testutil.Int32(1), testutil.String32("/locks/order"), testutil.Int32(0), testutil.Int32(0), testutil.Int32(0)),
+			response:   reply(2, 0x100000013, -110),
+			contentKey: "create",
+			xid:        2,
+			zxid:       0x100000013,
+			errCode:    -110,
+			isError:    true,
+			errorMsg:   "NODEEXISTS",
+		},
+		{
+			name:       "exists no node",
+			request:    request(3, 3, "/config/feature"),
+			response:   reply(3, 0x100000014, -101),
+			contentKey: "exists",
+			xid:        3,
+			zxid:       0x100000014,
+			errCode:    -101,
+		},
+		{
+			name:       "setWatches",
+			request:    request(xidSetWatches, 101, ""),
+			response:   reply(xidSetWatches, 0x100000015, 0),
+			contentKey: "setWatches",
+			xid:        xidSetWatches,
+			zxid:       0x100000015,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states := protocol.NewConnectionStates()
+			request := protocol.NewRequestMessage(tt.request)
+			request.SetConnectionStates(states)
+			if !parser.ParseRequest(request) {
+				t.Fatalf("failed to parse the request")
+			}
+			response := protocol.NewResponseMessage(tt.response, model.NewAttributeMap())
+			response.SetConnectionStates(states)
+			if !parser.ParseResponse(response) {
+				t.Fatalf("failed to parse the response")
+			}
+			if parser.PairMatch([]*protocol.PayloadMessage{request}, response) != 0 {
+				t.Fatalf("the response is not paired with the request")
+			}
+			if got := request.GetStringAttribute(constlabels.ContentKey); got != tt.contentKey {
+				t.Errorf("content key = %q, want %q", got, tt.contentKey)
+			}
+			if got := response.GetIntAttribute(constlabels.ZookeeperXid); got != tt.xid {
+				t.Errorf("xid = %d, want %d", got, tt.xid)
+			}
+			if got := response.GetIntAttribute(constlabels.ZookeeperZxid); got != tt.zxid {
+				t.Errorf("zxid = %d, want %d", got, tt.zxid)
+			}
+			if got := response.GetIntAttribute(constlabels.ZookeeperErrorCode); got != tt.errCode {
+				t.Errorf("error code = %d, want %d", got, tt.errCode)
+			}
+			if got := response.GetBoolAttribute(constlabels.IsError); got != tt.isError {
+				t.Errorf("is_error = %v, want %v", got, tt.isError)
+			}
+			if got := response.GetStringAttribute(constlabels.ZookeeperErrorMsg); got != tt.errorMsg {
+				t.Errorf("error msg = %q, want %q", got, tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestParseZookeeper_Pipelined(t *testing.T) {
+	parser := NewZookeeperParser()
+	states := protocol.NewConnectionStates()
+	var requests []*protocol.PayloadMessage
+	for _, data := range [][]byte{request(7, 3, "/a"), request(8, 8, "/b"), request(xidPing, 11, "")} {
+		message := protocol.NewRequestMessage(data)
+		message.SetConnectionStates(states)
+		if !parser.ParseRequest(message) {
+			t.Fatalf("failed to parse the request")
+		}
+		requests = append(requests, message)
+	}
+	if !requests[2].IsIgnored() {
+		t.Errorf("the ping should be ignored")
+	}
+
+	response := protocol.NewResponseMessage(reply(8, 0x20, -101), model.NewAttributeMap())
+	response.SetConnectionStates(states)
+	if !parser.ParseResponse(response) {
+		t.Fatalf("failed to parse the response")
+	}
+	if got := parser.PairMatch(requests, response); got != 1 {
+		t.Errorf("paired with %d, want 1", got)
+	}
+	if !response.GetBoolAttribute(constlabels.IsError) {
+		t.Errorf("NONODE of getChildren should be an error")
+	}
+
+	for _, xid := range []int32{xidPing, xidNotification} {
+		response := protocol.NewResponseMessage(reply(xid, 0x20, 0), model.NewAttributeMap())
+		if !parser.ParseResponse(response) {
+			t.Fatalf("failed to parse the response")
+		}
+		if !response.IsIgnored() {
+			t.Errorf("the response of xid %d should be ignored", xid)
+		}
+	}
+}
+
+func TestParseRequest_NotZookeeper(t *testing.T) {
+	parser := NewZookeeperParser()
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "http", data: []byte("GET /index.html HTTP/1.1\r\n\r\n")},
+		{name: "unknown op type", data: request(1, 99, "/a")},
+		{name: "notification xid", data: request(xidNotification, 4, "/a")},
+		{name: "too long", data: append(testutil.Int32(int(maxPacketLength+1)), request(1, 4, "/a")[4:]...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if parser.ParseRequest(protocol.NewRequestMessage(tt.data)) {
+				t.Errorf("the data should not be parsed as ZooKeeper")
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package zookeeper
+
+import (
+	"encoding/binary"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func fastfailZookeeperRequest() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < 4+requestHeaderLength
+	}
+}
+
+/*
+The requests start with the RequestHeader, except the ConnectRequest.
+
+	int32   the length of the packet, excluding itself
+	int32   xid
+	int32   type
+	bytes   the request, like the path and the watch flag of getData
+*/
+func parseZookeeperRequest() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		packet, length, ok := readPacket(message.Data)
+		if !ok {
+			return false, true
+		}
+		if isConnect(packet, length, connectRequestLength, connectRequestPasswordOffset) {
+			message.AddIntAttribute(constlabels.ZookeeperXid, xidConnect)
+			message.AddStringAttribute(constlabels.ContentKey, "connect")
+			return true, true
+		}
+		if length < requestHeaderLength || len(packet) < requestHeaderLength {
+			return false, true
+		}
+		xid := int32(binary.BigEndian.Uint32(packet))
+		opType := int32(binary.BigEndian.Uint32(packet[4:]))
+		name, ok := opTypes[opType]
+		if !ok || !isValidXid(xid) {
+			return false, true
+		}
+		switch opType {
+		case opPing:
+			// The heartbeats are not counted in the latency.
+			message.Ignore()
+		case opExists:
+			addPendingExists(message.GetConnectionStates(), xid)
+		}
+		message.AddIntAttribute(constlabels.ZookeeperXid, int64(xid))
+		message.AddStringAttribute(constlabels.ContentKey, name)
+		return true, true
+	}
+}
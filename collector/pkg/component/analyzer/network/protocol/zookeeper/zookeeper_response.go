@@ -0,0 +1,65 @@
+package zookeeper
+
+import (
+	"encoding/binary"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network/protocol"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+func fastfailZookeeperResponse() protocol.FastFailFn {
+	return func(message *protocol.PayloadMessage) bool {
+		return len(message.Data) < 4+replyHeaderLength
+	}
+}
+
+/*
+The replies start with the ReplyHeader, except the ConnectResponse.
+
+	int32   the length of the packet, excluding itself
+	int32   xid
+	int64   zxid
+	int32   err
+	bytes   the response, which is absent if err is not 0
+
+NONODE is not an error of exists, which tells the node does not exist.
+*/
+func parseZookeeperResponse() protocol.ParsePkgFn {
+	return func(message *protocol.PayloadMessage) (bool, bool) {
+		packet, length, ok := readPacket(message.Data)
+		if !ok {
+			return false, true
+		}
+		if isConnect(packet, length, connectResponseLength, connectResponsePasswordOffset) {
+			message.AddIntAttribute(constlabels.ZookeeperXid, xidConnect)
+			return true, true
+		}
+		if length < replyHeaderLength || len(packet) < replyHeaderLength {
+			return false, true
+		}
+		xid := int32(binary.BigEndian.Uint32(packet))
+		zxid := int64(binary.BigEndian.Uint64(packet[4:]))
+		errCode := int32(binary.BigEndian.Uint32(packet[12:]))
+		if xid != xidNotification && !isValidXid(xid) {
+			return false, true
+		}
+		message.AddIntAttribute(constlabels.ZookeeperXid, int64(xid))
+		if xid == xidNotification || xid == xidPing {
+			// The watch events and the replies of the heartbeats
+			message.Ignore()
+			return true, true
+		}
+		message.AddIntAttribute(constlabels.ZookeeperZxid, zxid)
+		message.AddIntAttribute(constlabels.ZookeeperErrorCode, int64(errCode))
+		exists := removePendingExists(message.GetConnectionStates(), xid)
+		if errCode == 0 || (errCode == errNoNode && exists) {
+			return true, true
+		}
+		message.AddBoolAttribute(constlabels.IsError, true)
+		message.AddIntAttribute(constlabels.ErrorType, int64(constlabels.ProtocolError))
+		if msg, ok := errorCodes[errCode]; ok {
+			message.AddStringAttribute(constlabels.ZookeeperErrorMsg, msg)
+		}
+		return true, true
+	}
+}
@@ -1,12 +1,14 @@
 package tcpconnectanalyzer
 
 import (
+	"context"
 	"time"
 
 	"github.com/Kindling-project/kindling/collector/pkg/component"
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer"
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/tcpconnectanalyzer/internal"
 	"github.com/Kindling-project/kindling/collector/pkg/component/consumer"
+	"github.com/Kindling-project/kindling/collector/pkg/eventqueue"
 	conntrackerpackge "github.com/Kindling-project/kindling/collector/pkg/metadata/conntracker"
 	"github.com/Kindling-project/kindling/collector/pkg/model"
 	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
@@ -22,12 +24,14 @@ type TcpConnectAnalyzer struct {
 	nextConsumers []consumer.Consumer
 	conntracker   conntrackerpackge.Conntracker
 
-	eventChannel   chan *model.KindlingEvent
+	queue          *eventqueue.Queue
 	connectMonitor *internal.ConnectMonitor
 
-	stopCh chan bool
+	stopCh    chan bool
+	stoppedCh chan struct{}
 
 	telemetry *component.TelemetryTools
+	component.HealthReporter
 }
 
 func New(cfg interface{}, telemetry *component.TelemetryTools, consumers []consumer.Consumer) analyzer.Analyzer {
@@ -36,8 +40,8 @@ func New(cfg interface{}, telemetry *component.TelemetryTools, consumers []consu
 		config:        config,
 		nextConsumers: consumers,
 		telemetry:     telemetry,
-		eventChannel:  make(chan *model.KindlingEvent, config.ChannelSize),
 		stopCh:        make(chan bool),
+		stoppedCh:     make(chan struct{}),
 
 		connectMonitor: internal.NewConnectMonitor(telemetry.Logger),
 	}
@@ -46,7 +50,6 @@ func New(cfg interface{}, telemetry *component.TelemetryTools, consumers []consu
 		telemetry.Logger.Warn("Conntracker cannot work as expected:", zap.Error(err))
 	}
 	ret.conntracker = conntracker
-	newSelfMetrics(telemetry.MeterProvider, ret.connectMonitor)
 	return ret
 }
 
@@ -63,19 +66,30 @@ func (a *TcpConnectAnalyzer) ConsumableEvents() []string {
 }
 
 // Start initializes the analyzer
-func (a *TcpConnectAnalyzer) Start() error {
+func (a *TcpConnectAnalyzer) Start(ctx context.Context) error {
+	queueConfig := a.config.queueConfig()
+	// The queue is built after its configuration is validated, because an invalid size panics.
+	if err := queueConfig.Validate(); err != nil {
+		return err
+	}
+	a.queue = eventqueue.New(queueConfig)
+	newSelfMetrics(a.telemetry.MeterProvider, a.connectMonitor, a.queue)
+	a.SetHealth(component.StatusRunning, nil)
 	go func() {
 		scanTcpStateTicker := time.NewTicker(time.Duration(a.config.WaitEventSecond/3) * time.Second)
 		for {
 			select {
 			case <-scanTcpStateTicker.C:
 				a.trimConnectionsWithTcpStat()
-			case event := <-a.eventChannel:
+			case event := <-a.queue.Events():
 				a.consumeChannelEvent(event)
 			case <-a.stopCh:
+				// The receiver has stopped, so the events left in the channel are consumed first.
+				a.drainChannelEvents()
 				// Only trim the connections expired. For those unfinished, we leave them
 				// unchanged and just shutdown this goroutine.
 				a.trimConnectionsWithTcpStat()
+				close(a.stoppedCh)
 				return
 			}
 		}
@@ -83,9 +97,20 @@ func (a *TcpConnectAnalyzer) Start() error {
 	return nil
 }
 
+func (a *TcpConnectAnalyzer) drainChannelEvents() {
+	for {
+		select {
+		case event := <-a.queue.Events():
+			a.consumeChannelEvent(event)
+		default:
+			return
+		}
+	}
+}
+
 // ConsumeEvent gets the event from the previous component
 func (a *TcpConnectAnalyzer) ConsumeEvent(event *model.KindlingEvent) error {
-	a.eventChannel <- event
+	a.queue.Push(event)
 	return nil
 }
 
@@ -234,9 +259,23 @@ func (a *TcpConnectAnalyzer) findDNatTuple(sIp string, sPort uint64, dIp string,
 }
 
 // Shutdown cleans all the resources used by the analyzer
-func (a *TcpConnectAnalyzer) Shutdown() error {
-	a.stopCh <- true
-	return nil
+func (a *TcpConnectAnalyzer) Shutdown(ctx context.Context) error {
+	if a.Health().Status != component.StatusRunning {
+		return nil
+	}
+	defer a.SetHealth(component.StatusStopped, nil)
+	select {
+	case a.stopCh <- true:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	// Wait until the goroutine finishes the last trimming
+	select {
+	case <-a.stoppedCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // Type returns the type of the analyzer
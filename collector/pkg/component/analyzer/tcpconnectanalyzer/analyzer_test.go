@@ -0,0 +1,17 @@
+package tcpconnectanalyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component"
+)
+
+func TestStartWithInvalidQueue(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.ChannelSize = -1
+	a := New(cfg, component.NewDefaultTelemetryTools(), nil)
+	if err := a.Start(context.Background()); err == nil {
+		t.Errorf("Expected an error for the negative channel_size, but get nil")
+	}
+}
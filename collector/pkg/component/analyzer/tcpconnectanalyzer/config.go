@@ -1,15 +1,29 @@
 package tcpconnectanalyzer
 
+import "github.com/Kindling-project/kindling/collector/pkg/eventqueue"
+
 type Config struct {
-	ChannelSize     int  `mapstructure:"channel_size"`
-	WaitEventSecond int  `mapstructure:"wait_event_second"`
-	NeedProcessInfo bool `mapstructure:"need_process_info"`
+	ChannelSize int `mapstructure:"channel_size"`
+	// OverflowPolicy and SampleRates decide what to do when the channel is full. See eventqueue.Config.
+	OverflowPolicy  string         `mapstructure:"overflow_policy"`
+	SampleRates     map[string]int `mapstructure:"sample_rates"`
+	WaitEventSecond int            `mapstructure:"wait_event_second"`
+	NeedProcessInfo bool           `mapstructure:"need_process_info"`
 }
 
 func NewDefaultConfig() *Config {
 	return &Config{
 		ChannelSize:     2000,
+		OverflowPolicy:  eventqueue.PolicyBlock,
 		WaitEventSecond: 10,
 		NeedProcessInfo: false,
 	}
 }
+
+func (c *Config) queueConfig() eventqueue.Config {
+	return eventqueue.Config{
+		Size:           c.ChannelSize,
+		OverflowPolicy: c.OverflowPolicy,
+		SampleRates:    c.SampleRates,
+	}
+}
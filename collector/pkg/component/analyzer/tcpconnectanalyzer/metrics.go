@@ -5,19 +5,24 @@ import (
 	"sync"
 
 	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/tcpconnectanalyzer/internal"
+	"github.com/Kindling-project/kindling/collector/pkg/eventqueue"
 	"go.opentelemetry.io/otel/metric"
 )
 
 var once sync.Once
 
-const mapSizeMetric = "kindling_telemetry_tcpconnectanalyzer_map_size"
+const (
+	mapSizeMetric     = "kindling_telemetry_tcpconnectanalyzer_map_size"
+	queueMetricPrefix = "kindling_telemetry_tcpconnectanalyzer"
+)
 
-func newSelfMetrics(meterProvider metric.MeterProvider, monitor *internal.ConnectMonitor) {
+func newSelfMetrics(meterProvider metric.MeterProvider, monitor *internal.ConnectMonitor, queue *eventqueue.Queue) {
 	once.Do(func() {
 		meter := metric.Must(meterProvider.Meter("kindling"))
 		meter.NewInt64GaugeObserver(mapSizeMetric,
 			func(ctx context.Context, result metric.Int64ObserverResult) {
 				result.Observe(int64(monitor.GetMapSize()))
 			})
+		eventqueue.RegisterSelfMetrics(meter, queueMetricPrefix, queue)
 	})
 }
@@ -1,6 +1,7 @@
 package tcpmetricanalyzer
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/Kindling-project/kindling/collector/pkg/component"
@@ -23,6 +24,7 @@ type TcpMetricAnalyzer struct {
 	consumers   []consumer.Consumer
 	conntracker conntrackerpackge.Conntracker
 	telemetry   *component.TelemetryTools
+	component.HealthReporter
 }
 
 func NewTcpMetricAnalyzer(cfg interface{}, telemetry *component.TelemetryTools, nextConsumers []consumer.Consumer) analyzer.Analyzer {
@@ -38,7 +40,8 @@ func NewTcpMetricAnalyzer(cfg interface{}, telemetry *component.TelemetryTools,
 	return retAnalyzer
 }
 
-func (a *TcpMetricAnalyzer) Start() error {
+func (a *TcpMetricAnalyzer) Start(ctx context.Context) error {
+	a.SetHealth(component.StatusRunning, nil)
 	return nil
 }
 
@@ -161,7 +164,8 @@ func (a *TcpMetricAnalyzer) findDNatTuple(sIp string, sPort uint64, dIp string,
 }
 
 // Shutdown cleans all the resources used by the analyzer
-func (a *TcpMetricAnalyzer) Shutdown() error {
+func (a *TcpMetricAnalyzer) Shutdown(ctx context.Context) error {
+	a.SetHealth(component.StatusStopped, nil)
 	return nil
 }
 
@@ -1,9 +1,11 @@
 package exporter
 
 import (
+	"github.com/Kindling-project/kindling/collector/pkg/component"
 	"github.com/Kindling-project/kindling/collector/pkg/component/consumer"
 )
 
 type Exporter interface {
 	consumer.Consumer
+	component.Component
 }
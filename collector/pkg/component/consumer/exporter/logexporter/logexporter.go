@@ -1,6 +1,8 @@
 package logexporter
 
 import (
+	"context"
+
 	"github.com/Kindling-project/kindling/collector/pkg/component"
 	"github.com/Kindling-project/kindling/collector/pkg/component/consumer/exporter"
 	"github.com/Kindling-project/kindling/collector/pkg/model"
@@ -12,6 +14,7 @@ const Type = "logexporter"
 
 type LogExporter struct {
 	telemetry *component.TelemetryTools
+	component.HealthReporter
 }
 
 func New(config interface{}, telemetry *component.TelemetryTools) exporter.Exporter {
@@ -20,6 +23,16 @@ func New(config interface{}, telemetry *component.TelemetryTools) exporter.Expor
 	}
 }
 
+func (e *LogExporter) Start(ctx context.Context) error {
+	e.SetHealth(component.StatusRunning, nil)
+	return nil
+}
+
+func (e *LogExporter) Shutdown(ctx context.Context) error {
+	e.SetHealth(component.StatusStopped, nil)
+	return nil
+}
+
 func (e *LogExporter) Consume(dataGroup *model.DataGroup) error {
 	if ce := e.telemetry.Logger.Check(zapcore.DebugLevel, "Receiver DataGroup"); ce != nil {
 		ce.Write(
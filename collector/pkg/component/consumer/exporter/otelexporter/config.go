@@ -32,4 +32,10 @@ type AdapterConfig struct {
 	NeedTraceAsMetric       bool `mapstructure:"need_trace_as_metric"`
 	NeedPodDetail           bool `mapstructure:"need_pod_detail"`
 	StoreExternalSrcIP      bool `mapstructure:"store_external_src_ip"`
+	// ExtractedDimensions are the labels extracted by networkanalyzer and aggregated by
+	// aggregateprocessor, which are exported with the metrics and the spans.
+	ExtractedDimensions []string `mapstructure:"extracted_dimensions"`
+	// ExtractedAttributes are the other labels extracted by networkanalyzer, which are only
+	// exported with the spans.
+	ExtractedAttributes []string `mapstructure:"extracted_attributes"`
 }
@@ -19,6 +19,8 @@ func (e *OtelExporter) Consume(dataGroup *model.DataGroup) error {
 		// no need consume
 		return nil
 	}
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
 
 	dataGroupReceiverCounter.Add(context.Background(), 1, attribute.String("name", dataGroup.Name))
 	if ce := e.telemetry.Logger.Check(zap.DebugLevel, "exporter receives a dataGroup: "); ce != nil {
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"reflect"
+	"sync"
 	"time"
 
 	"github.com/Kindling-project/kindling/collector/pkg/component"
@@ -28,6 +31,7 @@ import (
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
 	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 )
 
@@ -69,8 +73,16 @@ type OtelExporter struct {
 	customLabels         []attribute.KeyValue
 	instrumentFactory    *instrumentFactory
 	telemetry            *component.TelemetryTools
+	promExporter         *prometheus.Exporter
+	resource             *resource.Resource
 
 	adapters []adapter.Adapter
+	// mutex protects the fields above which are replaced when a new configuration
+	// is applied at runtime.
+	mutex sync.RWMutex
+	// promServer serves the metrics in prometheus mode
+	promServer *http.Server
+	component.HealthReporter
 }
 
 func NewExporter(config interface{}, telemetry *component.TelemetryTools) exporter.Exporter {
@@ -79,19 +91,6 @@ func NewExporter(config interface{}, telemetry *component.TelemetryTools) export
 	if !ok {
 		telemetry.Logger.Panic("Cannot convert Component config", zap.String("componentType", Otel))
 	}
-	customLabels := make([]attribute.KeyValue, 0, len(cfg.CustomLabels))
-	for k, v := range cfg.CustomLabels {
-		customLabels = append(customLabels, attribute.String(k, v))
-	}
-
-	if cfg.ExportKind != PrometheusKindExporter {
-		commonLabels := GetCommonLabels(false, telemetry.Logger)
-		for i := 0; i < len(commonLabels); i++ {
-			if _, find := cfg.CustomLabels[string(commonLabels[i].Key)]; !find {
-				customLabels = append(customLabels, commonLabels[i])
-			}
-		}
-	}
 
 	hostName, err := os.Hostname()
 	if err != nil {
@@ -113,8 +112,78 @@ func NewExporter(config interface{}, telemetry *component.TelemetryTools) export
 		),
 	)
 
-	var otelexporter *OtelExporter
-	var cont *controller.Controller
+	return &OtelExporter{
+		cfg:       cfg,
+		telemetry: telemetry,
+		resource:  rs,
+	}
+}
+
+// Start creates the providers, and then starts the metric controller in push mode or
+// the HTTP server in prometheus mode.
+func (e *OtelExporter) Start(ctx context.Context) error {
+	err := e.start(ctx)
+	if err != nil {
+		e.SetHealth(component.StatusFailed, err)
+		return err
+	}
+	e.SetHealth(component.StatusRunning, nil)
+	return nil
+}
+
+func (e *OtelExporter) start(ctx context.Context) error {
+	newExporter, err := newOtelExporter(e.cfg, e.resource, e.telemetry)
+	if err != nil {
+		return fmt.Errorf("error happened when creating otel exporter: %w", err)
+	}
+	e.replaceWith(newExporter)
+	if e.cfg.ExportKind == PrometheusKindExporter {
+		e.promServer, err = StartServer(http.HandlerFunc(e.servePrometheus), e.telemetry.Logger, e.cfg.PromCfg.Port)
+		if err != nil {
+			return fmt.Errorf("error starting otelexporter prometheus server: %w", err)
+		}
+		return nil
+	}
+	if err = e.metricController.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start controller: %w", err)
+	}
+	return nil
+}
+
+// newOtelExporter creates an OtelExporter whose controller has not been started yet.
+func newOtelExporter(cfg *Config, rs *resource.Resource, telemetry *component.TelemetryTools) (*OtelExporter, error) {
+	customLabels := make([]attribute.KeyValue, 0, len(cfg.CustomLabels))
+	for k, v := range cfg.CustomLabels {
+		customLabels = append(customLabels, attribute.String(k, v))
+	}
+
+	if cfg.ExportKind != PrometheusKindExporter {
+		commonLabels := GetCommonLabels(false, telemetry.Logger)
+		for i := 0; i < len(commonLabels); i++ {
+			if _, find := cfg.CustomLabels[string(commonLabels[i].Key)]; !find {
+				customLabels = append(customLabels, commonLabels[i])
+			}
+		}
+	}
+
+	otelexporter := &OtelExporter{
+		cfg:                  cfg,
+		customLabels:         customLabels,
+		metricAggregationMap: cfg.MetricAggregationMap,
+		telemetry:            telemetry,
+		resource:             rs,
+		adapters: []adapter.Adapter{
+			adapter.NewNetAdapter(customLabels, &adapter.NetAdapterConfig{
+				StoreTraceAsMetric:  cfg.AdapterConfig.NeedTraceAsMetric,
+				StoreTraceAsSpan:    cfg.AdapterConfig.NeedTraceAsResourceSpan,
+				StorePodDetail:      cfg.AdapterConfig.NeedPodDetail,
+				StoreExternalSrcIP:  cfg.AdapterConfig.StoreExternalSrcIP,
+				ExtractedDimensions: cfg.AdapterConfig.ExtractedDimensions,
+				ExtractedAttributes: cfg.AdapterConfig.ExtractedAttributes,
+			}),
+			adapter.NewSimpleAdapter([]string{constnames.TcpMetricGroupName, constnames.TcpConnectMetricGroupName}, customLabels),
+		},
+	}
 
 	if cfg.ExportKind == PrometheusKindExporter {
 		config := prometheus.Config{}
@@ -129,106 +198,145 @@ func NewExporter(config interface{}, telemetry *component.TelemetryTools) export
 			controller.WithResource(rs),
 		)
 		exp, err := prometheus.New(config, c)
-
 		if err != nil {
-			telemetry.Logger.Panic("failed to initialize prometheus exporter %v", zap.Error(err))
-			return nil
+			return nil, fmt.Errorf("failed to initialize prometheus exporter: %w", err)
 		}
+		otelexporter.metricController = c
+		otelexporter.promExporter = exp
+		otelexporter.instrumentFactory = newInstrumentFactory(exp.MeterProvider().Meter(MeterName), telemetry.Logger, customLabels)
+		return otelexporter, nil
+	}
 
-		otelexporter = &OtelExporter{
-			cfg:                  cfg,
-			metricController:     c,
-			traceProvider:        nil,
-			defaultTracer:        nil,
-			customLabels:         customLabels,
-			instrumentFactory:    newInstrumentFactory(exp.MeterProvider().Meter(MeterName), telemetry.Logger, customLabels),
-			metricAggregationMap: cfg.MetricAggregationMap,
-			telemetry:            telemetry,
-			adapters: []adapter.Adapter{
-				adapter.NewNetAdapter(customLabels, &adapter.NetAdapterConfig{
-					StoreTraceAsMetric: cfg.AdapterConfig.NeedTraceAsMetric,
-					StoreTraceAsSpan:   cfg.AdapterConfig.NeedTraceAsResourceSpan,
-					StorePodDetail:     cfg.AdapterConfig.NeedPodDetail,
-					StoreExternalSrcIP: cfg.AdapterConfig.StoreExternalSrcIP,
-				}),
-				adapter.NewSimpleAdapter([]string{constnames.TcpMetricGroupName, constnames.TcpConnectMetricGroupName}, customLabels),
-			},
-		}
-		go func() {
-			err := StartServer(exp, telemetry.Logger, cfg.PromCfg.Port)
-			if err != nil {
-				telemetry.Logger.Warn("error starting otelexporter prometheus server: ", zap.Error(err))
-			}
-		}()
+	var collectPeriod time.Duration
+	if cfg.ExportKind == StdoutKindExporter {
+		collectPeriod = cfg.StdoutCfg.CollectPeriod
+	} else if cfg.ExportKind == OtlpGrpcKindExporter {
+		collectPeriod = cfg.OtlpGrpcCfg.CollectPeriod
 	} else {
-		var collectPeriod time.Duration
-
-		if cfg.ExportKind == StdoutKindExporter {
-			collectPeriod = cfg.StdoutCfg.CollectPeriod
-		} else if cfg.ExportKind == OtlpGrpcKindExporter {
-			collectPeriod = cfg.OtlpGrpcCfg.CollectPeriod
-		} else {
-			telemetry.Logger.Panic("Err! No exporter kind matched ", zap.String("exportKind", cfg.ExportKind))
-			return nil
-		}
+		return nil, fmt.Errorf("no exporter kind matched: %s", cfg.ExportKind)
+	}
 
-		exporters, err := newExporters(context.Background(), cfg, telemetry.Logger)
-		if err != nil {
-			telemetry.Logger.Panic("Error happened when creating otel exporter:", zap.Error(err))
-			return nil
-		}
+	exporters, err := newExporters(context.Background(), cfg, telemetry.Logger)
+	if err != nil {
+		return nil, err
+	}
 
-		cont = controller.New(
-			otelprocessor.NewFactory(simple.NewWithHistogramDistribution(
-				histogram.WithExplicitBoundaries(exponentialInt64NanosecondsBoundaries),
-			), exporters.metricExporter),
-			controller.WithExporter(exporters.metricExporter),
-			controller.WithCollectPeriod(collectPeriod),
-			controller.WithResource(rs),
-		)
+	cont := controller.New(
+		otelprocessor.NewFactory(simple.NewWithHistogramDistribution(
+			histogram.WithExplicitBoundaries(exponentialInt64NanosecondsBoundaries),
+		), exporters.metricExporter),
+		controller.WithExporter(exporters.metricExporter),
+		controller.WithCollectPeriod(collectPeriod),
+		controller.WithResource(rs),
+	)
 
-		// Init TraceProvider
-		ssp := sdktrace.NewBatchSpanProcessor(
-			exporters.traceExporter,
-			sdktrace.WithMaxQueueSize(2048),
-			sdktrace.WithMaxExportBatchSize(512),
-		)
+	// Init TraceProvider
+	ssp := sdktrace.NewBatchSpanProcessor(
+		exporters.traceExporter,
+		sdktrace.WithMaxQueueSize(2048),
+		sdktrace.WithMaxExportBatchSize(512),
+	)
 
-		tracerProvider := sdktrace.NewTracerProvider(
-			sdktrace.WithSampler(sdktrace.AlwaysSample()),
-			sdktrace.WithSpanProcessor(ssp),
-			sdktrace.WithResource(rs),
-		)
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSpanProcessor(ssp),
+		sdktrace.WithResource(rs),
+	)
 
-		tracer := tracerProvider.Tracer(TracerName)
-
-		otelexporter = &OtelExporter{
-			cfg:                  cfg,
-			metricController:     cont,
-			traceProvider:        tracerProvider,
-			defaultTracer:        tracer,
-			customLabels:         customLabels,
-			instrumentFactory:    newInstrumentFactory(cont.Meter(MeterName), telemetry.Logger, customLabels),
-			metricAggregationMap: cfg.MetricAggregationMap,
-			telemetry:            telemetry,
-			adapters: []adapter.Adapter{
-				adapter.NewNetAdapter(customLabels, &adapter.NetAdapterConfig{
-					StoreTraceAsMetric: cfg.AdapterConfig.NeedTraceAsMetric,
-					StoreTraceAsSpan:   cfg.AdapterConfig.NeedTraceAsResourceSpan,
-					StorePodDetail:     cfg.AdapterConfig.NeedPodDetail,
-					StoreExternalSrcIP: cfg.AdapterConfig.StoreExternalSrcIP,
-				}),
-				adapter.NewSimpleAdapter([]string{constnames.TcpMetricGroupName, constnames.TcpConnectMetricGroupName}, customLabels),
-			},
-		}
+	otelexporter.metricController = cont
+	otelexporter.traceProvider = tracerProvider
+	otelexporter.defaultTracer = tracerProvider.Tracer(TracerName)
+	otelexporter.instrumentFactory = newInstrumentFactory(cont.Meter(MeterName), telemetry.Logger, customLabels)
+	return otelexporter, nil
+}
 
-		if err = cont.Start(context.Background()); err != nil {
-			telemetry.Logger.Panic("failed to start controller:", zap.Error(err))
-			return nil
+// ApplyConfig applies a new configuration at runtime. The providers are rebuilt and swapped in,
+// and the old ones are stopped to flush their pending data. Note the cumulative metrics restart
+// from zero after the configuration is applied.
+func (e *OtelExporter) ApplyConfig(config interface{}) error {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return fmt.Errorf("cannot convert [%s] config", Otel)
+	}
+	e.mutex.RLock()
+	oldCfg := e.cfg
+	e.mutex.RUnlock()
+	if cfg.ExportKind != oldCfg.ExportKind {
+		return component.NewNotReloadableError(Otel, "export_kind")
+	}
+	if cfg.ExportKind == PrometheusKindExporter && !reflect.DeepEqual(cfg.PromCfg, oldCfg.PromCfg) {
+		return component.NewNotReloadableError(Otel, "prometheus.port")
+	}
+
+	newExporter, err := newOtelExporter(cfg, e.resource, e.telemetry)
+	if err != nil {
+		return err
+	}
+	if newExporter.traceProvider != nil {
+		if err = newExporter.metricController.Start(context.Background()); err != nil {
+			return fmt.Errorf("failed to start controller: %w", err)
 		}
 	}
 
-	return otelexporter
+	oldController, oldTraceProvider := e.replaceWith(newExporter)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err = stopProviders(ctx, oldController, oldTraceProvider); err != nil {
+		e.telemetry.Logger.Warn("Error happened when stopping the old providers: ", zap.Error(err))
+	}
+	e.telemetry.Logger.Sugar().Infof("[%s] applied the new configuration", Otel)
+	return nil
+}
+
+// replaceWith replaces the providers and the states built from the configuration with the
+// ones of newExporter, and returns the old providers.
+func (e *OtelExporter) replaceWith(newExporter *OtelExporter) (*controller.Controller, *sdktrace.TracerProvider) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	oldController, oldTraceProvider := e.metricController, e.traceProvider
+	e.cfg = newExporter.cfg
+	e.metricController = newExporter.metricController
+	e.traceProvider = newExporter.traceProvider
+	e.defaultTracer = newExporter.defaultTracer
+	e.metricAggregationMap = newExporter.metricAggregationMap
+	e.customLabels = newExporter.customLabels
+	e.instrumentFactory = newExporter.instrumentFactory
+	e.promExporter = newExporter.promExporter
+	e.adapters = newExporter.adapters
+	return oldController, oldTraceProvider
+}
+
+// Shutdown flushes the pending data before the agent exits. In push mode the metric controller
+// exports the last collection and the span batcher exports all the queued spans. In prometheus
+// mode the HTTP server is closed because nothing needs to be flushed.
+func (e *OtelExporter) Shutdown(ctx context.Context) error {
+	if e.Health().Status != component.StatusRunning {
+		return nil
+	}
+	defer e.SetHealth(component.StatusStopped, nil)
+	if e.promServer != nil {
+		return e.promServer.Shutdown(ctx)
+	}
+	e.mutex.RLock()
+	metricController, traceProvider := e.metricController, e.traceProvider
+	e.mutex.RUnlock()
+	return stopProviders(ctx, metricController, traceProvider)
+}
+
+// stopProviders stops the providers created in push mode. The trace provider is nil in
+// prometheus mode, where the controller is never started.
+func stopProviders(ctx context.Context, metricController *controller.Controller, traceProvider *sdktrace.TracerProvider) error {
+	if traceProvider == nil {
+		return nil
+	}
+	return multierr.Combine(metricController.Stop(ctx), traceProvider.Shutdown(ctx))
+}
+
+func (e *OtelExporter) servePrometheus(w http.ResponseWriter, r *http.Request) {
+	e.mutex.RLock()
+	exp := e.promExporter
+	e.mutex.RUnlock()
+	exp.ServeHTTP(w, r)
 }
 
 func (e *OtelExporter) findInstrumentKind(metricName string) (MetricAggregationKind, bool) {
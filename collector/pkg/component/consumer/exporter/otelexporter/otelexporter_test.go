@@ -35,7 +35,11 @@ func InitOtelExporter(t *testing.T) exporter.Exporter {
 	if err != nil {
 		t.Fatalf("error happened when unmarshaling config: %v", err)
 	}
-	return NewExporter(config, component.NewDefaultTelemetryTools())
+	exp := NewExporter(config, component.NewDefaultTelemetryTools())
+	if err = exp.Start(context.Background()); err != nil {
+		t.Fatalf("error happened when starting exporter: %v", err)
+	}
+	return exp
 }
 
 func TestConsumeAggNetMetricGroup(t *testing.T) {
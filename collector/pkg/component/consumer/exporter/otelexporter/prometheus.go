@@ -1,28 +1,34 @@
 package otelexporter
 
 import (
+	"net"
 	"net/http"
 
-	"go.opentelemetry.io/otel/exporters/prometheus"
 	"go.uber.org/zap"
 )
 
-func StartServer(exporter *prometheus.Exporter, logger *zap.Logger, port string) error {
-	http.HandleFunc("/metrics", exporter.ServeHTTP)
-
-	srv := http.Server{
+// StartServer listens at the port and serves the handler at "/metrics" in the background.
+// The returned server should be shut down when the exporter stops.
+func StartServer(handler http.Handler, logger *zap.Logger, port string) (*http.Server, error) {
+	ln, err := net.Listen("tcp", port)
+	if err != nil {
+		return nil, err
+	}
+	serveMux := http.NewServeMux()
+	serveMux.Handle("/metrics", handler)
+	srv := &http.Server{
 		Addr:    port,
-		Handler: http.DefaultServeMux,
+		Handler: serveMux,
 	}
 
 	logger.Sugar().Infof("Prometheus Server listening at port: [%s]", port)
-	err := srv.ListenAndServe()
-
-	if err != nil && err != http.ErrServerClosed {
-		return err
-	}
-
-	logger.Sugar().Infof("Prometheus gracefully shutdown the http server...\n")
-
-	return nil
+	go func() {
+		err := srv.Serve(ln)
+		if err != nil && err != http.ErrServerClosed {
+			logger.Warn("error happened when serving otelexporter prometheus server: ", zap.Error(err))
+			return
+		}
+		logger.Sugar().Infof("Prometheus gracefully shutdown the http server...\n")
+	}()
+	return srv, nil
 }
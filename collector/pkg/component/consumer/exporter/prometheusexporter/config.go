@@ -16,4 +16,7 @@ type AdapterConfig struct {
 	NeedTraceAsMetric       bool `mapstructure:"need_trace_as_metric"`
 	NeedPodDetail           bool `mapstructure:"need_pod_detail"`
 	StoreExternalSrcIP      bool `mapstructure:"store_external_src_ip"`
+	// ExtractedDimensions are the labels extracted by networkanalyzer and aggregated by
+	// aggregateprocessor, which are exported with the metrics.
+	ExtractedDimensions []string `mapstructure:"extracted_dimensions"`
 }
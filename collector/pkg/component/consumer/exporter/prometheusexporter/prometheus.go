@@ -35,6 +35,7 @@ type prometheusExporter struct {
 	handler              http.Handler
 	adapters             map[string][]adapter3.Adapter
 	adapter              adapter3.Adapter
+	component.HealthReporter
 }
 
 func NewExporter(config interface{}, telemetry *component.TelemetryTools) exporter.Exporter {
@@ -48,10 +49,11 @@ func NewExporter(config interface{}, telemetry *component.TelemetryTools) export
 	registry.Register(collector)
 
 	netAdapter := adapter3.NewNetAdapter(nil, &adapter3.NetAdapterConfig{
-		StoreTraceAsMetric: cfg.AdapterConfig.NeedTraceAsMetric,
-		StoreTraceAsSpan:   false,
-		StorePodDetail:     cfg.AdapterConfig.NeedPodDetail,
-		StoreExternalSrcIP: cfg.AdapterConfig.StoreExternalSrcIP,
+		StoreTraceAsMetric:  cfg.AdapterConfig.NeedTraceAsMetric,
+		StoreTraceAsSpan:    false,
+		StorePodDetail:      cfg.AdapterConfig.NeedPodDetail,
+		StoreExternalSrcIP:  cfg.AdapterConfig.StoreExternalSrcIP,
+		ExtractedDimensions: cfg.AdapterConfig.ExtractedDimensions,
 	})
 	simpleAdapter := adapter3.NewSimpleAdapter([]string{constnames.TcpMetricGroupName}, nil)
 
@@ -75,10 +77,6 @@ func NewExporter(config interface{}, telemetry *component.TelemetryTools) export
 		},
 		adapter: simpleAdapter,
 	}
-
-	go func() {
-		prometheusExporter.Start(context.Background())
-	}()
 	return prometheusExporter
 }
 
@@ -102,6 +100,7 @@ var exponentialInt64NanosecondsBoundaries = func(bounds []float64) (asint []floa
 func (p *prometheusExporter) Start(_ context.Context) error {
 	ln, err := net.Listen("tcp", p.cfg.PromCfg.Endpoint)
 	if err != nil {
+		p.SetHealth(component.StatusFailed, err)
 		return err
 	}
 
@@ -113,10 +112,18 @@ func (p *prometheusExporter) Start(_ context.Context) error {
 	go func() {
 		_ = srv.Serve(ln)
 	}()
-
+	p.SetHealth(component.StatusRunning, nil)
 	return nil
 }
 
+func (p *prometheusExporter) Shutdown(_ context.Context) error {
+	if p.Health().Status != component.StatusRunning {
+		return nil
+	}
+	p.SetHealth(component.StatusStopped, nil)
+	return p.shutdownFunc()
+}
+
 type promLogger struct {
 	realLog *zap.Logger
 }
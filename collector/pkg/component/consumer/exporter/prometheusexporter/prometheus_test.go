@@ -1,6 +1,7 @@
 package prometheusexporter
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -25,7 +26,11 @@ func InitPrometheusExporter(t *testing.T) exporter.Exporter {
 	if err != nil {
 		t.Fatalf("error happened when unmarshaling config: %v", err)
 	}
-	return NewExporter(config, component.NewDefaultTelemetryTools())
+	exp := NewExporter(config, component.NewDefaultTelemetryTools())
+	if err = exp.Start(context.Background()); err != nil {
+		t.Fatalf("error happened when starting exporter: %v", err)
+	}
+	return exp
 }
 
 func TestConsumeAggNetMetricGroup(t *testing.T) {
@@ -69,16 +69,36 @@ type extraLabelsParam struct {
 
 func updateProtocolKey(key *extraLabelsKey, labels *model.AttributeMap) *extraLabelsKey {
 	switch labels.GetStringValue(constlabels.Protocol) {
-	case constvalues.ProtocolHttp:
+	case constvalues.ProtocolHttp, constvalues.ProtocolHttp2:
 		key.protocol = HTTP
 	case constvalues.ProtocolGrpc:
 		key.protocol = GRPC
 	case constvalues.ProtocolMysql:
 		key.protocol = MYSQL
+	case constvalues.ProtocolPostgresql:
+		key.protocol = POSTGRESQL
+	case constvalues.ProtocolMongodb:
+		key.protocol = MONGODB
+	case constvalues.ProtocolCassandra:
+		key.protocol = CASSANDRA
 	case constvalues.ProtocolDns:
 		key.protocol = DNS
 	case constvalues.ProtocolKafka:
 		key.protocol = KAFKA
+	case constvalues.ProtocolRocketmq:
+		key.protocol = ROCKETMQ
+	case constvalues.ProtocolAmqp:
+		key.protocol = AMQP
+	case constvalues.ProtocolMemcached:
+		key.protocol = MEMCACHED
+	case constvalues.ProtocolZookeeper:
+		key.protocol = ZOOKEEPER
+	case constvalues.ProtocolMqtt:
+		key.protocol = MQTT
+	case constvalues.ProtocolThrift:
+		key.protocol = THRIFT
+	case constvalues.ProtocolFastcgi:
+		key.protocol = FASTCGI
 	case constvalues.ProtocolDubbo:
 		key.protocol = DUBBO
 	default:
@@ -18,6 +18,12 @@ type NetAdapterConfig struct {
 	StoreTraceAsSpan   bool
 	StorePodDetail     bool
 	StoreExternalSrcIP bool
+	// ExtractedDimensions are the names of the labels extracted from the payloads, which are
+	// aggregated by the processor and exported with the metrics and the spans.
+	ExtractedDimensions []string
+	// ExtractedAttributes are the names of the other extracted labels, which are only exported
+	// with the spans.
+	ExtractedAttributes []string
 }
 
 func (n *NetMetricGroupAdapter) Adapt(dataGroup *model.DataGroup, attrType AttrType) ([]*AdaptedResult, error) {
@@ -133,62 +139,65 @@ type NetAdapterManager struct {
 	traceToMetricAdapter  *LabelConverter
 }
 
-func createNetAdapterManager(constLabels []attribute.KeyValue) *NetAdapterManager {
+func createNetAdapterManager(constLabels []attribute.KeyValue, config *NetAdapterConfig) *NetAdapterManager {
+	dimensionDicList := extractedDicList(config.ExtractedDimensions)
+	spanExtractedDicList := append(extractedDicList(config.ExtractedDimensions), extractedDicList(config.ExtractedAttributes)...)
+
 	// TODO deal Error
 	aggEntityAdapterWithIsSlow, _ := newAdapterBuilder(entityMetricDicList,
-		[][]dictionary{isSlowDicList}).
+		[][]dictionary{isSlowDicList, dimensionDicList}).
 		withExtraLabels(entityProtocol, updateProtocolKey).
 		withConstLabels(constLabels).
 		build()
 
 	detailEntityAdapterWithIsSlow, _ := newAdapterBuilder(entityMetricDicList,
-		[][]dictionary{entityInstanceMetricDicList, entityDetailMetricDicList, isSlowDicList}).
+		[][]dictionary{entityInstanceMetricDicList, entityDetailMetricDicList, isSlowDicList, dimensionDicList}).
 		withExtraLabels(entityProtocol, updateProtocolKey).
 		withConstLabels(constLabels).
 		build()
 
 	aggTopologyAdapterWithIsSlow, _ := newAdapterBuilder(topologyMetricDicList,
-		[][]dictionary{isSlowDicList}).
+		[][]dictionary{isSlowDicList, dimensionDicList}).
 		withExtraLabels(topologyProtocol, updateProtocolKey).
 		withAdjust(removeDstPodInfoForNonExternal()).
 		withConstLabels(constLabels).
 		build()
 
 	detailTopologyAdapterWithIsSlow, _ := newAdapterBuilder(topologyMetricDicList,
-		[][]dictionary{topologyInstanceMetricDicList, topologyDetailMetricDicList, isSlowDicList}).
+		[][]dictionary{topologyInstanceMetricDicList, topologyDetailMetricDicList, isSlowDicList, dimensionDicList}).
 		withExtraLabels(topologyProtocol, updateProtocolKey).
 		withAdjust(replaceDstIpOrDstPortByDNat()).
 		withConstLabels(constLabels).
 		build()
 
 	aggEntityAdapter, _ := newAdapterBuilder(entityMetricDicList,
-		[][]dictionary{}).
+		[][]dictionary{dimensionDicList}).
 		withExtraLabels(entityProtocol, updateProtocolKey).
 		withConstLabels(constLabels).
 		build()
 
 	detailEntityAdapter, _ := newAdapterBuilder(entityMetricDicList,
-		[][]dictionary{entityInstanceMetricDicList, entityDetailMetricDicList}).
+		[][]dictionary{entityInstanceMetricDicList, entityDetailMetricDicList, dimensionDicList}).
 		withExtraLabels(entityProtocol, updateProtocolKey).
 		withConstLabels(constLabels).
 		build()
 
 	aggTopologyAdapter, _ := newAdapterBuilder(topologyMetricDicList,
-		[][]dictionary{}).
+		[][]dictionary{dimensionDicList}).
 		withExtraLabels(topologyProtocol, updateProtocolKey).
 		withAdjust(removeDstPodInfoForNonExternal()).
 		withConstLabels(constLabels).
 		build()
 
 	detailTopologyAdapter, _ := newAdapterBuilder(topologyMetricDicList,
-		[][]dictionary{topologyInstanceMetricDicList, topologyDetailMetricDicList}).
+		[][]dictionary{topologyInstanceMetricDicList, topologyDetailMetricDicList, dimensionDicList}).
 		withExtraLabels(topologyProtocol, updateProtocolKey).
 		withAdjust(replaceDstIpOrDstPortByDNat()).
 		withConstLabels(constLabels).
 		build()
 
 	traceToSpanAdapter, _ := newAdapterBuilder(topologyMetricDicList,
-		[][]dictionary{topologyInstanceMetricDicList, SpanDicList, dNatDicList}).
+		[][]dictionary{topologyInstanceMetricDicList, SpanDicList, dNatDicList, spanExtractedDicList}).
 		withExtraLabels(spanProtocol, updateProtocolKey).
 		withValueToLabels(traceSpanStatus, getTraceSpanStatusLabels).
 		withConstLabels(constLabels).
@@ -216,7 +225,7 @@ func NewNetAdapter(
 	config *NetAdapterConfig,
 ) *NetMetricGroupAdapter {
 	return &NetMetricGroupAdapter{
-		NetAdapterManager: createNetAdapterManager(customLabels),
+		NetAdapterManager: createNetAdapterManager(customLabels, config),
 		NetAdapterConfig:  config,
 	}
 }
@@ -17,6 +17,16 @@ const (
 	MYSQL
 	GRPC
 	DUBBO
+	POSTGRESQL
+	MONGODB
+	CASSANDRA
+	ROCKETMQ
+	AMQP
+	MEMCACHED
+	ZOOKEEPER
+	MQTT
+	THRIFT
+	FASTCGI
 	UNSUPPORTED
 )
 
@@ -179,6 +189,16 @@ func replaceDstIpOrDstPortByDNat() adjustFunctions {
 	}
 }
 
+// extractedDicList returns the dictionaries of the labels extracted from the payloads, which are
+// exported with their original names.
+func extractedDicList(names []string) []dictionary {
+	dicList := make([]dictionary, 0, len(names))
+	for _, name := range names {
+		dicList = append(dicList, dictionary{name, name, String})
+	}
+	return dicList
+}
+
 var entityProtocol = []extraLabelsParam{
 	{[]dictionary{
 		{constlabels.RequestContent, constlabels.ContentKey, String},
@@ -188,13 +208,53 @@ var entityProtocol = []extraLabelsParam{
 		{constlabels.RequestContent, constlabels.KafkaTopic, String},
 		{constlabels.ResponseContent, constlabels.STR_EMPTY, StrEmpty},
 	}, extraLabelsKey{KAFKA}},
+	{[]dictionary{
+		{constlabels.RequestContent, constlabels.Topic, String},
+		{constlabels.ResponseContent, constlabels.RocketmqErrorCode, FromInt64ToString},
+	}, extraLabelsKey{ROCKETMQ}},
+	{[]dictionary{
+		{constlabels.RequestContent, constlabels.ContentKey, String},
+		{constlabels.ResponseContent, constlabels.AmqpReplyCode, FromInt64ToString},
+	}, extraLabelsKey{AMQP}},
+	{[]dictionary{
+		{constlabels.RequestContent, constlabels.ContentKey, String},
+		{constlabels.ResponseContent, constlabels.MemcachedStatus, String},
+	}, extraLabelsKey{MEMCACHED}},
+	{[]dictionary{
+		{constlabels.RequestContent, constlabels.ContentKey, String},
+		{constlabels.ResponseContent, constlabels.ZookeeperErrorCode, FromInt64ToString},
+	}, extraLabelsKey{ZOOKEEPER}},
+	{[]dictionary{
+		{constlabels.RequestContent, constlabels.ContentKey, String},
+		{constlabels.ResponseContent, constlabels.MqttReasonCode, FromInt64ToString},
+	}, extraLabelsKey{MQTT}},
+	{[]dictionary{
+		{constlabels.RequestContent, constlabels.ContentKey, String},
+		{constlabels.ResponseContent, constlabels.ThriftMessageType, String},
+	}, extraLabelsKey{THRIFT}},
+	{[]dictionary{
+		{constlabels.RequestContent, constlabels.ContentKey, String},
+		{constlabels.ResponseContent, constlabels.FastcgiStatusCode, FromInt64ToString},
+	}, extraLabelsKey{FASTCGI}},
 	{[]dictionary{
 		{constlabels.RequestContent, constlabels.ContentKey, String},
 		{constlabels.ResponseContent, constlabels.SqlErrCode, FromInt64ToString},
 	}, extraLabelsKey{MYSQL}},
 	{[]dictionary{
 		{constlabels.RequestContent, constlabels.ContentKey, String},
-		{constlabels.ResponseContent, constlabels.HttpStatusCode, FromInt64ToString},
+		{constlabels.ResponseContent, constlabels.SqlState, String},
+	}, extraLabelsKey{POSTGRESQL}},
+	{[]dictionary{
+		{constlabels.RequestContent, constlabels.ContentKey, String},
+		{constlabels.ResponseContent, constlabels.MongodbErrorCode, FromInt64ToString},
+	}, extraLabelsKey{MONGODB}},
+	{[]dictionary{
+		{constlabels.RequestContent, constlabels.ContentKey, String},
+		{constlabels.ResponseContent, constlabels.CassandraErrorCode, FromInt64ToString},
+	}, extraLabelsKey{CASSANDRA}},
+	{[]dictionary{
+		{constlabels.RequestContent, constlabels.ContentKey, String},
+		{constlabels.ResponseContent, constlabels.GrpcStatusCode, FromInt64ToString},
 	}, extraLabelsKey{GRPC}},
 	{[]dictionary{
 		{constlabels.RequestContent, constlabels.DnsDomain, String},
@@ -227,6 +287,67 @@ var spanProtocol = []extraLabelsParam{
 		{constlabels.SpanMysqlErrorCode, constlabels.SqlErrCode, Int64},
 		{constlabels.SpanMysqlErrorMsg, constlabels.SqlErrMsg, String},
 	}, extraLabelsKey{MYSQL}},
+	{[]dictionary{
+		{constlabels.SpanPostgresqlSql, constlabels.Sql, String},
+		{constlabels.SpanPostgresqlSqlState, constlabels.SqlState, String},
+		{constlabels.SpanPostgresqlErrorMsg, constlabels.SqlErrMsg, String},
+	}, extraLabelsKey{POSTGRESQL}},
+	{[]dictionary{
+		{constlabels.SpanMongodbCommand, constlabels.ContentKey, String},
+		{constlabels.SpanMongodbErrorCode, constlabels.MongodbErrorCode, Int64},
+		{constlabels.SpanMongodbErrorMsg, constlabels.MongodbErrorMsg, String},
+	}, extraLabelsKey{MONGODB}},
+	{[]dictionary{
+		{constlabels.SpanCassandraStatement, constlabels.Sql, String},
+		{constlabels.SpanCassandraErrorCode, constlabels.CassandraErrorCode, Int64},
+		{constlabels.SpanCassandraErrorMsg, constlabels.CassandraErrorMsg, String},
+	}, extraLabelsKey{CASSANDRA}},
+	{[]dictionary{
+		{constlabels.SpanRocketmqTopic, constlabels.Topic, String},
+		{constlabels.SpanRocketmqOperation, constlabels.Operation, String},
+		{constlabels.SpanRocketmqErrorCode, constlabels.RocketmqErrorCode, Int64},
+		{constlabels.SpanRocketmqErrorMsg, constlabels.RocketmqErrorMsg, String},
+	}, extraLabelsKey{ROCKETMQ}},
+	{[]dictionary{
+		{constlabels.SpanAmqpMethod, constlabels.AmqpMethod, String},
+		{constlabels.SpanAmqpExchange, constlabels.AmqpExchange, String},
+		{constlabels.SpanAmqpRoutingKey, constlabels.AmqpRoutingKey, String},
+		{constlabels.SpanAmqpQueue, constlabels.AmqpQueue, String},
+		{constlabels.SpanAmqpReplyCode, constlabels.AmqpReplyCode, Int64},
+		{constlabels.SpanAmqpReplyText, constlabels.AmqpReplyText, String},
+	}, extraLabelsKey{AMQP}},
+	{[]dictionary{
+		{constlabels.SpanMemcachedCommand, constlabels.ContentKey, String},
+		{constlabels.SpanMemcachedStatus, constlabels.MemcachedStatus, String},
+		{constlabels.SpanMemcachedErrorMsg, constlabels.MemcachedErrorMsg, String},
+	}, extraLabelsKey{MEMCACHED}},
+	{[]dictionary{
+		{constlabels.SpanZookeeperOpType, constlabels.ContentKey, String},
+		{constlabels.SpanZookeeperZxid, constlabels.ZookeeperZxid, Int64},
+		{constlabels.SpanZookeeperErrorCode, constlabels.ZookeeperErrorCode, Int64},
+		{constlabels.SpanZookeeperErrorMsg, constlabels.ZookeeperErrorMsg, String},
+	}, extraLabelsKey{ZOOKEEPER}},
+	{[]dictionary{
+		{constlabels.SpanMqttPacketType, constlabels.MqttPacketType, String},
+		{constlabels.SpanMqttTopic, constlabels.ContentKey, String},
+		{constlabels.SpanMqttQos, constlabels.MqttQos, Int64},
+		{constlabels.SpanMqttReasonCode, constlabels.MqttReasonCode, Int64},
+	}, extraLabelsKey{MQTT}},
+	{[]dictionary{
+		{constlabels.SpanThriftMethod, constlabels.ContentKey, String},
+		{constlabels.SpanThriftMessageType, constlabels.ThriftMessageType, String},
+		{constlabels.SpanThriftErrorType, constlabels.ThriftErrorType, Int64},
+		{constlabels.SpanThriftErrorMsg, constlabels.ThriftErrorMsg, String},
+	}, extraLabelsKey{THRIFT}},
+	{[]dictionary{
+		{constlabels.SpanFastcgiMethod, constlabels.FastcgiMethod, String},
+		{constlabels.SpanFastcgiRequestUri, constlabels.FastcgiRequestUri, String},
+		{constlabels.SpanFastcgiScriptFilename, constlabels.FastcgiScriptFilename, String},
+		{constlabels.SpanFastcgiStatusCode, constlabels.FastcgiStatusCode, Int64},
+		{constlabels.SpanFastcgiAppStatus, constlabels.FastcgiAppStatus, Int64},
+		{constlabels.SpanFastcgiProtocolStatus, constlabels.FastcgiProtocolStatus, Int64},
+		{constlabels.SpanFastcgiErrorMsg, constlabels.FastcgiErrorMsg, String},
+	}, extraLabelsKey{FASTCGI}},
 	{[]dictionary{
 		{constlabels.SpanDnsDomain, constlabels.DnsDomain, String},
 		{constlabels.SpanDnsRCode, constlabels.DnsRcode, FromInt64ToString},
@@ -249,11 +370,41 @@ var topologyProtocol = []extraLabelsParam{
 		{constlabels.StatusCode, constlabels.STR_EMPTY, StrEmpty},
 		//{constlabels.HttpStatusCode, constlabels.STR_EMPTY, StrEmpty},
 	}, extraLabelsKey{KAFKA}},
+	{[]dictionary{
+		{constlabels.StatusCode, constlabels.RocketmqErrorCode, FromInt64ToString},
+	}, extraLabelsKey{ROCKETMQ}},
+	{[]dictionary{
+		{constlabels.StatusCode, constlabels.AmqpReplyCode, FromInt64ToString},
+	}, extraLabelsKey{AMQP}},
+	{[]dictionary{
+		{constlabels.StatusCode, constlabels.MemcachedStatus, String},
+	}, extraLabelsKey{MEMCACHED}},
+	{[]dictionary{
+		{constlabels.StatusCode, constlabels.ZookeeperErrorCode, FromInt64ToString},
+	}, extraLabelsKey{ZOOKEEPER}},
+	{[]dictionary{
+		{constlabels.StatusCode, constlabels.MqttReasonCode, FromInt64ToString},
+	}, extraLabelsKey{MQTT}},
+	{[]dictionary{
+		{constlabels.StatusCode, constlabels.ThriftMessageType, String},
+	}, extraLabelsKey{THRIFT}},
+	{[]dictionary{
+		{constlabels.StatusCode, constlabels.FastcgiStatusCode, FromInt64ToString},
+	}, extraLabelsKey{FASTCGI}},
 	{[]dictionary{
 		{constlabels.StatusCode, constlabels.SqlErrCode, FromInt64ToString},
 	}, extraLabelsKey{MYSQL}},
 	{[]dictionary{
-		{constlabels.StatusCode, constlabels.HttpStatusCode, FromInt64ToString},
+		{constlabels.StatusCode, constlabels.SqlState, String},
+	}, extraLabelsKey{POSTGRESQL}},
+	{[]dictionary{
+		{constlabels.StatusCode, constlabels.MongodbErrorCode, FromInt64ToString},
+	}, extraLabelsKey{MONGODB}},
+	{[]dictionary{
+		{constlabels.StatusCode, constlabels.CassandraErrorCode, FromInt64ToString},
+	}, extraLabelsKey{CASSANDRA}},
+	{[]dictionary{
+		{constlabels.StatusCode, constlabels.GrpcStatusCode, FromInt64ToString},
 	}, extraLabelsKey{GRPC}},
 	{[]dictionary{
 		{constlabels.StatusCode, constlabels.DnsRcode, FromInt64ToString},
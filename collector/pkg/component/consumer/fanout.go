@@ -0,0 +1,32 @@
+package consumer
+
+import (
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/hashicorp/go-multierror"
+)
+
+// fanOutConsumer passes every dataGroup to all the consumers it holds.
+// It is used when one component has to feed more than one next consumers
+// but only accepts a single one, e.g. a processor followed by several exporters.
+type fanOutConsumer struct {
+	consumers []Consumer
+}
+
+// NewFanOutConsumer returns a Consumer that sends dataGroups to all the input consumers.
+// If there is only one consumer, it is returned directly.
+func NewFanOutConsumer(consumers ...Consumer) Consumer {
+	if len(consumers) == 1 {
+		return consumers[0]
+	}
+	return &fanOutConsumer{consumers: consumers}
+}
+
+func (f *fanOutConsumer) Consume(dataGroup *model.DataGroup) error {
+	var retErr error
+	for _, consumer := range f.consumers {
+		if err := consumer.Consume(dataGroup); err != nil {
+			retErr = multierror.Append(retErr, err)
+		}
+	}
+	return retErr
+}
@@ -1,11 +1,24 @@
 package aggregateprocessor
 
+import (
+	"fmt"
+
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+// maxExtractedDimensions limits the extracted labels aggregated as dimensions, because each of
+// them multiplies the number of the aggregated series.
+const maxExtractedDimensions = 8
+
 type Config struct {
 	// The unit is second.
 	TickerInterval int `mapstructure:"ticker_interval"`
 
 	AggregateKindMap map[string][]AggregatedKindConfig `mapstructure:"aggregate_kind_map"`
 	SamplingRate     *SampleConfig                     `mapstructure:"sampling_rate"`
+	// ExtractedDimensions are the names of the labels extracted by networkanalyzer, by which
+	// the net requests are also aggregated.
+	ExtractedDimensions []string `mapstructure:"extracted_dimensions"`
 }
 
 type AggregatedKindConfig struct {
@@ -41,3 +54,20 @@ func NewDefaultConfig() *Config {
 	}
 	return ret
 }
+
+func validateExtractedDimensions(dimensions []string) error {
+	if len(dimensions) > maxExtractedDimensions {
+		return fmt.Errorf("too many extracted_dimensions of [%s]: %d, the limit is %d", Type, len(dimensions), maxExtractedDimensions)
+	}
+	names := make(map[string]bool, len(dimensions))
+	for _, name := range dimensions {
+		if constlabels.IsReserved(name) {
+			return fmt.Errorf("extracted dimension %s of [%s] is taken by the built-in label", name, Type)
+		}
+		if names[name] {
+			return fmt.Errorf("duplicated extracted dimension of [%s]: %s", Type, name)
+		}
+		names[name] = true
+	}
+	return nil
+}
@@ -1,7 +1,11 @@
 package aggregateprocessor
 
 import (
+	"context"
+	"fmt"
 	"math/rand"
+	"reflect"
+	"sync"
 	"time"
 
 	"github.com/Kindling-project/kindling/collector/pkg/aggregator"
@@ -27,11 +31,16 @@ type AggregateProcessor struct {
 	telemetry    *component.TelemetryTools
 	nextConsumer consumer.Consumer
 
+	// mutex protects cfg, aggregator and netRequestLabelSelectors, which could be replaced
+	// when a new configuration is applied at runtime.
+	mutex                    sync.RWMutex
 	aggregator               aggregator.Aggregator
 	netRequestLabelSelectors *aggregator.LabelSelectors
 	tcpLabelSelectors        *aggregator.LabelSelectors
 	stopCh                   chan struct{}
+	tickerDone               chan struct{}
 	ticker                   *time.Ticker
+	component.HealthReporter
 }
 
 func New(config interface{}, telemetry *component.TelemetryTools, nextConsumer consumer.Consumer) processor.Processor {
@@ -42,15 +51,25 @@ func New(config interface{}, telemetry *component.TelemetryTools, nextConsumer c
 		nextConsumer: nextConsumer,
 
 		aggregator:               defaultaggregator.NewDefaultAggregator(toAggregatedConfig(cfg.AggregateKindMap)),
-		netRequestLabelSelectors: newNetRequestLabelSelectors(),
+		netRequestLabelSelectors: newNetRequestLabelSelectors(cfg.ExtractedDimensions...),
 		tcpLabelSelectors:        newTcpLabelSelectors(),
 		stopCh:                   make(chan struct{}),
+		tickerDone:               make(chan struct{}),
 		ticker:                   time.NewTicker(time.Duration(cfg.TickerInterval) * time.Second),
 	}
-	go p.runTicker()
 	return p
 }
 
+// Start starts dumping the aggregator periodically.
+func (p *AggregateProcessor) Start(ctx context.Context) error {
+	if err := validateExtractedDimensions(p.cfg.ExtractedDimensions); err != nil {
+		return err
+	}
+	go p.runTicker()
+	p.SetHealth(component.StatusRunning, nil)
+	return nil
+}
+
 func toAggregatedConfig(m map[string][]AggregatedKindConfig) *defaultaggregator.AggregatedConfig {
 	ret := &defaultaggregator.AggregatedConfig{KindMap: make(map[string][]defaultaggregator.KindConfig)}
 	for k, v := range m {
@@ -89,26 +108,92 @@ func newKindConfig(rawConfig *AggregatedKindConfig) (kindConfig defaultaggregato
 	}
 }
 
-// TODO: Graceful shutdown
 func (p *AggregateProcessor) runTicker() {
+	defer close(p.tickerDone)
 	for {
 		select {
 		case <-p.stopCh:
 			return
 		case <-p.ticker.C:
+			p.mutex.RLock()
 			aggResults := p.aggregator.Dump()
-			for _, agg := range aggResults {
-				err := p.nextConsumer.Consume(agg)
-				if err != nil {
-					p.telemetry.Logger.Warn("Error happened when consuming aggregated recordersMap",
-						zap.Error(err))
-				}
-			}
+			p.mutex.RUnlock()
+			p.consumeAggResults(aggResults)
 		}
 	}
 }
 
+func (p *AggregateProcessor) consumeAggResults(aggResults []*model.DataGroup) {
+	for _, agg := range aggResults {
+		err := p.nextConsumer.Consume(agg)
+		if err != nil {
+			p.telemetry.Logger.Warn("Error happened when consuming aggregated recordersMap",
+				zap.Error(err))
+		}
+	}
+}
+
+// Shutdown stops the ticker and dumps the aggregator one last time, so the data aggregated
+// since the last tick is sent to the next consumer instead of being lost.
+func (p *AggregateProcessor) Shutdown(ctx context.Context) error {
+	if p.Health().Status != component.StatusRunning {
+		return nil
+	}
+	defer p.SetHealth(component.StatusStopped, nil)
+	close(p.stopCh)
+	p.ticker.Stop()
+	// Wait for the dumping in progress to avoid sending the results out of order
+	select {
+	case <-p.tickerDone:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	p.mutex.RLock()
+	aggResults := p.aggregator.Dump()
+	p.mutex.RUnlock()
+	p.consumeAggResults(aggResults)
+	p.telemetry.Logger.Sugar().Infof("[%s] dumped %d aggregated data groups before shutting down", Type, len(aggResults))
+	return nil
+}
+
+// ApplyConfig applies a new configuration at runtime. If the aggregation kinds are changed,
+// the current aggregation window is dumped before the new aggregator takes over.
+func (p *AggregateProcessor) ApplyConfig(config interface{}) error {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return fmt.Errorf("cannot convert [%s] config", Type)
+	}
+	if cfg.TickerInterval <= 0 {
+		return fmt.Errorf("invalid ticker_interval of [%s]: %d", Type, cfg.TickerInterval)
+	}
+	if cfg.SamplingRate == nil {
+		return fmt.Errorf("sampling_rate of [%s] is not set", Type)
+	}
+	if err := validateExtractedDimensions(cfg.ExtractedDimensions); err != nil {
+		return err
+	}
+	var aggResults []*model.DataGroup
+	p.mutex.Lock()
+	// The keys of the aggregated data change with the extracted dimensions.
+	if !reflect.DeepEqual(cfg.AggregateKindMap, p.cfg.AggregateKindMap) ||
+		!reflect.DeepEqual(cfg.ExtractedDimensions, p.cfg.ExtractedDimensions) {
+		aggResults = p.aggregator.Dump()
+		p.aggregator = defaultaggregator.NewDefaultAggregator(toAggregatedConfig(cfg.AggregateKindMap))
+		p.netRequestLabelSelectors = newNetRequestLabelSelectors(cfg.ExtractedDimensions...)
+	}
+	if cfg.TickerInterval != p.cfg.TickerInterval {
+		p.ticker.Reset(time.Duration(cfg.TickerInterval) * time.Second)
+	}
+	p.cfg = cfg
+	p.mutex.Unlock()
+	p.consumeAggResults(aggResults)
+	p.telemetry.Logger.Sugar().Infof("[%s] applied the new configuration: %+v", Type, cfg)
+	return nil
+}
+
 func (p *AggregateProcessor) Consume(dataGroup *model.DataGroup) error {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
 	switch dataGroup.Name {
 	case constnames.NetRequestMetricGroupName:
 		var abnormalDataErr error
@@ -135,8 +220,9 @@ func (p *AggregateProcessor) Consume(dataGroup *model.DataGroup) error {
 }
 
 // TODO: make it configurable instead of hard-coded
-func newNetRequestLabelSelectors() *aggregator.LabelSelectors {
-	return aggregator.NewLabelSelectors(
+// The extracted dimensions are appended to the built-in labels.
+func newNetRequestLabelSelectors(extractedDimensions ...string) *aggregator.LabelSelectors {
+	selectors := aggregator.NewLabelSelectors(
 		aggregator.LabelSelector{Name: constlabels.Pid, VType: aggregator.IntType},
 		aggregator.LabelSelector{Name: constlabels.Comm, VType: aggregator.StringType},
 		aggregator.LabelSelector{Name: constlabels.Protocol, VType: aggregator.StringType},
@@ -170,11 +256,28 @@ func newNetRequestLabelSelectors() *aggregator.LabelSelectors {
 		aggregator.LabelSelector{Name: constlabels.IsSlow, VType: aggregator.BooleanType},
 		aggregator.LabelSelector{Name: constlabels.HttpStatusCode, VType: aggregator.IntType},
 		aggregator.LabelSelector{Name: constlabels.DnsRcode, VType: aggregator.IntType},
+		aggregator.LabelSelector{Name: constlabels.GrpcStatusCode, VType: aggregator.IntType},
 		aggregator.LabelSelector{Name: constlabels.SqlErrCode, VType: aggregator.IntType},
+		aggregator.LabelSelector{Name: constlabels.SqlState, VType: aggregator.StringType},
+		aggregator.LabelSelector{Name: constlabels.MongodbErrorCode, VType: aggregator.IntType},
+		aggregator.LabelSelector{Name: constlabels.CassandraErrorCode, VType: aggregator.IntType},
+		aggregator.LabelSelector{Name: constlabels.AmqpReplyCode, VType: aggregator.IntType},
+		aggregator.LabelSelector{Name: constlabels.MemcachedStatus, VType: aggregator.StringType},
+		aggregator.LabelSelector{Name: constlabels.ZookeeperErrorCode, VType: aggregator.IntType},
+		aggregator.LabelSelector{Name: constlabels.MqttReasonCode, VType: aggregator.IntType},
+		aggregator.LabelSelector{Name: constlabels.ThriftMessageType, VType: aggregator.StringType},
+		aggregator.LabelSelector{Name: constlabels.FastcgiStatusCode, VType: aggregator.IntType},
 		aggregator.LabelSelector{Name: constlabels.ContentKey, VType: aggregator.StringType},
 		aggregator.LabelSelector{Name: constlabels.DnsDomain, VType: aggregator.StringType},
 		aggregator.LabelSelector{Name: constlabels.KafkaTopic, VType: aggregator.StringType},
+		aggregator.LabelSelector{Name: constlabels.Topic, VType: aggregator.StringType},
+		aggregator.LabelSelector{Name: constlabels.Operation, VType: aggregator.StringType},
+		aggregator.LabelSelector{Name: constlabels.RocketmqErrorCode, VType: aggregator.IntType},
 	)
+	for _, name := range extractedDimensions {
+		selectors.AppendSelectors(aggregator.LabelSelector{Name: name, VType: aggregator.StringType})
+	}
+	return selectors
 }
 
 func newTcpLabelSelectors() *aggregator.LabelSelectors {
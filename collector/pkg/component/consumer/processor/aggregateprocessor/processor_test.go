@@ -0,0 +1,147 @@
+package aggregateprocessor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/aggregator"
+	"github.com/Kindling-project/kindling/collector/pkg/component"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constnames"
+)
+
+type recordConsumer struct {
+	dataGroups []*model.DataGroup
+}
+
+func (c *recordConsumer) Consume(dataGroup *model.DataGroup) error {
+	c.dataGroups = append(c.dataGroups, dataGroup)
+	return nil
+}
+
+func TestShutdown(t *testing.T) {
+	cfg := NewDefaultConfig()
+	// Make sure the ticker never fires during the test
+	cfg.TickerInterval = 3600
+	nextConsumer := &recordConsumer{}
+	p := New(cfg, component.NewDefaultTelemetryTools(), nextConsumer).(*AggregateProcessor)
+	if err := p.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	labels := model.NewAttributeMap()
+	labels.AddStringValue(constlabels.SrcIp, "10.0.0.1")
+	_ = p.Consume(model.NewDataGroup(constnames.TcpMetricGroupName, labels, 100, model.NewIntMetric("kindling_tcp_retransmit_total", 1)))
+	if len(nextConsumer.dataGroups) != 0 {
+		t.Fatalf("Nothing is expected to be sent before shutting down, but get %v", nextConsumer.dataGroups)
+	}
+
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(nextConsumer.dataGroups) != 1 {
+		t.Fatalf("Expected 1 aggregated data group, but get %d", len(nextConsumer.dataGroups))
+	}
+	metric, ok := nextConsumer.dataGroups[0].GetMetric("kindling_tcp_retransmit_total")
+	if !ok || metric.GetInt().Value != 1 {
+		t.Errorf("Expected kindling_tcp_retransmit_total to be 1, but get %v", metric)
+	}
+}
+
+// TestLabelSelectorsSize checks all the selectors are used as the key of the aggregation, which
+// silently drops the ones beyond the size of aggregator.LabelKeys.
+func TestLabelSelectorsSize(t *testing.T) {
+	maxSize := aggregator.NewLabelKeys().Len()
+	dimensions := make([]string, maxExtractedDimensions)
+	for i := range dimensions {
+		dimensions[i] = fmt.Sprintf("label_%d", i)
+	}
+	withExtractedLabels := newNetRequestLabelSelectors(dimensions...)
+	for name, selectors := range map[string]*aggregator.LabelSelectors{
+		"net request":                       newNetRequestLabelSelectors(),
+		"net request with extracted labels": withExtractedLabels,
+		"tcp":                               newTcpLabelSelectors(),
+		"tcp connect":                       newTcpConnectLabelSelectors(),
+	} {
+		if selectors.Len() > maxSize {
+			t.Errorf("%d %s selectors are more than %d", selectors.Len(), name, maxSize)
+		}
+	}
+}
+
+func TestConsume_ExtractedDimensions(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.TickerInterval = 3600
+	cfg.SamplingRate.SlowData = 0
+	cfg.SamplingRate.ErrorData = 0
+	cfg.ExtractedDimensions = []string{"tenant"}
+	nextConsumer := &recordConsumer{}
+	p := New(cfg, component.NewDefaultTelemetryTools(), nextConsumer).(*AggregateProcessor)
+	if err := p.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, tenant := range []string{"a", "b", "a"} {
+		labels := model.NewAttributeMap()
+		labels.AddStringValue(constlabels.ContentKey, "/api")
+		labels.AddStringValue("tenant", tenant)
+		labels.AddStringValue("user_agent", "curl/"+tenant)
+		_ = p.Consume(model.NewDataGroup(constnames.NetRequestMetricGroupName, labels, 100, model.NewIntMetric("request_io", 1)))
+	}
+
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	requests := map[string]int64{}
+	for _, dataGroup := range nextConsumer.dataGroups {
+		if dataGroup.Labels.HasAttribute("user_agent") {
+			t.Errorf("the attributes should not be aggregated: %v", dataGroup.Labels)
+		}
+		metric, _ := dataGroup.GetMetric("request_io")
+		requests[dataGroup.Labels.GetStringValue("tenant")] = metric.GetInt().Value
+	}
+	if len(requests) != 2 || requests["a"] != 2 || requests["b"] != 1 {
+		t.Errorf("requests by tenant = %v, want map[a:2 b:1]", requests)
+	}
+}
+
+func TestApplyConfig_ExtractedDimensions(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.TickerInterval = 3600
+	p := New(cfg, component.NewDefaultTelemetryTools(), &recordConsumer{}).(*AggregateProcessor)
+	if err := p.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Shutdown(context.Background())
+
+	newConfig := NewDefaultConfig()
+	newConfig.TickerInterval = 3600
+	newConfig.ExtractedDimensions = []string{"tenant"}
+	if err := p.ApplyConfig(newConfig); err != nil {
+		t.Fatalf("Failed to apply config: %v", err)
+	}
+	if got := p.netRequestLabelSelectors.Len(); got != newNetRequestLabelSelectors().Len()+1 {
+		t.Errorf("Expected the selectors with the extracted dimension, but get %d selectors", got)
+	}
+
+	tooManyDimensions := make([]string, maxExtractedDimensions+1)
+	for i := range tooManyDimensions {
+		tooManyDimensions[i] = fmt.Sprintf("label_%d", i)
+	}
+	for name, dimensions := range map[string][]string{
+		"too many dimensions": tooManyDimensions,
+		"built-in label":      {constlabels.DstIp},
+		"duplicated name":     {"tenant", "tenant"},
+	} {
+		invalidConfig := NewDefaultConfig()
+		invalidConfig.TickerInterval = 3600
+		invalidConfig.ExtractedDimensions = dimensions
+		if err := p.ApplyConfig(invalidConfig); err == nil {
+			t.Errorf("[%s] Expected an error, but get nil", name)
+		}
+	}
+	if p.cfg != newConfig {
+		t.Errorf("The configuration should not be changed when an error happens")
+	}
+}
@@ -22,3 +22,10 @@ var DefaultConfig Config = Config{
 	GraceDeletePeriod: 60,
 	Enable:            true,
 }
+
+// NewDefaultConfig returns a copy of DefaultConfig, so the configurations constructed
+// for different factories don't share the same instance.
+func NewDefaultConfig() *Config {
+	config := DefaultConfig
+	return &config
+}
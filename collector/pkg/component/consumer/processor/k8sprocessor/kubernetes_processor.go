@@ -1,6 +1,8 @@
 package k8sprocessor
 
 import (
+	"context"
+	"fmt"
 	"strconv"
 
 	"github.com/Kindling-project/kindling/collector/pkg/component"
@@ -25,6 +27,7 @@ type K8sMetadataProcessor struct {
 	localNodeIp   string
 	localNodeName string
 	telemetry     *component.TelemetryTools
+	component.HealthReporter
 }
 
 func NewKubernetesProcessor(cfg interface{}, telemetry *component.TelemetryTools, nextConsumer consumer.Consumer) processor.Processor {
@@ -33,7 +36,6 @@ func NewKubernetesProcessor(cfg interface{}, telemetry *component.TelemetryTools
 		telemetry.Logger.Panic("Cannot convert Component config", zap.String("componentType", K8sMetadata))
 	}
 	if !config.Enable {
-		telemetry.Logger.Info("The kubernetes processor is disabled by the configuration. Won't connect to the API-server and no Kubernetes metadata will be fetched.")
 		return &K8sMetadataProcessor{
 			config:       config,
 			metadata:     kubernetes.MetaDataCache,
@@ -41,17 +43,9 @@ func NewKubernetesProcessor(cfg interface{}, telemetry *component.TelemetryTools
 			telemetry:    telemetry,
 		}
 	}
-	var options []kubernetes.Option
-	options = append(options, kubernetes.WithAuthType(config.KubeAuthType))
-	options = append(options, kubernetes.WithKubeConfigDir(config.KubeConfigDir))
-	options = append(options, kubernetes.WithGraceDeletePeriod(config.GraceDeletePeriod))
-	err := kubernetes.InitK8sHandler(options...)
-	if err != nil {
-		telemetry.Logger.Sugar().Panicf("Failed to initialize [%s]: %v. Set the option 'enable' false if you want to run the agent in the non-Kubernetes environment.", K8sMetadata, err)
-		return nil
-	}
 
 	var localNodeIp, localNodeName string
+	var err error
 	if localNodeIp, err = getHostIpFromEnv(); err != nil {
 		telemetry.Logger.Warn("Local NodeIp can not found", zap.Error(err))
 	}
@@ -68,6 +62,34 @@ func NewKubernetesProcessor(cfg interface{}, telemetry *component.TelemetryTools
 	}
 }
 
+// Start connects to the API-server and starts watching the Kubernetes metadata.
+func (p *K8sMetadataProcessor) Start(ctx context.Context) error {
+	if !p.config.Enable {
+		p.telemetry.Logger.Info("The kubernetes processor is disabled by the configuration. Won't connect to the API-server and no Kubernetes metadata will be fetched.")
+		p.SetHealth(component.StatusRunning, nil)
+		return nil
+	}
+	var options []kubernetes.Option
+	options = append(options, kubernetes.WithAuthType(p.config.KubeAuthType))
+	options = append(options, kubernetes.WithKubeConfigDir(p.config.KubeConfigDir))
+	options = append(options, kubernetes.WithGraceDeletePeriod(p.config.GraceDeletePeriod))
+	err := kubernetes.InitK8sHandler(options...)
+	if err != nil {
+		err = fmt.Errorf("failed to initialize [%s]: %w. Set the option 'enable' false if you want to run the agent in the non-Kubernetes environment", K8sMetadata, err)
+		p.SetHealth(component.StatusFailed, err)
+		return err
+	}
+	p.SetHealth(component.StatusRunning, nil)
+	return nil
+}
+
+// Shutdown does nothing but updates the status, because the metadata cache is shared
+// and lives as long as the agent.
+func (p *K8sMetadataProcessor) Shutdown(ctx context.Context) error {
+	p.SetHealth(component.StatusStopped, nil)
+	return nil
+}
+
 func (p *K8sMetadataProcessor) Consume(dataGroup *model.DataGroup) error {
 	if !p.config.Enable {
 		return p.nextConsumer.Consume(dataGroup)
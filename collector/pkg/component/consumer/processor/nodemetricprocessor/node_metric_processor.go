@@ -1,6 +1,7 @@
 package nodemetricprocessor
 
 import (
+	"context"
 	"time"
 
 	"github.com/Kindling-project/kindling/collector/pkg/component"
@@ -24,6 +25,7 @@ type NodeMetricProcessor struct {
 	nextConsumer consumer.Consumer
 
 	telemetry *component.TelemetryTools
+	component.HealthReporter
 }
 
 func New(config interface{}, telemetry *component.TelemetryTools, nextConsumer consumer.Consumer) processor.Processor {
@@ -38,6 +40,16 @@ func New(config interface{}, telemetry *component.TelemetryTools, nextConsumer c
 	}
 }
 
+func (p *NodeMetricProcessor) Start(ctx context.Context) error {
+	p.SetHealth(component.StatusRunning, nil)
+	return nil
+}
+
+func (p *NodeMetricProcessor) Shutdown(ctx context.Context) error {
+	p.SetHealth(component.StatusStopped, nil)
+	return nil
+}
+
 func (p *NodeMetricProcessor) Consume(dataGroup *model.DataGroup) error {
 	labels := dataGroup.Labels
 	// Filter the data which labels is nil
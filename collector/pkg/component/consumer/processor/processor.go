@@ -1,9 +1,11 @@
 package processor
 
 import (
+	"github.com/Kindling-project/kindling/collector/pkg/component"
 	"github.com/Kindling-project/kindling/collector/pkg/component/consumer"
 )
 
 type Processor interface {
 	consumer.Consumer
+	component.Component
 }
@@ -0,0 +1,71 @@
+package component
+
+import (
+	"context"
+	"sync"
+)
+
+// Component is the lifecycle shared by all the components in the pipeline. The application
+// starts the components against the direction of the data flow, so a component is always
+// started after the ones it sends data to. They are shut down in the opposite order.
+type Component interface {
+	// Start starts the background work of the component. Failures should be returned instead
+	// of panicking, so the application could stop the started components and exit.
+	Start(ctx context.Context) error
+	// Shutdown stops the component and flushes its pending data. It should give up and
+	// return the error of ctx once ctx is done.
+	Shutdown(ctx context.Context) error
+	// Health returns the current status of the component.
+	Health() Health
+}
+
+// Status is the running status of a component.
+type Status int
+
+const (
+	StatusNotStarted Status = iota
+	StatusRunning
+	StatusFailed
+	StatusStopped
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusNotStarted:
+		return "not_started"
+	case StatusRunning:
+		return "running"
+	case StatusFailed:
+		return "failed"
+	case StatusStopped:
+		return "stopped"
+	default:
+		return "unknown"
+	}
+}
+
+// Health is the status of a component and the error causing it, if any.
+type Health struct {
+	Status Status
+	Err    error
+}
+
+// HealthReporter records the health of a component. It is expected to be embedded into the
+// components to implement the Health method of Component.
+type HealthReporter struct {
+	mutex  sync.RWMutex
+	health Health
+}
+
+func (r *HealthReporter) Health() Health {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.health
+}
+
+// SetHealth updates the health of the component. err could be nil.
+func (r *HealthReporter) SetHealth(status Status, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.health = Health{Status: status, Err: err}
+}
@@ -10,6 +10,10 @@ package cgoreceiver
 */
 import "C"
 import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
 	"sync"
 	"time"
 	"unsafe"
@@ -17,25 +21,31 @@ import (
 	"github.com/Kindling-project/kindling/collector/pkg/component"
 	analyzerpackage "github.com/Kindling-project/kindling/collector/pkg/component/analyzer"
 	"github.com/Kindling-project/kindling/collector/pkg/component/receiver"
+	"github.com/Kindling-project/kindling/collector/pkg/eventqueue"
+	"github.com/Kindling-project/kindling/collector/pkg/eventrecord"
 	"github.com/Kindling-project/kindling/collector/pkg/model"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-const (
-	Cgo = "cgoreceiver"
-)
-
 type CKindlingEventForGo C.struct_kindling_event_t_for_go
 
 type CgoReceiver struct {
 	cfg             *Config
+	cfgMutex        sync.Mutex
 	analyzerManager *analyzerpackage.Manager
-	shutdownWG      sync.WaitGroup
-	telemetry       *component.TelemetryTools
-	eventChannel    chan *model.KindlingEvent
-	stopCh          chan interface{}
-	stats           eventCounter
+	// getEventWG waits for the goroutine polling events from the probe, and
+	// consumeWG waits for the goroutine sending events to the analyzers.
+	getEventWG sync.WaitGroup
+	consumeWG  sync.WaitGroup
+	telemetry  *component.TelemetryTools
+	// queue buffers the events polled from the probe until they are sent to the analyzers.
+	queue  *eventqueue.Queue
+	stopCh chan interface{}
+	stats  eventCounter
+	// recorder is nil if recording is not enabled.
+	recorder *eventrecord.Writer
+	component.HealthReporter
 }
 
 func NewCgoReceiver(config interface{}, telemetry *component.TelemetryTools, analyzerManager *analyzerpackage.Manager) receiver.Receiver {
@@ -47,68 +57,116 @@ func NewCgoReceiver(config interface{}, telemetry *component.TelemetryTools, ana
 		cfg:             cfg,
 		analyzerManager: analyzerManager,
 		telemetry:       telemetry,
-		eventChannel:    make(chan *model.KindlingEvent, 3e5),
 		stopCh:          make(chan interface{}, 1),
 	}
 	cgoReceiver.stats = newDynamicStats(cfg.SubscribeInfo)
-	newSelfMetrics(telemetry.MeterProvider, cgoReceiver)
 	return cgoReceiver
 }
 
-func (r *CgoReceiver) Start() error {
+func (r *CgoReceiver) Start(ctx context.Context) error {
 	r.telemetry.Logger.Info("Start CgoReceiver")
+	// The queue is built after its configuration is validated, because an invalid size panics.
+	if err := r.cfg.Queue.Validate(); err != nil {
+		return err
+	}
+	r.queue = eventqueue.New(r.cfg.Queue)
+	newSelfMetrics(r.telemetry.MeterProvider, r)
+	if r.cfg.Record.Enabled {
+		recorder, err := eventrecord.NewWriter(&r.cfg.Record)
+		if err != nil {
+			return fmt.Errorf("failed to record events: %w", err)
+		}
+		r.recorder = recorder
+		r.telemetry.Logger.Sugar().Infof("Record the events into %s", r.cfg.Record.Path)
+	}
 	C.runForGo()
 	time.Sleep(2 * time.Second)
 	r.subEvent()
 	// Wait for the C routine running
 	time.Sleep(2 * time.Second)
-	go r.consumeEvents()
+	r.consumeWG.Add(1)
+	go r.consumeEvents(analyzerpackage.NewDispatcher(r.analyzerManager, r.cfg.Workers, r.telemetry.Logger))
+	r.getEventWG.Add(1)
 	go r.startGetEvent()
+	r.SetHealth(component.StatusRunning, nil)
 	return nil
 }
 
 func (r *CgoReceiver) startGetEvent() {
+	defer r.getEventWG.Done()
+	// This goroutine is the only producer, so the queue is closed here to
+	// tell consumeEvents there are no more events to drain.
+	defer r.queue.Close()
 	var pKindlingEvent unsafe.Pointer
-	r.shutdownWG.Add(1)
 	for {
 		select {
 		case <-r.stopCh:
-			r.shutdownWG.Done()
 			return
 		default:
 			res := int(C.getKindlingEvent(&pKindlingEvent))
 			if res == 1 {
 				event := convertEvent((*CKindlingEventForGo)(pKindlingEvent))
-				r.eventChannel <- event
 				r.stats.add(event.Name, 1)
+				r.queue.Push(event)
 			}
 		}
 	}
 }
 
-func (r *CgoReceiver) consumeEvents() {
-	r.shutdownWG.Add(1)
-	for {
-		select {
-		case <-r.stopCh:
-			r.shutdownWG.Done()
-			return
-		case ev := <-r.eventChannel:
-			err := r.sendToNextConsumer(ev)
-			if err != nil {
-				r.telemetry.Logger.Info("Failed to send KindlingEvent: ", zap.Error(err))
+func (r *CgoReceiver) consumeEvents(dispatcher *analyzerpackage.Dispatcher) {
+	defer r.consumeWG.Done()
+	// Wait for the workers, so all the events are analyzed when Shutdown returns.
+	defer dispatcher.Close()
+	for ev := range r.queue.Events() {
+		// Record the event before the analyzers could modify it.
+		if r.recorder != nil {
+			if err := r.recorder.Write(ev); err != nil {
+				r.telemetry.Logger.Warn("Failed to record KindlingEvent: ", zap.Error(err))
 			}
 		}
+		err := r.sendToNextConsumer(dispatcher, ev)
+		if err != nil {
+			r.telemetry.Logger.Info("Failed to send KindlingEvent: ", zap.Error(err))
+		}
 	}
 }
 
-func (r *CgoReceiver) Shutdown() error {
-	// TODO stop the C routine
+// Shutdown stops polling events from the probe, closes the probe, and then
+// waits until all the events left in the channel are sent to the analyzers.
+func (r *CgoReceiver) Shutdown(ctx context.Context) error {
+	if r.Health().Status != component.StatusRunning {
+		return nil
+	}
+	defer r.SetHealth(component.StatusStopped, nil)
 	close(r.stopCh)
-	r.shutdownWG.Wait()
+	if err := waitWithContext(ctx, &r.getEventWG); err != nil {
+		return err
+	}
+	C.stopForGo()
+	r.telemetry.Logger.Sugar().Infof("The probe is stopped, and %d events left are being drained", r.queue.Len())
+	if err := waitWithContext(ctx, &r.consumeWG); err != nil {
+		return err
+	}
+	if r.recorder != nil {
+		return r.recorder.Close()
+	}
 	return nil
 }
 
+func waitWithContext(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func convertEvent(cgoEvent *CKindlingEventForGo) *model.KindlingEvent {
 	ev := new(model.KindlingEvent)
 	ev.Timestamp = uint64(cgoEvent.timestamp)
@@ -150,7 +208,7 @@ func If(condition bool, trueVal, falseVal interface{}) interface{} {
 	return falseVal
 }
 
-func (r *CgoReceiver) sendToNextConsumer(evt *model.KindlingEvent) error {
+func (r *CgoReceiver) sendToNextConsumer(dispatcher *analyzerpackage.Dispatcher, evt *model.KindlingEvent) error {
 	if ce := r.telemetry.Logger.Check(zapcore.DebugLevel, "Receive Event"); ce != nil {
 		ce.Write(
 			zap.String("event", evt.String()),
@@ -161,12 +219,7 @@ func (r *CgoReceiver) sendToNextConsumer(evt *model.KindlingEvent) error {
 		r.telemetry.Logger.Info("analyzer not found for event ", zap.String("eventName", evt.Name))
 		return nil
 	}
-	for _, analyzer := range analyzers {
-		err := analyzer.ConsumeEvent(evt)
-		if err != nil {
-			r.telemetry.Logger.Warn("Error sending event to next consumer: ", zap.Error(err))
-		}
-	}
+	dispatcher.Dispatch(evt)
 	return nil
 }
 
@@ -176,7 +229,55 @@ func (r *CgoReceiver) subEvent() {
 	} else {
 		r.telemetry.Logger.Sugar().Infof("The subscribed events are: %v", r.cfg.SubscribeInfo)
 	}
-	for _, value := range r.cfg.SubscribeInfo {
+	r.subscribe(r.cfg.SubscribeInfo)
+}
+
+func (r *CgoReceiver) subscribe(events []SubEvent) {
+	for _, value := range events {
 		C.subEventForGo(C.CString(value.Name), C.CString(value.Category))
 	}
 }
+
+// ApplyConfig subscribes the events newly added to the configuration at runtime.
+// The probe doesn't support unsubscribing events now, so removing events is rejected.
+// The newly subscribed events are counted as "other" in the self metrics until the agent restarts.
+func (r *CgoReceiver) ApplyConfig(config interface{}) error {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return fmt.Errorf("cannot convert [%s] config", Cgo)
+	}
+	r.cfgMutex.Lock()
+	defer r.cfgMutex.Unlock()
+	if !reflect.DeepEqual(cfg.Record, r.cfg.Record) {
+		return errors.New("the record settings can't be changed at runtime, please restart the agent to apply it")
+	}
+	if !reflect.DeepEqual(cfg.Queue, r.cfg.Queue) {
+		return errors.New("the queue settings can't be changed at runtime, please restart the agent to apply it")
+	}
+	if cfg.Workers != r.cfg.Workers {
+		return errors.New("the number of workers can't be changed at runtime, please restart the agent to apply it")
+	}
+	newEvents := make(map[SubEvent]bool, len(cfg.SubscribeInfo))
+	for _, event := range cfg.SubscribeInfo {
+		newEvents[event] = true
+	}
+	oldEvents := make(map[SubEvent]bool, len(r.cfg.SubscribeInfo))
+	for _, event := range r.cfg.SubscribeInfo {
+		if !newEvents[event] {
+			return fmt.Errorf("event [%s] can't be unsubscribed at runtime, please restart the agent to apply it", event.Name)
+		}
+		oldEvents[event] = true
+	}
+	addedEvents := make([]SubEvent, 0)
+	for _, event := range cfg.SubscribeInfo {
+		if !oldEvents[event] {
+			addedEvents = append(addedEvents, event)
+		}
+	}
+	if len(addedEvents) > 0 {
+		r.telemetry.Logger.Sugar().Infof("Subscribe new events: %v", addedEvents)
+		r.subscribe(addedEvents)
+	}
+	r.cfg = cfg
+	return nil
+}
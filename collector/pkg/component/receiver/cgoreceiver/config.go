@@ -1,7 +1,30 @@
 package cgoreceiver
 
+import (
+	"github.com/Kindling-project/kindling/collector/pkg/eventqueue"
+	"github.com/Kindling-project/kindling/collector/pkg/eventrecord"
+)
+
+const (
+	Cgo = "cgoreceiver"
+)
+
 type Config struct {
 	SubscribeInfo []SubEvent `mapstructure:"subscribe"`
+	// Record writes all the received events into files, which could be replayed by filereceiver.
+	Record eventrecord.Config `mapstructure:"record"`
+	// Queue buffers the events between the probe and the analyzers.
+	Queue eventqueue.Config `mapstructure:"queue"`
+	// Workers is the number of the goroutines analyzing the events in parallel. The events of a
+	// socket are always analyzed by the same goroutine, so they keep their order.
+	Workers int `mapstructure:"workers"`
+}
+
+func NewDefaultConfig() *Config {
+	return &Config{
+		Queue:   eventqueue.NewDefaultConfig(),
+		Workers: 1,
+	}
 }
 
 type SubEvent struct {
@@ -1,3 +1,6 @@
+//go:build cgo
+// +build cgo
+
 package cgoreceiver
 
 import (
@@ -6,6 +9,7 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/Kindling-project/kindling/collector/pkg/eventqueue"
 	"github.com/Kindling-project/kindling/collector/pkg/model/constnames"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -15,7 +19,9 @@ var once sync.Once
 
 const (
 	eventReceivedMetric = "kindling_telemetry_cgoreceiver_events_total"
-	channelSizeMetric   = "kindling_telemetry_cgoreceiver_channel_size"
+	// queueMetricPrefix is the prefix of the metrics of the queue, including
+	// kindling_telemetry_cgoreceiver_channel_size which was the only one before.
+	queueMetricPrefix = "kindling_telemetry_cgoreceiver"
 )
 
 func newSelfMetrics(meterProvider metric.MeterProvider, receiver *CgoReceiver) {
@@ -27,10 +33,7 @@ func newSelfMetrics(meterProvider metric.MeterProvider, receiver *CgoReceiver) {
 					result.Observe(value, attribute.String("name", name))
 				}
 			})
-		meter.NewInt64GaugeObserver(channelSizeMetric,
-			func(ctx context.Context, result metric.Int64ObserverResult) {
-				result.Observe(int64(len(receiver.eventChannel)))
-			})
+		eventqueue.RegisterSelfMetrics(meter, queueMetricPrefix, receiver.queue)
 	})
 }
 
@@ -0,0 +1,22 @@
+package filereceiver
+
+const (
+	// ReplayModeRealtime replays the events at the intervals they were recorded.
+	ReplayModeRealtime = "realtime"
+	// ReplayModeFast replays the events as fast as possible.
+	ReplayModeFast = "fast"
+)
+
+type Config struct {
+	// Files is the glob pattern of the files recorded by cgoreceiver. The matched files are
+	// replayed in lexical order, which is the order they were written in.
+	Files string `mapstructure:"files"`
+	// ReplayMode is either "realtime" or "fast".
+	ReplayMode string `mapstructure:"replay_mode"`
+}
+
+func NewDefaultConfig() *Config {
+	return &Config{
+		ReplayMode: ReplayModeFast,
+	}
+}
@@ -0,0 +1,170 @@
+package filereceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component"
+	analyzerpackage "github.com/Kindling-project/kindling/collector/pkg/component/analyzer"
+	"github.com/Kindling-project/kindling/collector/pkg/component/receiver"
+	"github.com/Kindling-project/kindling/collector/pkg/eventrecord"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"go.uber.org/zap"
+)
+
+const (
+	File = "filereceiver"
+)
+
+var errStopped = errors.New("the replay is stopped")
+
+// FileReceiver replays the events recorded by cgoreceiver through the analyzers, so that the
+// analyzers and the protocol parsers could be debugged without the probe.
+type FileReceiver struct {
+	cfg             *Config
+	analyzerManager *analyzerpackage.Manager
+	telemetry       *component.TelemetryTools
+	files           []string
+	stopCh          chan struct{}
+	doneCh          chan struct{}
+	component.HealthReporter
+}
+
+func NewFileReceiver(config interface{}, telemetry *component.TelemetryTools, analyzerManager *analyzerpackage.Manager) receiver.Receiver {
+	cfg, ok := config.(*Config)
+	if !ok {
+		telemetry.Logger.Sugar().Panicf("Cannot convert [%s] config", File)
+	}
+	return &FileReceiver{
+		cfg:             cfg,
+		analyzerManager: analyzerManager,
+		telemetry:       telemetry,
+		stopCh:          make(chan struct{}),
+		doneCh:          make(chan struct{}),
+	}
+}
+
+func (r *FileReceiver) Start(ctx context.Context) error {
+	if r.cfg.ReplayMode != ReplayModeRealtime && r.cfg.ReplayMode != ReplayModeFast {
+		return fmt.Errorf("unknown replay mode [%s], must be %s or %s", r.cfg.ReplayMode, ReplayModeRealtime, ReplayModeFast)
+	}
+	files, err := eventrecord.ListFiles(r.cfg.Files)
+	if err != nil {
+		return fmt.Errorf("invalid files pattern [%s]: %w", r.cfg.Files, err)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no files match the pattern [%s]", r.cfg.Files)
+	}
+	r.files = files
+	r.telemetry.Logger.Sugar().Infof("Start FileReceiver to replay %d files in %s mode: %v", len(files), r.cfg.ReplayMode, files)
+	go r.replay()
+	r.SetHealth(component.StatusRunning, nil)
+	return nil
+}
+
+// Shutdown stops replaying the events. The events not replayed yet are discarded.
+func (r *FileReceiver) Shutdown(ctx context.Context) error {
+	if r.Health().Status != component.StatusRunning {
+		return nil
+	}
+	defer r.SetHealth(component.StatusStopped, nil)
+	close(r.stopCh)
+	select {
+	case <-r.doneCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *FileReceiver) replay() {
+	defer close(r.doneCh)
+	r.telemetry.Logger.Info("Replay started")
+	var replayed int
+	// The wall time and the timestamp of the first event, used to keep the original intervals
+	// in realtime mode.
+	var startTime time.Time
+	var startTimestamp uint64
+	for _, file := range r.files {
+		count, err := r.replayFile(file, func(ev *model.KindlingEvent) error {
+			if r.cfg.ReplayMode == ReplayModeRealtime {
+				if startTime.IsZero() {
+					startTime = time.Now()
+					startTimestamp = ev.Timestamp
+				} else if ev.Timestamp > startTimestamp {
+					if !r.sleepUntil(startTime.Add(time.Duration(ev.Timestamp - startTimestamp))) {
+						return errStopped
+					}
+				}
+			}
+			return r.sendToNextConsumer(ev)
+		})
+		replayed += count
+		if err == errStopped {
+			r.telemetry.Logger.Sugar().Infof("Replay stopped after %d events", replayed)
+			return
+		}
+		if err != nil {
+			r.telemetry.Logger.Warn("Error happened while replaying file "+file, zap.Error(err))
+		}
+	}
+	r.telemetry.Logger.Sugar().Infof("Replay finished, %d events are sent to the analyzers", replayed)
+}
+
+// replayFile reads the events from the file and calls send for each of them. The number of the
+// events read is returned.
+func (r *FileReceiver) replayFile(file string, send func(ev *model.KindlingEvent) error) (int, error) {
+	reader, err := eventrecord.OpenFile(file)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	count := 0
+	for {
+		select {
+		case <-r.stopCh:
+			return count, errStopped
+		default:
+		}
+		ev, err := reader.Read()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+		count++
+		if err = send(ev); err != nil {
+			return count, err
+		}
+	}
+}
+
+// sleepUntil returns false if the receiver is stopped before the time.
+func (r *FileReceiver) sleepUntil(t time.Time) bool {
+	d := time.Until(t)
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-r.stopCh:
+		return false
+	}
+}
+
+func (r *FileReceiver) sendToNextConsumer(evt *model.KindlingEvent) error {
+	for _, analyzer := range r.analyzerManager.GetConsumableAnalyzers(evt.Name) {
+		err := analyzer.ConsumeEvent(evt)
+		if err != nil {
+			r.telemetry.Logger.Warn("Error sending event to next consumer: ", zap.Error(err))
+		}
+	}
+	return nil
+}
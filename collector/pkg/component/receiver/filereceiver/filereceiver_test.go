@@ -0,0 +1,109 @@
+package filereceiver
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer"
+	"github.com/Kindling-project/kindling/collector/pkg/eventrecord"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+)
+
+type recordAnalyzer struct {
+	mutex  sync.Mutex
+	events []*model.KindlingEvent
+	component.HealthReporter
+}
+
+func (a *recordAnalyzer) Start(ctx context.Context) error    { return nil }
+func (a *recordAnalyzer) Shutdown(ctx context.Context) error { return nil }
+func (a *recordAnalyzer) Type() analyzer.Type                { return "recordanalyzer" }
+func (a *recordAnalyzer) ConsumableEvents() []string         { return []string{"read"} }
+
+func (a *recordAnalyzer) ConsumeEvent(event *model.KindlingEvent) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.events = append(a.events, event)
+	return nil
+}
+
+func writeTestFile(t *testing.T, path string, timestamps ...uint64) {
+	writer, err := eventrecord.NewWriter(&eventrecord.Config{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, timestamp := range timestamps {
+		ev := &model.KindlingEvent{Name: "read", Timestamp: timestamp, ParamsNumber: 1}
+		ev.UserAttributes[0] = model.KeyValue{Key: "data", ValueType: model.ValueType_BYTEBUF, Value: []byte("PING\r\n")}
+		if err = writer.Write(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func replayTestFiles(t *testing.T, cfg *Config) (*recordAnalyzer, time.Duration) {
+	recorder := &recordAnalyzer{}
+	manager, err := analyzer.NewManager(recorder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewFileReceiver(cfg, component.NewDefaultTelemetryTools(), manager).(*FileReceiver)
+	start := time.Now()
+	if err = r.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	<-r.doneCh
+	elapsed := time.Since(start)
+	if err = r.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return recorder, elapsed
+}
+
+func TestReplay(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "events-2022-07-01T08-00-00.000.kev"), 1, 2)
+	writeTestFile(t, filepath.Join(dir, "events.kev"), 3)
+
+	recorder, _ := replayTestFiles(t, &Config{Files: filepath.Join(dir, "events*.kev"), ReplayMode: ReplayModeFast})
+	if len(recorder.events) != 3 {
+		t.Fatalf("Expected 3 events, but get %d", len(recorder.events))
+	}
+	for i, ev := range recorder.events {
+		if ev.Timestamp != uint64(i+1) {
+			t.Errorf("Expected the events in the recorded order, but get timestamp %d at %d", ev.Timestamp, i)
+		}
+		if string(ev.GetData()) != "PING\r\n" {
+			t.Errorf("Unexpected data %q", ev.GetData())
+		}
+	}
+}
+
+func TestReplayRealtime(t *testing.T) {
+	dir := t.TempDir()
+	interval := 100 * time.Millisecond
+	writeTestFile(t, filepath.Join(dir, "events.kev"), 0, uint64(interval))
+
+	recorder, elapsed := replayTestFiles(t, &Config{Files: filepath.Join(dir, "events*.kev"), ReplayMode: ReplayModeRealtime})
+	if len(recorder.events) != 2 {
+		t.Fatalf("Expected 2 events, but get %d", len(recorder.events))
+	}
+	if elapsed < interval {
+		t.Errorf("Expected the events replayed at the original interval %v, but it takes %v", interval, elapsed)
+	}
+}
+
+func TestStartWithoutFiles(t *testing.T) {
+	manager, _ := analyzer.NewManager(&recordAnalyzer{})
+	r := NewFileReceiver(&Config{Files: filepath.Join(t.TempDir(), "*.kev"), ReplayMode: ReplayModeFast}, component.NewDefaultTelemetryTools(), manager)
+	if err := r.Start(context.Background()); err == nil {
+		t.Fatal("Expected an error when no files match the pattern")
+	}
+}
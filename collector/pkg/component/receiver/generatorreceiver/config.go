@@ -0,0 +1,96 @@
+package generatorreceiver
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+const (
+	RoleServer = "server"
+	RoleClient = "client"
+)
+
+type Config struct {
+	// Duration is how long to generate events. 0 means generating until the agent stops.
+	Duration time.Duration `mapstructure:"duration"`
+	// Seed makes the generated events reproducible. A random seed is used if it is 0.
+	Seed      int64             `mapstructure:"seed"`
+	Workloads []*WorkloadConfig `mapstructure:"workloads"`
+}
+
+// WorkloadConfig describes the request/response pairs of a protocol between a client and a server.
+type WorkloadConfig struct {
+	// Protocol is one of http, mysql, redis, kafka and dns.
+	Protocol string `mapstructure:"protocol"`
+	// Rate is the number of request/response pairs generated per second.
+	Rate int `mapstructure:"rate"`
+	// Role is the side the events are observed on, either server or client.
+	Role string `mapstructure:"role"`
+	// ServerAddress is "ip:port" of the server. The port is the protocol's default one if it is absent.
+	ServerAddress string `mapstructure:"server_address"`
+	ClientIp      string `mapstructure:"client_ip"`
+	ContainerId   string `mapstructure:"container_id"`
+	// Connections is the number of the connections the pairs are sent over in turn.
+	Connections int `mapstructure:"connections"`
+	// RequestsPerConnection is the number of the pairs sent over a connection before it is
+	// replaced with a new one. 0 means the connections are never replaced.
+	RequestsPerConnection int `mapstructure:"requests_per_connection"`
+	// Latency is the time between a request and its response, randomized by LatencyJitter in
+	// both directions.
+	Latency       time.Duration `mapstructure:"latency"`
+	LatencyJitter time.Duration `mapstructure:"latency_jitter"`
+	// ErrorRatio is the ratio of the error responses, from 0 to 1.
+	ErrorRatio float64 `mapstructure:"error_ratio"`
+	// Template is the URL of HTTP, the SQL of MySQL, the key of Redis, the topic of Kafka, or the
+	// domain of DNS. "{{id}}" in it is replaced with a random number less than Cardinality.
+	Template    string `mapstructure:"template"`
+	Cardinality int    `mapstructure:"cardinality"`
+}
+
+func NewDefaultConfig() *Config {
+	return &Config{}
+}
+
+// complete validates the workload and fills the absent options with the default values.
+func (w *WorkloadConfig) complete() error {
+	generator, ok := protocolGenerators[w.Protocol]
+	if !ok {
+		return fmt.Errorf("unsupported protocol [%s]", w.Protocol)
+	}
+	if w.Rate <= 0 {
+		return fmt.Errorf("the rate of [%s] must be positive", w.Protocol)
+	}
+	if w.Role == "" {
+		w.Role = RoleServer
+	}
+	if w.Role != RoleServer && w.Role != RoleClient {
+		return fmt.Errorf("unknown role [%s], must be %s or %s", w.Role, RoleServer, RoleClient)
+	}
+	if w.ServerAddress == "" {
+		w.ServerAddress = "10.0.0.1"
+	}
+	if _, _, err := net.SplitHostPort(w.ServerAddress); err != nil {
+		w.ServerAddress = net.JoinHostPort(w.ServerAddress, strconv.Itoa(int(generator.defaultPort)))
+	}
+	if w.ClientIp == "" {
+		w.ClientIp = "10.0.0.2"
+	}
+	if w.Connections <= 0 {
+		w.Connections = 10
+	}
+	if w.Latency <= 0 {
+		w.Latency = 5 * time.Millisecond
+	}
+	if w.ErrorRatio < 0 || w.ErrorRatio > 1 {
+		return fmt.Errorf("the error ratio of [%s] must be between 0 and 1", w.Protocol)
+	}
+	if w.Template == "" {
+		w.Template = generator.defaultTemplate
+	}
+	if w.Cardinality <= 0 {
+		w.Cardinality = 1
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package generatorreceiver
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component"
+	analyzerpackage "github.com/Kindling-project/kindling/collector/pkg/component/analyzer"
+	"github.com/Kindling-project/kindling/collector/pkg/component/receiver"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"go.uber.org/zap"
+)
+
+const (
+	Generator = "generatorreceiver"
+
+	// tickInterval is the interval of generating a batch of pairs.
+	tickInterval = 10 * time.Millisecond
+)
+
+// GeneratorReceiver fabricates the request/response events of several protocols at configurable
+// rates, so the analyzers, processors and exporters could be benchmarked without the probe.
+type GeneratorReceiver struct {
+	cfg             *Config
+	analyzerManager *analyzerpackage.Manager
+	telemetry       *component.TelemetryTools
+	workloads       []*workload
+	stopCh          chan struct{}
+	doneCh          chan struct{}
+	component.HealthReporter
+}
+
+func NewGeneratorReceiver(config interface{}, telemetry *component.TelemetryTools, analyzerManager *analyzerpackage.Manager) receiver.Receiver {
+	cfg, ok := config.(*Config)
+	if !ok {
+		telemetry.Logger.Sugar().Panicf("Cannot convert [%s] config", Generator)
+	}
+	return &GeneratorReceiver{
+		cfg:             cfg,
+		analyzerManager: analyzerManager,
+		telemetry:       telemetry,
+		stopCh:          make(chan struct{}),
+		doneCh:          make(chan struct{}),
+	}
+}
+
+func (r *GeneratorReceiver) Start(ctx context.Context) error {
+	if len(r.cfg.Workloads) == 0 {
+		return errors.New("no workloads are configured")
+	}
+	r.workloads = make([]*workload, 0, len(r.cfg.Workloads))
+	for i, cfg := range r.cfg.Workloads {
+		// The configuration is copied so that the default values are not written back.
+		w, err := newWorkload(*cfg, i)
+		if err != nil {
+			return err
+		}
+		r.workloads = append(r.workloads, w)
+	}
+	seed := r.cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	newSelfMetrics(r.telemetry.MeterProvider, r)
+	r.telemetry.Logger.Sugar().Infof("Start GeneratorReceiver with %d workloads and seed %d", len(r.workloads), seed)
+	go r.run(rand.New(rand.NewSource(seed)))
+	r.SetHealth(component.StatusRunning, nil)
+	return nil
+}
+
+// Shutdown stops generating events.
+func (r *GeneratorReceiver) Shutdown(ctx context.Context) error {
+	if r.Health().Status != component.StatusRunning {
+		return nil
+	}
+	defer r.SetHealth(component.StatusStopped, nil)
+	close(r.stopCh)
+	select {
+	case <-r.doneCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run generates the pairs due every tick, so that the average rate of each workload is as
+// configured. If the analyzers can't keep up with the rates, the pairs are generated as fast
+// as possible and the real rate is logged when finished.
+func (r *GeneratorReceiver) run(random *rand.Rand) {
+	defer close(r.doneCh)
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	start := time.Now()
+	last := start
+	finished := false
+	for !finished {
+		select {
+		case <-r.stopCh:
+			finished = true
+		case <-ticker.C:
+		}
+		now := time.Now()
+		elapsed := now.Sub(start)
+		if r.cfg.Duration > 0 && elapsed >= r.cfg.Duration {
+			elapsed = r.cfg.Duration
+			finished = true
+		}
+		// The pairs due are spread evenly since the last tick.
+		interval := uint64(now.Sub(last))
+		for _, w := range r.workloads {
+			due := int64(float64(w.cfg.Rate)*elapsed.Seconds()) - atomic.LoadInt64(&w.sent)
+			for i := int64(0); i < due; i++ {
+				timestamp := uint64(last.UnixNano()) + interval*uint64(i+1)/uint64(due)
+				request, response := w.generate(random, timestamp)
+				r.sendToNextConsumer(request)
+				r.sendToNextConsumer(response)
+			}
+		}
+		last = now
+	}
+	var total int64
+	for _, w := range r.workloads {
+		total += atomic.LoadInt64(&w.sent)
+	}
+	elapsed := time.Since(start)
+	r.telemetry.Logger.Sugar().Infof("GeneratorReceiver finished: %d pairs are generated in %v, %.0f events/s",
+		total, elapsed, float64(2*total)/elapsed.Seconds())
+}
+
+func (r *GeneratorReceiver) sendToNextConsumer(evt *model.KindlingEvent) {
+	for _, analyzer := range r.analyzerManager.GetConsumableAnalyzers(evt.Name) {
+		err := analyzer.ConsumeEvent(evt)
+		if err != nil {
+			r.telemetry.Logger.Warn("Error sending event to next consumer: ", zap.Error(err))
+		}
+	}
+}
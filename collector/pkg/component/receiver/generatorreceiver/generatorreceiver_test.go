@@ -0,0 +1,106 @@
+package generatorreceiver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network"
+	"github.com/Kindling-project/kindling/collector/pkg/component/consumer"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+type pairRecord struct {
+	protocol string
+	isError  bool
+	isServer bool
+}
+
+// recordConsumer records the labels only, because the data groups are reused by the analyzer.
+type recordConsumer struct {
+	mutex sync.Mutex
+	pairs []pairRecord
+}
+
+func (c *recordConsumer) Consume(dataGroup *model.DataGroup) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	labels := dataGroup.Labels
+	c.pairs = append(c.pairs, pairRecord{
+		protocol: labels.GetStringValue(constlabels.Protocol),
+		isError:  labels.GetBoolValue(constlabels.IsError),
+		isServer: labels.GetBoolValue(constlabels.IsServer),
+	})
+	return nil
+}
+
+// TestGenerate checks that the generated events are recognized by the network analyzer.
+func TestGenerate(t *testing.T) {
+	cfg := &Config{
+		Duration: 100 * time.Millisecond,
+		Seed:     1,
+		Workloads: []*WorkloadConfig{
+			{Protocol: "http", Rate: 1000, ErrorRatio: 1},
+			{Protocol: "mysql", Rate: 1000, RequestsPerConnection: 5},
+			{Protocol: "redis", Rate: 1000, Role: RoleClient},
+			{Protocol: "kafka", Rate: 1000},
+			{Protocol: "dns", Rate: 1000, Cardinality: 10},
+		},
+	}
+	analyzerCfg := network.NewDefaultConfig()
+	analyzerCfg.EnableConntrack = false
+	recorder := &recordConsumer{}
+	telemetry := component.NewDefaultTelemetryTools()
+	networkAnalyzer := network.NewNetworkAnalyzer(analyzerCfg, telemetry, []consumer.Consumer{recorder})
+	if err := networkAnalyzer.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	manager, err := analyzer.NewManager(networkAnalyzer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewGeneratorReceiver(cfg, telemetry, manager).(*GeneratorReceiver)
+	if err = r.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	<-r.doneCh
+	if err = r.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// The last pair of each connection is flushed when the analyzer shuts down.
+	if err = networkAnalyzer.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make(map[string]int)
+	for _, pair := range recorder.pairs {
+		counts[pair.protocol]++
+		if pair.protocol == "http" && !pair.isError {
+			t.Errorf("Expected all the HTTP responses to be errors, but get %v", pair)
+		}
+		if pair.protocol == "redis" && pair.isServer {
+			t.Errorf("Expected Redis observed on the client side, but get %v", pair)
+		}
+	}
+	for _, w := range cfg.Workloads {
+		// 100 pairs are expected
+		if counts[w.Protocol] != 100 {
+			t.Errorf("Expected 100 pairs of %s, but get %d", w.Protocol, counts[w.Protocol])
+		}
+	}
+	if cfg.Workloads[0].Role != "" {
+		t.Errorf("The configuration is not expected to be changed")
+	}
+}
+
+func TestUnsupportedProtocol(t *testing.T) {
+	manager, _ := analyzer.NewManager(network.NewNetworkAnalyzer(network.NewDefaultConfig(), component.NewDefaultTelemetryTools(), nil))
+	r := NewGeneratorReceiver(&Config{Workloads: []*WorkloadConfig{{Protocol: "smtp", Rate: 1}}}, component.NewDefaultTelemetryTools(), manager)
+	if err := r.Start(context.Background()); err == nil {
+		t.Fatal("Expected an error for the unsupported protocol")
+	}
+}
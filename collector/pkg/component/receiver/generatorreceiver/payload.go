@@ -0,0 +1,228 @@
+package generatorreceiver
+
+import (
+	"encoding/binary"
+	"strconv"
+	"strings"
+
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constnames"
+)
+
+// protocolGenerator builds the payloads of a protocol. seq increases with the requests sent over
+// a connection, and is used as the correlation id for the protocols that have one.
+type protocolGenerator struct {
+	defaultPort     uint32
+	defaultTemplate string
+	l4Proto         model.L4Proto
+	// readEvent and writeEvent are the names of the syscalls receiving and sending the payloads.
+	readEvent  string
+	writeEvent string
+	request    func(key string, seq uint32) []byte
+	response   func(key string, seq uint32, isError bool) []byte
+}
+
+var protocolGenerators = map[string]*protocolGenerator{
+	"http": {
+		defaultPort:     8080,
+		defaultTemplate: "/api/v1/items/{{id}}",
+		l4Proto:         model.L4Proto_TCP,
+		readEvent:       constnames.ReadEvent,
+		writeEvent:      constnames.WriteEvent,
+		request:         httpRequest,
+		response:        httpResponse,
+	},
+	"mysql": {
+		defaultPort:     3306,
+		defaultTemplate: "SELECT * FROM items WHERE id = {{id}}",
+		l4Proto:         model.L4Proto_TCP,
+		readEvent:       constnames.RecvFromEvent,
+		writeEvent:      constnames.SendToEvent,
+		request:         mysqlRequest,
+		response:        mysqlResponse,
+	},
+	"redis": {
+		defaultPort:     6379,
+		defaultTemplate: "item:{{id}}",
+		l4Proto:         model.L4Proto_TCP,
+		readEvent:       constnames.ReadEvent,
+		writeEvent:      constnames.WriteEvent,
+		request:         redisRequest,
+		response:        redisResponse,
+	},
+	"kafka": {
+		defaultPort:     9092,
+		defaultTemplate: "topic-{{id}}",
+		l4Proto:         model.L4Proto_TCP,
+		readEvent:       constnames.ReadEvent,
+		writeEvent:      constnames.WriteEvent,
+		request:         kafkaProduceRequest,
+		response:        kafkaProduceResponse,
+	},
+	"dns": {
+		defaultPort:     53,
+		defaultTemplate: "service-{{id}}.default.svc.cluster.local",
+		l4Proto:         model.L4Proto_UDP,
+		readEvent:       constnames.RecvMsgEvent,
+		writeEvent:      constnames.SendMsgEvent,
+		request:         dnsRequest,
+		response:        dnsResponse,
+	},
+}
+
+func httpRequest(url string, _ uint32) []byte {
+	return []byte("GET " + url + " HTTP/1.1\r\nHost: kindling-generator\r\nUser-Agent: kindling-generator\r\nAccept: */*\r\n\r\n")
+}
+
+func httpResponse(_ string, _ uint32, isError bool) []byte {
+	if isError {
+		return []byte("HTTP/1.1 500 Internal Server Error\r\nContent-Type: text/plain\r\nContent-Length: 5\r\n\r\nerror")
+	}
+	return []byte("HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\nContent-Length: 2\r\n\r\nOK")
+}
+
+// mysqlPacket prepends the packet header of int<3> payload_length and int<1> sequence_id.
+func mysqlPacket(sequenceId byte, payload []byte) []byte {
+	packet := make([]byte, 4, 4+len(payload))
+	packet[0] = byte(len(payload))
+	packet[1] = byte(len(payload) >> 8)
+	packet[2] = byte(len(payload) >> 16)
+	packet[3] = sequenceId
+	return append(packet, payload...)
+}
+
+func mysqlRequest(sql string, _ uint32) []byte {
+	// COM_QUERY
+	return mysqlPacket(0, append([]byte{0x03}, sql...))
+}
+
+func mysqlResponse(_ string, _ uint32, isError bool) []byte {
+	if isError {
+		// ERR_Packet of 1146 (42S02) Table doesn't exist
+		payload := []byte{0xff, 0x7a, 0x04}
+		payload = append(payload, "#42S02Table doesn't exist"...)
+		return mysqlPacket(1, payload)
+	}
+	// OK_Packet without affected rows and warnings
+	return mysqlPacket(1, []byte{0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00})
+}
+
+func redisRequest(key string, _ uint32) []byte {
+	return []byte("*2\r\n$3\r\nGET\r\n$" + strconv.Itoa(len(key)) + "\r\n" + key + "\r\n")
+}
+
+func redisResponse(_ string, _ uint32, isError bool) []byte {
+	if isError {
+		return []byte("-ERR generated error\r\n")
+	}
+	return []byte("$5\r\nvalue\r\n")
+}
+
+func appendKafkaString(b []byte, s string) []byte {
+	b = appendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+// kafkaMessage prepends the size of the message.
+func kafkaMessage(b []byte) []byte {
+	binary.BigEndian.PutUint32(b[0:4], uint32(len(b)-4))
+	return b
+}
+
+func kafkaProduceRequest(topic string, seq uint32) []byte {
+	b := make([]byte, 4, 64)
+	// Produce request v2
+	b = appendUint16(b, 0)
+	b = appendUint16(b, 2)
+	b = appendUint32(b, seq)
+	b = appendKafkaString(b, "kindling-generator")
+	// acks, timeout_ms
+	b = appendUint16(b, 1)
+	b = appendUint32(b, 30000)
+	// One topic with one partition and an empty record set
+	b = appendUint32(b, 1)
+	b = appendKafkaString(b, topic)
+	b = appendUint32(b, 1)
+	b = appendUint32(b, 0)
+	b = appendUint32(b, 0)
+	return kafkaMessage(b)
+}
+
+func kafkaProduceResponse(topic string, seq uint32, isError bool) []byte {
+	var errorCode uint16
+	if isError {
+		// UNKNOWN_TOPIC_OR_PARTITION
+		errorCode = 3
+	}
+	b := make([]byte, 4, 64)
+	b = appendUint32(b, seq)
+	b = appendUint32(b, 1)
+	b = appendKafkaString(b, topic)
+	b = appendUint32(b, 1)
+	// partition, error_code, base_offset, log_append_time
+	b = appendUint32(b, 0)
+	b = appendUint16(b, errorCode)
+	b = appendUint64(b, uint64(seq))
+	b = appendUint64(b, ^uint64(0))
+	// throttle_time_ms
+	b = appendUint32(b, 0)
+	return kafkaMessage(b)
+}
+
+func appendDnsHeader(b []byte, id uint16, flags uint16, answers uint16) []byte {
+	b = appendUint16(b, id)
+	b = appendUint16(b, flags)
+	// QDCOUNT, ANCOUNT, NSCOUNT, ARCOUNT
+	b = appendUint16(b, 1)
+	b = appendUint16(b, answers)
+	b = appendUint16(b, 0)
+	return appendUint16(b, 0)
+}
+
+func appendDnsQuestion(b []byte, domain string) []byte {
+	for _, label := range strings.Split(strings.Trim(domain, "."), ".") {
+		b = append(b, byte(len(label)))
+		b = append(b, label...)
+	}
+	b = append(b, 0)
+	// Type A, class IN
+	b = appendUint16(b, 1)
+	return appendUint16(b, 1)
+}
+
+func dnsRequest(domain string, seq uint32) []byte {
+	b := make([]byte, 0, 64)
+	// Standard query with recursion desired
+	b = appendDnsHeader(b, uint16(seq), 0x0100, 0)
+	return appendDnsQuestion(b, domain)
+}
+
+func dnsResponse(domain string, seq uint32, isError bool) []byte {
+	b := make([]byte, 0, 80)
+	if isError {
+		// NXDOMAIN
+		b = appendDnsHeader(b, uint16(seq), 0x8183, 0)
+		return appendDnsQuestion(b, domain)
+	}
+	b = appendDnsHeader(b, uint16(seq), 0x8180, 1)
+	b = appendDnsQuestion(b, domain)
+	// The answer points to the name in the question, with type A, class IN, TTL 60s and 10.0.0.100
+	b = append(b, 0xc0, 0x0c)
+	b = appendUint16(b, 1)
+	b = appendUint16(b, 1)
+	b = appendUint32(b, 60)
+	b = appendUint16(b, 4)
+	return append(b, 10, 0, 0, 100)
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v>>32)), uint32(v))
+}
@@ -0,0 +1,27 @@
+package generatorreceiver
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var once sync.Once
+
+const generatedPairsMetric = "kindling_telemetry_generatorreceiver_pairs_total"
+
+func newSelfMetrics(meterProvider metric.MeterProvider, receiver *GeneratorReceiver) {
+	once.Do(func() {
+		meter := metric.Must(meterProvider.Meter("kindling"))
+		meter.NewInt64CounterObserver(generatedPairsMetric,
+			func(ctx context.Context, result metric.Int64ObserverResult) {
+				for i, w := range receiver.workloads {
+					result.Observe(atomic.LoadInt64(&w.sent), attribute.Int("workload", i),
+						attribute.String("protocol", w.cfg.Protocol), attribute.String("role", w.cfg.Role))
+				}
+			})
+	})
+}
@@ -0,0 +1,177 @@
+package generatorreceiver
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+)
+
+const (
+	idPlaceholder = "{{id}}"
+	// syscallLatency is the latency of the generated syscalls in nanoseconds.
+	syscallLatency = 10000
+	firstFd        = 100
+	firstPort      = 32768
+	lastPort       = 60999
+)
+
+// connection is a socket reused by the pairs of a workload.
+type connection struct {
+	fd       int32
+	port     uint32
+	requests int
+	seq      uint32
+}
+
+type workload struct {
+	cfg        WorkloadConfig
+	generator  *protocolGenerator
+	pid        uint32
+	serverIp   uint32
+	serverPort uint32
+	clientIp   uint32
+	conns      []*connection
+	next       int
+	nextFd     int32
+	nextPort   uint32
+	// sent is the number of the pairs generated, read by the self metrics.
+	sent int64
+}
+
+func newWorkload(cfg WorkloadConfig, index int) (*workload, error) {
+	if err := cfg.complete(); err != nil {
+		return nil, err
+	}
+	host, port, err := net.SplitHostPort(cfg.ServerAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server address [%s]: %w", cfg.ServerAddress, err)
+	}
+	serverIp, err := ipToUint32(host)
+	if err != nil {
+		return nil, err
+	}
+	serverPort, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server port [%s]: %w", port, err)
+	}
+	clientIp, err := ipToUint32(cfg.ClientIp)
+	if err != nil {
+		return nil, err
+	}
+	w := &workload{
+		cfg:        cfg,
+		generator:  protocolGenerators[cfg.Protocol],
+		pid:        uint32(10000 + index),
+		serverIp:   serverIp,
+		serverPort: uint32(serverPort),
+		clientIp:   clientIp,
+		conns:      make([]*connection, cfg.Connections),
+		nextFd:     firstFd,
+		nextPort:   firstPort,
+	}
+	for i := range w.conns {
+		w.conns[i] = &connection{}
+		w.renew(w.conns[i])
+	}
+	return w, nil
+}
+
+// ipToUint32 converts the IPv4 address in the way model.IPLong2String converts it back.
+func ipToUint32(ip string) (uint32, error) {
+	parsed := net.ParseIP(ip).To4()
+	if parsed == nil {
+		return 0, fmt.Errorf("invalid IPv4 address [%s]", ip)
+	}
+	return binary.LittleEndian.Uint32(parsed), nil
+}
+
+// renew replaces the connection with a new one, which has a new fd and a new client port.
+func (w *workload) renew(conn *connection) {
+	conn.fd = w.nextFd
+	conn.port = w.nextPort
+	conn.requests = 0
+	w.nextFd++
+	w.nextPort++
+	if w.nextPort > lastPort {
+		w.nextPort = firstPort
+	}
+}
+
+// generate creates a request and its response starting at the timestamp in nanoseconds.
+func (w *workload) generate(random *rand.Rand, timestamp uint64) (request *model.KindlingEvent, response *model.KindlingEvent) {
+	conn := w.conns[w.next]
+	w.next = (w.next + 1) % len(w.conns)
+	if w.cfg.RequestsPerConnection > 0 && conn.requests >= w.cfg.RequestsPerConnection {
+		w.renew(conn)
+	}
+	conn.requests++
+	conn.seq++
+
+	key := w.cfg.Template
+	if strings.Contains(key, idPlaceholder) {
+		key = strings.ReplaceAll(key, idPlaceholder, strconv.Itoa(random.Intn(w.cfg.Cardinality)))
+	}
+	latency := w.cfg.Latency
+	if w.cfg.LatencyJitter > 0 {
+		latency += time.Duration(random.Int63n(int64(2*w.cfg.LatencyJitter))) - w.cfg.LatencyJitter
+		if latency < 0 {
+			latency = 0
+		}
+	}
+	isError := w.cfg.ErrorRatio > 0 && random.Float64() < w.cfg.ErrorRatio
+
+	requestName, responseName := w.generator.readEvent, w.generator.writeEvent
+	if w.cfg.Role == RoleClient {
+		requestName, responseName = responseName, requestName
+	}
+	request = w.newEvent(conn, requestName, timestamp, w.generator.request(key, conn.seq))
+	response = w.newEvent(conn, responseName, timestamp+uint64(latency), w.generator.response(key, conn.seq, isError))
+	atomic.AddInt64(&w.sent, 1)
+	return request, response
+}
+
+func (w *workload) newEvent(conn *connection, name string, timestamp uint64, data []byte) *model.KindlingEvent {
+	ev := &model.KindlingEvent{
+		Source:       model.Source_SYSCALL_EXIT,
+		Timestamp:    timestamp,
+		Name:         name,
+		Category:     model.Category_CAT_NET,
+		ParamsNumber: 3,
+		Ctx: model.Context{
+			ThreadInfo: model.Thread{
+				Pid:         w.pid,
+				Tid:         w.pid,
+				Comm:        "generator-" + w.cfg.Protocol,
+				ContainerId: w.cfg.ContainerId,
+			},
+			FdInfo: model.Fd{
+				Num:      conn.fd,
+				TypeFd:   model.FDType_FD_IPV4_SOCK,
+				Protocol: w.generator.l4Proto,
+				Role:     w.cfg.Role == RoleServer,
+				Sip:      []uint32{w.clientIp},
+				Sport:    conn.port,
+				Dip:      []uint32{w.serverIp},
+				Dport:    w.serverPort,
+			},
+		},
+	}
+	ev.UserAttributes[0] = model.KeyValue{Key: "latency", ValueType: model.ValueType_UINT64, Value: uint64Bytes(syscallLatency)}
+	ev.UserAttributes[1] = model.KeyValue{Key: "res", ValueType: model.ValueType_INT64, Value: uint64Bytes(uint64(len(data)))}
+	ev.UserAttributes[2] = model.KeyValue{Key: "data", ValueType: model.ValueType_BYTEBUF, Value: data}
+	return ev
+}
+
+// uint64Bytes encodes the value in little endian, which is the byte order of the probe on x86 and arm64.
+func uint64Bytes(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, v)
+	return b
+}
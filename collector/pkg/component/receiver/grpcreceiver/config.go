@@ -0,0 +1,28 @@
+package grpcreceiver
+
+import "github.com/Kindling-project/kindling/collector/pkg/eventqueue"
+
+type Config struct {
+	// Endpoint is the address to listen on. Use "unix:///path/to/socket" for a Unix domain
+	// socket, or "host:port" for TCP.
+	Endpoint      string     `mapstructure:"endpoint"`
+	SubscribeInfo []SubEvent `mapstructure:"subscribe"`
+	// Queue buffers the events between the event sources and the analyzers.
+	Queue eventqueue.Config `mapstructure:"queue"`
+	// Workers is the number of the goroutines analyzing the events in parallel. The events of a
+	// socket are always analyzed by the same goroutine, so they keep their order.
+	Workers int `mapstructure:"workers"`
+}
+
+type SubEvent struct {
+	Category string `mapstructure:"category"`
+	Name     string `mapstructure:"name"`
+}
+
+func NewDefaultConfig() *Config {
+	return &Config{
+		Endpoint: "unix:///var/run/kindling/events.sock",
+		Queue:    eventqueue.NewDefaultConfig(),
+		Workers:  1,
+	}
+}
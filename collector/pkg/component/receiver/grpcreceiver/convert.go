@@ -0,0 +1,100 @@
+package grpcreceiver
+
+import (
+	"fmt"
+
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+)
+
+// ToModel converts the message into model.KindlingEvent.
+func (m *KindlingEvent) ToModel() (*model.KindlingEvent, error) {
+	if len(m.UserAttributes) > len(model.KindlingEvent{}.UserAttributes) {
+		return nil, fmt.Errorf("event [%s] has %d user attributes, more than %d", m.Name, len(m.UserAttributes), len(model.KindlingEvent{}.UserAttributes))
+	}
+	ev := &model.KindlingEvent{
+		Source:       model.Source(m.Source),
+		Timestamp:    m.Timestamp,
+		Name:         m.Name,
+		Category:     model.Category(m.Category),
+		ParamsNumber: uint16(len(m.UserAttributes)),
+	}
+	for i, attr := range m.UserAttributes {
+		if attr == nil {
+			continue
+		}
+		ev.UserAttributes[i] = model.KeyValue{Key: attr.Key, ValueType: model.ValueType(attr.ValueType), Value: attr.Value}
+	}
+	if m.Ctx == nil {
+		return ev, nil
+	}
+	if thread := m.Ctx.ThreadInfo; thread != nil {
+		ev.Ctx.ThreadInfo = model.Thread{
+			Pid:           thread.Pid,
+			Tid:           thread.Tid,
+			Uid:           thread.Uid,
+			Gid:           thread.Gid,
+			Comm:          thread.Comm,
+			ContainerId:   thread.ContainerId,
+			ContainerName: thread.ContainerName,
+		}
+	}
+	if fd := m.Ctx.FdInfo; fd != nil {
+		ev.Ctx.FdInfo = model.Fd{
+			Num:         fd.Num,
+			TypeFd:      model.FDType(fd.TypeFd),
+			Filename:    fd.Filename,
+			Directory:   fd.Directory,
+			Protocol:    model.L4Proto(fd.Protocol),
+			Role:        fd.Role,
+			Sip:         fd.Sip,
+			Dip:         fd.Dip,
+			Sport:       fd.Sport,
+			Dport:       fd.Dport,
+			Source:      fd.Source,
+			Destination: fd.Destination,
+		}
+	}
+	return ev, nil
+}
+
+// FromModel converts model.KindlingEvent into the message. It is used by the event sources
+// written in Go.
+func FromModel(ev *model.KindlingEvent) *KindlingEvent {
+	m := &KindlingEvent{
+		Source:         int32(ev.Source),
+		Timestamp:      ev.Timestamp,
+		Name:           ev.Name,
+		Category:       int32(ev.Category),
+		UserAttributes: make([]*KeyValue, 0, ev.ParamsNumber),
+		Ctx: &Context{
+			ThreadInfo: &Thread{
+				Pid:           ev.Ctx.ThreadInfo.Pid,
+				Tid:           ev.Ctx.ThreadInfo.Tid,
+				Uid:           ev.Ctx.ThreadInfo.Uid,
+				Gid:           ev.Ctx.ThreadInfo.Gid,
+				Comm:          ev.Ctx.ThreadInfo.Comm,
+				ContainerId:   ev.Ctx.ThreadInfo.ContainerId,
+				ContainerName: ev.Ctx.ThreadInfo.ContainerName,
+			},
+			FdInfo: &Fd{
+				Num:         ev.Ctx.FdInfo.Num,
+				TypeFd:      int32(ev.Ctx.FdInfo.TypeFd),
+				Filename:    ev.Ctx.FdInfo.Filename,
+				Directory:   ev.Ctx.FdInfo.Directory,
+				Protocol:    int32(ev.Ctx.FdInfo.Protocol),
+				Role:        ev.Ctx.FdInfo.Role,
+				Sip:         ev.Ctx.FdInfo.Sip,
+				Dip:         ev.Ctx.FdInfo.Dip,
+				Sport:       ev.Ctx.FdInfo.Sport,
+				Dport:       ev.Ctx.FdInfo.Dport,
+				Source:      ev.Ctx.FdInfo.Source,
+				Destination: ev.Ctx.FdInfo.Destination,
+			},
+		},
+	}
+	for i := 0; i < int(ev.ParamsNumber) && i < len(ev.UserAttributes); i++ {
+		attr := &ev.UserAttributes[i]
+		m.UserAttributes = append(m.UserAttributes, &KeyValue{Key: attr.Key, ValueType: int32(attr.ValueType), Value: attr.Value})
+	}
+	return m
+}
@@ -0,0 +1,276 @@
+package grpcreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component"
+	analyzerpackage "github.com/Kindling-project/kindling/collector/pkg/component/analyzer"
+	"github.com/Kindling-project/kindling/collector/pkg/component/receiver"
+	"github.com/Kindling-project/kindling/collector/pkg/eventqueue"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	Grpc = "grpcreceiver"
+
+	unixPrefix = "unix://"
+)
+
+var errShuttingDown = status.Error(codes.Unavailable, "the receiver is shutting down")
+
+// GrpcReceiver receives the events streamed by the probe or any other event source running in
+// another process, through gRPC over TCP or a Unix domain socket. Unlike cgoreceiver, it doesn't
+// need cgo, so a crash of the event source doesn't affect the collector.
+type GrpcReceiver struct {
+	cfg             *Config
+	analyzerManager *analyzerpackage.Manager
+	telemetry       *component.TelemetryTools
+	server          *grpc.Server
+	// queue buffers the events received until they are sent to the analyzers.
+	queue     *eventqueue.Queue
+	consumeWG sync.WaitGroup
+
+	// mutex guards the fields below. The handlers are tracked by handlerWG, so that the queue
+	// is closed after all of them return.
+	mutex     sync.Mutex
+	stopped   bool
+	stopCh    chan struct{}
+	handlerWG sync.WaitGroup
+	// subscribers are notified when the subscription changes.
+	subscribers map[chan struct{}]struct{}
+	component.HealthReporter
+}
+
+func NewGrpcReceiver(config interface{}, telemetry *component.TelemetryTools, analyzerManager *analyzerpackage.Manager) receiver.Receiver {
+	cfg, ok := config.(*Config)
+	if !ok {
+		telemetry.Logger.Sugar().Panicf("Cannot convert [%s] config", Grpc)
+	}
+	return &GrpcReceiver{
+		cfg:             cfg,
+		analyzerManager: analyzerManager,
+		telemetry:       telemetry,
+		stopCh:          make(chan struct{}),
+		subscribers:     make(map[chan struct{}]struct{}),
+	}
+}
+
+func (r *GrpcReceiver) Start(ctx context.Context) error {
+	// The queue is built after its configuration is validated, because an invalid size panics.
+	if err := r.cfg.Queue.Validate(); err != nil {
+		return err
+	}
+	r.queue = eventqueue.New(r.cfg.Queue)
+	newSelfMetrics(r.telemetry.MeterProvider, r)
+	listener, err := listen(r.cfg.Endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", r.cfg.Endpoint, err)
+	}
+	r.server = grpc.NewServer()
+	RegisterEventServiceServer(r.server, r)
+	r.consumeWG.Add(1)
+	go r.consumeEvents(analyzerpackage.NewDispatcher(r.analyzerManager, r.cfg.Workers, r.telemetry.Logger))
+	go func() {
+		if err := r.server.Serve(listener); err != nil {
+			r.telemetry.Logger.Error("gRPC server of grpcreceiver stopped with an error", zap.Error(err))
+			r.SetHealth(component.StatusFailed, err)
+		}
+	}()
+	r.telemetry.Logger.Sugar().Infof("Start GrpcReceiver listening on %s", r.cfg.Endpoint)
+	r.SetHealth(component.StatusRunning, nil)
+	return nil
+}
+
+func listen(endpoint string) (net.Listener, error) {
+	if !strings.HasPrefix(endpoint, unixPrefix) {
+		return net.Listen("tcp", endpoint)
+	}
+	path := strings.TrimPrefix(endpoint, unixPrefix)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	// Remove the socket left by the last run, otherwise it can't be listened on again.
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return net.Listen("unix", path)
+}
+
+// Shutdown closes all the connections, and then waits until the events already received are
+// sent to the analyzers. The event sources are expected to reconnect when the collector restarts.
+func (r *GrpcReceiver) Shutdown(ctx context.Context) error {
+	if r.Health().Status != component.StatusRunning {
+		return nil
+	}
+	defer r.SetHealth(component.StatusStopped, nil)
+	r.mutex.Lock()
+	r.stopped = true
+	close(r.stopCh)
+	r.mutex.Unlock()
+	// The streams sending events never end by themselves, so don't stop gracefully.
+	r.server.Stop()
+	if err := waitWithContext(ctx, &r.handlerWG); err != nil {
+		return err
+	}
+	r.queue.Close()
+	r.telemetry.Logger.Sugar().Infof("The gRPC server is stopped, and %d events left are being drained", r.queue.Len())
+	return waitWithContext(ctx, &r.consumeWG)
+}
+
+// enterHandler returns false if the receiver is shutting down, otherwise leaveHandler must be
+// called when the handler returns.
+func (r *GrpcReceiver) enterHandler() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.stopped {
+		return false
+	}
+	r.handlerWG.Add(1)
+	return true
+}
+
+func (r *GrpcReceiver) leaveHandler() {
+	r.handlerWG.Done()
+}
+
+// Subscribe sends the subscribed events to the event source, and sends them again when they change.
+func (r *GrpcReceiver) Subscribe(req *SubscribeRequest, stream EventService_SubscribeServer) error {
+	if !r.enterHandler() {
+		return errShuttingDown
+	}
+	defer r.leaveHandler()
+	r.telemetry.Logger.Sugar().Infof("Event source [%s] (pid %d) subscribes to events", req.SourceName, req.Pid)
+	notifyCh := make(chan struct{}, 1)
+	r.mutex.Lock()
+	r.subscribers[notifyCh] = struct{}{}
+	r.mutex.Unlock()
+	defer func() {
+		r.mutex.Lock()
+		delete(r.subscribers, notifyCh)
+		r.mutex.Unlock()
+	}()
+	for {
+		if err := stream.Send(r.subscription()); err != nil {
+			return err
+		}
+		select {
+		case <-notifyCh:
+		case <-stream.Context().Done():
+			return nil
+		case <-r.stopCh:
+			return errShuttingDown
+		}
+	}
+}
+
+func (r *GrpcReceiver) subscription() *model.SubEvent {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	labels := make([]*model.Label, 0, len(r.cfg.SubscribeInfo))
+	for _, event := range r.cfg.SubscribeInfo {
+		labels = append(labels, &model.Label{Category: event.Category, Name: event.Name})
+	}
+	return &model.SubEvent{Pid: uint32(os.Getpid()), Labels: labels}
+}
+
+// Send receives the events and pushes them into the queue. What happens when the queue is full
+// is decided by its overflow policy, and the events dropped are not counted as received.
+func (r *GrpcReceiver) Send(stream EventService_SendServer) error {
+	if !r.enterHandler() {
+		return errShuttingDown
+	}
+	defer r.leaveHandler()
+	var received uint64
+	for {
+		batch, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&SendResponse{Received: received})
+		}
+		if err != nil {
+			return err
+		}
+		for _, m := range batch.Events {
+			if m == nil {
+				continue
+			}
+			ev, err := m.ToModel()
+			if err != nil {
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+			if r.queue.Push(ev) {
+				received++
+			}
+		}
+	}
+}
+
+func (r *GrpcReceiver) consumeEvents(dispatcher *analyzerpackage.Dispatcher) {
+	defer r.consumeWG.Done()
+	// Wait for the workers, so all the events are analyzed when Shutdown returns.
+	defer dispatcher.Close()
+	for ev := range r.queue.Events() {
+		r.sendToNextConsumer(dispatcher, ev)
+	}
+}
+
+func (r *GrpcReceiver) sendToNextConsumer(dispatcher *analyzerpackage.Dispatcher, evt *model.KindlingEvent) {
+	if ce := r.telemetry.Logger.Check(zapcore.DebugLevel, "Receive Event"); ce != nil {
+		ce.Write(
+			zap.String("event", evt.String()),
+		)
+	}
+	dispatcher.Dispatch(evt)
+}
+
+// ApplyConfig changes the subscribed events at runtime and notifies the event sources.
+func (r *GrpcReceiver) ApplyConfig(config interface{}) error {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return fmt.Errorf("cannot convert [%s] config", Grpc)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if cfg.Endpoint != r.cfg.Endpoint || cfg.Workers != r.cfg.Workers {
+		return errors.New("the endpoint and the workers can't be changed at runtime, please restart the agent to apply it")
+	}
+	if !reflect.DeepEqual(cfg.Queue, r.cfg.Queue) {
+		return errors.New("the queue settings can't be changed at runtime, please restart the agent to apply it")
+	}
+	r.cfg = cfg
+	r.telemetry.Logger.Sugar().Infof("The subscribed events are changed to: %v", cfg.SubscribeInfo)
+	for notifyCh := range r.subscribers {
+		select {
+		case notifyCh <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+func waitWithContext(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,146 @@
+package grpcreceiver
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"google.golang.org/grpc"
+)
+
+type recordAnalyzer struct {
+	mutex  sync.Mutex
+	events []*model.KindlingEvent
+	component.HealthReporter
+}
+
+func (a *recordAnalyzer) Start(ctx context.Context) error    { return nil }
+func (a *recordAnalyzer) Shutdown(ctx context.Context) error { return nil }
+func (a *recordAnalyzer) Type() analyzer.Type                { return "recordanalyzer" }
+func (a *recordAnalyzer) ConsumableEvents() []string         { return []string{"read"} }
+
+func (a *recordAnalyzer) ConsumeEvent(event *model.KindlingEvent) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.events = append(a.events, event)
+	return nil
+}
+
+func (a *recordAnalyzer) getEvents() []*model.KindlingEvent {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.events
+}
+
+func newTestReceiver(t *testing.T) (*GrpcReceiver, *recordAnalyzer, EventServiceClient) {
+	cfg := NewDefaultConfig()
+	cfg.Endpoint = "unix://" + filepath.Join(t.TempDir(), "events.sock")
+	cfg.SubscribeInfo = []SubEvent{{Category: "net", Name: "syscall_exit-read"}}
+	recorder := &recordAnalyzer{}
+	manager, err := analyzer.NewManager(recorder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewGrpcReceiver(cfg, component.NewDefaultTelemetryTools(), manager).(*GrpcReceiver)
+	if err = r.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	conn, err := grpc.Dial(cfg.Endpoint, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return r, recorder, NewEventServiceClient(conn)
+}
+
+func TestSend(t *testing.T) {
+	r, recorder, client := newTestReceiver(t)
+	ev := &model.KindlingEvent{
+		Name:         "read",
+		Timestamp:    100,
+		Category:     model.Category_CAT_NET,
+		ParamsNumber: 1,
+		Ctx: model.Context{
+			FdInfo: model.Fd{Protocol: model.L4Proto_TCP, Sip: []uint32{1}, Dip: []uint32{2}, Sport: 8080, Dport: 51234},
+		},
+	}
+	ev.UserAttributes[0] = model.KeyValue{Key: "data", ValueType: model.ValueType_BYTEBUF, Value: []byte("PING\r\n")}
+
+	stream, err := client.Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = stream.Send(&EventBatch{Events: []*KindlingEvent{FromModel(ev), FromModel(ev)}}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Received != 2 {
+		t.Errorf("Expected 2 events received, but get %d", resp.Received)
+	}
+
+	if err = r.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	events := recorder.getEvents()
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events sent to the analyzer, but get %d", len(events))
+	}
+	got := events[0]
+	if got.Timestamp != 100 || got.Ctx.FdInfo.Dport != 51234 || string(got.GetData()) != "PING\r\n" {
+		t.Errorf("The event is changed during the transmission: %v", got)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	r, _, client := newTestReceiver(t)
+	defer r.Shutdown(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.Subscribe(ctx, &SubscribeRequest{SourceName: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	subEvent, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subEvent.Labels) != 1 || subEvent.Labels[0].Name != "syscall_exit-read" {
+		t.Fatalf("Unexpected subscription %v", subEvent)
+	}
+
+	newCfg := *r.cfg
+	newCfg.SubscribeInfo = []SubEvent{{Category: "net", Name: "syscall_exit-write"}}
+	if err = r.ApplyConfig(&newCfg); err != nil {
+		t.Fatal(err)
+	}
+	subEvent, err = stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subEvent.Labels) != 1 || subEvent.Labels[0].Name != "syscall_exit-write" {
+		t.Errorf("Expected the changed subscription, but get %v", subEvent)
+	}
+}
+
+func TestStartWithInvalidQueue(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Endpoint = "unix://" + filepath.Join(t.TempDir(), "events.sock")
+	cfg.Queue.OverflowPolicy = "unknown"
+	manager, err := analyzer.NewManager(&recordAnalyzer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewGrpcReceiver(cfg, component.NewDefaultTelemetryTools(), manager)
+	if err = r.Start(context.Background()); err == nil {
+		t.Error("Expected an error for the unknown overflow policy, but get nil")
+	}
+}
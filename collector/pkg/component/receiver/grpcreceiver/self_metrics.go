@@ -0,0 +1,21 @@
+package grpcreceiver
+
+import (
+	"sync"
+
+	"github.com/Kindling-project/kindling/collector/pkg/eventqueue"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var once sync.Once
+
+// queueMetricPrefix is the prefix of the metrics of the queue, like
+// kindling_telemetry_grpcreceiver_dropped_events_total.
+const queueMetricPrefix = "kindling_telemetry_grpcreceiver"
+
+func newSelfMetrics(meterProvider metric.MeterProvider, receiver *GrpcReceiver) {
+	once.Do(func() {
+		meter := metric.Must(meterProvider.Meter("kindling"))
+		eventqueue.RegisterSelfMetrics(meter, queueMetricPrefix, receiver.queue)
+	})
+}
@@ -0,0 +1,376 @@
+package pcapreceiver
+
+import (
+	"encoding/binary"
+	"sort"
+
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+)
+
+const (
+	// pcapPid is the pid of the events. The connections are told apart by their fds.
+	pcapPid = 1
+	firstFd = 3
+	// maxPendingSegments is the number of the out-of-order segments buffered for a direction of a
+	// connection. Once exceeded, the missing bytes are regarded as not captured and skipped.
+	maxPendingSegments = 64
+
+	fromClient  = 0
+	fromServer  = 1
+	noDirection = -1
+)
+
+type connKey struct {
+	protocol   uint8
+	clientIp   uint32
+	serverIp   uint32
+	clientPort uint16
+	serverPort uint16
+}
+
+// halfStream is a direction of a TCP connection.
+type halfStream struct {
+	nextSeq uint32
+	synced  bool
+	fin     bool
+	pending []pendingSegment
+}
+
+type pendingSegment struct {
+	seq       uint32
+	payload   []byte
+	timestamp uint64
+}
+
+type connection struct {
+	key     connKey
+	fd      int32
+	streams [2]halfStream
+	// The message being assembled. A message is the bytes sent in a direction until the peer
+	// starts sending, which is what the probe sees as a request or a response.
+	msgDirection int
+	msgData      []byte
+	msgSize      int
+	msgStart     uint64
+	msgEnd       uint64
+}
+
+// assembler reassembles the TCP streams and the UDP datagrams into the read/write events the
+// probe would generate on the server or the client.
+type assembler struct {
+	cfg         *Config
+	serverPorts map[uint16]bool
+	conns       map[connKey]*connection
+	nextFd      int32
+	emit        func(ev *model.KindlingEvent) error
+}
+
+func newAssembler(cfg *Config, emit func(ev *model.KindlingEvent) error) *assembler {
+	serverPorts := make(map[uint16]bool, len(cfg.ServerPorts))
+	for _, port := range cfg.ServerPorts {
+		serverPorts[uint16(port)] = true
+	}
+	return &assembler{
+		cfg:         cfg,
+		serverPorts: serverPorts,
+		conns:       make(map[connKey]*connection),
+		nextFd:      firstFd,
+		emit:        emit,
+	}
+}
+
+// process handles a decoded segment captured at the timestamp in nanoseconds.
+func (a *assembler) process(seg *segment, timestamp uint64) error {
+	if seg.protocol == ipProtocolUDP {
+		return a.processUDP(seg, timestamp)
+	}
+	return a.processTCP(seg, timestamp)
+}
+
+func (a *assembler) processUDP(seg *segment, timestamp uint64) error {
+	if len(seg.payload) == 0 {
+		return nil
+	}
+	conn, direction := a.lookup(seg)
+	if conn == nil {
+		conn, direction = a.newConnection(seg, a.serverIsDestination(seg))
+	}
+	return a.emit(a.newEvent(conn, direction, a.truncate(nil, seg.payload), len(seg.payload), timestamp, timestamp))
+}
+
+func (a *assembler) processTCP(seg *segment, timestamp uint64) error {
+	isSyn := seg.flags&tcpFlagSYN != 0
+	conn, direction := a.lookup(seg)
+	// A SYN with another sequence number means the 4-tuple is reused by a new connection, whose
+	// predecessor didn't end in the capture.
+	if conn != nil && isSyn && seg.flags&tcpFlagACK == 0 {
+		stream := &conn.streams[direction]
+		if stream.synced && stream.nextSeq != seg.seq+1 {
+			if err := a.close(conn); err != nil {
+				return err
+			}
+			conn = nil
+		}
+	}
+	if conn == nil {
+		switch {
+		case isSyn:
+			// The SYN is sent by the client, and the SYN/ACK by the server.
+			conn, direction = a.newConnection(seg, seg.flags&tcpFlagACK == 0)
+		case len(seg.payload) > 0:
+			conn, direction = a.newConnection(seg, a.serverIsDestination(seg))
+		default:
+			// The tail of a connection whose data is not captured.
+			return nil
+		}
+	}
+
+	stream := &conn.streams[direction]
+	seq := seg.seq
+	if isSyn {
+		// The SYN occupies a sequence number.
+		seq++
+		stream.nextSeq = seq
+		stream.synced = true
+	} else if !stream.synced {
+		stream.nextSeq = seq
+		stream.synced = true
+	}
+	if len(seg.payload) > 0 {
+		if err := a.receive(conn, direction, seq, seg.payload, timestamp); err != nil {
+			return err
+		}
+	}
+	if seg.flags&tcpFlagRST != 0 {
+		return a.close(conn)
+	}
+	if seg.flags&tcpFlagFIN != 0 {
+		stream.fin = true
+		if conn.streams[1-direction].fin {
+			return a.close(conn)
+		}
+	}
+	return nil
+}
+
+// receive puts the payload into the stream in the order of the sequence numbers.
+func (a *assembler) receive(conn *connection, direction int, seq uint32, payload []byte, timestamp uint64) error {
+	stream := &conn.streams[direction]
+	if int32(seq-stream.nextSeq) > 0 {
+		stream.pending = append(stream.pending, pendingSegment{
+			seq:       seq,
+			payload:   append([]byte(nil), payload...),
+			timestamp: timestamp,
+		})
+		if len(stream.pending) <= maxPendingSegments {
+			return nil
+		}
+		// Skip the missing bytes. The message being assembled is broken, so it ends here.
+		earliest := stream.pending[0].seq
+		for _, p := range stream.pending[1:] {
+			if int32(p.seq-earliest) < 0 {
+				earliest = p.seq
+			}
+		}
+		stream.nextSeq = earliest
+		if err := a.flushMessage(conn); err != nil {
+			return err
+		}
+	} else if err := a.deliver(conn, direction, seq, payload, timestamp); err != nil {
+		return err
+	}
+	return a.drainPending(conn, direction)
+}
+
+// deliver appends the in-order payload to the message. The bytes received before are trimmed.
+func (a *assembler) deliver(conn *connection, direction int, seq uint32, payload []byte, timestamp uint64) error {
+	stream := &conn.streams[direction]
+	if overlap := int64(int32(stream.nextSeq - seq)); overlap > 0 {
+		if overlap >= int64(len(payload)) {
+			// A retransmission
+			return nil
+		}
+		payload = payload[overlap:]
+	}
+	stream.nextSeq += uint32(len(payload))
+	if conn.msgDirection != direction {
+		if err := a.flushMessage(conn); err != nil {
+			return err
+		}
+		conn.msgDirection = direction
+		conn.msgStart = timestamp
+		conn.msgEnd = timestamp
+	}
+	conn.msgData = a.truncate(conn.msgData, payload)
+	conn.msgSize += len(payload)
+	// The segments buffered out of order may be captured earlier than the ones before them.
+	if timestamp < conn.msgStart {
+		conn.msgStart = timestamp
+	}
+	if timestamp > conn.msgEnd {
+		conn.msgEnd = timestamp
+	}
+	return nil
+}
+
+// drainPending delivers the buffered segments which are in order now.
+func (a *assembler) drainPending(conn *connection, direction int) error {
+	stream := &conn.streams[direction]
+	for {
+		index := -1
+		for i, p := range stream.pending {
+			if int32(p.seq-stream.nextSeq) <= 0 {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil
+		}
+		p := stream.pending[index]
+		stream.pending = append(stream.pending[:index], stream.pending[index+1:]...)
+		if err := a.deliver(conn, direction, p.seq, p.payload, p.timestamp); err != nil {
+			return err
+		}
+	}
+}
+
+// truncate appends the payload to the data without exceeding MaxDataSize.
+func (a *assembler) truncate(data []byte, payload []byte) []byte {
+	room := a.cfg.MaxDataSize - len(data)
+	if room <= 0 {
+		return data
+	}
+	if len(payload) > room {
+		payload = payload[:room]
+	}
+	return append(data, payload...)
+}
+
+func (a *assembler) flushMessage(conn *connection) error {
+	if conn.msgDirection == noDirection {
+		return nil
+	}
+	ev := a.newEvent(conn, conn.msgDirection, conn.msgData, conn.msgSize, conn.msgStart, conn.msgEnd)
+	conn.msgDirection = noDirection
+	conn.msgData = nil
+	conn.msgSize = 0
+	return a.emit(ev)
+}
+
+func (a *assembler) close(conn *connection) error {
+	delete(a.conns, conn.key)
+	return a.flushMessage(conn)
+}
+
+// flush emits the messages being assembled and forgets all the connections. It is called when
+// all the files are read.
+func (a *assembler) flush() error {
+	conns := make([]*connection, 0, len(a.conns))
+	for _, conn := range a.conns {
+		conns = append(conns, conn)
+	}
+	sort.Slice(conns, func(i, j int) bool { return conns[i].fd < conns[j].fd })
+	for _, conn := range conns {
+		if err := a.close(conn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup returns the connection of the segment and the direction the segment is sent in.
+func (a *assembler) lookup(seg *segment) (*connection, int) {
+	key := connKey{protocol: seg.protocol, clientIp: seg.srcIp, clientPort: seg.srcPort, serverIp: seg.dstIp, serverPort: seg.dstPort}
+	if conn, ok := a.conns[key]; ok {
+		return conn, fromClient
+	}
+	key = connKey{protocol: seg.protocol, clientIp: seg.dstIp, clientPort: seg.dstPort, serverIp: seg.srcIp, serverPort: seg.srcPort}
+	if conn, ok := a.conns[key]; ok {
+		return conn, fromServer
+	}
+	return nil, noDirection
+}
+
+func (a *assembler) newConnection(seg *segment, serverIsDestination bool) (*connection, int) {
+	key := connKey{protocol: seg.protocol, clientIp: seg.srcIp, clientPort: seg.srcPort, serverIp: seg.dstIp, serverPort: seg.dstPort}
+	direction := fromClient
+	if !serverIsDestination {
+		key = connKey{protocol: seg.protocol, clientIp: seg.dstIp, clientPort: seg.dstPort, serverIp: seg.srcIp, serverPort: seg.srcPort}
+		direction = fromServer
+	}
+	conn := &connection{key: key, fd: a.nextFd, msgDirection: noDirection}
+	a.nextFd++
+	a.conns[key] = conn
+	return conn, direction
+}
+
+// serverIsDestination guesses the server of a connection whose handshake is not captured.
+func (a *assembler) serverIsDestination(seg *segment) bool {
+	srcIsServer, dstIsServer := a.serverPorts[seg.srcPort], a.serverPorts[seg.dstPort]
+	if srcIsServer != dstIsServer {
+		return dstIsServer
+	}
+	if seg.srcPort != seg.dstPort {
+		return seg.dstPort < seg.srcPort
+	}
+	return true
+}
+
+// newEvent creates the event of a message. The messages from the clients are read by the
+// servers and the messages from the servers are written by them, and vice versa for the clients.
+func (a *assembler) newEvent(conn *connection, direction int, data []byte, size int, start uint64, end uint64) *model.KindlingEvent {
+	isServer := a.cfg.Role == RoleServer
+	isRead := (direction == fromClient) == isServer
+	var name string
+	protocol := model.L4Proto_TCP
+	if conn.key.protocol == ipProtocolUDP {
+		protocol = model.L4Proto_UDP
+		if isRead {
+			name = "recvfrom"
+		} else {
+			name = "sendto"
+		}
+	} else if isRead {
+		name = "read"
+	} else {
+		name = "write"
+	}
+	ev := &model.KindlingEvent{
+		Source:       model.Source_SYSCALL_EXIT,
+		Timestamp:    end,
+		Name:         name,
+		Category:     model.Category_CAT_NET,
+		ParamsNumber: 3,
+		Ctx: model.Context{
+			ThreadInfo: model.Thread{
+				Pid:  pcapPid,
+				Tid:  pcapPid,
+				Comm: "pcap",
+			},
+			FdInfo: model.Fd{
+				Num:      conn.fd,
+				TypeFd:   model.FDType_FD_IPV4_SOCK,
+				Protocol: protocol,
+				Role:     isServer,
+				Sip:      []uint32{conn.key.clientIp},
+				Sport:    uint32(conn.key.clientPort),
+				Dip:      []uint32{conn.key.serverIp},
+				Dport:    uint32(conn.key.serverPort),
+			},
+		},
+	}
+	// The latency of the event covers all the packets of the message, so the analyzers see the
+	// time the message started as its start time.
+	ev.UserAttributes[0] = model.KeyValue{Key: "latency", ValueType: model.ValueType_UINT64, Value: uint64Bytes(end - start)}
+	ev.UserAttributes[1] = model.KeyValue{Key: "res", ValueType: model.ValueType_INT64, Value: uint64Bytes(uint64(size))}
+	ev.UserAttributes[2] = model.KeyValue{Key: "data", ValueType: model.ValueType_BYTEBUF, Value: data}
+	return ev
+}
+
+// uint64Bytes encodes the value in little endian, which is the byte order of the probe on x86 and arm64.
+func uint64Bytes(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, v)
+	return b
+}
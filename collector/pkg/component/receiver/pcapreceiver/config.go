@@ -0,0 +1,56 @@
+package pcapreceiver
+
+import "fmt"
+
+const (
+	// ReplayModeRealtime replays the packets at the intervals they were captured.
+	ReplayModeRealtime = "realtime"
+	// ReplayModeFast replays the packets as fast as possible.
+	ReplayModeFast = "fast"
+
+	RoleServer = "server"
+	RoleClient = "client"
+)
+
+type Config struct {
+	// Files is the glob pattern of the pcap or pcapng files. The matched files are replayed in
+	// lexical order, which is the order tcpdump writes them in with -C or -G.
+	Files string `mapstructure:"files"`
+	// ReplayMode is either "realtime" or "fast".
+	ReplayMode string `mapstructure:"replay_mode"`
+	// Role is the side the events are generated for, either server or client. A capture contains
+	// both directions of a connection, so it could be analyzed as if the probe ran on either side.
+	Role string `mapstructure:"role"`
+	// ServerPorts are the ports of the servers in the capture. They are used to tell the servers
+	// from the clients when the handshake of a connection is not captured. Otherwise the side with
+	// the lower port is regarded as the server.
+	ServerPorts []int `mapstructure:"server_ports"`
+	// MaxDataSize is the maximum bytes of a message carried by an event, like the snaplen of the probe.
+	MaxDataSize int `mapstructure:"max_data_size"`
+}
+
+func NewDefaultConfig() *Config {
+	return &Config{
+		ReplayMode:  ReplayModeFast,
+		Role:        RoleServer,
+		MaxDataSize: 4096,
+	}
+}
+
+func (c *Config) validate() error {
+	if c.ReplayMode != ReplayModeRealtime && c.ReplayMode != ReplayModeFast {
+		return fmt.Errorf("unknown replay mode [%s], must be %s or %s", c.ReplayMode, ReplayModeRealtime, ReplayModeFast)
+	}
+	if c.Role != RoleServer && c.Role != RoleClient {
+		return fmt.Errorf("unknown role [%s], must be %s or %s", c.Role, RoleServer, RoleClient)
+	}
+	for _, port := range c.ServerPorts {
+		if port <= 0 || port > 65535 {
+			return fmt.Errorf("invalid server port %d", port)
+		}
+	}
+	if c.MaxDataSize <= 0 {
+		return fmt.Errorf("max_data_size must be positive, got %d", c.MaxDataSize)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package pcapreceiver
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// The link types defined at https://www.tcpdump.org/linktypes.html
+const (
+	linkTypeNull      = 0
+	linkTypeEthernet  = 1
+	linkTypeRaw       = 101
+	linkTypeLoop      = 108
+	linkTypeLinuxSLL  = 113
+	linkTypeIPv4      = 228
+	linkTypeIPv6      = 229
+	linkTypeLinuxSLL2 = 276
+	// Some BSDs write DLT_RAW as 12 or 14 instead of LINKTYPE_RAW.
+	linkTypeRawBSD  = 12
+	linkTypeRawBSD2 = 14
+)
+
+const (
+	etherTypeIPv4 = 0x0800
+	etherTypeIPv6 = 0x86dd
+	etherTypeVLAN = 0x8100
+	etherTypeQinQ = 0x88a8
+	ipProtocolTCP = 6
+	ipProtocolUDP = 17
+	tcpFlagFIN    = 0x01
+	tcpFlagSYN    = 0x02
+	tcpFlagRST    = 0x04
+	tcpFlagACK    = 0x10
+)
+
+var (
+	errUnsupportedLinkType = errors.New("unsupported link type")
+	errNotIPv4             = errors.New("not an IPv4 packet")
+	errFragment            = errors.New("IP fragment")
+	errNotTCPOrUDP         = errors.New("not a TCP or UDP segment")
+	errTruncated           = errors.New("truncated packet")
+)
+
+// segment is a TCP segment or a UDP datagram. The IP addresses are encoded in the same way as
+// the probe, i.e. the first octet is the least significant byte.
+type segment struct {
+	protocol uint8
+	srcIp    uint32
+	dstIp    uint32
+	srcPort  uint16
+	dstPort  uint16
+	// The following fields are only for TCP.
+	seq     uint32
+	flags   uint8
+	payload []byte
+}
+
+// decodePacket decodes the link, network and transport layers of a packet. Only IPv4 is
+// supported, because the probe events only carry one uint32 for each address.
+func decodePacket(p *packet, seg *segment) error {
+	var (
+		etherType uint16
+		data      = p.data
+	)
+	switch p.linkType {
+	case linkTypeEthernet:
+		if len(data) < 14 {
+			return errTruncated
+		}
+		etherType = binary.BigEndian.Uint16(data[12:14])
+		data = data[14:]
+		for etherType == etherTypeVLAN || etherType == etherTypeQinQ {
+			if len(data) < 4 {
+				return errTruncated
+			}
+			etherType = binary.BigEndian.Uint16(data[2:4])
+			data = data[4:]
+		}
+	case linkTypeLinuxSLL:
+		if len(data) < 16 {
+			return errTruncated
+		}
+		etherType = binary.BigEndian.Uint16(data[14:16])
+		data = data[16:]
+	case linkTypeLinuxSLL2:
+		if len(data) < 20 {
+			return errTruncated
+		}
+		etherType = binary.BigEndian.Uint16(data[0:2])
+		data = data[20:]
+	case linkTypeNull, linkTypeLoop:
+		// The address family is in the byte order of the capturing host, and AF_INET is 2 everywhere.
+		if len(data) < 4 {
+			return errTruncated
+		}
+		if binary.LittleEndian.Uint32(data[0:4]) != 2 && binary.BigEndian.Uint32(data[0:4]) != 2 {
+			return errNotIPv4
+		}
+		etherType = etherTypeIPv4
+		data = data[4:]
+	case linkTypeRaw, linkTypeRawBSD, linkTypeRawBSD2, linkTypeIPv4, linkTypeIPv6:
+		if len(data) < 1 {
+			return errTruncated
+		}
+		if data[0]>>4 == 4 {
+			etherType = etherTypeIPv4
+		} else {
+			etherType = etherTypeIPv6
+		}
+	default:
+		return errUnsupportedLinkType
+	}
+	if etherType != etherTypeIPv4 {
+		return errNotIPv4
+	}
+	return decodeIPv4(data, seg)
+}
+
+func decodeIPv4(data []byte, seg *segment) error {
+	if len(data) < 20 {
+		return errTruncated
+	}
+	if data[0]>>4 != 4 {
+		return errNotIPv4
+	}
+	headerLen := int(data[0]&0x0f) * 4
+	totalLen := int(binary.BigEndian.Uint16(data[2:4]))
+	if headerLen < 20 || totalLen < headerLen || len(data) < headerLen {
+		return errTruncated
+	}
+	// The frame may be padded, or the total length may be 0 with TCP segmentation offload.
+	if totalLen != 0 && totalLen < len(data) {
+		data = data[:totalLen]
+	}
+	// Fragments are rare for TCP and DNS, so they are not reassembled.
+	if binary.BigEndian.Uint16(data[6:8])&0x3fff != 0 {
+		return errFragment
+	}
+	seg.protocol = data[9]
+	seg.srcIp = binary.LittleEndian.Uint32(data[12:16])
+	seg.dstIp = binary.LittleEndian.Uint32(data[16:20])
+	data = data[headerLen:]
+
+	switch seg.protocol {
+	case ipProtocolTCP:
+		if len(data) < 20 {
+			return errTruncated
+		}
+		dataOffset := int(data[12]>>4) * 4
+		if dataOffset < 20 || len(data) < dataOffset {
+			return errTruncated
+		}
+		seg.srcPort = binary.BigEndian.Uint16(data[0:2])
+		seg.dstPort = binary.BigEndian.Uint16(data[2:4])
+		seg.seq = binary.BigEndian.Uint32(data[4:8])
+		seg.flags = data[13]
+		seg.payload = data[dataOffset:]
+	case ipProtocolUDP:
+		if len(data) < 8 {
+			return errTruncated
+		}
+		seg.srcPort = binary.BigEndian.Uint16(data[0:2])
+		seg.dstPort = binary.BigEndian.Uint16(data[2:4])
+		seg.seq = 0
+		seg.flags = 0
+		seg.payload = data[8:]
+		if udpLen := int(binary.BigEndian.Uint16(data[4:6])); udpLen >= 8 && udpLen-8 < len(seg.payload) {
+			seg.payload = seg.payload[:udpLen-8]
+		}
+	default:
+		return errNotTCPOrUDP
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package pcapreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component"
+	analyzerpackage "github.com/Kindling-project/kindling/collector/pkg/component/analyzer"
+	"github.com/Kindling-project/kindling/collector/pkg/component/receiver"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"go.uber.org/zap"
+)
+
+const (
+	Pcap = "pcapreceiver"
+)
+
+var errStopped = errors.New("the replay is stopped")
+
+// PcapReceiver reads the packets captured by tcpdump or Wireshark, and turns the TCP streams
+// and the UDP datagrams into the network events of the probe. The protocol parsers and the RED
+// metrics of NetworkAnalyzer could then be applied to the historic captures.
+type PcapReceiver struct {
+	cfg             *Config
+	analyzerManager *analyzerpackage.Manager
+	telemetry       *component.TelemetryTools
+	files           []string
+	stopCh          chan struct{}
+	doneCh          chan struct{}
+	component.HealthReporter
+}
+
+// replayStats counts the packets and the events of a replay.
+type replayStats struct {
+	packets int
+	skipped int
+	events  int
+}
+
+func NewPcapReceiver(config interface{}, telemetry *component.TelemetryTools, analyzerManager *analyzerpackage.Manager) receiver.Receiver {
+	cfg, ok := config.(*Config)
+	if !ok {
+		telemetry.Logger.Sugar().Panicf("Cannot convert [%s] config", Pcap)
+	}
+	return &PcapReceiver{
+		cfg:             cfg,
+		analyzerManager: analyzerManager,
+		telemetry:       telemetry,
+		stopCh:          make(chan struct{}),
+		doneCh:          make(chan struct{}),
+	}
+}
+
+func (r *PcapReceiver) Start(ctx context.Context) error {
+	if err := r.cfg.validate(); err != nil {
+		return err
+	}
+	files, err := filepath.Glob(r.cfg.Files)
+	if err != nil {
+		return fmt.Errorf("invalid files pattern [%s]: %w", r.cfg.Files, err)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no files match the pattern [%s]", r.cfg.Files)
+	}
+	sort.Strings(files)
+	r.files = files
+	r.telemetry.Logger.Sugar().Infof("Start PcapReceiver to replay %d files in %s mode as %s: %v", len(files), r.cfg.ReplayMode, r.cfg.Role, files)
+	go r.replay()
+	r.SetHealth(component.StatusRunning, nil)
+	return nil
+}
+
+// Shutdown stops replaying the packets. The packets not replayed yet are discarded.
+func (r *PcapReceiver) Shutdown(ctx context.Context) error {
+	if r.Health().Status != component.StatusRunning {
+		return nil
+	}
+	defer r.SetHealth(component.StatusStopped, nil)
+	close(r.stopCh)
+	select {
+	case <-r.doneCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *PcapReceiver) replay() {
+	defer close(r.doneCh)
+	r.telemetry.Logger.Info("Replay started")
+	stats := &replayStats{}
+	// The connections are kept across the files, because tcpdump rotates the files without
+	// regard to the connections.
+	asm := newAssembler(r.cfg, func(ev *model.KindlingEvent) error {
+		stats.events++
+		return r.sendToNextConsumer(ev)
+	})
+	// The wall time and the timestamp of the first packet, used to keep the original intervals
+	// in realtime mode.
+	var startTime time.Time
+	var startTimestamp uint64
+	for _, file := range r.files {
+		err := r.replayFile(file, stats, func(seg *segment, timestamp uint64) error {
+			if r.cfg.ReplayMode == ReplayModeRealtime {
+				if startTime.IsZero() {
+					startTime = time.Now()
+					startTimestamp = timestamp
+				} else if timestamp > startTimestamp {
+					if !r.sleepUntil(startTime.Add(time.Duration(timestamp - startTimestamp))) {
+						return errStopped
+					}
+				}
+			}
+			return asm.process(seg, timestamp)
+		})
+		if err == errStopped {
+			r.telemetry.Logger.Sugar().Infof("Replay stopped after %d packets", stats.packets)
+			return
+		}
+		if err != nil {
+			r.telemetry.Logger.Warn("Error happened while replaying file "+file, zap.Error(err))
+		}
+	}
+	_ = asm.flush()
+	r.telemetry.Logger.Sugar().Infof("Replay finished, %d events are sent to the analyzers from %d packets, %d packets are skipped",
+		stats.events, stats.packets, stats.skipped)
+}
+
+// replayFile reads the packets from the file and calls process for each TCP or UDP segment.
+// The packets which are not TCP or UDP over IPv4 are skipped.
+func (r *PcapReceiver) replayFile(file string, stats *replayStats, process func(seg *segment, timestamp uint64) error) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	reader, err := newPacketReader(f)
+	if err != nil {
+		return err
+	}
+	var seg segment
+	for {
+		select {
+		case <-r.stopCh:
+			return errStopped
+		default:
+		}
+		p, err := reader.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		stats.packets++
+		if err = decodePacket(p, &seg); err != nil {
+			stats.skipped++
+			continue
+		}
+		if err = process(&seg, p.timestamp); err != nil {
+			return err
+		}
+	}
+}
+
+// sleepUntil returns false if the receiver is stopped before the time.
+func (r *PcapReceiver) sleepUntil(t time.Time) bool {
+	d := time.Until(t)
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-r.stopCh:
+		return false
+	}
+}
+
+func (r *PcapReceiver) sendToNextConsumer(evt *model.KindlingEvent) error {
+	for _, analyzer := range r.analyzerManager.GetConsumableAnalyzers(evt.Name) {
+		err := analyzer.ConsumeEvent(evt)
+		if err != nil {
+			r.telemetry.Logger.Warn("Error sending event to next consumer: ", zap.Error(err))
+		}
+	}
+	return nil
+}
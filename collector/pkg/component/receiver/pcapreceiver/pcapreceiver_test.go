@@ -0,0 +1,297 @@
+package pcapreceiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Kindling-project/kindling/collector/pkg/component"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer"
+	"github.com/Kindling-project/kindling/collector/pkg/component/analyzer/network"
+	"github.com/Kindling-project/kindling/collector/pkg/component/consumer"
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/Kindling-project/kindling/collector/pkg/model/constlabels"
+)
+
+type frame struct {
+	// offset is the time since the start of the capture.
+	offset time.Duration
+	data   []byte
+}
+
+var captureStart = time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+
+// ethernetFrame builds an Ethernet frame carrying an IPv4 packet. The checksums are left zero,
+// because they are not verified.
+func ethernetFrame(protocol uint8, src string, sport uint16, dst string, dport uint16, seq uint32, flags uint8, payload string) []byte {
+	var transport []byte
+	if protocol == ipProtocolTCP {
+		transport = make([]byte, 20)
+		binary.BigEndian.PutUint32(transport[4:8], seq)
+		transport[12] = 5 << 4
+		transport[13] = flags
+	} else {
+		transport = make([]byte, 8)
+		binary.BigEndian.PutUint16(transport[4:6], uint16(8+len(payload)))
+	}
+	binary.BigEndian.PutUint16(transport[0:2], sport)
+	binary.BigEndian.PutUint16(transport[2:4], dport)
+	transport = append(transport, payload...)
+
+	ip := make([]byte, 20)
+	ip[0] = 0x45
+	binary.BigEndian.PutUint16(ip[2:4], uint16(20+len(transport)))
+	ip[8] = 64
+	ip[9] = protocol
+	copy(ip[12:16], net.ParseIP(src).To4())
+	copy(ip[16:20], net.ParseIP(dst).To4())
+
+	ethernet := make([]byte, 14)
+	binary.BigEndian.PutUint16(ethernet[12:14], etherTypeIPv4)
+	return append(append(ethernet, ip...), transport...)
+}
+
+func dnsMessage(response bool) string {
+	msg := []byte{0x12, 0x34, 0x01, 0x00, 0, 1, 0, 0, 0, 0, 0, 0}
+	msg = append(msg, 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0, 0, 1, 0, 1)
+	if response {
+		msg[2], msg[3], msg[7] = 0x81, 0x80, 1
+		msg = append(msg, 0xc0, 0x0c, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4, 93, 184, 216, 34)
+	}
+	return string(msg)
+}
+
+// testFrames contains an HTTP connection with out-of-order and retransmitted segments, a Redis
+// connection whose handshake is not captured, and a DNS query.
+func testFrames() []frame {
+	const (
+		server = "10.0.0.1"
+		client = "10.0.0.2"
+	)
+	request := "GET /ok HTTP/1.1\r\nHost: test\r\n\r\n"
+	response := "HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n"
+	return []frame{
+		{0, ethernetFrame(ipProtocolTCP, client, 40000, server, 8080, 1000, tcpFlagSYN, "")},
+		{time.Millisecond, ethernetFrame(ipProtocolTCP, server, 8080, client, 40000, 5000, tcpFlagSYN|tcpFlagACK, "")},
+		{2 * time.Millisecond, ethernetFrame(ipProtocolTCP, client, 40000, server, 8080, 1001, tcpFlagACK, "")},
+		{3 * time.Millisecond, ethernetFrame(ipProtocolTCP, client, 40000, server, 8080, 1011, tcpFlagACK, request[10:])},
+		{4 * time.Millisecond, ethernetFrame(ipProtocolTCP, client, 40000, server, 8080, 1001, tcpFlagACK, request[:10])},
+		{5 * time.Millisecond, ethernetFrame(ipProtocolTCP, client, 40000, server, 8080, 1001, tcpFlagACK, request[:10])},
+		{10 * time.Millisecond, ethernetFrame(ipProtocolTCP, server, 8080, client, 40000, 5001, tcpFlagACK, response)},
+		{11 * time.Millisecond, ethernetFrame(ipProtocolTCP, client, 40000, server, 8080, 1001+uint32(len(request)), tcpFlagACK|tcpFlagFIN, "")},
+		{12 * time.Millisecond, ethernetFrame(ipProtocolTCP, server, 8080, client, 40000, 5001+uint32(len(response)), tcpFlagACK|tcpFlagFIN, "")},
+
+		{20 * time.Millisecond, ethernetFrame(ipProtocolTCP, "10.0.0.3", 50000, server, 6379, 7000, tcpFlagACK, "*2\r\n$3\r\nGET\r\n$3\r\nkey\r\n")},
+		{21 * time.Millisecond, ethernetFrame(ipProtocolTCP, server, 6379, "10.0.0.3", 50000, 9000, tcpFlagACK, "$-1\r\n")},
+
+		{30 * time.Millisecond, ethernetFrame(ipProtocolUDP, client, 53000, "10.0.0.53", 53, 0, 0, dnsMessage(false))},
+		{31 * time.Millisecond, ethernetFrame(ipProtocolUDP, "10.0.0.53", 53, client, 53000, 0, 0, dnsMessage(true))},
+	}
+}
+
+func writePcap(frames []frame) []byte {
+	buf := &bytes.Buffer{}
+	header := make([]byte, 24)
+	binary.LittleEndian.PutUint32(header[0:4], pcapMagicMicroseconds)
+	binary.LittleEndian.PutUint16(header[4:6], 2)
+	binary.LittleEndian.PutUint16(header[6:8], 4)
+	binary.LittleEndian.PutUint32(header[16:20], 65535)
+	binary.LittleEndian.PutUint32(header[20:24], linkTypeEthernet)
+	buf.Write(header)
+	for _, f := range frames {
+		ts := captureStart.Add(f.offset)
+		record := make([]byte, 16)
+		binary.LittleEndian.PutUint32(record[0:4], uint32(ts.Unix()))
+		binary.LittleEndian.PutUint32(record[4:8], uint32(ts.Nanosecond()/1000))
+		binary.LittleEndian.PutUint32(record[8:12], uint32(len(f.data)))
+		binary.LittleEndian.PutUint32(record[12:16], uint32(len(f.data)))
+		buf.Write(record)
+		buf.Write(f.data)
+	}
+	return buf.Bytes()
+}
+
+// writePcapng writes the frames in big endian with the timestamps in nanoseconds.
+func writePcapng(frames []frame) []byte {
+	buf := &bytes.Buffer{}
+	writeBlock := func(blockType uint32, body []byte) {
+		for len(body)%4 != 0 {
+			body = append(body, 0)
+		}
+		length := uint32(12 + len(body))
+		_ = binary.Write(buf, binary.BigEndian, blockType)
+		_ = binary.Write(buf, binary.BigEndian, length)
+		buf.Write(body)
+		_ = binary.Write(buf, binary.BigEndian, length)
+	}
+	// The section header with an unknown section length
+	writeBlock(pcapngSectionHeader, []byte{0x1a, 0x2b, 0x3c, 0x4d, 0, 1, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	// The interface description with if_tsresol = 9
+	writeBlock(pcapngInterfaceDescription, []byte{0, linkTypeEthernet, 0, 0, 0, 0, 0xff, 0xff, 0, 9, 0, 1, 9, 0, 0, 0, 0, 0, 0, 0})
+	// A block of an unknown type is skipped.
+	writeBlock(0x0bad, []byte{1, 2, 3, 4})
+	for _, f := range frames {
+		ts := uint64(captureStart.Add(f.offset).UnixNano())
+		body := make([]byte, 20)
+		binary.BigEndian.PutUint32(body[4:8], uint32(ts>>32))
+		binary.BigEndian.PutUint32(body[8:12], uint32(ts))
+		binary.BigEndian.PutUint32(body[12:16], uint32(len(f.data)))
+		binary.BigEndian.PutUint32(body[16:20], uint32(len(f.data)))
+		writeBlock(pcapngEnhancedPacket, append(body, f.data...))
+	}
+	return buf.Bytes()
+}
+
+type pairRecord struct {
+	protocol string
+	url      string
+	domain   string
+	srcIp    string
+	dstPort  int64
+	isServer bool
+}
+
+// recordConsumer records the labels only, because the data groups are reused by the analyzer.
+type recordConsumer struct {
+	mutex sync.Mutex
+	pairs []pairRecord
+}
+
+func (c *recordConsumer) Consume(dataGroup *model.DataGroup) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	labels := dataGroup.Labels
+	c.pairs = append(c.pairs, pairRecord{
+		protocol: labels.GetStringValue(constlabels.Protocol),
+		url:      labels.GetStringValue(constlabels.HttpUrl),
+		domain:   labels.GetStringValue(constlabels.DnsDomain),
+		srcIp:    labels.GetStringValue(constlabels.SrcIp),
+		dstPort:  labels.GetIntValue(constlabels.DstPort),
+		isServer: labels.GetBoolValue(constlabels.IsServer),
+	})
+	return nil
+}
+
+// replay replays the files through the network analyzer and returns the pairs it found.
+func replay(t *testing.T, cfg *Config) []pairRecord {
+	analyzerCfg := network.NewDefaultConfig()
+	analyzerCfg.EnableConntrack = false
+	recorder := &recordConsumer{}
+	telemetry := component.NewDefaultTelemetryTools()
+	networkAnalyzer := network.NewNetworkAnalyzer(analyzerCfg, telemetry, []consumer.Consumer{recorder})
+	if err := networkAnalyzer.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	manager, err := analyzer.NewManager(networkAnalyzer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewPcapReceiver(cfg, telemetry, manager).(*PcapReceiver)
+	if err = r.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	<-r.doneCh
+	if err = r.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err = networkAnalyzer.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(recorder.pairs, func(i, j int) bool { return recorder.pairs[i].protocol < recorder.pairs[j].protocol })
+	return recorder.pairs
+}
+
+func TestReplay(t *testing.T) {
+	frames := testFrames()
+	tests := []struct {
+		name  string
+		files map[string][]byte
+		role  string
+	}{
+		{name: "pcap", files: map[string][]byte{"capture.pcap": writePcap(frames)}, role: RoleServer},
+		{name: "pcapng", files: map[string][]byte{"capture.pcapng": writePcapng(frames)}, role: RoleServer},
+		{name: "client", files: map[string][]byte{"capture.pcap": writePcap(frames)}, role: RoleClient},
+		// The Redis connection spans the rotated files.
+		{name: "rotated", files: map[string][]byte{"capture.pcap0": writePcap(frames[:10]), "capture.pcap1": writePcapng(frames[10:])}, role: RoleServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			cfg := NewDefaultConfig()
+			cfg.Files = filepath.Join(dir, "capture.*")
+			cfg.Role = tt.role
+			pairs := replay(t, cfg)
+			isServer := tt.role == RoleServer
+			expected := []pairRecord{
+				{protocol: "dns", domain: "example.com.", srcIp: "10.0.0.2", dstPort: 53, isServer: isServer},
+				{protocol: "http", url: "/ok", srcIp: "10.0.0.2", dstPort: 8080, isServer: isServer},
+				{protocol: "redis", srcIp: "10.0.0.3", dstPort: 6379, isServer: isServer},
+			}
+			if len(pairs) != len(expected) {
+				t.Fatalf("Expected %d pairs, but get %d: %v", len(expected), len(pairs), pairs)
+			}
+			for i := range expected {
+				if pairs[i] != expected[i] {
+					t.Errorf("Expected %+v, but get %+v", expected[i], pairs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAssembleMessages(t *testing.T) {
+	var events []*model.KindlingEvent
+	cfg := NewDefaultConfig()
+	cfg.MaxDataSize = 16
+	asm := newAssembler(cfg, func(ev *model.KindlingEvent) error {
+		events = append(events, ev)
+		return nil
+	})
+	p := &packet{linkType: linkTypeEthernet}
+	var seg segment
+	for _, f := range testFrames()[:9] {
+		p.data = f.data
+		p.timestamp = uint64(f.offset)
+		if err := decodePacket(p, &seg); err != nil {
+			t.Fatal(err)
+		}
+		if err := asm.process(&seg, p.timestamp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(asm.conns) != 0 {
+		t.Errorf("Expected the closed connection to be forgotten, but %d connections are left", len(asm.conns))
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected a request and a response, but get %d events", len(events))
+	}
+	request, response := events[0], events[1]
+	if request.Name != "read" || response.Name != "write" {
+		t.Errorf("Expected read and write, but get %s and %s", request.Name, response.Name)
+	}
+	if string(request.GetData()) != "GET /ok HTTP/1.1" {
+		t.Errorf("Expected the request truncated to 16 bytes, but get %q", request.GetData())
+	}
+	if request.GetResVal() != 32 {
+		t.Errorf("Expected the size of the request 32, but get %d", request.GetResVal())
+	}
+	// The request started with the segment captured at 3ms and ended at 4ms.
+	if request.Timestamp != uint64(4*time.Millisecond) || request.GetLatency() != uint64(time.Millisecond) {
+		t.Errorf("Expected the request ended at 4ms in 1ms, but get %d in %d", request.Timestamp, request.GetLatency())
+	}
+	if request.GetSport() != 40000 || request.GetDport() != 8080 || request.GetFd() != response.GetFd() {
+		t.Errorf("Unexpected fd info %+v and %+v", request.Ctx.FdInfo, response.Ctx.FdInfo)
+	}
+}
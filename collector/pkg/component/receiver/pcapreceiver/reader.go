@@ -0,0 +1,252 @@
+package pcapreceiver
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	pcapMagicMicroseconds = 0xa1b2c3d4
+	pcapMagicNanoseconds  = 0xa1b23c4d
+	pcapngSectionHeader   = 0x0a0d0d0a
+	pcapngByteOrderMagic  = 0x1a2b3c4d
+
+	pcapngInterfaceDescription = 0x00000001
+	pcapngObsoletePacket       = 0x00000002
+	pcapngEnhancedPacket       = 0x00000006
+
+	// maxBlockSize limits the memory allocated for a corrupted length.
+	maxBlockSize = 16 << 20
+)
+
+// packet is a captured frame with its timestamp in nanoseconds.
+type packet struct {
+	timestamp uint64
+	linkType  uint32
+	data      []byte
+}
+
+// packetReader reads the packets of a capture file.
+type packetReader interface {
+	// next returns io.EOF when there are no more packets. The returned data is only valid until
+	// the next call.
+	next() (*packet, error)
+}
+
+// newPacketReader detects the format of the file by its magic number, and returns the reader
+// of pcap or pcapng.
+func newPacketReader(r io.Reader) (packetReader, error) {
+	br := bufio.NewReaderSize(r, 64*1024)
+	magic, err := br.Peek(4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the magic number: %w", err)
+	}
+	switch {
+	case binary.LittleEndian.Uint32(magic) == pcapngSectionHeader:
+		return &pcapngReader{reader: br}, nil
+	case binary.LittleEndian.Uint32(magic) == pcapMagicMicroseconds || binary.LittleEndian.Uint32(magic) == pcapMagicNanoseconds:
+		return newPcapReader(br, binary.LittleEndian)
+	case binary.BigEndian.Uint32(magic) == pcapMagicMicroseconds || binary.BigEndian.Uint32(magic) == pcapMagicNanoseconds:
+		return newPcapReader(br, binary.BigEndian)
+	default:
+		return nil, errors.New("unknown file format, only pcap and pcapng are supported")
+	}
+}
+
+// pcapReader reads the classic libpcap format.
+type pcapReader struct {
+	reader    io.Reader
+	byteOrder binary.ByteOrder
+	// nanoseconds is true if the fraction of the timestamps is in nanoseconds, otherwise in microseconds.
+	nanoseconds bool
+	linkType    uint32
+	header      [16]byte
+	buf         []byte
+}
+
+func newPcapReader(r io.Reader, byteOrder binary.ByteOrder) (*pcapReader, error) {
+	var header [24]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, fmt.Errorf("failed to read the pcap header: %w", err)
+	}
+	return &pcapReader{
+		reader:      r,
+		byteOrder:   byteOrder,
+		nanoseconds: byteOrder.Uint32(header[0:4]) == pcapMagicNanoseconds,
+		linkType:    byteOrder.Uint32(header[20:24]) & 0x0fffffff,
+	}, nil
+}
+
+func (r *pcapReader) next() (*packet, error) {
+	if _, err := io.ReadFull(r.reader, r.header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("truncated packet header: %w", err)
+		}
+		return nil, err
+	}
+	seconds := uint64(r.byteOrder.Uint32(r.header[0:4]))
+	fraction := uint64(r.byteOrder.Uint32(r.header[4:8]))
+	capturedLen := r.byteOrder.Uint32(r.header[8:12])
+	if capturedLen > maxBlockSize {
+		return nil, fmt.Errorf("packet size %d exceeds the limit", capturedLen)
+	}
+	if !r.nanoseconds {
+		fraction *= 1000
+	}
+	if _, err := io.ReadFull(r.reader, r.growBuf(int(capturedLen))); err != nil {
+		return nil, fmt.Errorf("truncated packet: %w", err)
+	}
+	return &packet{timestamp: seconds*1e9 + fraction, linkType: r.linkType, data: r.buf}, nil
+}
+
+func (r *pcapReader) growBuf(n int) []byte {
+	if cap(r.buf) < n {
+		r.buf = make([]byte, n)
+	}
+	r.buf = r.buf[:n]
+	return r.buf
+}
+
+// pcapngInterface is the link type and the timestamp resolution of an interface in pcapng.
+type pcapngInterface struct {
+	linkType uint32
+	// unitsPerSecond is the number of the timestamp units in a second.
+	unitsPerSecond uint64
+}
+
+// pcapngReader reads the pcapng format. Only the packets in the enhanced packet blocks and the
+// obsolete packet blocks are read, because the simple packet blocks don't have timestamps.
+type pcapngReader struct {
+	reader     *bufio.Reader
+	byteOrder  binary.ByteOrder
+	interfaces []pcapngInterface
+	header     [8]byte
+	buf        []byte
+}
+
+func (r *pcapngReader) next() (*packet, error) {
+	for {
+		blockType, body, err := r.readBlock()
+		if err != nil {
+			return nil, err
+		}
+		switch blockType {
+		case pcapngInterfaceDescription:
+			if err = r.readInterface(body); err != nil {
+				return nil, err
+			}
+		case pcapngEnhancedPacket, pcapngObsoletePacket:
+			if len(body) < 20 {
+				return nil, errors.New("truncated packet block")
+			}
+			var interfaceId uint32
+			if blockType == pcapngEnhancedPacket {
+				interfaceId = r.byteOrder.Uint32(body[0:4])
+			} else {
+				interfaceId = uint32(r.byteOrder.Uint16(body[0:2]))
+			}
+			if int(interfaceId) >= len(r.interfaces) {
+				return nil, fmt.Errorf("unknown interface %d", interfaceId)
+			}
+			iface := r.interfaces[interfaceId]
+			units := uint64(r.byteOrder.Uint32(body[4:8]))<<32 | uint64(r.byteOrder.Uint32(body[8:12]))
+			capturedLen := r.byteOrder.Uint32(body[12:16])
+			if uint64(capturedLen) > uint64(len(body)-20) {
+				return nil, errors.New("truncated packet data")
+			}
+			return &packet{
+				timestamp: units/iface.unitsPerSecond*1e9 + units%iface.unitsPerSecond*1e9/iface.unitsPerSecond,
+				linkType:  iface.linkType,
+				data:      body[20 : 20+capturedLen],
+			}, nil
+		}
+	}
+}
+
+// readBlock returns the type and the body of the next block. The section header blocks are
+// handled here, because they determine the byte order of the blocks following them.
+func (r *pcapngReader) readBlock() (uint32, []byte, error) {
+	for {
+		if _, err := io.ReadFull(r.reader, r.header[:]); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				return 0, nil, fmt.Errorf("truncated block header: %w", err)
+			}
+			return 0, nil, err
+		}
+		// The type of the section header block is a palindrome, so it is read in any byte order.
+		blockType := binary.LittleEndian.Uint32(r.header[0:4])
+		if blockType == pcapngSectionHeader {
+			magic, err := r.reader.Peek(4)
+			if err != nil {
+				return 0, nil, fmt.Errorf("truncated section header: %w", err)
+			}
+			if binary.LittleEndian.Uint32(magic) == pcapngByteOrderMagic {
+				r.byteOrder = binary.LittleEndian
+			} else if binary.BigEndian.Uint32(magic) == pcapngByteOrderMagic {
+				r.byteOrder = binary.BigEndian
+			} else {
+				return 0, nil, errors.New("invalid byte order magic in the section header")
+			}
+			// The interfaces are numbered in each section.
+			r.interfaces = r.interfaces[:0]
+		} else if r.byteOrder == nil {
+			return 0, nil, errors.New("the file doesn't start with a section header block")
+		} else {
+			blockType = r.byteOrder.Uint32(r.header[0:4])
+		}
+		totalLen := r.byteOrder.Uint32(r.header[4:8])
+		if totalLen < 12 || totalLen > maxBlockSize || totalLen%4 != 0 {
+			return 0, nil, fmt.Errorf("invalid block length %d", totalLen)
+		}
+		// The body is followed by the total length again.
+		if cap(r.buf) < int(totalLen-8) {
+			r.buf = make([]byte, totalLen-8)
+		}
+		r.buf = r.buf[:totalLen-8]
+		if _, err := io.ReadFull(r.reader, r.buf); err != nil {
+			return 0, nil, fmt.Errorf("truncated block: %w", err)
+		}
+		if blockType != pcapngSectionHeader {
+			return blockType, r.buf[:totalLen-12], nil
+		}
+	}
+}
+
+func (r *pcapngReader) readInterface(body []byte) error {
+	if len(body) < 8 {
+		return errors.New("truncated interface description block")
+	}
+	iface := pcapngInterface{
+		linkType:       uint32(r.byteOrder.Uint16(body[0:2])),
+		unitsPerSecond: 1e6,
+	}
+	// Look for the option if_tsresol
+	options := body[8:]
+	for len(options) >= 4 {
+		code := r.byteOrder.Uint16(options[0:2])
+		length := int(r.byteOrder.Uint16(options[2:4]))
+		if code == 0 || len(options) < 4+length {
+			break
+		}
+		if code == 9 && length >= 1 {
+			resolution := options[4]
+			exponent := float64(resolution & 0x7f)
+			if resolution&0x80 == 0 {
+				iface.unitsPerSecond = uint64(math.Pow(10, exponent))
+			} else {
+				iface.unitsPerSecond = uint64(math.Pow(2, exponent))
+			}
+			if iface.unitsPerSecond == 0 {
+				return fmt.Errorf("invalid timestamp resolution %d", resolution)
+			}
+		}
+		// The options are padded to 32 bits.
+		options = options[4+(length+3)/4*4:]
+	}
+	r.interfaces = append(r.interfaces, iface)
+	return nil
+}
@@ -1,9 +1,10 @@
 package receiver
 
+import "github.com/Kindling-project/kindling/collector/pkg/component"
+
+// Receiver starts to receive events when it is started, and stops receiving events when
+// it is shut down.
+// Note receiver should not shutdown other components though it holds a reference
 type Receiver interface {
-	// Start initializes the receiver and start to receive events.
-	Start() error
-	// Shutdown closes the receiver and stops receiving events.
-	// Note receiver should not shutdown other components though it holds a reference
-	Shutdown() error
+	component.Component
 }
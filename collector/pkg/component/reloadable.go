@@ -0,0 +1,17 @@
+package component
+
+import "fmt"
+
+// Reloadable is implemented by the components that are able to apply a new configuration
+// at runtime without being rebuilt.
+type Reloadable interface {
+	// ApplyConfig applies the new configuration to the component. If any option that can't
+	// be changed at runtime is modified, an error should be returned before anything is changed.
+	ApplyConfig(config interface{}) error
+}
+
+// NewNotReloadableError returns the error used when an option of the component can't be
+// changed at runtime.
+func NewNotReloadableError(componentName string, option string) error {
+	return fmt.Errorf("the option [%s] of [%s] can't be changed at runtime, please restart the agent to apply it", option, componentName)
+}
@@ -0,0 +1,54 @@
+package eventqueue
+
+import "fmt"
+
+const (
+	// PolicyBlock makes the producer wait until there is room in the queue.
+	PolicyBlock = "block"
+	// PolicyDropNewest discards the events arriving when the queue is full.
+	PolicyDropNewest = "drop_newest"
+	// PolicyDropOldest discards the events at the head of the queue to make room for the new ones.
+	PolicyDropOldest = "drop_oldest"
+	// PolicySample keeps one out of every N events of a name arriving when the queue is full,
+	// and waits for room for the kept ones. N is configured in SampleRates.
+	PolicySample = "sample"
+
+	DefaultSize = 300000
+)
+
+type Config struct {
+	// Size is the maximum number of the events in the queue.
+	Size int `mapstructure:"size"`
+	// OverflowPolicy is what to do when the queue is full. It is one of block, drop_newest,
+	// drop_oldest and sample.
+	OverflowPolicy string `mapstructure:"overflow_policy"`
+	// SampleRates maps the event names like "read" to N, meaning one out of every N events is kept
+	// when the queue is full. The events not listed are all kept, and 0 means dropping all of them.
+	// It only works with the policy sample.
+	SampleRates map[string]int `mapstructure:"sample_rates"`
+}
+
+func NewDefaultConfig() Config {
+	return Config{
+		Size:           DefaultSize,
+		OverflowPolicy: PolicyBlock,
+	}
+}
+
+func (c *Config) Validate() error {
+	if c.Size <= 0 {
+		return fmt.Errorf("the queue size must be positive, got %d", c.Size)
+	}
+	switch c.OverflowPolicy {
+	case PolicyBlock, PolicyDropNewest, PolicyDropOldest, PolicySample:
+	default:
+		return fmt.Errorf("unknown overflow policy [%s], must be one of %s, %s, %s and %s",
+			c.OverflowPolicy, PolicyBlock, PolicyDropNewest, PolicyDropOldest, PolicySample)
+	}
+	for name, rate := range c.SampleRates {
+		if rate < 0 {
+			return fmt.Errorf("the sample rate of [%s] must not be negative, got %d", name, rate)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package eventqueue
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+)
+
+// Queue is a bounded queue of events between the goroutine producing them and the goroutine
+// consuming them. What happens when the queue is full is decided by the overflow policy, and
+// the events dropped are counted by their names.
+type Queue struct {
+	policy      string
+	sampleRates map[string]int
+	ch          chan *model.KindlingEvent
+	// blocked is the number of the events which found the queue full and waited for room.
+	blocked int64
+
+	mutex   sync.Mutex
+	dropped map[string]int64
+	// overflowed counts the events of each name arriving when the queue is full, for sampling.
+	overflowed map[string]int
+}
+
+// New creates a queue. The configuration is expected to be validated already.
+func New(cfg Config) *Queue {
+	sampleRates := make(map[string]int, len(cfg.SampleRates))
+	for name, rate := range cfg.SampleRates {
+		sampleRates[name] = rate
+	}
+	return &Queue{
+		policy:      cfg.OverflowPolicy,
+		sampleRates: sampleRates,
+		ch:          make(chan *model.KindlingEvent, cfg.Size),
+		dropped:     make(map[string]int64),
+		overflowed:  make(map[string]int),
+	}
+}
+
+// Push puts the event into the queue, and returns false if the event is dropped.
+func (q *Queue) Push(ev *model.KindlingEvent) bool {
+	select {
+	case q.ch <- ev:
+		return true
+	default:
+	}
+	switch q.policy {
+	case PolicyDropNewest:
+		q.drop(ev.Name)
+		return false
+	case PolicyDropOldest:
+		for {
+			select {
+			case q.ch <- ev:
+				return true
+			default:
+			}
+			select {
+			case oldest := <-q.ch:
+				q.drop(oldest.Name)
+			default:
+			}
+		}
+	case PolicySample:
+		if !q.sample(ev.Name) {
+			q.drop(ev.Name)
+			return false
+		}
+	}
+	atomic.AddInt64(&q.blocked, 1)
+	q.ch <- ev
+	return true
+}
+
+// sample returns true if the event arriving when the queue is full should be kept.
+func (q *Queue) sample(name string) bool {
+	rate, ok := q.sampleRates[name]
+	if !ok {
+		return true
+	}
+	if rate == 0 {
+		return false
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	count := q.overflowed[name]
+	q.overflowed[name] = count + 1
+	return count%rate == 0
+}
+
+func (q *Queue) drop(name string) {
+	q.mutex.Lock()
+	q.dropped[name]++
+	q.mutex.Unlock()
+}
+
+// Events returns the channel to receive the events from.
+func (q *Queue) Events() <-chan *model.KindlingEvent {
+	return q.ch
+}
+
+// Close tells the consumer there are no more events after the ones left. It must be called by
+// the producer after the last Push.
+func (q *Queue) Close() {
+	close(q.ch)
+}
+
+// Len returns the number of the events in the queue.
+func (q *Queue) Len() int {
+	return len(q.ch)
+}
+
+// Cap returns the maximum number of the events in the queue.
+func (q *Queue) Cap() int {
+	return cap(q.ch)
+}
+
+// Blocked returns the number of the events which waited for room in the queue.
+func (q *Queue) Blocked() int64 {
+	return atomic.LoadInt64(&q.blocked)
+}
+
+// Dropped returns the number of the events dropped by their names.
+func (q *Queue) Dropped() map[string]int64 {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	ret := make(map[string]int64, len(q.dropped))
+	for name, count := range q.dropped {
+		ret[name] = count
+	}
+	return ret
+}
@@ -0,0 +1,115 @@
+package eventqueue
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+)
+
+func newEvent(name string, timestamp uint64) *model.KindlingEvent {
+	return &model.KindlingEvent{Name: name, Timestamp: timestamp}
+}
+
+// drain returns the timestamps of the events left in the queue.
+func drain(q *Queue) []uint64 {
+	q.Close()
+	var ret []uint64
+	for ev := range q.Events() {
+		ret = append(ret, ev.Timestamp)
+	}
+	return ret
+}
+
+func TestDropNewest(t *testing.T) {
+	q := New(Config{Size: 2, OverflowPolicy: PolicyDropNewest})
+	for i := uint64(1); i <= 5; i++ {
+		q.Push(newEvent("read", i))
+	}
+	if got := drain(q); len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("Expected the first 2 events kept, but get %v", got)
+	}
+	if dropped := q.Dropped()["read"]; dropped != 3 {
+		t.Errorf("Expected 3 events dropped, but get %d", dropped)
+	}
+}
+
+func TestDropOldest(t *testing.T) {
+	q := New(Config{Size: 2, OverflowPolicy: PolicyDropOldest})
+	for i := uint64(1); i <= 5; i++ {
+		q.Push(newEvent("write", i))
+	}
+	if got := drain(q); len(got) != 2 || got[0] != 4 || got[1] != 5 {
+		t.Errorf("Expected the last 2 events kept, but get %v", got)
+	}
+	if dropped := q.Dropped()["write"]; dropped != 3 {
+		t.Errorf("Expected 3 events dropped, but get %d", dropped)
+	}
+}
+
+func TestSample(t *testing.T) {
+	q := New(Config{Size: 1, OverflowPolicy: PolicySample, SampleRates: map[string]int{"read": 3, "write": 0}})
+	// One out of every 3 reads arriving when the queue is full is kept.
+	var kept []bool
+	for i := 0; i < 6; i++ {
+		kept = append(kept, q.sample("read"))
+	}
+	if fmt.Sprint(kept) != "[true false false true false false]" {
+		t.Errorf("Expected one out of 3 reads kept, but get %v", kept)
+	}
+
+	q.Push(newEvent("read", 0))
+	if q.Push(newEvent("write", 1)) {
+		t.Errorf("Expected the write dropped with the rate 0")
+	}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-q.Events()
+	}()
+	if !q.Push(newEvent("tcp_close", 2)) {
+		t.Errorf("Expected the event without a sample rate kept")
+	}
+	if q.Blocked() != 1 {
+		t.Errorf("Expected 1 blocked event, but get %d", q.Blocked())
+	}
+}
+
+func TestBlock(t *testing.T) {
+	q := New(Config{Size: 1, OverflowPolicy: PolicyBlock})
+	q.Push(newEvent("read", 1))
+	pushed := make(chan struct{})
+	go func() {
+		q.Push(newEvent("read", 2))
+		close(pushed)
+	}()
+	select {
+	case <-pushed:
+		t.Fatal("Expected the producer blocked while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+	<-q.Events()
+	<-pushed
+	if got := drain(q); len(got) != 1 || got[0] != 2 {
+		t.Errorf("Expected the second event left, but get %v", got)
+	}
+	if q.Blocked() != 1 || len(q.Dropped()) != 0 {
+		t.Errorf("Expected 1 blocked event and none dropped, but get %d and %v", q.Blocked(), q.Dropped())
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := NewDefaultConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected the default configuration valid, but get %v", err)
+	}
+	for _, cfg := range []Config{
+		{Size: 0, OverflowPolicy: PolicyBlock},
+		{Size: 1, OverflowPolicy: "unknown"},
+		{Size: 1, OverflowPolicy: PolicySample, SampleRates: map[string]int{"read": -1}},
+	} {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Expected an error for %+v", cfg)
+		}
+	}
+}
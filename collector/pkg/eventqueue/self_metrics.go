@@ -0,0 +1,31 @@
+package eventqueue
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// RegisterSelfMetrics observes the depth, the capacity, the blocked events and the dropped
+// events of the queue as the metrics named with the prefix, e.g. "kindling_telemetry_cgoreceiver".
+func RegisterSelfMetrics(meter metric.MeterMust, prefix string, queue *Queue) {
+	meter.NewInt64GaugeObserver(prefix+"_channel_size",
+		func(ctx context.Context, result metric.Int64ObserverResult) {
+			result.Observe(int64(queue.Len()))
+		})
+	meter.NewInt64GaugeObserver(prefix+"_channel_capacity",
+		func(ctx context.Context, result metric.Int64ObserverResult) {
+			result.Observe(int64(queue.Cap()))
+		})
+	meter.NewInt64CounterObserver(prefix+"_blocked_events_total",
+		func(ctx context.Context, result metric.Int64ObserverResult) {
+			result.Observe(queue.Blocked())
+		})
+	meter.NewInt64CounterObserver(prefix+"_dropped_events_total",
+		func(ctx context.Context, result metric.Int64ObserverResult) {
+			for name, count := range queue.Dropped() {
+				result.Observe(count, attribute.String("name", name))
+			}
+		})
+}
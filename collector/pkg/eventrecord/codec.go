@@ -0,0 +1,245 @@
+package eventrecord
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+)
+
+// formatVersion is written at the beginning of each record, so that the format could be changed
+// later without breaking the files recorded before.
+const formatVersion byte = 1
+
+// maxUserAttributes is the length of model.KindlingEvent.UserAttributes.
+const maxUserAttributes = len(model.KindlingEvent{}.UserAttributes)
+
+var errShortRecord = errors.New("record is too short")
+
+// Marshal appends the encoding of the event to buf and returns the extended buffer.
+//
+// The integers are encoded as varints and the strings and bytes are prefixed with their lengths,
+// so a record is usually not much larger than the data buffer it carries. Only the first
+// ParamsNumber user attributes are encoded.
+func Marshal(buf []byte, ev *model.KindlingEvent) []byte {
+	buf = append(buf, formatVersion)
+	buf = appendVarint(buf, int64(ev.Source))
+	buf = appendUvarint(buf, ev.Timestamp)
+	buf = appendString(buf, ev.Name)
+	buf = appendVarint(buf, int64(ev.Category))
+
+	thread := &ev.Ctx.ThreadInfo
+	buf = appendUvarint(buf, uint64(thread.Pid))
+	buf = appendUvarint(buf, uint64(thread.Tid))
+	buf = appendUvarint(buf, uint64(thread.Uid))
+	buf = appendUvarint(buf, uint64(thread.Gid))
+	buf = appendString(buf, thread.Comm)
+	buf = appendString(buf, thread.ContainerId)
+	buf = appendString(buf, thread.ContainerName)
+
+	fd := &ev.Ctx.FdInfo
+	buf = appendVarint(buf, int64(fd.Num))
+	buf = appendVarint(buf, int64(fd.TypeFd))
+	buf = appendString(buf, fd.Filename)
+	buf = appendString(buf, fd.Directory)
+	buf = appendVarint(buf, int64(fd.Protocol))
+	if fd.Role {
+		buf = append(buf, 1)
+	} else {
+		buf = append(buf, 0)
+	}
+	buf = appendUint32s(buf, fd.Sip)
+	buf = appendUint32s(buf, fd.Dip)
+	buf = appendUvarint(buf, uint64(fd.Sport))
+	buf = appendUvarint(buf, uint64(fd.Dport))
+	buf = appendUvarint(buf, fd.Source)
+	buf = appendUvarint(buf, fd.Destination)
+
+	paramsNumber := int(ev.ParamsNumber)
+	if paramsNumber > maxUserAttributes {
+		paramsNumber = maxUserAttributes
+	}
+	buf = appendUvarint(buf, uint64(paramsNumber))
+	for i := 0; i < paramsNumber; i++ {
+		attr := &ev.UserAttributes[i]
+		buf = appendString(buf, attr.Key)
+		buf = appendVarint(buf, int64(attr.ValueType))
+		buf = appendUvarint(buf, uint64(len(attr.Value)))
+		buf = append(buf, attr.Value...)
+	}
+	return buf
+}
+
+// Unmarshal decodes a record encoded by Marshal. The returned event doesn't reference data.
+func Unmarshal(data []byte) (*model.KindlingEvent, error) {
+	if len(data) == 0 {
+		return nil, errShortRecord
+	}
+	if data[0] != formatVersion {
+		return nil, fmt.Errorf("unsupported record version %d", data[0])
+	}
+	d := &decoder{data: data[1:]}
+	ev := new(model.KindlingEvent)
+	ev.Source = model.Source(d.varint())
+	ev.Timestamp = d.uvarint()
+	ev.Name = d.string()
+	ev.Category = model.Category(d.varint())
+
+	thread := &ev.Ctx.ThreadInfo
+	thread.Pid = uint32(d.uvarint())
+	thread.Tid = uint32(d.uvarint())
+	thread.Uid = uint32(d.uvarint())
+	thread.Gid = uint32(d.uvarint())
+	thread.Comm = d.string()
+	thread.ContainerId = d.string()
+	thread.ContainerName = d.string()
+
+	fd := &ev.Ctx.FdInfo
+	fd.Num = int32(d.varint())
+	fd.TypeFd = model.FDType(d.varint())
+	fd.Filename = d.string()
+	fd.Directory = d.string()
+	fd.Protocol = model.L4Proto(d.varint())
+	fd.Role = d.byte() != 0
+	fd.Sip = d.uint32s()
+	fd.Dip = d.uint32s()
+	fd.Sport = uint32(d.uvarint())
+	fd.Dport = uint32(d.uvarint())
+	fd.Source = d.uvarint()
+	fd.Destination = d.uvarint()
+
+	paramsNumber := d.uvarint()
+	if d.err == nil && paramsNumber > uint64(maxUserAttributes) {
+		return nil, fmt.Errorf("too many user attributes: %d", paramsNumber)
+	}
+	ev.ParamsNumber = uint16(paramsNumber)
+	for i := 0; i < int(ev.ParamsNumber); i++ {
+		attr := &ev.UserAttributes[i]
+		attr.Key = d.string()
+		attr.ValueType = model.ValueType(d.varint())
+		attr.Value = d.bytes()
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	if len(d.data) != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after the record", len(d.data))
+	}
+	return ev, nil
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = appendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+func appendUint32s(buf []byte, values []uint32) []byte {
+	buf = appendUvarint(buf, uint64(len(values)))
+	for _, v := range values {
+		buf = appendUvarint(buf, uint64(v))
+	}
+	return buf
+}
+
+// decoder reads the fields of a record in order. Once an error happens, the following reads
+// return zero values and the first error is kept.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = errShortRecord
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = errShortRecord
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *decoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.data) == 0 {
+		d.err = errShortRecord
+		return 0
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b
+}
+
+func (d *decoder) next(n uint64) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if uint64(len(d.data)) < n {
+		d.err = errShortRecord
+		return nil
+	}
+	ret := d.data[:n]
+	d.data = d.data[n:]
+	return ret
+}
+
+func (d *decoder) string() string {
+	return string(d.next(d.uvarint()))
+}
+
+func (d *decoder) bytes() []byte {
+	b := d.next(d.uvarint())
+	if len(b) == 0 {
+		return nil
+	}
+	ret := make([]byte, len(b))
+	copy(ret, b)
+	return ret
+}
+
+func (d *decoder) uint32s() []uint32 {
+	n := d.uvarint()
+	if d.err != nil || n == 0 {
+		return nil
+	}
+	// Each value takes at least one byte, so a corrupted length can't allocate too much memory.
+	if n > uint64(len(d.data)) {
+		d.err = errShortRecord
+		return nil
+	}
+	ret := make([]uint32, n)
+	for i := range ret {
+		ret[i] = uint32(d.uvarint())
+	}
+	return ret
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(scratch[:], v)
+	return append(buf, scratch[:n]...)
+}
+
+func appendVarint(buf []byte, v int64) []byte {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(scratch[:], v)
+	return append(buf, scratch[:n]...)
+}
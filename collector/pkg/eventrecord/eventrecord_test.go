@@ -0,0 +1,113 @@
+package eventrecord
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEvent(timestamp uint64, data []byte) *model.KindlingEvent {
+	ev := &model.KindlingEvent{
+		Source:       model.Source_SYSCALL_EXIT,
+		Timestamp:    timestamp,
+		Name:         "read",
+		Category:     model.Category_CAT_NET,
+		ParamsNumber: 3,
+		Ctx: model.Context{
+			ThreadInfo: model.Thread{
+				Pid:         1234,
+				Tid:         1235,
+				Comm:        "java",
+				ContainerId: "0123456789ab",
+			},
+			FdInfo: model.Fd{
+				Num:      -1,
+				TypeFd:   model.FDType_FD_IPV4_SOCK,
+				Protocol: model.L4Proto_TCP,
+				Role:     true,
+				Sip:      []uint32{16777343},
+				Dip:      []uint32{33554559},
+				Sport:    8080,
+				Dport:    51234,
+			},
+		},
+	}
+	ev.UserAttributes[0] = model.KeyValue{Key: "res", ValueType: model.ValueType_INT64, Value: []byte{4, 0, 0, 0, 0, 0, 0, 0}}
+	ev.UserAttributes[1] = model.KeyValue{Key: "data", ValueType: model.ValueType_BYTEBUF, Value: data}
+	ev.UserAttributes[2] = model.KeyValue{Key: "latency", ValueType: model.ValueType_UINT64}
+	return ev
+}
+
+func TestMarshal(t *testing.T) {
+	ev := newTestEvent(1656662400000000000, []byte("GET / HTTP/1.1\r\n"))
+	got, err := Unmarshal(Marshal(nil, ev))
+	require.NoError(t, err)
+	assert.Equal(t, ev, got)
+
+	record := Marshal(nil, ev)
+	for i := 0; i < len(record); i++ {
+		if _, err = Unmarshal(record[:i]); err == nil {
+			t.Fatalf("Expected an error for the record truncated at %d", i)
+		}
+	}
+}
+
+func TestReader(t *testing.T) {
+	var buf bytes.Buffer
+	for i := 0; i < 3; i++ {
+		record := Marshal(nil, newTestEvent(uint64(i), []byte("PING\r\n")))
+		buf.Write(appendUvarint(nil, uint64(len(record))))
+		buf.Write(record)
+	}
+	// The last record is incomplete
+	buf.Truncate(buf.Len() - 1)
+
+	reader := NewReader(&buf)
+	for i := 0; i < 2; i++ {
+		ev, err := reader.Read()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(i), ev.Timestamp)
+	}
+	_, err := reader.Read()
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+}
+
+func TestWriterRotation(t *testing.T) {
+	dir := t.TempDir()
+	writer, err := NewWriter(&Config{Path: filepath.Join(dir, "events.kev"), MaxSize: 1})
+	require.NoError(t, err)
+	data := bytes.Repeat([]byte{'a'}, 4096)
+	// About 1.2 MB in total
+	const count = 300
+	for i := 0; i < count; i++ {
+		require.NoError(t, writer.Write(newTestEvent(uint64(i), data)))
+	}
+	require.NoError(t, writer.Close())
+
+	files, err := ListFiles(filepath.Join(dir, "events*.kev"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, filepath.Join(dir, "events.kev"), files[1])
+
+	var timestamp uint64
+	for _, file := range files {
+		reader, err := OpenFile(file)
+		require.NoError(t, err)
+		for {
+			ev, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			assert.Equal(t, timestamp, ev.Timestamp)
+			timestamp++
+		}
+		require.NoError(t, reader.Close())
+	}
+	assert.Equal(t, uint64(count), timestamp)
+}
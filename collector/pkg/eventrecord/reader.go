@@ -0,0 +1,98 @@
+package eventrecord
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+)
+
+// maxRecordSize limits the memory allocated for a corrupted length.
+const maxRecordSize = 16 << 20
+
+// Reader reads the events recorded by Writer.
+type Reader struct {
+	reader  *bufio.Reader
+	closers []io.Closer
+	buf     []byte
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{reader: bufio.NewReaderSize(r, 64*1024)}
+}
+
+// OpenFile opens a recorded file. The files compressed by the rotation are recognized by
+// their ".gz" suffix.
+func OpenFile(path string) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		r := NewReader(file)
+		r.closers = []io.Closer{file}
+		return r, nil
+	}
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to read gzip file %s: %w", path, err)
+	}
+	r := NewReader(gzipReader)
+	r.closers = []io.Closer{gzipReader, file}
+	return r, nil
+}
+
+// Read returns the next event. io.EOF is returned when there are no more events, and
+// io.ErrUnexpectedEOF is returned if the last record is incomplete, which happens when
+// the agent is killed while writing.
+func (r *Reader) Read() (*model.KindlingEvent, error) {
+	length, err := binary.ReadUvarint(r.reader)
+	if err != nil {
+		return nil, err
+	}
+	if length > maxRecordSize {
+		return nil, fmt.Errorf("record size %d exceeds the limit %d", length, maxRecordSize)
+	}
+	if uint64(cap(r.buf)) < length {
+		r.buf = make([]byte, length)
+	}
+	r.buf = r.buf[:length]
+	if _, err = io.ReadFull(r.reader, r.buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return Unmarshal(r.buf)
+}
+
+func (r *Reader) Close() error {
+	var retErr error
+	for _, closer := range r.closers {
+		if err := closer.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}
+	return retErr
+}
+
+// ListFiles returns the files matching the glob pattern in lexical order. For the files
+// written by Writer, e.g. "/tmp/events*.kev*", this is the order they are written in,
+// because the rotated files are named with their rotation time and the one being written
+// is sorted last.
+func ListFiles(pattern string) ([]string, error) {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,68 @@
+package eventrecord
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/Kindling-project/kindling/collector/pkg/model"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Config is the configuration of recording events into files.
+type Config struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Path is the file to write the events to. The rotated files are put in the same directory
+	// and named after it with the rotation time, e.g. events-2022-07-01T08-00-00.000.kev for
+	// events.kev.
+	Path string `mapstructure:"path"`
+	// MaxSize is the maximum size in megabytes of a file before it gets rotated.
+	MaxSize int `mapstructure:"max_size"`
+	// MaxBackups is the maximum number of rotated files to retain. 0 means all of them.
+	MaxBackups int `mapstructure:"max_backups"`
+	// Compress determines whether the rotated files are compressed using gzip.
+	Compress bool `mapstructure:"compress"`
+}
+
+// Writer records events into files with rotation. It is safe for concurrent use.
+//
+// Each record is written as its length in uvarint followed by the encoding of Marshal. A
+// record is never split into two files, so every file could be replayed on its own.
+type Writer struct {
+	mutex  sync.Mutex
+	record []byte
+	buf    []byte
+	output *lumberjack.Logger
+}
+
+func NewWriter(cfg *Config) (*Writer, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("the path of the record file is not set")
+	}
+	return &Writer{
+		record: make([]byte, 0, 4096),
+		buf:    make([]byte, 0, 4096),
+		output: &lumberjack.Logger{
+			Filename:   cfg.Path,
+			MaxSize:    cfg.MaxSize,
+			MaxBackups: cfg.MaxBackups,
+			Compress:   cfg.Compress,
+		},
+	}, nil
+}
+
+func (w *Writer) Write(ev *model.KindlingEvent) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.record = Marshal(w.record[:0], ev)
+	w.buf = appendUvarint(w.buf[:0], uint64(len(w.record)))
+	w.buf = append(w.buf, w.record...)
+	// The record must be written with one call, otherwise it may be split by the rotation.
+	_, err := w.output.Write(w.buf)
+	return err
+}
+
+func (w *Writer) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.output.Close()
+}
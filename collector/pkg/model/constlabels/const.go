@@ -99,10 +99,61 @@ const (
 	SpanMysqlErrorCode = "mysql.error_code"
 	SpanMysqlErrorMsg  = "mysql.error_msg"
 
+	SpanPostgresqlSql      = "postgresql.sql"
+	SpanPostgresqlSqlState = "postgresql.sql_state"
+	SpanPostgresqlErrorMsg = "postgresql.error_msg"
+
 	SpanDubboErrorCode    = "dubbo.error_code"
 	SpanDubboRequestBody  = "dubbo.request_body"
 	SpanDubboResponseBody = "dubbo.response_body"
 
+	SpanMongodbCommand   = "mongodb.command"
+	SpanMongodbErrorCode = "mongodb.error_code"
+	SpanMongodbErrorMsg  = "mongodb.error_msg"
+
+	SpanCassandraStatement = "cassandra.statement"
+	SpanCassandraErrorCode = "cassandra.error_code"
+	SpanCassandraErrorMsg  = "cassandra.error_msg"
+
+	SpanRocketmqTopic     = "rocketmq.topic"
+	SpanRocketmqOperation = "rocketmq.operation"
+	SpanRocketmqErrorCode = "rocketmq.error_code"
+	SpanRocketmqErrorMsg  = "rocketmq.error_msg"
+
+	SpanAmqpMethod     = "amqp.method"
+	SpanAmqpExchange   = "amqp.exchange"
+	SpanAmqpRoutingKey = "amqp.routing_key"
+	SpanAmqpQueue      = "amqp.queue"
+	SpanAmqpReplyCode  = "amqp.reply_code"
+	SpanAmqpReplyText  = "amqp.reply_text"
+
+	SpanMqttPacketType = "mqtt.packet_type"
+	SpanMqttTopic      = "mqtt.topic"
+	SpanMqttQos        = "mqtt.qos"
+	SpanMqttReasonCode = "mqtt.reason_code"
+
+	SpanMemcachedCommand  = "memcached.command"
+	SpanMemcachedStatus   = "memcached.status"
+	SpanMemcachedErrorMsg = "memcached.error_msg"
+
+	SpanZookeeperOpType    = "zookeeper.op_type"
+	SpanZookeeperZxid      = "zookeeper.zxid"
+	SpanZookeeperErrorCode = "zookeeper.error_code"
+	SpanZookeeperErrorMsg  = "zookeeper.error_msg"
+
+	SpanThriftMethod      = "thrift.method"
+	SpanThriftMessageType = "thrift.message_type"
+	SpanThriftErrorType   = "thrift.error_type"
+	SpanThriftErrorMsg    = "thrift.error_msg"
+
+	SpanFastcgiMethod         = "fastcgi.method"
+	SpanFastcgiRequestUri     = "fastcgi.request_uri"
+	SpanFastcgiScriptFilename = "fastcgi.script_filename"
+	SpanFastcgiStatusCode     = "fastcgi.status_code"
+	SpanFastcgiAppStatus      = "fastcgi.app_status"
+	SpanFastcgiProtocolStatus = "fastcgi.protocol_status"
+	SpanFastcgiErrorMsg       = "fastcgi.error_msg"
+
 	NetWorkAnalyzeMetricGroup = "netAnalyzeMetrics"
 )
 const (
@@ -11,6 +11,9 @@ const (
 	HttpResponsePayload = "response_payload"
 	HttpStatusCode      = "http_status_code"
 
+	Http2StreamId  = "http2_stream_id"
+	GrpcStatusCode = "grpc_status_code"
+
 	DnsId     = "dns_id"
 	DnsDomain = "dns_domain"
 	DnsRcode  = "dns_rcode"
@@ -19,8 +22,34 @@ const (
 	Sql        = "sql"
 	SqlErrCode = "sql_error_code"
 	SqlErrMsg  = "sql_error_msg"
+	SqlState   = "sql_state"
+
+	RedisErrMsg       = "redis_error_msg"
+	RedisErrorCommand = "redis_error_command"
+	RedisPipelineSize = "redis_pipeline_size"
+
+	MemcachedOpcode   = "memcached_opcode"
+	MemcachedOpaque   = "memcached_opaque"
+	MemcachedStatus   = "memcached_status"
+	MemcachedErrorMsg = "memcached_error_msg"
+
+	ZookeeperXid       = "zookeeper_xid"
+	ZookeeperZxid      = "zookeeper_zxid"
+	ZookeeperErrorCode = "zookeeper_error_code"
+	ZookeeperErrorMsg  = "zookeeper_error_msg"
+
+	ThriftMessageType = "thrift_message_type"
+	ThriftSeqId       = "thrift_seq_id"
+	ThriftErrorType   = "thrift_error_type"
+	ThriftErrorMsg    = "thrift_error_msg"
 
-	RedisErrMsg = "redis_error_msg"
+	FastcgiMethod         = "fastcgi_method"
+	FastcgiRequestUri     = "fastcgi_request_uri"
+	FastcgiScriptFilename = "fastcgi_script_filename"
+	FastcgiStatusCode     = "fastcgi_status_code"
+	FastcgiAppStatus      = "fastcgi_app_status"
+	FastcgiProtocolStatus = "fastcgi_protocol_status"
+	FastcgiErrorMsg       = "fastcgi_error_msg"
 
 	KafkaApi           = "kafka_api"
 	KafkaVersion       = "kafka_version"
@@ -32,4 +61,29 @@ const (
 	DubboRequestPayload  = "request_payload"
 	DubboResponsePayload = "response_payload"
 	DubboErrorCode       = "dubbo_error_code"
+
+	MongodbRequestId = "mongodb_request_id"
+	MongodbErrorCode = "mongodb_error_code"
+	MongodbErrorMsg  = "mongodb_error_msg"
+
+	CassandraStreamId  = "cassandra_stream_id"
+	CassandraErrorCode = "cassandra_error_code"
+	CassandraErrorMsg  = "cassandra_error_msg"
+
+	RocketmqOpaque      = "rocketmq_opaque"
+	RocketmqRequestCode = "rocketmq_request_code"
+	RocketmqErrorCode   = "rocketmq_error_code"
+	RocketmqErrorMsg    = "rocketmq_error_msg"
+
+	AmqpMethod     = "amqp_method"
+	AmqpExchange   = "amqp_exchange"
+	AmqpRoutingKey = "amqp_routing_key"
+	AmqpQueue      = "amqp_queue"
+	AmqpReplyCode  = "amqp_reply_code"
+	AmqpReplyText  = "amqp_reply_text"
+
+	MqttPacketType = "mqtt_packet_type"
+	MqttPacketId   = "mqtt_packet_id"
+	MqttQos        = "mqtt_qos"
+	MqttReasonCode = "mqtt_reason_code"
 )
@@ -0,0 +1,143 @@
+package constlabels
+
+// reservedLabels are the names of the built-in labels. The labels configured by the users must
+// not take them, otherwise the built-in ones are overwritten.
+var reservedLabels = map[string]bool{
+	Comm:                    true,
+	Pid:                     true,
+	Protocol:                true,
+	IsError:                 true,
+	ErrorType:               true,
+	IsSlow:                  true,
+	IsServer:                true,
+	ContainerId:             true,
+	SrcNode:                 true,
+	SrcNodeIp:               true,
+	SrcNamespace:            true,
+	SrcPod:                  true,
+	SrcWorkloadName:         true,
+	SrcWorkloadKind:         true,
+	SrcService:              true,
+	SrcIp:                   true,
+	SrcPort:                 true,
+	SrcContainerId:          true,
+	SrcContainer:            true,
+	DstNode:                 true,
+	DstNodeIp:               true,
+	DstNamespace:            true,
+	DstPod:                  true,
+	DstWorkloadName:         true,
+	DstWorkloadKind:         true,
+	DstService:              true,
+	DstIp:                   true,
+	DstPort:                 true,
+	DnatIp:                  true,
+	DnatPort:                true,
+	DstContainerId:          true,
+	DstContainer:            true,
+	Node:                    true,
+	Namespace:               true,
+	WorkloadKind:            true,
+	WorkloadName:            true,
+	Service:                 true,
+	Pod:                     true,
+	Container:               true,
+	Ip:                      true,
+	Port:                    true,
+	Errno:                   true,
+	Success:                 true,
+	RequestContent:          true,
+	ResponseContent:         true,
+	StatusCode:              true,
+	Topic:                   true,
+	Operation:               true,
+	ConsumerId:              true,
+	RequestDurationStatus:   true,
+	RequestReqxferStatus:    true,
+	RequestProcessingStatus: true,
+	ResponseRspxferStatus:   true,
+	RequestTotalNs:          true,
+	RequestSentNs:           true,
+	WaitingTTfbNs:           true,
+	ContentDownloadNs:       true,
+	RequestIoBytes:          true,
+	ResponseIoBytes:         true,
+	Timestamp:               true,
+	IsConvergent:            true,
+	SpanSrcContainerId:      true,
+	SpanSrcContainerName:    true,
+	SpanDstContainerId:      true,
+	SpanDstContainerName:    true,
+	ContentKey:              true,
+	HttpMethod:              true,
+	HttpUrl:                 true,
+	HttpApmTraceType:        true,
+	HttpApmTraceId:          true,
+	HttpRequestPayload:      true,
+	HttpResponsePayload:     true,
+	HttpStatusCode:          true,
+	Http2StreamId:           true,
+	GrpcStatusCode:          true,
+	DnsId:                   true,
+	DnsDomain:               true,
+	DnsRcode:                true,
+	DnsIp:                   true,
+	Sql:                     true,
+	SqlErrCode:              true,
+	SqlErrMsg:               true,
+	SqlState:                true,
+	RedisErrMsg:             true,
+	RedisErrorCommand:       true,
+	RedisPipelineSize:       true,
+	MemcachedOpcode:         true,
+	MemcachedOpaque:         true,
+	MemcachedStatus:         true,
+	MemcachedErrorMsg:       true,
+	ZookeeperXid:            true,
+	ZookeeperZxid:           true,
+	ZookeeperErrorCode:      true,
+	ZookeeperErrorMsg:       true,
+	ThriftMessageType:       true,
+	ThriftSeqId:             true,
+	ThriftErrorType:         true,
+	ThriftErrorMsg:          true,
+	FastcgiMethod:           true,
+	FastcgiRequestUri:       true,
+	FastcgiScriptFilename:   true,
+	FastcgiStatusCode:       true,
+	FastcgiAppStatus:        true,
+	FastcgiProtocolStatus:   true,
+	FastcgiErrorMsg:         true,
+	KafkaApi:                true,
+	KafkaVersion:            true,
+	KafkaCorrelationId:      true,
+	KafkaTopic:              true,
+	KafkaPartition:          true,
+	KafkaErrorCode:          true,
+	DubboErrorCode:          true,
+	MongodbRequestId:        true,
+	MongodbErrorCode:        true,
+	MongodbErrorMsg:         true,
+	CassandraStreamId:       true,
+	CassandraErrorCode:      true,
+	CassandraErrorMsg:       true,
+	RocketmqOpaque:          true,
+	RocketmqRequestCode:     true,
+	RocketmqErrorCode:       true,
+	RocketmqErrorMsg:        true,
+	AmqpMethod:              true,
+	AmqpExchange:            true,
+	AmqpRoutingKey:          true,
+	AmqpQueue:               true,
+	AmqpReplyCode:           true,
+	AmqpReplyText:           true,
+	MqttPacketType:          true,
+	MqttPacketId:            true,
+	MqttQos:                 true,
+	MqttReasonCode:          true,
+}
+
+// IsReserved returns true if the name is taken by a built-in label.
+func IsReserved(name string) bool {
+	return reservedLabels[name]
+}
@@ -16,11 +16,21 @@ const (
 )
 
 const (
-	ProtocolHttp  = "http"
-	ProtocolHttp2 = "http2"
-	ProtocolGrpc  = "grpc"
-	ProtocolDubbo = "dubbo"
-	ProtocolDns   = "dns"
-	ProtocolKafka = "kafka"
-	ProtocolMysql = "mysql"
+	ProtocolHttp       = "http"
+	ProtocolHttp2      = "http2"
+	ProtocolGrpc       = "grpc"
+	ProtocolDubbo      = "dubbo"
+	ProtocolDns        = "dns"
+	ProtocolKafka      = "kafka"
+	ProtocolRocketmq   = "rocketmq"
+	ProtocolAmqp       = "amqp"
+	ProtocolMemcached  = "memcached"
+	ProtocolZookeeper  = "zookeeper"
+	ProtocolMqtt       = "mqtt"
+	ProtocolThrift     = "thrift"
+	ProtocolFastcgi    = "fastcgi"
+	ProtocolMysql      = "mysql"
+	ProtocolPostgresql = "postgresql"
+	ProtocolMongodb    = "mongodb"
+	ProtocolCassandra  = "cassandra"
 )
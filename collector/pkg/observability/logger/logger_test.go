@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"path/filepath"
 	"testing"
 
 	"go.uber.org/zap"
@@ -9,7 +10,7 @@ import (
 
 func Test_newLogger(t *testing.T) {
 	config := &lumberjack.Logger{
-		Filename:   "tmp.log",
+		Filename:   filepath.Join(t.TempDir(), "tmp.log"),
 		MaxSize:    500, // megabytes
 		MaxBackups: 3,
 		MaxAge:     28,
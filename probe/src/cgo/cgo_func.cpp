@@ -14,6 +14,10 @@ int getKindlingEvent(void **kindlingEvent){
 	return getEvent(kindlingEvent);
 }
 
+void stopForGo(){
+	stop_probe();
+}
+
 
 void subEventForGo(char* eventName, char* category){
 	sub_event(eventName, category);
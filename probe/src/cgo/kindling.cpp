@@ -133,6 +133,14 @@ void init_probe()
 	}
 }
 
+void stop_probe()
+{
+	if(inspector != nullptr)
+	{
+		inspector->close();
+	}
+}
+
 int getEvent(void **pp_kindling_event)
 {
 	int32_t res;
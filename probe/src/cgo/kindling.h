@@ -8,6 +8,7 @@
 
 void init_probe();
 int getEvent(void **kindlingEvent);
+void stop_probe();
 uint16_t get_kindling_category(sinsp_evt *sEvt);
 void init_sub_label();
 void sub_event(char* eventName, char* category);